@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	// "github.com/golang/glog"
@@ -36,6 +37,10 @@ const (
 	cmdAdd                      = "add"
 	cmdDel                      = "del"
 	cmdMonitor                  = "monitor"
+	cmdPoll                     = "poll"
+	cmdListeners                = "listeners"
+	cmdQueues                   = "queues"
+	cmdDynamic                  = "dynamic"
 )
 
 type options struct {
@@ -83,6 +88,9 @@ func newRootCmd() *cobra.Command {
 
 	rootCmd.AddCommand(newPeerCmd())
 	rootCmd.AddCommand(addRequiredFlag(newMonitorCmd(), true))
+	rootCmd.AddCommand(newListenerCmd())
+	rootCmd.AddCommand(newQueueCmd())
+	rootCmd.AddCommand(newDynamicCmd())
 
 	return rootCmd
 }
@@ -151,7 +159,31 @@ func newPeerCmd() *cobra.Command {
 
 				peer := response.Peer
 
-				fmt.Printf("%s\t%s\t%s <-> %s 127.0.0.1\n", peer.Name, peer.Address, state.Remote.State, state.Local.State)
+				role := "active"
+
+				if peer.Passive {
+					role = "passive"
+				}
+
+				if peer.Unsolicited {
+					role += ", unsolicited"
+				} else if peer.Dynamic {
+					role += ", dynamic"
+				}
+
+				if peer.Interface != "" {
+					role += ", micro"
+				}
+
+				if peer.IsVxlan {
+					role += fmt.Sprintf(", vni %d", peer.Vni)
+				}
+
+				fmt.Printf("%s\t%s\t%s <-> %s 127.0.0.1\t%s\n", peer.Name, peer.Address, state.Remote.State, state.Local.State, role)
+
+				if len(response.Violations) > 0 {
+					fmt.Printf("\tInvalid packets: %s\n", formatViolations(response.Violations))
+				}
 			}
 
 			if count == 0 {
@@ -167,6 +199,7 @@ func newPeerCmd() *cobra.Command {
 	peers.AddCommand(addRequiredFlag(newPeerDelCmd(), true))
 	peers.AddCommand(addRequiredFlag(newPeerEnableCmd(), true))
 	peers.AddCommand(addRequiredFlag(newPeerDisableCmd(), true))
+	peers.AddCommand(addRequiredFlag(newPeerPollCmd(), true))
 
 	return peers
 }
@@ -309,24 +342,49 @@ func newPeerSetDetectMultiplierCmd() *cobra.Command {
 }
 
 func newPeerAddCmd() *cobra.Command {
-	var ip net.IP
+	var address string
 	var txinterval, rxinterval, multiplier uint64
+	var authentication *api.Authentication
+	var keyId uint8
+	var demandMode bool
+	var demandPollInterval uint32
+	var echoInterval uint32
+	var echoReflector bool
+	var multiHop bool
+	var localAddress string
+	var minTTL uint8
+	var passive bool
+	var vni uint32
+	var padToSize uint32
 
 	cmd := &cobra.Command{
 		Use: cmdAdd,
 		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) != 7 {
-				return errors.New("Please pass the following parameters: {Name} {IP} {DesiredMinTxInterval} {RequiredMinRxInterval} {DetectMultiplier} {IsMultiHop[Yes|No]} {Authentication[None|SimplePassword|KeyedMD5|MeticulousKeyedMD5|KeyedSHA1|MeticulousKeyedSHA1]} {PasswordIfAuthenticationIsNotNone}")
+			if len(args) != 7 && len(args) != 8 {
+				return errors.New("Please pass the following parameters: {Name} {IP} {DesiredMinTxInterval} {RequiredMinRxInterval} {DetectMultiplier} {IsMultiHop[Yes|No]} {Authentication[None|SimplePassword|KeyedMD5|MeticulousKeyedMD5|KeyedSHA1|MeticulousKeyedSHA1|MeticulousKeyedHMACSHA256]} {PasswordIfAuthenticationIsNotNone}")
 			}
 
 			var err error
 
-			ip = net.ParseIP(args[1])
+			// link local IPv6 addresses can be followed by a zone, e.g. fe80::1%eth0
+			host, zone := args[1], ""
+
+			if i := strings.LastIndex(host, "%"); i >= 0 {
+				host, zone = host[:i], host[i+1:]
+			}
+
+			ip := net.ParseIP(host)
 
-			if ip == nil {
+			if ip == nil || (zone != "" && ip.To4() != nil) {
 				return errors.New("Please pass a valid ip address")
 			}
 
+			address = ip.String()
+
+			if zone != "" {
+				address += "%" + zone
+			}
+
 			txinterval, err = strconv.ParseUint(args[2], 10, 32)
 
 			if err != nil {
@@ -345,12 +403,42 @@ func newPeerAddCmd() *cobra.Command {
 				return errors.New(fmt.Sprintf("Error parsing DetectMultiplier: %s", err.Error()))
 			}
 
-			if args[5] != "No" {
-				return errors.New("Only No is supported for IsMultiHop")
+			switch args[5] {
+			case "Yes":
+				multiHop = true
+			case "No":
+				multiHop = false
+			default:
+				return errors.New("Please pass Yes or No for IsMultiHop")
 			}
 
-			if args[6] != "None" {
-				return errors.New("Only None is suppored for Authentication")
+			if localAddress != "" && net.ParseIP(localAddress) == nil {
+				return errors.New("Please pass a valid local address")
+			}
+
+			authType, err := api.ParseAuthenticationType(args[6])
+
+			if err != nil {
+				return err
+			}
+
+			if authType == api.AuthenticationType_NONE {
+				if len(args) != 7 {
+					return errors.New("No password can be passed if Authentication is None")
+				}
+
+				authentication = nil
+				return nil
+			}
+
+			if len(args) != 8 {
+				return errors.New("Please pass a password for the Authentication, prefix it with 0x to pass it hex encoded")
+			}
+
+			authentication = &api.Authentication{
+				Type:     authType,
+				Password: args[7],
+				KeyId:    uint32(keyId),
 			}
 
 			return nil
@@ -359,10 +447,22 @@ func newPeerAddCmd() *cobra.Command {
 			_, err := client.AddPeer(context.Background(), &api.AddPeerRequest{
 				Peer: &api.Peer{
 					Name:                  args[0],
-					Address:               ip.String(),
+					Address:               address,
 					DesiredMinTxInterval:  uint32(txinterval),
 					RequiredMinRxInterval: uint32(rxinterval),
 					DetectMultiplier:      uint32(multiplier),
+					Authentication:        authentication,
+					DemandMode:            demandMode,
+					DemandPollInterval:    demandPollInterval,
+					EchoInterval:          echoInterval,
+					EchoReflector:         echoReflector,
+					IsMultiHop:            multiHop,
+					LocalAddress:          localAddress,
+					MinTtl:                uint32(minTTL),
+					Passive:               passive,
+					IsVxlan:               cmd.Flags().Changed("vni"),
+					Vni:                   vni,
+					PadToSize:             padToSize,
 				},
 			})
 
@@ -374,6 +474,17 @@ func newPeerAddCmd() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().Uint8VarP(&keyId, "key-id", "k", 0, "Authentication key id sent with every packet")
+	cmd.Flags().BoolVarP(&demandMode, "demand", "d", false, "Request Demand mode once the session is up")
+	cmd.Flags().Uint32VarP(&demandPollInterval, "demand-poll-interval", "", 0, "Interval in ms of the poll sequences verifying the session in Demand mode")
+	cmd.Flags().Uint32VarP(&echoInterval, "echo-interval", "e", 0, "Interval in ms of the echo packets, 0 disables the echo function")
+	cmd.Flags().BoolVarP(&echoReflector, "echo-reflector", "", false, "Send the echo packets to the echo reflector of a bfdd peer instead of its forwarding plane")
+	cmd.Flags().StringVarP(&localAddress, "local-address", "l", "", "Source address of the session, used to match multi hop sessions")
+	cmd.Flags().Uint8VarP(&minTTL, "min-ttl", "", 0, "Minimum TTL of received multi hop packets")
+	cmd.Flags().BoolVarP(&passive, "passive", "", false, "Don't send packets until the peer sent one")
+	cmd.Flags().Uint32VarP(&vni, "vni", "", 0, "Sends the packets inside VXLAN with this VNI to the VTEP at the ip")
+	cmd.Flags().Uint32VarP(&padToSize, "pad-to-size", "", 0, "Pads the packets to this size in bytes with DF set to verify the path MTU")
+
 	return cmd
 }
 
@@ -456,6 +567,32 @@ func newPeerDisableCmd() *cobra.Command {
 	return disable
 }
 
+func newPeerPollCmd() *cobra.Command {
+	poll := &cobra.Command{
+		Use:  cmdPoll,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			peers, _ := getPeers()
+
+			if uuid, ok := peers[peer]; ok {
+				_, err := client.PollPeer(context.Background(), &api.PollPeerRequest{
+					Uuid: uuid,
+				})
+
+				if err != nil {
+					fmt.Printf("Error polling peer: %s\n", err.Error())
+				} else {
+					fmt.Printf("Started poll sequence for peer %s\n", peer)
+				}
+			} else {
+				fmt.Printf("Peer not found!\n")
+			}
+		},
+	}
+
+	return poll
+}
+
 func newMonitorCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use: cmdMonitor,
@@ -494,6 +631,128 @@ func newMonitorCmd() *cobra.Command {
 	return cmd
 }
 
+func newListenerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use: cmdListeners,
+		Run: func(cmd *cobra.Command, args []string) {
+			stream, err := client.ListListener(context.Background(), &api.ListListenerRequest{})
+
+			if err != nil {
+				exitWithError(err)
+			}
+
+			for {
+				response, err := stream.Recv()
+
+				if err == io.EOF {
+					break
+				}
+
+				if err != nil {
+					fmt.Printf("Error listing listeners: %s\n", err.Error())
+					return
+				}
+
+				kind := "single hop"
+
+				if response.IsMultiHop {
+					kind = "multi hop"
+				} else if response.IsMicro {
+					kind = "micro " + response.Interface
+				} else if response.IsVxlan {
+					kind = "vxlan"
+				}
+
+				violations := "no invalid packets"
+
+				if len(response.Violations) > 0 {
+					violations = formatViolations(response.Violations)
+				}
+
+				fmt.Printf("%s\t%s\t%s\n", response.Address, kind, violations)
+			}
+		},
+	}
+
+	return cmd
+}
+
+func newQueueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use: cmdQueues,
+		Run: func(cmd *cobra.Command, args []string) {
+			stream, err := client.ListInboundQueue(context.Background(), &api.ListInboundQueueRequest{})
+
+			if err != nil {
+				exitWithError(err)
+			}
+
+			for {
+				response, err := stream.Recv()
+
+				if err == io.EOF {
+					break
+				}
+
+				if err != nil {
+					fmt.Printf("Error listing queues: %s\n", err.Error())
+					return
+				}
+
+				fmt.Printf("Worker %d\t%d / %d queued\t%d dropped\n", response.Worker, response.Depth, response.Capacity, response.Drops)
+			}
+		},
+	}
+
+	return cmd
+}
+
+func newDynamicCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use: cmdDynamic,
+		Run: func(cmd *cobra.Command, args []string) {
+			stream, err := client.ListDynamicRange(context.Background(), &api.ListDynamicRangeRequest{})
+
+			if err != nil {
+				exitWithError(err)
+			}
+
+			for {
+				response, err := stream.Recv()
+
+				if err == io.EOF {
+					break
+				}
+
+				if err != nil {
+					fmt.Printf("Error listing dynamic ranges: %s\n", err.Error())
+					return
+				}
+
+				limit := "unlimited"
+
+				if response.MaxSessions > 0 {
+					limit = fmt.Sprintf("%d", response.MaxSessions)
+				}
+
+				fmt.Printf("%s\t%d / %s sessions\t%d rejected\n", response.Prefix, response.Sessions, limit, response.Rejected)
+			}
+		},
+	}
+
+	return cmd
+}
+
+func formatViolations(violations []*api.ViolationCount) string {
+	counts := make([]string, 0, len(violations))
+
+	for _, v := range violations {
+		counts = append(counts, fmt.Sprintf("%s: %d", v.Violation, v.Count))
+	}
+
+	return strings.Join(counts, ", ")
+}
+
 func exitWithError(err error) {
 	printError(err)
 	os.Exit(1)
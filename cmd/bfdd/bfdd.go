@@ -45,13 +45,15 @@ func main() {
 	}
 
 	app := app.NewBfdApp()
-	app.Start()
 
+	// the config is applied while starting, the settings of the server can't be changed once it listens
 	if err := app.LoadConfig(options.Config); err != nil {
 		glog.Errorf("%s", err.Error())
 	}
 
-	exit_ch := make(chan os.Signal)
+	app.Start()
+
+	exit_ch := make(chan os.Signal, 1)
 
 	signal.Notify(exit_ch, syscall.SIGTERM)
 	signal.Notify(exit_ch, os.Interrupt)
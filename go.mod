@@ -12,7 +12,7 @@ require (
 	github.com/spf13/cobra v0.0.4-0.20190109003409-7547e83b2d85
 	github.com/spf13/pflag v1.0.3
 	golang.org/x/net v0.0.0-20190225153610-fe579d43d832
-	golang.org/x/sys v0.0.0-20190225065934-cc5685c2db12 // indirect
+	golang.org/x/sys v0.0.0-20190225065934-cc5685c2db12
 	google.golang.org/genproto v0.0.0-20190219182410-082222b4a5c5 // indirect
 	google.golang.org/grpc v1.18.0
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
@@ -21,12 +21,14 @@ type BfdApp struct {
 	srv *server.BfdServer
 	grpc *grpc.Server
 	api  *server.BfdApiServer
+	conf *config.Config
 }
 
 func NewBfdApp() *BfdApp {
 	return &BfdApp{}
 }
 
+// LoadConfig reads the config applied by Start, it needs to be called before
 func (s *BfdApp) LoadConfig(path string) error {
 	// Read our yaml config file
 	data, err := ioutil.ReadFile(path)
@@ -44,31 +46,296 @@ func (s *BfdApp) LoadConfig(path string) error {
 
 	glog.Infof("%v", conf)
 
+	s.conf = conf
+
+	return nil
+}
+
+// serve applies the loaded config to srv and starts it, the settings of the server and the
+// listeners are applied before serving, as they can't be changed once the server listens
+func (s *BfdApp) serve(srv *server.BfdServer) error {
+	s.srv = srv
+
+	if s.conf != nil {
+		s.loadServer(s.conf)
+	}
+
+	err := s.srv.Serve()
+
+	if err != nil {
+		return err
+	}
+
+	if s.conf != nil {
+		s.loadSessions(s.conf)
+	}
+
+	return nil
+}
+
+func (s *BfdApp) loadServer(conf *config.Config) {
+	if conf.Inbound != nil {
+		err := s.srv.SetInboundQueues(conf.Inbound.Workers, conf.Inbound.QueueLength)
+
+		if err != nil {
+			glog.Errorf("Error setting the inbound queues: %s", err)
+		}
+	}
+
+	if conf.Batch != nil {
+		err := s.srv.SetBatchIO(conf.Batch.Size, conf.Batch.Sockets, conf.Batch.SharedSocket)
+
+		if err != nil {
+			glog.Errorf("Error enabling batched I/O: %s", err)
+		}
+	}
+
 	for _, ip := range conf.Listen {
-		s.srv.Listen(ip)
+		err := s.srv.Listen(ip)
+
+		if err != nil {
+			glog.Errorf("Error listening on %s: %s", ip, err)
+		}
+	}
+
+	for _, ip := range conf.ListenMultiHop {
+		err := s.srv.ListenMultiHop(ip)
+
+		if err != nil {
+			glog.Errorf("Error listening for multi hop sessions on %s: %s", ip, err)
+		}
+	}
+}
+
+func (s *BfdApp) loadSessions(conf *config.Config) {
+	for _, ip := range conf.ListenVxlan {
+		err := s.srv.ListenVxlan(ip)
+
+		if err != nil {
+			glog.Errorf("Error listening for VXLAN sessions on %s: %s", ip, err)
+		}
+	}
+
+	if conf.Echo != nil {
+		// the forwarding plane loops the echo packets back, the interval only needs to be advertised
+		s.srv.SetEchoRequiredMinRxInterval(uint32(conf.Echo.RequiredMinRxInterval * 1000))
+
+		for _, ip := range conf.Echo.Listen {
+			err := s.srv.ListenEcho(ip, uint32(conf.Echo.RequiredMinRxInterval * 1000))
+
+			if err != nil {
+				glog.Errorf("Error starting echo reflector on %s: %s", ip, err)
+			}
+		}
+	}
+
+	// Key chains need to exist before the peers referencing them
+	for name, keys := range conf.KeyChains {
+		err := s.srv.AddKeyChain(toApiKeyChain(name, keys))
+
+		if err != nil {
+			glog.Errorf("Error adding key chain %s: %s", name, err)
+		}
 	}
 
 	// Update the live config
 	for ip, settings := range conf.Peers {
-		peer, err := s.srv.AddPeer(&api.Peer{
-			Name: settings.Name,
-			Address: ip,
+		api_peer, err := toApiPeer(ip, settings)
+
+		if err != nil {
+			glog.Errorf("Error adding peer %s: %s", ip, err)
+			continue
+		}
+
+		peer, err := s.srv.AddPeer(api_peer)
+
+		if err != nil {
+			glog.Errorf("Error adding peer: %s", err)
+			continue
+		}
+
+		go s.ListenStateUpdates(peer)
+	}
+
+	if conf.Sbfd != nil {
+		s.loadSbfd(conf.Sbfd)
+	}
+
+	for name, settings := range conf.Lags {
+		err := s.loadLag(name, settings)
+
+		if err != nil {
+			glog.Errorf("Error adding LAG %s: %s", name, err)
+		}
+	}
+
+	for _, dynamic := range conf.Dynamic {
+		template, err := toApiPeer("", dynamic.Peer)
+
+		if err == nil {
+			err = s.srv.AddDynamicRange(dynamic.Prefix, template, time.Duration(dynamic.IdleTimeout) * time.Second, dynamic.MaxSessions)
+		}
+
+		if err != nil {
+			glog.Errorf("Error adding dynamic range %s: %s", dynamic.Prefix, err)
+		}
+	}
+
+	for iface, unsolicited := range conf.Unsolicited {
+		profile, err := toApiPeer("", unsolicited.Peer)
+
+		if err == nil {
+			err = s.srv.EnableUnsolicited(iface, unsolicited.Prefixes, profile, time.Duration(unsolicited.IdleTimeout) * time.Second)
+		}
+
+		if err != nil {
+			glog.Errorf("Error enabling unsolicited BFD on %s: %s", iface, err)
+		}
+	}
+}
+
+func (s *BfdApp) loadSbfd(conf *config.Sbfd) {
+	if conf.Reflector != nil {
+		for _, ip := range conf.Reflector.Listen {
+			err := s.srv.ListenSbfdReflector(ip, uint32(conf.Reflector.RequiredMinRxInterval * 1000))
+
+			if err != nil {
+				glog.Errorf("Error starting S-BFD reflector on %s: %s", ip, err)
+			}
+		}
+
+		for _, discriminator := range conf.Reflector.Discriminators {
+			err := s.srv.AddSbfdReflector(discriminator)
+
+			if err != nil {
+				glog.Errorf("Error adding S-BFD reflector discriminator %d: %s", discriminator, err)
+			}
+		}
+	}
+
+	for name, settings := range conf.Initiators {
+		_, err := s.srv.AddSbfdInitiator(&api.SbfdInitiator{
+			Name: name,
+			Address: settings.Address,
+			RemoteDiscriminator: settings.RemoteDiscriminator,
 			DesiredMinTxInterval: uint32(settings.Interval),
-			RequiredMinRxInterval: uint32(settings.Interval),
 			DetectMultiplier: uint32(settings.DetectionMultiplier),
+			LocalAddress: settings.LocalAddress,
 		})
 
 		if err != nil {
-			glog.Errorf("Error adding peer: %s", err)
-			continue
+			glog.Errorf("Error adding S-BFD initiator %s: %s", name, err)
 		}
+	}
+}
+
+func (s *BfdApp) loadLag(name string, settings config.Lag) error {
+	auth, err := toApiAuthentication(settings.Authentication)
 
+	if err != nil {
+		return err
+	}
+
+	lag, err := s.srv.AddLag(&api.Lag{
+		Name: name,
+		Address: settings.Address,
+		Members: settings.Members,
+		MinLinks: uint32(settings.MinLinks),
+		DesiredMinTxInterval: uint32(settings.Interval),
+		RequiredMinRxInterval: uint32(settings.Interval),
+		DetectMultiplier: uint32(settings.DetectionMultiplier),
+		Authentication: auth,
+		LocalAddress: settings.LocalAddress,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, peer := range lag.Members() {
 		go s.ListenStateUpdates(peer)
 	}
 
 	return nil
 }
 
+func toApiPeer(address string, settings config.Peer) (*api.Peer, error) {
+	auth, err := toApiAuthentication(settings.Authentication)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Peer{
+		Name: settings.Name,
+		Address: address,
+		DesiredMinTxInterval: uint32(settings.Interval),
+		RequiredMinRxInterval: uint32(settings.Interval),
+		DetectMultiplier: uint32(settings.DetectionMultiplier),
+		Authentication: auth,
+		DemandMode: settings.DemandMode,
+		DemandPollInterval: uint32(settings.DemandPollInterval),
+		EchoInterval: uint32(settings.EchoInterval),
+		EchoReflector: settings.EchoReflector,
+		IsMultiHop: settings.MultiHop,
+		LocalAddress: settings.LocalAddress,
+		MinTtl: uint32(settings.MinTTL),
+		Passive: settings.Passive,
+		IsVxlan: settings.Vxlan,
+		Vni: settings.Vni,
+		PadToSize: uint32(settings.PadToSize),
+	}, nil
+}
+
+func toApiAuthentication(auth *config.Authentication) (*api.Authentication, error) {
+	if auth == nil {
+		return nil, nil
+	}
+
+	authType, err := api.ParseAuthenticationType(auth.Type)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Authentication{
+		Type: authType,
+		KeyId: uint32(auth.KeyId),
+		Password: auth.Password,
+		KeyChain: auth.KeyChain,
+		Optimized: auth.Optimized,
+	}, nil
+}
+
+func toApiKeyChain(name string, keys []config.Key) *api.KeyChain {
+	chain := &api.KeyChain{
+		Name: name,
+		Keys: make([]*api.Key, len(keys)),
+	}
+
+	for idx, key := range keys {
+		chain.Keys[idx] = &api.Key{
+			Id: uint32(key.Id),
+			Password: key.Password,
+			SendStart: toUnix(key.SendStart),
+			SendEnd: toUnix(key.SendEnd),
+			AcceptStart: toUnix(key.AcceptStart),
+			AcceptEnd: toUnix(key.AcceptEnd),
+		}
+	}
+
+	return chain
+}
+
+// toUnix converts a lifetime boundary, the zero time means unbounded
+func toUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
+
 func (s *BfdApp) ListenStateUpdates(peer *server.Peer) {
 	downCounter := 0
 
@@ -110,11 +377,11 @@ func (s *BfdApp) Start() {
 	// init our default random source
 	rand.Seed(time.Now().UnixNano())
 
-	s.srv = server.NewBfdServer()
+	srv := server.NewBfdServer()
 
 	s.grpc = s.NewGrpcServer()
 
-	s.api = server.NewBfdApiServer(s.srv, s.grpc)
+	s.api = server.NewBfdApiServer(srv, s.grpc)
 
 	go s.api.ServeApi("127.0.0.1:" + strconv.Itoa(api.GRPC_PORT))
 
@@ -123,7 +390,7 @@ func (s *BfdApp) Start() {
 		return
 	}*/
 
-	err := s.srv.Serve()
+	err := s.serve(srv)
 
 	if err != nil {
 		glog.Errorf("Error starting server: %s", err.Error())
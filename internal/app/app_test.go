@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/server"
+)
+
+const testConfig = `
+inbound:
+  workers: 3
+  queueLength: 16
+listen:
+  - 10.0.0.1:3784
+listenMultiHop:
+  - 10.0.0.1:4784
+peers:
+  10.0.0.2:
+    interval: 100
+    detectionMultiplier: 3
+`
+
+func loadTestConfig(t *testing.T, app *BfdApp, conf string) {
+	f, err := ioutil.TempFile("", "bfdd-*.yaml")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer os.Remove(f.Name())
+
+	f.WriteString(conf)
+	f.Close()
+
+	if err := app.LoadConfig(f.Name()); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestServeAppliesConfig(t *testing.T) {
+	network := server.NewVirtualNetwork(1)
+
+	srv := server.NewBfdServer()
+	srv.SetClock(network.Clock())
+	srv.SetTransport(network.Transport(net.ParseIP("10.0.0.1")))
+
+	app := NewBfdApp()
+	loadTestConfig(t, app, testConfig)
+
+	if err := app.serve(srv); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer srv.Shutdown()
+
+	// the inbound queues can only be set before the server listens
+	queues := 0
+
+	srv.ListInboundQueue(context.Background(), func(q *api.ListInboundQueueResponse) error {
+		queues++
+
+		if q.Capacity != 16 {
+			t.Errorf("Expected a queue length of 16, got %d", q.Capacity)
+		}
+
+		return nil
+	})
+
+	if queues != 3 {
+		t.Errorf("Expected 3 inbound workers, got %d", queues)
+	}
+
+	// the configured listeners replace the default ones
+	listeners := map[string]bool{}
+
+	srv.ListListener(context.Background(), func(l *api.ListListenerResponse) error {
+		listeners[l.Address] = l.IsMultiHop
+
+		return nil
+	})
+
+	if multiHop, ok := listeners["10.0.0.1:3784"]; len(listeners) != 2 || !ok || multiHop || !listeners["10.0.0.1:4784"] {
+		t.Errorf("Expected the configured listeners only, got %v", listeners)
+	}
+
+	peers := 0
+
+	srv.ListPeer(context.Background(), func(uuid []byte, peer *api.Peer, violations []*api.ViolationCount) error {
+		peers++
+
+		return nil
+	})
+
+	if peers != 1 {
+		t.Errorf("Expected the configured peer to be added, got %d peers", peers)
+	}
+}
@@ -103,12 +103,13 @@ func (DiagnosticCode) EnumDescriptor() ([]byte, []int) {
 type AuthenticationType int32
 
 const (
-	AuthenticationType_NONE                  AuthenticationType = 0
-	AuthenticationType_SIMPLE_PASSWORD       AuthenticationType = 1
-	AuthenticationType_KEYED_MD5             AuthenticationType = 2
-	AuthenticationType_METICULOUS_KEYED_MD5  AuthenticationType = 3
-	AuthenticationType_KEYED_SHA1            AuthenticationType = 4
-	AuthenticationType_METICULOUS_KEYED_SHA1 AuthenticationType = 5
+	AuthenticationType_NONE                         AuthenticationType = 0
+	AuthenticationType_SIMPLE_PASSWORD              AuthenticationType = 1
+	AuthenticationType_KEYED_MD5                    AuthenticationType = 2
+	AuthenticationType_METICULOUS_KEYED_MD5         AuthenticationType = 3
+	AuthenticationType_KEYED_SHA1                   AuthenticationType = 4
+	AuthenticationType_METICULOUS_KEYED_SHA1        AuthenticationType = 5
+	AuthenticationType_METICULOUS_KEYED_HMAC_SHA256 AuthenticationType = 6
 )
 
 var AuthenticationType_name = map[int32]string{
@@ -118,15 +119,17 @@ var AuthenticationType_name = map[int32]string{
 	3: "METICULOUS_KEYED_MD5",
 	4: "KEYED_SHA1",
 	5: "METICULOUS_KEYED_SHA1",
+	6: "METICULOUS_KEYED_HMAC_SHA256",
 }
 
 var AuthenticationType_value = map[string]int32{
-	"NONE":                  0,
-	"SIMPLE_PASSWORD":       1,
-	"KEYED_MD5":             2,
-	"METICULOUS_KEYED_MD5":  3,
-	"KEYED_SHA1":            4,
-	"METICULOUS_KEYED_SHA1": 5,
+	"NONE":                         0,
+	"SIMPLE_PASSWORD":              1,
+	"KEYED_MD5":                    2,
+	"METICULOUS_KEYED_MD5":         3,
+	"KEYED_SHA1":                   4,
+	"METICULOUS_KEYED_SHA1":        5,
+	"METICULOUS_KEYED_HMAC_SHA256": 6,
 }
 
 func (x AuthenticationType) String() string {
@@ -137,6 +140,52 @@ func (AuthenticationType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{2}
 }
 
+type Violation int32
+
+const (
+	Violation_INVALID_VERSION               Violation = 0
+	Violation_INVALID_LENGTH                Violation = 1
+	Violation_INVALID_AUTHENTICATION_LENGTH Violation = 2
+	Violation_INVALID_AUTHENTICATION_TYPE   Violation = 3
+	Violation_ZERO_DETECT_MULTIPLIER        Violation = 4
+	Violation_MULTIPOINT_SET                Violation = 5
+	Violation_ZERO_MY_DISCRIMINATOR         Violation = 6
+	Violation_ZERO_YOUR_DISCRIMINATOR       Violation = 7
+	Violation_RESERVED_FIELD_SET            Violation = 8
+)
+
+var Violation_name = map[int32]string{
+	0: "INVALID_VERSION",
+	1: "INVALID_LENGTH",
+	2: "INVALID_AUTHENTICATION_LENGTH",
+	3: "INVALID_AUTHENTICATION_TYPE",
+	4: "ZERO_DETECT_MULTIPLIER",
+	5: "MULTIPOINT_SET",
+	6: "ZERO_MY_DISCRIMINATOR",
+	7: "ZERO_YOUR_DISCRIMINATOR",
+	8: "RESERVED_FIELD_SET",
+}
+
+var Violation_value = map[string]int32{
+	"INVALID_VERSION":               0,
+	"INVALID_LENGTH":                1,
+	"INVALID_AUTHENTICATION_LENGTH": 2,
+	"INVALID_AUTHENTICATION_TYPE":   3,
+	"ZERO_DETECT_MULTIPLIER":        4,
+	"MULTIPOINT_SET":                5,
+	"ZERO_MY_DISCRIMINATOR":         6,
+	"ZERO_YOUR_DISCRIMINATOR":       7,
+	"RESERVED_FIELD_SET":            8,
+}
+
+func (x Violation) String() string {
+	return proto.EnumName(Violation_name, int32(x))
+}
+
+func (Violation) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+}
+
 type StartRequest struct {
 	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
 	Port                 uint32   `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
@@ -411,11 +460,12 @@ func (m *ListPeerRequest) XXX_DiscardUnknown() {
 var xxx_messageInfo_ListPeerRequest proto.InternalMessageInfo
 
 type ListPeerResponse struct {
-	Uuid                 []byte   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	Peer                 *Peer    `protobuf:"bytes,2,opt,name=peer,proto3" json:"peer,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Uuid                 []byte            `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Peer                 *Peer             `protobuf:"bytes,2,opt,name=peer,proto3" json:"peer,omitempty"`
+	Violations           []*ViolationCount `protobuf:"bytes,3,rep,name=violations,proto3" json:"violations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *ListPeerResponse) Reset()         { *m = ListPeerResponse{} }
@@ -457,6 +507,13 @@ func (m *ListPeerResponse) GetPeer() *Peer {
 	return nil
 }
 
+func (m *ListPeerResponse) GetViolations() []*ViolationCount {
+	if m != nil {
+		return m.Violations
+	}
+	return nil
+}
+
 type GetPeerStateRequest struct {
 	Uuid                 []byte   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -660,372 +717,2547 @@ func (m *EnablePeerRequest) GetUuid() []byte {
 	return nil
 }
 
-type Peer struct {
-	Name                  string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Address               string          `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
-	DesiredMinTxInterval  uint32          `protobuf:"varint,3,opt,name=desired_min_tx_interval,json=desiredMinTxInterval,proto3" json:"desired_min_tx_interval,omitempty"`
-	RequiredMinRxInterval uint32          `protobuf:"varint,4,opt,name=required_min_rx_interval,json=requiredMinRxInterval,proto3" json:"required_min_rx_interval,omitempty"`
-	DetectMultiplier      uint32          `protobuf:"varint,5,opt,name=detect_multiplier,json=detectMultiplier,proto3" json:"detect_multiplier,omitempty"`
-	IsMultiHop            bool            `protobuf:"varint,6,opt,name=is_multi_hop,json=isMultiHop,proto3" json:"is_multi_hop,omitempty"`
-	Authentication        *Authentication `protobuf:"bytes,7,opt,name=authentication,proto3" json:"authentication,omitempty"`
-	XXX_NoUnkeyedLiteral  struct{}        `json:"-"`
-	XXX_unrecognized      []byte          `json:"-"`
-	XXX_sizecache         int32           `json:"-"`
+type PollPeerRequest struct {
+	Uuid                 []byte   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Peer) Reset()         { *m = Peer{} }
-func (m *Peer) String() string { return proto.CompactTextString(m) }
-func (*Peer) ProtoMessage()    {}
-func (*Peer) Descriptor() ([]byte, []int) {
+func (m *PollPeerRequest) Reset()         { *m = PollPeerRequest{} }
+func (m *PollPeerRequest) String() string { return proto.CompactTextString(m) }
+func (*PollPeerRequest) ProtoMessage()    {}
+func (*PollPeerRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{13}
 }
 
-func (m *Peer) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Peer.Unmarshal(m, b)
+func (m *PollPeerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PollPeerRequest.Unmarshal(m, b)
 }
-func (m *Peer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Peer.Marshal(b, m, deterministic)
+func (m *PollPeerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PollPeerRequest.Marshal(b, m, deterministic)
 }
-func (m *Peer) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Peer.Merge(m, src)
+func (m *PollPeerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PollPeerRequest.Merge(m, src)
 }
-func (m *Peer) XXX_Size() int {
-	return xxx_messageInfo_Peer.Size(m)
+func (m *PollPeerRequest) XXX_Size() int {
+	return xxx_messageInfo_PollPeerRequest.Size(m)
 }
-func (m *Peer) XXX_DiscardUnknown() {
-	xxx_messageInfo_Peer.DiscardUnknown(m)
+func (m *PollPeerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PollPeerRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Peer proto.InternalMessageInfo
+var xxx_messageInfo_PollPeerRequest proto.InternalMessageInfo
 
-func (m *Peer) GetName() string {
+func (m *PollPeerRequest) GetUuid() []byte {
 	if m != nil {
-		return m.Name
+		return m.Uuid
 	}
-	return ""
+	return nil
 }
 
-func (m *Peer) GetAddress() string {
-	if m != nil {
-		return m.Address
-	}
-	return ""
+type AddKeyChainRequest struct {
+	KeyChain             *KeyChain `protobuf:"bytes,1,opt,name=key_chain,json=keyChain,proto3" json:"key_chain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
-func (m *Peer) GetDesiredMinTxInterval() uint32 {
-	if m != nil {
-		return m.DesiredMinTxInterval
-	}
-	return 0
+func (m *AddKeyChainRequest) Reset()         { *m = AddKeyChainRequest{} }
+func (m *AddKeyChainRequest) String() string { return proto.CompactTextString(m) }
+func (*AddKeyChainRequest) ProtoMessage()    {}
+func (*AddKeyChainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
 }
 
-func (m *Peer) GetRequiredMinRxInterval() uint32 {
-	if m != nil {
-		return m.RequiredMinRxInterval
-	}
-	return 0
+func (m *AddKeyChainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddKeyChainRequest.Unmarshal(m, b)
+}
+func (m *AddKeyChainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddKeyChainRequest.Marshal(b, m, deterministic)
+}
+func (m *AddKeyChainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddKeyChainRequest.Merge(m, src)
+}
+func (m *AddKeyChainRequest) XXX_Size() int {
+	return xxx_messageInfo_AddKeyChainRequest.Size(m)
+}
+func (m *AddKeyChainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddKeyChainRequest.DiscardUnknown(m)
 }
 
-func (m *Peer) GetDetectMultiplier() uint32 {
+var xxx_messageInfo_AddKeyChainRequest proto.InternalMessageInfo
+
+func (m *AddKeyChainRequest) GetKeyChain() *KeyChain {
 	if m != nil {
-		return m.DetectMultiplier
+		return m.KeyChain
 	}
-	return 0
+	return nil
 }
 
-func (m *Peer) GetIsMultiHop() bool {
-	if m != nil {
-		return m.IsMultiHop
-	}
-	return false
+type DeleteKeyChainRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Peer) GetAuthentication() *Authentication {
+func (m *DeleteKeyChainRequest) Reset()         { *m = DeleteKeyChainRequest{} }
+func (m *DeleteKeyChainRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteKeyChainRequest) ProtoMessage()    {}
+func (*DeleteKeyChainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{15}
+}
+
+func (m *DeleteKeyChainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteKeyChainRequest.Unmarshal(m, b)
+}
+func (m *DeleteKeyChainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteKeyChainRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteKeyChainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteKeyChainRequest.Merge(m, src)
+}
+func (m *DeleteKeyChainRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteKeyChainRequest.Size(m)
+}
+func (m *DeleteKeyChainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteKeyChainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteKeyChainRequest proto.InternalMessageInfo
+
+func (m *DeleteKeyChainRequest) GetName() string {
 	if m != nil {
-		return m.Authentication
+		return m.Name
 	}
-	return nil
+	return ""
 }
 
-//
-//Password can either start with
-//0x.... -> then it's hex
-//or be a string
-//
-//SimplePassword = 16 bytes
-//MD5 = 16 bytes
-//SHA1 = 20 bytes
-type Authentication struct {
-	Type                 AuthenticationType `protobuf:"varint,1,opt,name=type,proto3,enum=api.AuthenticationType" json:"type,omitempty"`
-	Password             string             `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+type ListKeyChainRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Authentication) Reset()         { *m = Authentication{} }
-func (m *Authentication) String() string { return proto.CompactTextString(m) }
-func (*Authentication) ProtoMessage()    {}
-func (*Authentication) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
+func (m *ListKeyChainRequest) Reset()         { *m = ListKeyChainRequest{} }
+func (m *ListKeyChainRequest) String() string { return proto.CompactTextString(m) }
+func (*ListKeyChainRequest) ProtoMessage()    {}
+func (*ListKeyChainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{16}
 }
 
-func (m *Authentication) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Authentication.Unmarshal(m, b)
+func (m *ListKeyChainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListKeyChainRequest.Unmarshal(m, b)
 }
-func (m *Authentication) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Authentication.Marshal(b, m, deterministic)
+func (m *ListKeyChainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListKeyChainRequest.Marshal(b, m, deterministic)
 }
-func (m *Authentication) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Authentication.Merge(m, src)
+func (m *ListKeyChainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListKeyChainRequest.Merge(m, src)
 }
-func (m *Authentication) XXX_Size() int {
-	return xxx_messageInfo_Authentication.Size(m)
+func (m *ListKeyChainRequest) XXX_Size() int {
+	return xxx_messageInfo_ListKeyChainRequest.Size(m)
 }
-func (m *Authentication) XXX_DiscardUnknown() {
-	xxx_messageInfo_Authentication.DiscardUnknown(m)
+func (m *ListKeyChainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListKeyChainRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Authentication proto.InternalMessageInfo
+var xxx_messageInfo_ListKeyChainRequest proto.InternalMessageInfo
 
-func (m *Authentication) GetType() AuthenticationType {
-	if m != nil {
-		return m.Type
-	}
-	return AuthenticationType_NONE
+type ListKeyChainResponse struct {
+	KeyChain             *KeyChain `protobuf:"bytes,1,opt,name=key_chain,json=keyChain,proto3" json:"key_chain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
-func (m *Authentication) GetPassword() string {
+func (m *ListKeyChainResponse) Reset()         { *m = ListKeyChainResponse{} }
+func (m *ListKeyChainResponse) String() string { return proto.CompactTextString(m) }
+func (*ListKeyChainResponse) ProtoMessage()    {}
+func (*ListKeyChainResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{17}
+}
+
+func (m *ListKeyChainResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListKeyChainResponse.Unmarshal(m, b)
+}
+func (m *ListKeyChainResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListKeyChainResponse.Marshal(b, m, deterministic)
+}
+func (m *ListKeyChainResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListKeyChainResponse.Merge(m, src)
+}
+func (m *ListKeyChainResponse) XXX_Size() int {
+	return xxx_messageInfo_ListKeyChainResponse.Size(m)
+}
+func (m *ListKeyChainResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListKeyChainResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListKeyChainResponse proto.InternalMessageInfo
+
+func (m *ListKeyChainResponse) GetKeyChain() *KeyChain {
 	if m != nil {
-		return m.Password
+		return m.KeyChain
 	}
-	return ""
+	return nil
 }
 
-type PeerState struct {
-	State                SessionState   `protobuf:"varint,1,opt,name=state,proto3,enum=api.SessionState" json:"state,omitempty"`
-	Diagnostic           DiagnosticCode `protobuf:"varint,2,opt,name=diagnostic,proto3,enum=api.DiagnosticCode" json:"diagnostic,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
-	XXX_unrecognized     []byte         `json:"-"`
-	XXX_sizecache        int32          `json:"-"`
+type AddKeyRequest struct {
+	KeyChain             string   `protobuf:"bytes,1,opt,name=key_chain,json=keyChain,proto3" json:"key_chain,omitempty"`
+	Key                  *Key     `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PeerState) Reset()         { *m = PeerState{} }
-func (m *PeerState) String() string { return proto.CompactTextString(m) }
-func (*PeerState) ProtoMessage()    {}
-func (*PeerState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{15}
+func (m *AddKeyRequest) Reset()         { *m = AddKeyRequest{} }
+func (m *AddKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*AddKeyRequest) ProtoMessage()    {}
+func (*AddKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{18}
 }
 
-func (m *PeerState) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PeerState.Unmarshal(m, b)
+func (m *AddKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddKeyRequest.Unmarshal(m, b)
 }
-func (m *PeerState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PeerState.Marshal(b, m, deterministic)
+func (m *AddKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddKeyRequest.Marshal(b, m, deterministic)
 }
-func (m *PeerState) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PeerState.Merge(m, src)
+func (m *AddKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddKeyRequest.Merge(m, src)
 }
-func (m *PeerState) XXX_Size() int {
-	return xxx_messageInfo_PeerState.Size(m)
+func (m *AddKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_AddKeyRequest.Size(m)
 }
-func (m *PeerState) XXX_DiscardUnknown() {
-	xxx_messageInfo_PeerState.DiscardUnknown(m)
+func (m *AddKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddKeyRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PeerState proto.InternalMessageInfo
+var xxx_messageInfo_AddKeyRequest proto.InternalMessageInfo
 
-func (m *PeerState) GetState() SessionState {
+func (m *AddKeyRequest) GetKeyChain() string {
 	if m != nil {
-		return m.State
+		return m.KeyChain
 	}
-	return SessionState_ADMIN_DOWN
+	return ""
 }
 
-func (m *PeerState) GetDiagnostic() DiagnosticCode {
+func (m *AddKeyRequest) GetKey() *Key {
 	if m != nil {
-		return m.Diagnostic
+		return m.Key
 	}
-	return DiagnosticCode_NO_DIAGNOSTIC
+	return nil
 }
 
-func init() {
-	proto.RegisterEnum("api.SessionState", SessionState_name, SessionState_value)
-	proto.RegisterEnum("api.DiagnosticCode", DiagnosticCode_name, DiagnosticCode_value)
-	proto.RegisterEnum("api.AuthenticationType", AuthenticationType_name, AuthenticationType_value)
-	proto.RegisterType((*StartRequest)(nil), "api.StartRequest")
-	proto.RegisterType((*StopRequest)(nil), "api.StopRequest")
-	proto.RegisterType((*AddPeerRequest)(nil), "api.AddPeerRequest")
-	proto.RegisterType((*AddPeerResponse)(nil), "api.AddPeerResponse")
-	proto.RegisterType((*UpdatePeerRequest)(nil), "api.UpdatePeerRequest")
-	proto.RegisterType((*DeletePeerRequest)(nil), "api.DeletePeerRequest")
-	proto.RegisterType((*ListPeerRequest)(nil), "api.ListPeerRequest")
-	proto.RegisterType((*ListPeerResponse)(nil), "api.ListPeerResponse")
-	proto.RegisterType((*GetPeerStateRequest)(nil), "api.GetPeerStateRequest")
-	proto.RegisterType((*MonitorPeerRequest)(nil), "api.MonitorPeerRequest")
-	proto.RegisterType((*PeerStateResponse)(nil), "api.PeerStateResponse")
-	proto.RegisterType((*DisablePeerRequest)(nil), "api.DisablePeerRequest")
-	proto.RegisterType((*EnablePeerRequest)(nil), "api.EnablePeerRequest")
-	proto.RegisterType((*Peer)(nil), "api.Peer")
-	proto.RegisterType((*Authentication)(nil), "api.Authentication")
-	proto.RegisterType((*PeerState)(nil), "api.PeerState")
+type DeleteKeyRequest struct {
+	KeyChain             string   `protobuf:"bytes,1,opt,name=key_chain,json=keyChain,proto3" json:"key_chain,omitempty"`
+	Id                   uint32   `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
+func (m *DeleteKeyRequest) Reset()         { *m = DeleteKeyRequest{} }
+func (m *DeleteKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteKeyRequest) ProtoMessage()    {}
+func (*DeleteKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{19}
+}
 
-var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 993 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x55, 0xef, 0x4e, 0xe3, 0xc6,
-	0x17, 0x25, 0xc1, 0x09, 0xe4, 0x02, 0xc1, 0x19, 0xfe, 0xac, 0x7f, 0xf9, 0xa9, 0x15, 0xb2, 0xda,
-	0x42, 0x59, 0x09, 0x28, 0xdb, 0x55, 0xd5, 0x16, 0x55, 0xeb, 0x8d, 0x07, 0xb0, 0x9a, 0xd8, 0x91,
-	0x6d, 0x96, 0xf2, 0x69, 0x64, 0xf0, 0x2c, 0x3b, 0x52, 0xb0, 0xbd, 0xf6, 0xa4, 0x5d, 0x9e, 0xa0,
-	0x1f, 0xfa, 0x32, 0x7d, 0xa1, 0xbe, 0x4b, 0xe5, 0xf1, 0x24, 0x71, 0x12, 0xb2, 0xf4, 0xdb, 0xcc,
-	0x3d, 0xe7, 0xdc, 0x39, 0x73, 0x33, 0xf1, 0x81, 0x46, 0x90, 0xb0, 0xa3, 0x24, 0x8d, 0x79, 0x8c,
-	0x96, 0x83, 0x84, 0xb5, 0xff, 0x7f, 0x1f, 0xc7, 0xf7, 0x03, 0x7a, 0x2c, 0x4a, 0xb7, 0xc3, 0xf7,
-	0xc7, 0xf4, 0x21, 0xe1, 0x8f, 0x05, 0x43, 0x3f, 0x83, 0x75, 0x8f, 0x07, 0x29, 0x77, 0xe9, 0xc7,
-	0x21, 0xcd, 0x38, 0xd2, 0x60, 0x25, 0x08, 0xc3, 0x94, 0x66, 0x99, 0x56, 0xd9, 0xab, 0x1c, 0x34,
-	0xdc, 0xd1, 0x16, 0x21, 0x50, 0x92, 0x38, 0xe5, 0x5a, 0x75, 0xaf, 0x72, 0xb0, 0xe1, 0x8a, 0xb5,
-	0xbe, 0x01, 0x6b, 0x1e, 0x8f, 0x13, 0x29, 0xd6, 0x8f, 0xa1, 0x69, 0x84, 0x61, 0x9f, 0xd2, 0x74,
-	0xd4, 0xee, 0x0b, 0x50, 0x12, 0x4a, 0x53, 0xd1, 0x6b, 0xed, 0xb4, 0x71, 0x94, 0x5b, 0x13, 0xb8,
-	0x28, 0xeb, 0x5f, 0xc3, 0xe6, 0x58, 0x90, 0x25, 0x71, 0x94, 0xd1, 0xfc, 0x98, 0xe1, 0x90, 0x85,
-	0x42, 0xb1, 0xee, 0x8a, 0xb5, 0x7e, 0x0e, 0xad, 0xab, 0x24, 0x0c, 0x38, 0x2d, 0xb7, 0x7e, 0x82,
-	0x38, 0x3e, 0xae, 0xfa, 0xf4, 0x71, 0xfb, 0xd0, 0x32, 0xe9, 0x80, 0x3e, 0xdb, 0x47, 0x6f, 0xc1,
-	0x66, 0x97, 0x65, 0xbc, 0x44, 0xd3, 0x31, 0xa8, 0x93, 0xd2, 0x62, 0xaf, 0xcf, 0x59, 0xf8, 0x16,
-	0xb6, 0x2e, 0xa8, 0xe8, 0xe2, 0xf1, 0x80, 0xd3, 0xcf, 0x99, 0x38, 0x00, 0xd4, 0x8b, 0x23, 0xc6,
-	0xe3, 0xf4, 0x39, 0xbb, 0x01, 0xb4, 0x4a, 0x1d, 0xa5, 0xb9, 0xaf, 0xa0, 0x36, 0x88, 0xef, 0x82,
-	0x81, 0x9c, 0x7d, 0x73, 0xec, 0xa4, 0xa0, 0x15, 0x20, 0xfa, 0x06, 0xea, 0x29, 0x7d, 0x88, 0x39,
-	0x95, 0x86, 0x67, 0x69, 0x12, 0xcd, 0xcd, 0x98, 0x2c, 0x0b, 0x6e, 0x07, 0xcf, 0xce, 0x6e, 0x1f,
-	0x5a, 0x38, 0xfa, 0x2f, 0xc4, 0xbf, 0xab, 0xa0, 0xe4, 0x9c, 0x1c, 0x8c, 0x82, 0x07, 0x2a, 0x1f,
-	0x9c, 0x58, 0x97, 0xdf, 0x61, 0x75, 0xfa, 0x1d, 0xbe, 0x86, 0x17, 0x21, 0xcd, 0x58, 0x4a, 0x43,
-	0xf2, 0xc0, 0x22, 0xc2, 0x3f, 0x11, 0x16, 0x71, 0x9a, 0xfe, 0x1e, 0x0c, 0xb4, 0x65, 0xf1, 0x34,
-	0xb7, 0x25, 0xdc, 0x63, 0x91, 0xff, 0xc9, 0x92, 0x18, 0xfa, 0x01, 0xb4, 0x94, 0x7e, 0x1c, 0x8e,
-	0x75, 0x69, 0x49, 0xa7, 0x08, 0xdd, 0xce, 0x08, 0xef, 0xb1, 0xc8, 0x9d, 0x08, 0x5f, 0x42, 0x2b,
-	0xa4, 0x9c, 0xde, 0x71, 0xf2, 0x30, 0x1c, 0x70, 0x96, 0x0c, 0x18, 0x4d, 0xb5, 0x9a, 0x50, 0xa8,
-	0x05, 0xd0, 0x1b, 0xd7, 0xd1, 0x1e, 0xac, 0xb3, 0xac, 0x20, 0x92, 0x0f, 0x71, 0xa2, 0xd5, 0xf7,
-	0x2a, 0x07, 0xab, 0x2e, 0xb0, 0x4c, 0x70, 0x2e, 0xe3, 0x04, 0xfd, 0x0c, 0xcd, 0x60, 0xc8, 0x3f,
-	0xd0, 0x88, 0xb3, 0xbb, 0x80, 0xb3, 0x38, 0xd2, 0x56, 0xc4, 0xe0, 0xb7, 0xc4, 0xe0, 0x8d, 0x29,
-	0xc8, 0x9d, 0xa1, 0xea, 0x37, 0xd0, 0x9c, 0x66, 0xa0, 0x97, 0xa0, 0xf0, 0xc7, 0xa4, 0x98, 0x5d,
-	0xf3, 0xf4, 0xc5, 0x13, 0x4d, 0xfc, 0xc7, 0x84, 0xba, 0x82, 0x84, 0xda, 0xb0, 0x9a, 0x04, 0x59,
-	0xf6, 0x47, 0x9c, 0x86, 0x72, 0xaa, 0xe3, 0xbd, 0xce, 0xa0, 0x31, 0xfe, 0xd5, 0xd1, 0x3e, 0xd4,
-	0xb2, 0x7c, 0x21, 0xdb, 0xb6, 0x44, 0x5b, 0x8f, 0x66, 0x19, 0x8b, 0x23, 0xf9, 0x7c, 0x04, 0x8e,
-	0x5e, 0x01, 0x84, 0x2c, 0xb8, 0x8f, 0xe2, 0x8c, 0xb3, 0x3b, 0xd1, 0xb3, 0x29, 0x6f, 0x62, 0x8e,
-	0xcb, 0x9d, 0x38, 0xa4, 0x6e, 0x89, 0x76, 0xf8, 0x13, 0xac, 0x97, 0x7b, 0xa1, 0x26, 0x80, 0x61,
-	0xf6, 0x2c, 0x9b, 0x98, 0xce, 0xb5, 0xad, 0x2e, 0xa1, 0x55, 0x50, 0xc4, 0xaa, 0x92, 0xaf, 0x2c,
-	0xdb, 0xf2, 0xd5, 0x2a, 0xaa, 0x43, 0xf5, 0xaa, 0xaf, 0x2e, 0x1f, 0xfe, 0x55, 0x85, 0xe6, 0x74,
-	0x6b, 0xd4, 0x82, 0x0d, 0xdb, 0x21, 0xa6, 0x65, 0x5c, 0xd8, 0x8e, 0xe7, 0x5b, 0x1d, 0x75, 0x09,
-	0xe9, 0xf0, 0x65, 0xc7, 0xb1, 0x7d, 0xd7, 0xe9, 0x12, 0x13, 0xfb, 0xb8, 0xe3, 0x5b, 0x8e, 0x4d,
-	0x7c, 0xab, 0x87, 0x09, 0xfe, 0xad, 0x6f, 0xb9, 0xd8, 0x54, 0x2b, 0x48, 0x83, 0x6d, 0xdc, 0xb9,
-	0x74, 0xc8, 0xf9, 0x95, 0x5d, 0xe0, 0xe7, 0x86, 0xd5, 0xc5, 0xa6, 0x5a, 0xcd, 0xd5, 0x36, 0xb6,
-	0x2e, 0x2e, 0xdf, 0x3a, 0x2e, 0xf1, 0xac, 0x0b, 0xdb, 0xe8, 0x62, 0x93, 0x78, 0xd8, 0xf3, 0x72,
-	0x96, 0x70, 0xb6, 0x8c, 0xda, 0xb0, 0x7b, 0xee, 0xb8, 0xd7, 0x86, 0x6b, 0x5a, 0xf6, 0x05, 0xe9,
-	0x77, 0x0d, 0x1b, 0x13, 0x17, 0x7b, 0xd8, 0x57, 0x15, 0xb4, 0x01, 0x8d, 0xbe, 0xe1, 0x5f, 0x16,
-	0xd4, 0x5a, 0x4e, 0xed, 0x38, 0x76, 0xc7, 0xf0, 0xb1, 0x6d, 0xf8, 0xd8, 0x24, 0x13, 0xac, 0x8e,
-	0xfe, 0x07, 0x3b, 0xe2, 0xea, 0x96, 0xe7, 0xbb, 0x86, 0x6f, 0xbd, 0xc3, 0xdd, 0x9b, 0x02, 0x5a,
-	0xc9, 0x5d, 0xb8, 0xf8, 0x1d, 0x76, 0x3d, 0x4c, 0x16, 0xc8, 0x57, 0x0f, 0xff, 0xac, 0x00, 0x9a,
-	0xff, 0xb5, 0xf3, 0xb1, 0xd9, 0x8e, 0x8d, 0xd5, 0x25, 0xb4, 0x05, 0x9b, 0x9e, 0xd5, 0xeb, 0x77,
-	0x31, 0xe9, 0x1b, 0x9e, 0x77, 0xed, 0xb8, 0xf9, 0xcd, 0x37, 0xa0, 0xf1, 0x2b, 0xbe, 0xc1, 0x26,
-	0xe9, 0x99, 0xaf, 0xd5, 0x6a, 0x3e, 0x88, 0x1e, 0xf6, 0xad, 0xce, 0x55, 0xd7, 0xb9, 0xf2, 0xc8,
-	0x04, 0x59, 0xce, 0x7f, 0x98, 0x62, 0xeb, 0x5d, 0x1a, 0xdf, 0xa9, 0x4a, 0xee, 0x76, 0x8e, 0x29,
-	0xa0, 0xda, 0xe9, 0x3f, 0x0a, 0xd4, 0xdf, 0xbe, 0x0f, 0x8d, 0x84, 0xa1, 0x53, 0xa8, 0x89, 0x48,
-	0x41, 0xf2, 0xd9, 0x94, 0xe2, 0xa5, 0xbd, 0x7b, 0x54, 0x84, 0xd1, 0xd1, 0x28, 0x8c, 0x8e, 0x70,
-	0x1e, 0x46, 0xe8, 0x04, 0x94, 0x3c, 0x48, 0x90, 0x2a, 0x25, 0xe3, 0x4c, 0x59, 0xa8, 0xf8, 0x1e,
-	0x56, 0x64, 0x74, 0x20, 0xf9, 0xd7, 0x99, 0x4a, 0x9e, 0xf6, 0xf6, 0x74, 0x51, 0x7e, 0x14, 0xcf,
-	0x00, 0x26, 0x49, 0x82, 0x76, 0x05, 0x67, 0x2e, 0x5a, 0x16, 0x9e, 0x79, 0x06, 0x30, 0xc9, 0x0f,
-	0xa9, 0x9e, 0x0b, 0x94, 0x85, 0xea, 0x1f, 0x61, 0x75, 0x94, 0x20, 0xa8, 0x70, 0x37, 0x93, 0x31,
-	0xed, 0x9d, 0x99, 0x6a, 0x61, 0xfa, 0xa4, 0x82, 0xde, 0xc0, 0x7a, 0x39, 0x35, 0x90, 0x26, 0x88,
-	0x4f, 0x04, 0x49, 0x7b, 0x77, 0xe6, 0xfb, 0x3d, 0xba, 0xf8, 0x1b, 0x58, 0x2b, 0x85, 0x09, 0x2a,
-	0x3e, 0x14, 0xf3, 0xf1, 0xb2, 0x48, 0x7f, 0x52, 0x41, 0xbf, 0xc0, 0x5a, 0x29, 0x01, 0x64, 0x87,
-	0xf9, 0x4c, 0xf8, 0xdc, 0xf0, 0x26, 0xb9, 0x20, 0x87, 0x37, 0x17, 0x14, 0x8b, 0xd4, 0xb7, 0x75,
-	0xb1, 0x7f, 0xf5, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xf0, 0xe7, 0x40, 0x7d, 0xdd, 0x08, 0x00,
-	0x00,
+func (m *DeleteKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteKeyRequest.Unmarshal(m, b)
+}
+func (m *DeleteKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteKeyRequest.Merge(m, src)
+}
+func (m *DeleteKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteKeyRequest.Size(m)
+}
+func (m *DeleteKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteKeyRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_DeleteKeyRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *DeleteKeyRequest) GetKeyChain() string {
+	if m != nil {
+		return m.KeyChain
+	}
+	return ""
+}
 
-// BfdApiClient is the client API for BfdApi service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type BfdApiClient interface {
-	// Manage the overall server state
-	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	// Manage the peers of the bfd server
-	AddPeer(ctx context.Context, in *AddPeerRequest, opts ...grpc.CallOption) (*AddPeerResponse, error)
-	UpdatePeer(ctx context.Context, in *UpdatePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	DeletePeer(ctx context.Context, in *DeletePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	ListPeer(ctx context.Context, in *ListPeerRequest, opts ...grpc.CallOption) (BfdApi_ListPeerClient, error)
-	// Manage the state of the peers of the bfd server
-	GetPeerState(ctx context.Context, in *GetPeerStateRequest, opts ...grpc.CallOption) (*PeerStateResponse, error)
-	MonitorPeer(ctx context.Context, in *MonitorPeerRequest, opts ...grpc.CallOption) (BfdApi_MonitorPeerClient, error)
-	DisablePeer(ctx context.Context, in *DisablePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	EnablePeer(ctx context.Context, in *EnablePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+func (m *DeleteKeyRequest) GetId() uint32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
 }
 
-type bfdApiClient struct {
-	cc *grpc.ClientConn
+type AddSbfdReflectorRequest struct {
+	Discriminator        uint32   `protobuf:"varint,1,opt,name=discriminator,proto3" json:"discriminator,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func NewBfdApiClient(cc *grpc.ClientConn) BfdApiClient {
-	return &bfdApiClient{cc}
+func (m *AddSbfdReflectorRequest) Reset()         { *m = AddSbfdReflectorRequest{} }
+func (m *AddSbfdReflectorRequest) String() string { return proto.CompactTextString(m) }
+func (*AddSbfdReflectorRequest) ProtoMessage()    {}
+func (*AddSbfdReflectorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{20}
 }
 
-func (c *bfdApiClient) Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/api.BfdApi/Start", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *AddSbfdReflectorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddSbfdReflectorRequest.Unmarshal(m, b)
+}
+func (m *AddSbfdReflectorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddSbfdReflectorRequest.Marshal(b, m, deterministic)
+}
+func (m *AddSbfdReflectorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddSbfdReflectorRequest.Merge(m, src)
+}
+func (m *AddSbfdReflectorRequest) XXX_Size() int {
+	return xxx_messageInfo_AddSbfdReflectorRequest.Size(m)
+}
+func (m *AddSbfdReflectorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddSbfdReflectorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddSbfdReflectorRequest proto.InternalMessageInfo
+
+func (m *AddSbfdReflectorRequest) GetDiscriminator() uint32 {
+	if m != nil {
+		return m.Discriminator
+	}
+	return 0
+}
+
+type DeleteSbfdReflectorRequest struct {
+	Discriminator        uint32   `protobuf:"varint,1,opt,name=discriminator,proto3" json:"discriminator,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSbfdReflectorRequest) Reset()         { *m = DeleteSbfdReflectorRequest{} }
+func (m *DeleteSbfdReflectorRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteSbfdReflectorRequest) ProtoMessage()    {}
+func (*DeleteSbfdReflectorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{21}
+}
+
+func (m *DeleteSbfdReflectorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSbfdReflectorRequest.Unmarshal(m, b)
+}
+func (m *DeleteSbfdReflectorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSbfdReflectorRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteSbfdReflectorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSbfdReflectorRequest.Merge(m, src)
+}
+func (m *DeleteSbfdReflectorRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteSbfdReflectorRequest.Size(m)
+}
+func (m *DeleteSbfdReflectorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSbfdReflectorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSbfdReflectorRequest proto.InternalMessageInfo
+
+func (m *DeleteSbfdReflectorRequest) GetDiscriminator() uint32 {
+	if m != nil {
+		return m.Discriminator
+	}
+	return 0
+}
+
+type ListSbfdReflectorRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSbfdReflectorRequest) Reset()         { *m = ListSbfdReflectorRequest{} }
+func (m *ListSbfdReflectorRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSbfdReflectorRequest) ProtoMessage()    {}
+func (*ListSbfdReflectorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{22}
+}
+
+func (m *ListSbfdReflectorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSbfdReflectorRequest.Unmarshal(m, b)
+}
+func (m *ListSbfdReflectorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSbfdReflectorRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSbfdReflectorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSbfdReflectorRequest.Merge(m, src)
+}
+func (m *ListSbfdReflectorRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSbfdReflectorRequest.Size(m)
+}
+func (m *ListSbfdReflectorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSbfdReflectorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSbfdReflectorRequest proto.InternalMessageInfo
+
+type ListSbfdReflectorResponse struct {
+	Discriminator        uint32   `protobuf:"varint,1,opt,name=discriminator,proto3" json:"discriminator,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSbfdReflectorResponse) Reset()         { *m = ListSbfdReflectorResponse{} }
+func (m *ListSbfdReflectorResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSbfdReflectorResponse) ProtoMessage()    {}
+func (*ListSbfdReflectorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{23}
+}
+
+func (m *ListSbfdReflectorResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSbfdReflectorResponse.Unmarshal(m, b)
+}
+func (m *ListSbfdReflectorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSbfdReflectorResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSbfdReflectorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSbfdReflectorResponse.Merge(m, src)
+}
+func (m *ListSbfdReflectorResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSbfdReflectorResponse.Size(m)
+}
+func (m *ListSbfdReflectorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSbfdReflectorResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSbfdReflectorResponse proto.InternalMessageInfo
+
+func (m *ListSbfdReflectorResponse) GetDiscriminator() uint32 {
+	if m != nil {
+		return m.Discriminator
+	}
+	return 0
+}
+
+type AddSbfdInitiatorRequest struct {
+	Initiator            *SbfdInitiator `protobuf:"bytes,1,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AddSbfdInitiatorRequest) Reset()         { *m = AddSbfdInitiatorRequest{} }
+func (m *AddSbfdInitiatorRequest) String() string { return proto.CompactTextString(m) }
+func (*AddSbfdInitiatorRequest) ProtoMessage()    {}
+func (*AddSbfdInitiatorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{24}
+}
+
+func (m *AddSbfdInitiatorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddSbfdInitiatorRequest.Unmarshal(m, b)
+}
+func (m *AddSbfdInitiatorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddSbfdInitiatorRequest.Marshal(b, m, deterministic)
+}
+func (m *AddSbfdInitiatorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddSbfdInitiatorRequest.Merge(m, src)
+}
+func (m *AddSbfdInitiatorRequest) XXX_Size() int {
+	return xxx_messageInfo_AddSbfdInitiatorRequest.Size(m)
+}
+func (m *AddSbfdInitiatorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddSbfdInitiatorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddSbfdInitiatorRequest proto.InternalMessageInfo
+
+func (m *AddSbfdInitiatorRequest) GetInitiator() *SbfdInitiator {
+	if m != nil {
+		return m.Initiator
+	}
+	return nil
+}
+
+type AddSbfdInitiatorResponse struct {
+	Uuid                 []byte   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddSbfdInitiatorResponse) Reset()         { *m = AddSbfdInitiatorResponse{} }
+func (m *AddSbfdInitiatorResponse) String() string { return proto.CompactTextString(m) }
+func (*AddSbfdInitiatorResponse) ProtoMessage()    {}
+func (*AddSbfdInitiatorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{25}
+}
+
+func (m *AddSbfdInitiatorResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddSbfdInitiatorResponse.Unmarshal(m, b)
+}
+func (m *AddSbfdInitiatorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddSbfdInitiatorResponse.Marshal(b, m, deterministic)
+}
+func (m *AddSbfdInitiatorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddSbfdInitiatorResponse.Merge(m, src)
+}
+func (m *AddSbfdInitiatorResponse) XXX_Size() int {
+	return xxx_messageInfo_AddSbfdInitiatorResponse.Size(m)
+}
+func (m *AddSbfdInitiatorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddSbfdInitiatorResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddSbfdInitiatorResponse proto.InternalMessageInfo
+
+func (m *AddSbfdInitiatorResponse) GetUuid() []byte {
+	if m != nil {
+		return m.Uuid
+	}
+	return nil
+}
+
+type DeleteSbfdInitiatorRequest struct {
+	Uuid                 []byte   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSbfdInitiatorRequest) Reset()         { *m = DeleteSbfdInitiatorRequest{} }
+func (m *DeleteSbfdInitiatorRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteSbfdInitiatorRequest) ProtoMessage()    {}
+func (*DeleteSbfdInitiatorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{26}
+}
+
+func (m *DeleteSbfdInitiatorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSbfdInitiatorRequest.Unmarshal(m, b)
+}
+func (m *DeleteSbfdInitiatorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSbfdInitiatorRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteSbfdInitiatorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSbfdInitiatorRequest.Merge(m, src)
+}
+func (m *DeleteSbfdInitiatorRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteSbfdInitiatorRequest.Size(m)
+}
+func (m *DeleteSbfdInitiatorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSbfdInitiatorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSbfdInitiatorRequest proto.InternalMessageInfo
+
+func (m *DeleteSbfdInitiatorRequest) GetUuid() []byte {
+	if m != nil {
+		return m.Uuid
+	}
+	return nil
+}
+
+type ListSbfdInitiatorRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSbfdInitiatorRequest) Reset()         { *m = ListSbfdInitiatorRequest{} }
+func (m *ListSbfdInitiatorRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSbfdInitiatorRequest) ProtoMessage()    {}
+func (*ListSbfdInitiatorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{27}
+}
+
+func (m *ListSbfdInitiatorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSbfdInitiatorRequest.Unmarshal(m, b)
+}
+func (m *ListSbfdInitiatorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSbfdInitiatorRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSbfdInitiatorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSbfdInitiatorRequest.Merge(m, src)
+}
+func (m *ListSbfdInitiatorRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSbfdInitiatorRequest.Size(m)
+}
+func (m *ListSbfdInitiatorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSbfdInitiatorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSbfdInitiatorRequest proto.InternalMessageInfo
+
+type ListSbfdInitiatorResponse struct {
+	Uuid                 []byte         `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Initiator            *SbfdInitiator `protobuf:"bytes,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	State                SessionState   `protobuf:"varint,3,opt,name=state,proto3,enum=api.SessionState" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListSbfdInitiatorResponse) Reset()         { *m = ListSbfdInitiatorResponse{} }
+func (m *ListSbfdInitiatorResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSbfdInitiatorResponse) ProtoMessage()    {}
+func (*ListSbfdInitiatorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{28}
+}
+
+func (m *ListSbfdInitiatorResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSbfdInitiatorResponse.Unmarshal(m, b)
+}
+func (m *ListSbfdInitiatorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSbfdInitiatorResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSbfdInitiatorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSbfdInitiatorResponse.Merge(m, src)
+}
+func (m *ListSbfdInitiatorResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSbfdInitiatorResponse.Size(m)
+}
+func (m *ListSbfdInitiatorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSbfdInitiatorResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSbfdInitiatorResponse proto.InternalMessageInfo
+
+func (m *ListSbfdInitiatorResponse) GetUuid() []byte {
+	if m != nil {
+		return m.Uuid
+	}
+	return nil
+}
+
+func (m *ListSbfdInitiatorResponse) GetInitiator() *SbfdInitiator {
+	if m != nil {
+		return m.Initiator
+	}
+	return nil
+}
+
+func (m *ListSbfdInitiatorResponse) GetState() SessionState {
+	if m != nil {
+		return m.State
+	}
+	return SessionState_ADMIN_DOWN
+}
+
+type AddLagRequest struct {
+	Lag                  *Lag     `protobuf:"bytes,1,opt,name=lag,proto3" json:"lag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddLagRequest) Reset()         { *m = AddLagRequest{} }
+func (m *AddLagRequest) String() string { return proto.CompactTextString(m) }
+func (*AddLagRequest) ProtoMessage()    {}
+func (*AddLagRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{29}
+}
+
+func (m *AddLagRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddLagRequest.Unmarshal(m, b)
+}
+func (m *AddLagRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddLagRequest.Marshal(b, m, deterministic)
+}
+func (m *AddLagRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddLagRequest.Merge(m, src)
+}
+func (m *AddLagRequest) XXX_Size() int {
+	return xxx_messageInfo_AddLagRequest.Size(m)
+}
+func (m *AddLagRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddLagRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddLagRequest proto.InternalMessageInfo
+
+func (m *AddLagRequest) GetLag() *Lag {
+	if m != nil {
+		return m.Lag
+	}
+	return nil
+}
+
+type DeleteLagRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteLagRequest) Reset()         { *m = DeleteLagRequest{} }
+func (m *DeleteLagRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteLagRequest) ProtoMessage()    {}
+func (*DeleteLagRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{30}
+}
+
+func (m *DeleteLagRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteLagRequest.Unmarshal(m, b)
+}
+func (m *DeleteLagRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteLagRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteLagRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteLagRequest.Merge(m, src)
+}
+func (m *DeleteLagRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteLagRequest.Size(m)
+}
+func (m *DeleteLagRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteLagRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteLagRequest proto.InternalMessageInfo
+
+func (m *DeleteLagRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type ListLagRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListLagRequest) Reset()         { *m = ListLagRequest{} }
+func (m *ListLagRequest) String() string { return proto.CompactTextString(m) }
+func (*ListLagRequest) ProtoMessage()    {}
+func (*ListLagRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{31}
+}
+
+func (m *ListLagRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListLagRequest.Unmarshal(m, b)
+}
+func (m *ListLagRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListLagRequest.Marshal(b, m, deterministic)
+}
+func (m *ListLagRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListLagRequest.Merge(m, src)
+}
+func (m *ListLagRequest) XXX_Size() int {
+	return xxx_messageInfo_ListLagRequest.Size(m)
+}
+func (m *ListLagRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListLagRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListLagRequest proto.InternalMessageInfo
+
+type ListLagResponse struct {
+	Lag                  *Lag         `protobuf:"bytes,1,opt,name=lag,proto3" json:"lag,omitempty"`
+	State                SessionState `protobuf:"varint,2,opt,name=state,proto3,enum=api.SessionState" json:"state,omitempty"`
+	Members              []*LagMember `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListLagResponse) Reset()         { *m = ListLagResponse{} }
+func (m *ListLagResponse) String() string { return proto.CompactTextString(m) }
+func (*ListLagResponse) ProtoMessage()    {}
+func (*ListLagResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{32}
+}
+
+func (m *ListLagResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListLagResponse.Unmarshal(m, b)
+}
+func (m *ListLagResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListLagResponse.Marshal(b, m, deterministic)
+}
+func (m *ListLagResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListLagResponse.Merge(m, src)
+}
+func (m *ListLagResponse) XXX_Size() int {
+	return xxx_messageInfo_ListLagResponse.Size(m)
+}
+func (m *ListLagResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListLagResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListLagResponse proto.InternalMessageInfo
+
+func (m *ListLagResponse) GetLag() *Lag {
+	if m != nil {
+		return m.Lag
+	}
+	return nil
+}
+
+func (m *ListLagResponse) GetState() SessionState {
+	if m != nil {
+		return m.State
+	}
+	return SessionState_ADMIN_DOWN
+}
+
+func (m *ListLagResponse) GetMembers() []*LagMember {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+type Peer struct {
+	Name                  string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Address               string          `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	DesiredMinTxInterval  uint32          `protobuf:"varint,3,opt,name=desired_min_tx_interval,json=desiredMinTxInterval,proto3" json:"desired_min_tx_interval,omitempty"`
+	RequiredMinRxInterval uint32          `protobuf:"varint,4,opt,name=required_min_rx_interval,json=requiredMinRxInterval,proto3" json:"required_min_rx_interval,omitempty"`
+	DetectMultiplier      uint32          `protobuf:"varint,5,opt,name=detect_multiplier,json=detectMultiplier,proto3" json:"detect_multiplier,omitempty"`
+	IsMultiHop            bool            `protobuf:"varint,6,opt,name=is_multi_hop,json=isMultiHop,proto3" json:"is_multi_hop,omitempty"`
+	Authentication        *Authentication `protobuf:"bytes,7,opt,name=authentication,proto3" json:"authentication,omitempty"`
+	DemandMode            bool            `protobuf:"varint,8,opt,name=demand_mode,json=demandMode,proto3" json:"demand_mode,omitempty"`
+	DemandPollInterval    uint32          `protobuf:"varint,9,opt,name=demand_poll_interval,json=demandPollInterval,proto3" json:"demand_poll_interval,omitempty"`
+	EchoInterval          uint32          `protobuf:"varint,10,opt,name=echo_interval,json=echoInterval,proto3" json:"echo_interval,omitempty"`
+	LocalAddress          string          `protobuf:"bytes,11,opt,name=local_address,json=localAddress,proto3" json:"local_address,omitempty"`
+	MinTtl                uint32          `protobuf:"varint,12,opt,name=min_ttl,json=minTtl,proto3" json:"min_ttl,omitempty"`
+	Dynamic               bool            `protobuf:"varint,13,opt,name=dynamic,proto3" json:"dynamic,omitempty"`
+	Passive               bool            `protobuf:"varint,14,opt,name=passive,proto3" json:"passive,omitempty"`
+	Interface             string          `protobuf:"bytes,15,opt,name=interface,proto3" json:"interface,omitempty"`
+	IsVxlan               bool            `protobuf:"varint,16,opt,name=is_vxlan,json=isVxlan,proto3" json:"is_vxlan,omitempty"`
+	Vni                   uint32          `protobuf:"varint,17,opt,name=vni,proto3" json:"vni,omitempty"`
+	PadToSize             uint32          `protobuf:"varint,18,opt,name=pad_to_size,json=padToSize,proto3" json:"pad_to_size,omitempty"`
+	Unsolicited           bool            `protobuf:"varint,19,opt,name=unsolicited,proto3" json:"unsolicited,omitempty"`
+	EchoReflector         bool            `protobuf:"varint,20,opt,name=echo_reflector,json=echoReflector,proto3" json:"echo_reflector,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{}        `json:"-"`
+	XXX_unrecognized      []byte          `json:"-"`
+	XXX_sizecache         int32           `json:"-"`
+}
+
+func (m *Peer) Reset()         { *m = Peer{} }
+func (m *Peer) String() string { return proto.CompactTextString(m) }
+func (*Peer) ProtoMessage()    {}
+func (*Peer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{33}
+}
+
+func (m *Peer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Peer.Unmarshal(m, b)
+}
+func (m *Peer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Peer.Marshal(b, m, deterministic)
+}
+func (m *Peer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Peer.Merge(m, src)
+}
+func (m *Peer) XXX_Size() int {
+	return xxx_messageInfo_Peer.Size(m)
+}
+func (m *Peer) XXX_DiscardUnknown() {
+	xxx_messageInfo_Peer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Peer proto.InternalMessageInfo
+
+func (m *Peer) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Peer) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Peer) GetDesiredMinTxInterval() uint32 {
+	if m != nil {
+		return m.DesiredMinTxInterval
+	}
+	return 0
+}
+
+func (m *Peer) GetRequiredMinRxInterval() uint32 {
+	if m != nil {
+		return m.RequiredMinRxInterval
+	}
+	return 0
+}
+
+func (m *Peer) GetDetectMultiplier() uint32 {
+	if m != nil {
+		return m.DetectMultiplier
+	}
+	return 0
+}
+
+func (m *Peer) GetIsMultiHop() bool {
+	if m != nil {
+		return m.IsMultiHop
+	}
+	return false
+}
+
+func (m *Peer) GetAuthentication() *Authentication {
+	if m != nil {
+		return m.Authentication
+	}
+	return nil
+}
+
+func (m *Peer) GetDemandMode() bool {
+	if m != nil {
+		return m.DemandMode
+	}
+	return false
+}
+
+func (m *Peer) GetDemandPollInterval() uint32 {
+	if m != nil {
+		return m.DemandPollInterval
+	}
+	return 0
+}
+
+func (m *Peer) GetEchoInterval() uint32 {
+	if m != nil {
+		return m.EchoInterval
+	}
+	return 0
+}
+
+func (m *Peer) GetLocalAddress() string {
+	if m != nil {
+		return m.LocalAddress
+	}
+	return ""
+}
+
+func (m *Peer) GetMinTtl() uint32 {
+	if m != nil {
+		return m.MinTtl
+	}
+	return 0
+}
+
+func (m *Peer) GetDynamic() bool {
+	if m != nil {
+		return m.Dynamic
+	}
+	return false
+}
+
+func (m *Peer) GetPassive() bool {
+	if m != nil {
+		return m.Passive
+	}
+	return false
+}
+
+func (m *Peer) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+func (m *Peer) GetIsVxlan() bool {
+	if m != nil {
+		return m.IsVxlan
+	}
+	return false
+}
+
+func (m *Peer) GetVni() uint32 {
+	if m != nil {
+		return m.Vni
+	}
+	return 0
+}
+
+func (m *Peer) GetPadToSize() uint32 {
+	if m != nil {
+		return m.PadToSize
+	}
+	return 0
+}
+
+func (m *Peer) GetUnsolicited() bool {
+	if m != nil {
+		return m.Unsolicited
+	}
+	return false
+}
+
+func (m *Peer) GetEchoReflector() bool {
+	if m != nil {
+		return m.EchoReflector
+	}
+	return false
+}
+
+// Password can either start with
+// 0x.... -> then it's hex
+// or be a string
+//
+// SimplePassword = 16 bytes
+// MD5 = 16 bytes
+// SHA1 = 20 bytes
+//
+// key_id is sent along with every packet and needs to match on both sides
+//
+// If key_chain is set, password and key_id are ignored and the
+// keys of the named chain are used instead
+type Authentication struct {
+	Type                 AuthenticationType `protobuf:"varint,1,opt,name=type,proto3,enum=api.AuthenticationType" json:"type,omitempty"`
+	Password             string             `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	KeyId                uint32             `protobuf:"varint,3,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	KeyChain             string             `protobuf:"bytes,4,opt,name=key_chain,json=keyChain,proto3" json:"key_chain,omitempty"`
+	Optimized            bool               `protobuf:"varint,5,opt,name=optimized,proto3" json:"optimized,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *Authentication) Reset()         { *m = Authentication{} }
+func (m *Authentication) String() string { return proto.CompactTextString(m) }
+func (*Authentication) ProtoMessage()    {}
+func (*Authentication) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{34}
+}
+
+func (m *Authentication) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Authentication.Unmarshal(m, b)
+}
+func (m *Authentication) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Authentication.Marshal(b, m, deterministic)
+}
+func (m *Authentication) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Authentication.Merge(m, src)
+}
+func (m *Authentication) XXX_Size() int {
+	return xxx_messageInfo_Authentication.Size(m)
+}
+func (m *Authentication) XXX_DiscardUnknown() {
+	xxx_messageInfo_Authentication.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Authentication proto.InternalMessageInfo
+
+func (m *Authentication) GetType() AuthenticationType {
+	if m != nil {
+		return m.Type
+	}
+	return AuthenticationType_NONE
+}
+
+func (m *Authentication) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *Authentication) GetKeyId() uint32 {
+	if m != nil {
+		return m.KeyId
+	}
+	return 0
+}
+
+func (m *Authentication) GetKeyChain() string {
+	if m != nil {
+		return m.KeyChain
+	}
+	return ""
+}
+
+func (m *Authentication) GetOptimized() bool {
+	if m != nil {
+		return m.Optimized
+	}
+	return false
+}
+
+type KeyChain struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Keys                 []*Key   `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeyChain) Reset()         { *m = KeyChain{} }
+func (m *KeyChain) String() string { return proto.CompactTextString(m) }
+func (*KeyChain) ProtoMessage()    {}
+func (*KeyChain) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{35}
+}
+
+func (m *KeyChain) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyChain.Unmarshal(m, b)
+}
+func (m *KeyChain) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyChain.Marshal(b, m, deterministic)
+}
+func (m *KeyChain) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyChain.Merge(m, src)
+}
+func (m *KeyChain) XXX_Size() int {
+	return xxx_messageInfo_KeyChain.Size(m)
+}
+func (m *KeyChain) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyChain.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyChain proto.InternalMessageInfo
+
+func (m *KeyChain) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *KeyChain) GetKeys() []*Key {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+// Password follows the same rules as for Authentication, it's
+// never returned by ListKeyChain
+//
+// Lifetimes are unix timestamps in seconds, 0 means unbounded.
+// The key with a valid send lifetime and the latest send_start is
+// used to send, every key within its accept lifetime is accepted
+type Key struct {
+	Id                   uint32   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Password             string   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	SendStart            int64    `protobuf:"varint,3,opt,name=send_start,json=sendStart,proto3" json:"send_start,omitempty"`
+	SendEnd              int64    `protobuf:"varint,4,opt,name=send_end,json=sendEnd,proto3" json:"send_end,omitempty"`
+	AcceptStart          int64    `protobuf:"varint,5,opt,name=accept_start,json=acceptStart,proto3" json:"accept_start,omitempty"`
+	AcceptEnd            int64    `protobuf:"varint,6,opt,name=accept_end,json=acceptEnd,proto3" json:"accept_end,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Key) Reset()         { *m = Key{} }
+func (m *Key) String() string { return proto.CompactTextString(m) }
+func (*Key) ProtoMessage()    {}
+func (*Key) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{36}
+}
+
+func (m *Key) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Key.Unmarshal(m, b)
+}
+func (m *Key) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Key.Marshal(b, m, deterministic)
+}
+func (m *Key) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Key.Merge(m, src)
+}
+func (m *Key) XXX_Size() int {
+	return xxx_messageInfo_Key.Size(m)
+}
+func (m *Key) XXX_DiscardUnknown() {
+	xxx_messageInfo_Key.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Key proto.InternalMessageInfo
+
+func (m *Key) GetId() uint32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Key) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *Key) GetSendStart() int64 {
+	if m != nil {
+		return m.SendStart
+	}
+	return 0
+}
+
+func (m *Key) GetSendEnd() int64 {
+	if m != nil {
+		return m.SendEnd
+	}
+	return 0
+}
+
+func (m *Key) GetAcceptStart() int64 {
+	if m != nil {
+		return m.AcceptStart
+	}
+	return 0
+}
+
+func (m *Key) GetAcceptEnd() int64 {
+	if m != nil {
+		return m.AcceptEnd
+	}
+	return 0
+}
+
+// An initiator sends packets to the reflector discriminator of a target,
+// the session is up as long as the reflector answers them.
+// The port of the address defaults to 7784
+type SbfdInitiator struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	RemoteDiscriminator  uint32   `protobuf:"varint,3,opt,name=remote_discriminator,json=remoteDiscriminator,proto3" json:"remote_discriminator,omitempty"`
+	DesiredMinTxInterval uint32   `protobuf:"varint,4,opt,name=desired_min_tx_interval,json=desiredMinTxInterval,proto3" json:"desired_min_tx_interval,omitempty"`
+	DetectMultiplier     uint32   `protobuf:"varint,5,opt,name=detect_multiplier,json=detectMultiplier,proto3" json:"detect_multiplier,omitempty"`
+	LocalAddress         string   `protobuf:"bytes,6,opt,name=local_address,json=localAddress,proto3" json:"local_address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SbfdInitiator) Reset()         { *m = SbfdInitiator{} }
+func (m *SbfdInitiator) String() string { return proto.CompactTextString(m) }
+func (*SbfdInitiator) ProtoMessage()    {}
+func (*SbfdInitiator) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{37}
+}
+
+func (m *SbfdInitiator) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SbfdInitiator.Unmarshal(m, b)
+}
+func (m *SbfdInitiator) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SbfdInitiator.Marshal(b, m, deterministic)
+}
+func (m *SbfdInitiator) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SbfdInitiator.Merge(m, src)
+}
+func (m *SbfdInitiator) XXX_Size() int {
+	return xxx_messageInfo_SbfdInitiator.Size(m)
+}
+func (m *SbfdInitiator) XXX_DiscardUnknown() {
+	xxx_messageInfo_SbfdInitiator.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SbfdInitiator proto.InternalMessageInfo
+
+func (m *SbfdInitiator) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SbfdInitiator) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *SbfdInitiator) GetRemoteDiscriminator() uint32 {
+	if m != nil {
+		return m.RemoteDiscriminator
+	}
+	return 0
+}
+
+func (m *SbfdInitiator) GetDesiredMinTxInterval() uint32 {
+	if m != nil {
+		return m.DesiredMinTxInterval
+	}
+	return 0
+}
+
+func (m *SbfdInitiator) GetDetectMultiplier() uint32 {
+	if m != nil {
+		return m.DetectMultiplier
+	}
+	return 0
+}
+
+func (m *SbfdInitiator) GetLocalAddress() string {
+	if m != nil {
+		return m.LocalAddress
+	}
+	return ""
+}
+
+// A LAG runs a micro BFD session to address on each of its member
+// interfaces. The sessions share the timers and the authentication
+type Lag struct {
+	Name                  string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Address               string          `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Members               []string        `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`
+	MinLinks              uint32          `protobuf:"varint,4,opt,name=min_links,json=minLinks,proto3" json:"min_links,omitempty"`
+	DesiredMinTxInterval  uint32          `protobuf:"varint,5,opt,name=desired_min_tx_interval,json=desiredMinTxInterval,proto3" json:"desired_min_tx_interval,omitempty"`
+	RequiredMinRxInterval uint32          `protobuf:"varint,6,opt,name=required_min_rx_interval,json=requiredMinRxInterval,proto3" json:"required_min_rx_interval,omitempty"`
+	DetectMultiplier      uint32          `protobuf:"varint,7,opt,name=detect_multiplier,json=detectMultiplier,proto3" json:"detect_multiplier,omitempty"`
+	Authentication        *Authentication `protobuf:"bytes,8,opt,name=authentication,proto3" json:"authentication,omitempty"`
+	LocalAddress          string          `protobuf:"bytes,9,opt,name=local_address,json=localAddress,proto3" json:"local_address,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{}        `json:"-"`
+	XXX_unrecognized      []byte          `json:"-"`
+	XXX_sizecache         int32           `json:"-"`
+}
+
+func (m *Lag) Reset()         { *m = Lag{} }
+func (m *Lag) String() string { return proto.CompactTextString(m) }
+func (*Lag) ProtoMessage()    {}
+func (*Lag) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{38}
+}
+
+func (m *Lag) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Lag.Unmarshal(m, b)
+}
+func (m *Lag) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Lag.Marshal(b, m, deterministic)
+}
+func (m *Lag) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Lag.Merge(m, src)
+}
+func (m *Lag) XXX_Size() int {
+	return xxx_messageInfo_Lag.Size(m)
+}
+func (m *Lag) XXX_DiscardUnknown() {
+	xxx_messageInfo_Lag.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Lag proto.InternalMessageInfo
+
+func (m *Lag) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Lag) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Lag) GetMembers() []string {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *Lag) GetMinLinks() uint32 {
+	if m != nil {
+		return m.MinLinks
+	}
+	return 0
+}
+
+func (m *Lag) GetDesiredMinTxInterval() uint32 {
+	if m != nil {
+		return m.DesiredMinTxInterval
+	}
+	return 0
+}
+
+func (m *Lag) GetRequiredMinRxInterval() uint32 {
+	if m != nil {
+		return m.RequiredMinRxInterval
+	}
+	return 0
+}
+
+func (m *Lag) GetDetectMultiplier() uint32 {
+	if m != nil {
+		return m.DetectMultiplier
+	}
+	return 0
+}
+
+func (m *Lag) GetAuthentication() *Authentication {
+	if m != nil {
+		return m.Authentication
+	}
+	return nil
+}
+
+func (m *Lag) GetLocalAddress() string {
+	if m != nil {
+		return m.LocalAddress
+	}
+	return ""
+}
+
+type ListListenerRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListListenerRequest) Reset()         { *m = ListListenerRequest{} }
+func (m *ListListenerRequest) String() string { return proto.CompactTextString(m) }
+func (*ListListenerRequest) ProtoMessage()    {}
+func (*ListListenerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{39}
+}
+
+func (m *ListListenerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListListenerRequest.Unmarshal(m, b)
+}
+func (m *ListListenerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListListenerRequest.Marshal(b, m, deterministic)
+}
+func (m *ListListenerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListListenerRequest.Merge(m, src)
+}
+func (m *ListListenerRequest) XXX_Size() int {
+	return xxx_messageInfo_ListListenerRequest.Size(m)
+}
+func (m *ListListenerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListListenerRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListListenerRequest proto.InternalMessageInfo
+
+type ListListenerResponse struct {
+	Address              string            `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	IsMultiHop           bool              `protobuf:"varint,2,opt,name=is_multi_hop,json=isMultiHop,proto3" json:"is_multi_hop,omitempty"`
+	IsMicro              bool              `protobuf:"varint,3,opt,name=is_micro,json=isMicro,proto3" json:"is_micro,omitempty"`
+	IsVxlan              bool              `protobuf:"varint,4,opt,name=is_vxlan,json=isVxlan,proto3" json:"is_vxlan,omitempty"`
+	Interface            string            `protobuf:"bytes,5,opt,name=interface,proto3" json:"interface,omitempty"`
+	Violations           []*ViolationCount `protobuf:"bytes,6,rep,name=violations,proto3" json:"violations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ListListenerResponse) Reset()         { *m = ListListenerResponse{} }
+func (m *ListListenerResponse) String() string { return proto.CompactTextString(m) }
+func (*ListListenerResponse) ProtoMessage()    {}
+func (*ListListenerResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{40}
+}
+
+func (m *ListListenerResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListListenerResponse.Unmarshal(m, b)
+}
+func (m *ListListenerResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListListenerResponse.Marshal(b, m, deterministic)
+}
+func (m *ListListenerResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListListenerResponse.Merge(m, src)
+}
+func (m *ListListenerResponse) XXX_Size() int {
+	return xxx_messageInfo_ListListenerResponse.Size(m)
+}
+func (m *ListListenerResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListListenerResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListListenerResponse proto.InternalMessageInfo
+
+func (m *ListListenerResponse) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *ListListenerResponse) GetIsMultiHop() bool {
+	if m != nil {
+		return m.IsMultiHop
+	}
+	return false
+}
+
+func (m *ListListenerResponse) GetIsMicro() bool {
+	if m != nil {
+		return m.IsMicro
+	}
+	return false
+}
+
+func (m *ListListenerResponse) GetIsVxlan() bool {
+	if m != nil {
+		return m.IsVxlan
+	}
+	return false
+}
+
+func (m *ListListenerResponse) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+func (m *ListListenerResponse) GetViolations() []*ViolationCount {
+	if m != nil {
+		return m.Violations
+	}
+	return nil
+}
+
+type ListInboundQueueRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListInboundQueueRequest) Reset()         { *m = ListInboundQueueRequest{} }
+func (m *ListInboundQueueRequest) String() string { return proto.CompactTextString(m) }
+func (*ListInboundQueueRequest) ProtoMessage()    {}
+func (*ListInboundQueueRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{41}
+}
+
+func (m *ListInboundQueueRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListInboundQueueRequest.Unmarshal(m, b)
+}
+func (m *ListInboundQueueRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListInboundQueueRequest.Marshal(b, m, deterministic)
+}
+func (m *ListInboundQueueRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListInboundQueueRequest.Merge(m, src)
+}
+func (m *ListInboundQueueRequest) XXX_Size() int {
+	return xxx_messageInfo_ListInboundQueueRequest.Size(m)
+}
+func (m *ListInboundQueueRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListInboundQueueRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListInboundQueueRequest proto.InternalMessageInfo
+
+type ListInboundQueueResponse struct {
+	Worker               uint32   `protobuf:"varint,1,opt,name=worker,proto3" json:"worker,omitempty"`
+	Depth                uint32   `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	Capacity             uint32   `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Drops                uint64   `protobuf:"varint,4,opt,name=drops,proto3" json:"drops,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListInboundQueueResponse) Reset()         { *m = ListInboundQueueResponse{} }
+func (m *ListInboundQueueResponse) String() string { return proto.CompactTextString(m) }
+func (*ListInboundQueueResponse) ProtoMessage()    {}
+func (*ListInboundQueueResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{42}
+}
+
+func (m *ListInboundQueueResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListInboundQueueResponse.Unmarshal(m, b)
+}
+func (m *ListInboundQueueResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListInboundQueueResponse.Marshal(b, m, deterministic)
+}
+func (m *ListInboundQueueResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListInboundQueueResponse.Merge(m, src)
+}
+func (m *ListInboundQueueResponse) XXX_Size() int {
+	return xxx_messageInfo_ListInboundQueueResponse.Size(m)
+}
+func (m *ListInboundQueueResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListInboundQueueResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListInboundQueueResponse proto.InternalMessageInfo
+
+func (m *ListInboundQueueResponse) GetWorker() uint32 {
+	if m != nil {
+		return m.Worker
+	}
+	return 0
+}
+
+func (m *ListInboundQueueResponse) GetDepth() uint32 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+func (m *ListInboundQueueResponse) GetCapacity() uint32 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *ListInboundQueueResponse) GetDrops() uint64 {
+	if m != nil {
+		return m.Drops
+	}
+	return 0
+}
+
+type ListDynamicRangeRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDynamicRangeRequest) Reset()         { *m = ListDynamicRangeRequest{} }
+func (m *ListDynamicRangeRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDynamicRangeRequest) ProtoMessage()    {}
+func (*ListDynamicRangeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{43}
+}
+
+func (m *ListDynamicRangeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDynamicRangeRequest.Unmarshal(m, b)
+}
+func (m *ListDynamicRangeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDynamicRangeRequest.Marshal(b, m, deterministic)
+}
+func (m *ListDynamicRangeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDynamicRangeRequest.Merge(m, src)
+}
+func (m *ListDynamicRangeRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDynamicRangeRequest.Size(m)
+}
+func (m *ListDynamicRangeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDynamicRangeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDynamicRangeRequest proto.InternalMessageInfo
+
+type ListDynamicRangeResponse struct {
+	Prefix               string   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Sessions             uint32   `protobuf:"varint,2,opt,name=sessions,proto3" json:"sessions,omitempty"`
+	MaxSessions          uint32   `protobuf:"varint,3,opt,name=max_sessions,json=maxSessions,proto3" json:"max_sessions,omitempty"`
+	Rejected             uint64   `protobuf:"varint,4,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDynamicRangeResponse) Reset()         { *m = ListDynamicRangeResponse{} }
+func (m *ListDynamicRangeResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDynamicRangeResponse) ProtoMessage()    {}
+func (*ListDynamicRangeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{44}
+}
+
+func (m *ListDynamicRangeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDynamicRangeResponse.Unmarshal(m, b)
+}
+func (m *ListDynamicRangeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDynamicRangeResponse.Marshal(b, m, deterministic)
+}
+func (m *ListDynamicRangeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDynamicRangeResponse.Merge(m, src)
+}
+func (m *ListDynamicRangeResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDynamicRangeResponse.Size(m)
+}
+func (m *ListDynamicRangeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDynamicRangeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDynamicRangeResponse proto.InternalMessageInfo
+
+func (m *ListDynamicRangeResponse) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ListDynamicRangeResponse) GetSessions() uint32 {
+	if m != nil {
+		return m.Sessions
+	}
+	return 0
+}
+
+func (m *ListDynamicRangeResponse) GetMaxSessions() uint32 {
+	if m != nil {
+		return m.MaxSessions
+	}
+	return 0
+}
+
+func (m *ListDynamicRangeResponse) GetRejected() uint64 {
+	if m != nil {
+		return m.Rejected
+	}
+	return 0
+}
+
+type LagMember struct {
+	Interface            string       `protobuf:"bytes,1,opt,name=interface,proto3" json:"interface,omitempty"`
+	Uuid                 []byte       `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	State                SessionState `protobuf:"varint,3,opt,name=state,proto3,enum=api.SessionState" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *LagMember) Reset()         { *m = LagMember{} }
+func (m *LagMember) String() string { return proto.CompactTextString(m) }
+func (*LagMember) ProtoMessage()    {}
+func (*LagMember) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{45}
+}
+
+func (m *LagMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LagMember.Unmarshal(m, b)
+}
+func (m *LagMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LagMember.Marshal(b, m, deterministic)
+}
+func (m *LagMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LagMember.Merge(m, src)
+}
+func (m *LagMember) XXX_Size() int {
+	return xxx_messageInfo_LagMember.Size(m)
+}
+func (m *LagMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_LagMember.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LagMember proto.InternalMessageInfo
+
+func (m *LagMember) GetInterface() string {
+	if m != nil {
+		return m.Interface
+	}
+	return ""
+}
+
+func (m *LagMember) GetUuid() []byte {
+	if m != nil {
+		return m.Uuid
+	}
+	return nil
+}
+
+func (m *LagMember) GetState() SessionState {
+	if m != nil {
+		return m.State
+	}
+	return SessionState_ADMIN_DOWN
+}
+
+type PeerState struct {
+	State                SessionState   `protobuf:"varint,1,opt,name=state,proto3,enum=api.SessionState" json:"state,omitempty"`
+	Diagnostic           DiagnosticCode `protobuf:"varint,2,opt,name=diagnostic,proto3,enum=api.DiagnosticCode" json:"diagnostic,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *PeerState) Reset()         { *m = PeerState{} }
+func (m *PeerState) String() string { return proto.CompactTextString(m) }
+func (*PeerState) ProtoMessage()    {}
+func (*PeerState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{46}
+}
+
+func (m *PeerState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerState.Unmarshal(m, b)
+}
+func (m *PeerState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerState.Marshal(b, m, deterministic)
+}
+func (m *PeerState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerState.Merge(m, src)
+}
+func (m *PeerState) XXX_Size() int {
+	return xxx_messageInfo_PeerState.Size(m)
+}
+func (m *PeerState) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerState proto.InternalMessageInfo
+
+func (m *PeerState) GetState() SessionState {
+	if m != nil {
+		return m.State
+	}
+	return SessionState_ADMIN_DOWN
+}
+
+func (m *PeerState) GetDiagnostic() DiagnosticCode {
+	if m != nil {
+		return m.Diagnostic
+	}
+	return DiagnosticCode_NO_DIAGNOSTIC
+}
+
+// The number of received packets with a violation of RFC5880 6.8.6,
+// a packet with several violations is counted once for each of them.
+// Only violations that occurred are listed
+type ViolationCount struct {
+	Violation            Violation `protobuf:"varint,1,opt,name=violation,proto3,enum=api.Violation" json:"violation,omitempty"`
+	Count                uint64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ViolationCount) Reset()         { *m = ViolationCount{} }
+func (m *ViolationCount) String() string { return proto.CompactTextString(m) }
+func (*ViolationCount) ProtoMessage()    {}
+func (*ViolationCount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{47}
+}
+
+func (m *ViolationCount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ViolationCount.Unmarshal(m, b)
+}
+func (m *ViolationCount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ViolationCount.Marshal(b, m, deterministic)
+}
+func (m *ViolationCount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ViolationCount.Merge(m, src)
+}
+func (m *ViolationCount) XXX_Size() int {
+	return xxx_messageInfo_ViolationCount.Size(m)
+}
+func (m *ViolationCount) XXX_DiscardUnknown() {
+	xxx_messageInfo_ViolationCount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ViolationCount proto.InternalMessageInfo
+
+func (m *ViolationCount) GetViolation() Violation {
+	if m != nil {
+		return m.Violation
+	}
+	return Violation_INVALID_VERSION
+}
+
+func (m *ViolationCount) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("api.SessionState", SessionState_name, SessionState_value)
+	proto.RegisterEnum("api.DiagnosticCode", DiagnosticCode_name, DiagnosticCode_value)
+	proto.RegisterEnum("api.AuthenticationType", AuthenticationType_name, AuthenticationType_value)
+	proto.RegisterEnum("api.Violation", Violation_name, Violation_value)
+	proto.RegisterType((*StartRequest)(nil), "api.StartRequest")
+	proto.RegisterType((*StopRequest)(nil), "api.StopRequest")
+	proto.RegisterType((*AddPeerRequest)(nil), "api.AddPeerRequest")
+	proto.RegisterType((*AddPeerResponse)(nil), "api.AddPeerResponse")
+	proto.RegisterType((*UpdatePeerRequest)(nil), "api.UpdatePeerRequest")
+	proto.RegisterType((*DeletePeerRequest)(nil), "api.DeletePeerRequest")
+	proto.RegisterType((*ListPeerRequest)(nil), "api.ListPeerRequest")
+	proto.RegisterType((*ListPeerResponse)(nil), "api.ListPeerResponse")
+	proto.RegisterType((*GetPeerStateRequest)(nil), "api.GetPeerStateRequest")
+	proto.RegisterType((*MonitorPeerRequest)(nil), "api.MonitorPeerRequest")
+	proto.RegisterType((*PeerStateResponse)(nil), "api.PeerStateResponse")
+	proto.RegisterType((*DisablePeerRequest)(nil), "api.DisablePeerRequest")
+	proto.RegisterType((*EnablePeerRequest)(nil), "api.EnablePeerRequest")
+	proto.RegisterType((*PollPeerRequest)(nil), "api.PollPeerRequest")
+	proto.RegisterType((*AddKeyChainRequest)(nil), "api.AddKeyChainRequest")
+	proto.RegisterType((*DeleteKeyChainRequest)(nil), "api.DeleteKeyChainRequest")
+	proto.RegisterType((*ListKeyChainRequest)(nil), "api.ListKeyChainRequest")
+	proto.RegisterType((*ListKeyChainResponse)(nil), "api.ListKeyChainResponse")
+	proto.RegisterType((*AddKeyRequest)(nil), "api.AddKeyRequest")
+	proto.RegisterType((*DeleteKeyRequest)(nil), "api.DeleteKeyRequest")
+	proto.RegisterType((*AddSbfdReflectorRequest)(nil), "api.AddSbfdReflectorRequest")
+	proto.RegisterType((*DeleteSbfdReflectorRequest)(nil), "api.DeleteSbfdReflectorRequest")
+	proto.RegisterType((*ListSbfdReflectorRequest)(nil), "api.ListSbfdReflectorRequest")
+	proto.RegisterType((*ListSbfdReflectorResponse)(nil), "api.ListSbfdReflectorResponse")
+	proto.RegisterType((*AddSbfdInitiatorRequest)(nil), "api.AddSbfdInitiatorRequest")
+	proto.RegisterType((*AddSbfdInitiatorResponse)(nil), "api.AddSbfdInitiatorResponse")
+	proto.RegisterType((*DeleteSbfdInitiatorRequest)(nil), "api.DeleteSbfdInitiatorRequest")
+	proto.RegisterType((*ListSbfdInitiatorRequest)(nil), "api.ListSbfdInitiatorRequest")
+	proto.RegisterType((*ListSbfdInitiatorResponse)(nil), "api.ListSbfdInitiatorResponse")
+	proto.RegisterType((*AddLagRequest)(nil), "api.AddLagRequest")
+	proto.RegisterType((*DeleteLagRequest)(nil), "api.DeleteLagRequest")
+	proto.RegisterType((*ListLagRequest)(nil), "api.ListLagRequest")
+	proto.RegisterType((*ListLagResponse)(nil), "api.ListLagResponse")
+	proto.RegisterType((*Peer)(nil), "api.Peer")
+	proto.RegisterType((*Authentication)(nil), "api.Authentication")
+	proto.RegisterType((*KeyChain)(nil), "api.KeyChain")
+	proto.RegisterType((*Key)(nil), "api.Key")
+	proto.RegisterType((*SbfdInitiator)(nil), "api.SbfdInitiator")
+	proto.RegisterType((*Lag)(nil), "api.Lag")
+	proto.RegisterType((*ListListenerRequest)(nil), "api.ListListenerRequest")
+	proto.RegisterType((*ListListenerResponse)(nil), "api.ListListenerResponse")
+	proto.RegisterType((*ListInboundQueueRequest)(nil), "api.ListInboundQueueRequest")
+	proto.RegisterType((*ListInboundQueueResponse)(nil), "api.ListInboundQueueResponse")
+	proto.RegisterType((*ListDynamicRangeRequest)(nil), "api.ListDynamicRangeRequest")
+	proto.RegisterType((*ListDynamicRangeResponse)(nil), "api.ListDynamicRangeResponse")
+	proto.RegisterType((*LagMember)(nil), "api.LagMember")
+	proto.RegisterType((*PeerState)(nil), "api.PeerState")
+	proto.RegisterType((*ViolationCount)(nil), "api.ViolationCount")
+}
+
+func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
+
+var fileDescriptor_00212fb1f9d3bf1c = []byte{
+	// 2408 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0x4b, 0x73, 0xdb, 0xc8,
+	0x11, 0x36, 0xf8, 0x12, 0xd9, 0x14, 0x29, 0x6a, 0xf4, 0x82, 0x68, 0xcb, 0xd6, 0x22, 0xeb, 0xb5,
+	0x62, 0xa7, 0x64, 0xad, 0x76, 0xbd, 0x49, 0x36, 0xae, 0x5d, 0xc3, 0x24, 0x2c, 0xa1, 0xcc, 0x87,
+	0x16, 0x84, 0xe4, 0x38, 0x17, 0x14, 0x44, 0x8c, 0xe4, 0x89, 0x48, 0x00, 0x4b, 0x80, 0x5e, 0xc9,
+	0xa7, 0x9c, 0x93, 0xca, 0x0f, 0x49, 0xa5, 0xf2, 0x57, 0x72, 0xca, 0x2d, 0xbf, 0x20, 0xff, 0x20,
+	0xb9, 0xa5, 0x66, 0x30, 0x78, 0xf1, 0xa1, 0x47, 0x72, 0x60, 0x15, 0xa7, 0xfb, 0xeb, 0x9e, 0xee,
+	0xe9, 0x9e, 0xee, 0x41, 0x43, 0xc9, 0x74, 0xc9, 0xae, 0x3b, 0x72, 0x7c, 0x07, 0x65, 0x4d, 0x97,
+	0xd4, 0xef, 0x9f, 0x3b, 0xce, 0xf9, 0x00, 0x3f, 0x67, 0xa4, 0xd3, 0xf1, 0xd9, 0x73, 0x3c, 0x74,
+	0xfd, 0xab, 0x00, 0x21, 0xbd, 0x84, 0xc5, 0x9e, 0x6f, 0x8e, 0x7c, 0x0d, 0xff, 0x38, 0xc6, 0x9e,
+	0x8f, 0x44, 0x58, 0x30, 0x2d, 0x6b, 0x84, 0x3d, 0x4f, 0x14, 0xb6, 0x85, 0x9d, 0x92, 0x16, 0x2e,
+	0x11, 0x82, 0x9c, 0xeb, 0x8c, 0x7c, 0x31, 0xb3, 0x2d, 0xec, 0x54, 0x34, 0xf6, 0x5f, 0xaa, 0x40,
+	0xb9, 0xe7, 0x3b, 0x2e, 0x17, 0x96, 0x9e, 0x43, 0x55, 0xb6, 0xac, 0x23, 0x8c, 0x47, 0xa1, 0xba,
+	0x2d, 0xc8, 0xb9, 0x18, 0x8f, 0x98, 0xae, 0xf2, 0x7e, 0x69, 0x97, 0x9a, 0xc6, 0xf8, 0x8c, 0x2c,
+	0x3d, 0x86, 0xa5, 0x48, 0xc0, 0x73, 0x1d, 0xdb, 0xc3, 0x74, 0x9b, 0xf1, 0x98, 0x58, 0x4c, 0x62,
+	0x51, 0x63, 0xff, 0xa5, 0x37, 0xb0, 0x7c, 0xec, 0x5a, 0xa6, 0x8f, 0x93, 0xaa, 0x67, 0x00, 0xa3,
+	0xed, 0x32, 0xb3, 0xb7, 0x7b, 0x02, 0xcb, 0x4d, 0x3c, 0xc0, 0x37, 0xea, 0x91, 0x96, 0x61, 0xa9,
+	0x45, 0x3c, 0x3f, 0x01, 0x93, 0x3e, 0x41, 0x2d, 0x26, 0xcd, 0xb7, 0xf5, 0x06, 0x13, 0xd0, 0x57,
+	0x00, 0x1f, 0x89, 0x33, 0x30, 0x7d, 0xe2, 0xd8, 0x9e, 0x98, 0xdd, 0xce, 0xee, 0x94, 0xf7, 0x57,
+	0x18, 0xe8, 0x24, 0x24, 0x37, 0x9c, 0xb1, 0xed, 0x6b, 0x09, 0x98, 0xf4, 0x73, 0x58, 0x39, 0xc0,
+	0x6c, 0xeb, 0x9e, 0x6f, 0xfa, 0xf8, 0x3a, 0xcb, 0x77, 0x00, 0xb5, 0x1d, 0x9b, 0xf8, 0xce, 0xe8,
+	0x26, 0x1f, 0x4d, 0x58, 0x4e, 0x68, 0xe4, 0x1e, 0x7d, 0x0e, 0xf9, 0x81, 0xd3, 0x37, 0x07, 0x3c,
+	0x60, 0xd5, 0xc8, 0xfc, 0x00, 0x16, 0x30, 0xd1, 0x17, 0x50, 0x18, 0xe1, 0xa1, 0xe3, 0x63, 0x31,
+	0x33, 0x13, 0xc6, 0xb9, 0xd4, 0x98, 0x26, 0xf1, 0xcc, 0xd3, 0xc1, 0x8d, 0x07, 0xfe, 0x04, 0x96,
+	0x15, 0xfb, 0x36, 0xc0, 0xc7, 0xb0, 0x74, 0xe4, 0x0c, 0x06, 0x37, 0xc1, 0x5e, 0x01, 0x92, 0x2d,
+	0xeb, 0x2d, 0xbe, 0x6a, 0x7c, 0x30, 0x89, 0x1d, 0x22, 0x9f, 0x42, 0xe9, 0x02, 0x5f, 0x19, 0x7d,
+	0x4a, 0xe3, 0x1e, 0x56, 0x98, 0xe9, 0x11, 0xb0, 0x78, 0xc1, 0xff, 0x49, 0xcf, 0x60, 0x2d, 0xc8,
+	0x95, 0x49, 0x25, 0x08, 0x72, 0xb6, 0x39, 0xc4, 0xfc, 0x7a, 0xb0, 0xff, 0xd2, 0x1a, 0xac, 0xd0,
+	0xe4, 0x98, 0x80, 0x4a, 0xaf, 0x61, 0x35, 0x4d, 0xe6, 0xa7, 0x7c, 0x17, 0x3b, 0x0e, 0xa1, 0x12,
+	0x78, 0x12, 0xee, 0x7f, 0x7f, 0x52, 0xb8, 0x14, 0xa3, 0x51, 0x1d, 0xb2, 0x17, 0xf8, 0x8a, 0x87,
+	0xa5, 0x18, 0xea, 0xd4, 0x28, 0x51, 0xfa, 0x1e, 0x6a, 0x91, 0x47, 0xb7, 0x52, 0x56, 0x85, 0x0c,
+	0xb1, 0xf8, 0x7d, 0xcf, 0x10, 0x4b, 0xfa, 0x1e, 0x36, 0x64, 0xcb, 0xea, 0x9d, 0x9e, 0x59, 0x1a,
+	0x3e, 0x1b, 0xe0, 0xbe, 0xef, 0x44, 0x31, 0xf8, 0x1c, 0x2a, 0x16, 0xf1, 0xfa, 0x23, 0x32, 0x24,
+	0xb6, 0xe9, 0x3b, 0xc1, 0x85, 0xaf, 0x68, 0x69, 0xa2, 0xf4, 0x1a, 0xea, 0x81, 0x05, 0xff, 0x87,
+	0x8e, 0x3a, 0x88, 0xf4, 0x4c, 0x67, 0x69, 0x90, 0x64, 0xd8, 0x9c, 0xc1, 0x8b, 0x52, 0xfb, 0x36,
+	0xea, 0xdf, 0x46, 0x3e, 0xaa, 0x36, 0xf1, 0x89, 0x99, 0xb0, 0x6f, 0x0f, 0x4a, 0x24, 0xa4, 0xf1,
+	0xa8, 0x21, 0x76, 0xc2, 0x69, 0x74, 0x0c, 0x92, 0x76, 0x41, 0x9c, 0x56, 0x76, 0x4d, 0x9d, 0xdb,
+	0x4b, 0x9e, 0xcf, 0xd4, 0xfe, 0xb3, 0x24, 0x12, 0xa7, 0x31, 0x89, 0x97, 0xfe, 0x28, 0xc0, 0xe6,
+	0x0c, 0xe6, 0x35, 0xb5, 0x2b, 0xe5, 0x61, 0xe6, 0x16, 0x1e, 0xa2, 0x27, 0x90, 0xf7, 0xe8, 0x95,
+	0x17, 0xb3, 0xdb, 0xc2, 0x4e, 0x75, 0x7f, 0x39, 0x40, 0x63, 0xcf, 0x23, 0x8e, 0xcd, 0x4b, 0x06,
+	0xe3, 0x4b, 0xcf, 0x58, 0x1a, 0xb7, 0xcc, 0xf3, 0xd0, 0x9b, 0x3a, 0x64, 0x07, 0xe6, 0xb9, 0x28,
+	0x24, 0x32, 0x95, 0x72, 0x29, 0x51, 0xfa, 0x22, 0xcc, 0xd4, 0x04, 0x7e, 0xd6, 0xb5, 0xab, 0x41,
+	0x95, 0x3a, 0x18, 0xa3, 0xa4, 0x3f, 0x08, 0xb0, 0x14, 0x91, 0xb8, 0xa7, 0xd7, 0xec, 0x14, 0xdb,
+	0x9f, 0xb9, 0xde, 0x7e, 0xb4, 0x03, 0x0b, 0x43, 0x3c, 0x3c, 0xc5, 0xa3, 0xb0, 0x68, 0x57, 0x43,
+	0x45, 0x6d, 0x46, 0xd6, 0x42, 0xb6, 0xf4, 0xf7, 0x3c, 0xe4, 0x68, 0x79, 0x9a, 0x65, 0x71, 0xb2,
+	0xbd, 0x66, 0xd2, 0xed, 0xf5, 0x05, 0x6c, 0x58, 0xd8, 0x23, 0x23, 0x6c, 0x19, 0x43, 0x62, 0x1b,
+	0xfe, 0xa5, 0x41, 0x6c, 0x1f, 0x8f, 0x3e, 0x9a, 0x03, 0x76, 0xb6, 0x15, 0x6d, 0x95, 0xb3, 0xdb,
+	0xc4, 0xd6, 0x2f, 0x55, 0xce, 0x43, 0xbf, 0x04, 0x71, 0x84, 0x7f, 0x1c, 0x47, 0x72, 0xa3, 0x84,
+	0x5c, 0x8e, 0xc9, 0xad, 0x85, 0xfc, 0x36, 0xb1, 0xb5, 0x58, 0xf0, 0x19, 0x2c, 0x5b, 0xd8, 0xc7,
+	0x7d, 0xdf, 0x18, 0x8e, 0x07, 0x3e, 0x71, 0x07, 0x04, 0x8f, 0xc4, 0x3c, 0x93, 0xa8, 0x05, 0x8c,
+	0x76, 0x44, 0x47, 0xdb, 0xb0, 0x48, 0xbc, 0x00, 0x68, 0x7c, 0x70, 0x5c, 0xb1, 0xb0, 0x2d, 0xec,
+	0x14, 0x35, 0x20, 0x1e, 0xc3, 0x1c, 0x3a, 0x2e, 0xfa, 0x0d, 0x54, 0xcd, 0xb1, 0xff, 0x01, 0xdb,
+	0x3e, 0xe9, 0xb3, 0xae, 0x25, 0x2e, 0x6c, 0x0b, 0x51, 0x6f, 0x93, 0x53, 0x2c, 0x6d, 0x02, 0x8a,
+	0x1e, 0x41, 0xd9, 0xc2, 0x43, 0xd3, 0xb6, 0x8c, 0xa1, 0x63, 0x61, 0xb1, 0x18, 0x68, 0x0f, 0x48,
+	0x6d, 0xc7, 0xc2, 0x68, 0x0f, 0x56, 0x39, 0xc0, 0x75, 0x06, 0x83, 0xd8, 0xc3, 0x12, 0xb3, 0x17,
+	0x05, 0x3c, 0xda, 0x17, 0x22, 0xf7, 0x7e, 0x06, 0x15, 0xdc, 0xff, 0xe0, 0xc4, 0x50, 0x60, 0xd0,
+	0x45, 0x4a, 0x4c, 0x82, 0x58, 0x43, 0x33, 0xc2, 0x98, 0x94, 0x59, 0x4c, 0x16, 0x19, 0x51, 0xe6,
+	0x81, 0xd9, 0x80, 0x05, 0x16, 0x10, 0x7f, 0x20, 0x2e, 0x32, 0x1d, 0x85, 0x21, 0xb1, 0x75, 0x7f,
+	0x40, 0x63, 0x69, 0x5d, 0xd9, 0xe6, 0x90, 0xf4, 0xc5, 0x0a, 0xb3, 0x38, 0x5c, 0x52, 0x8e, 0x6b,
+	0x7a, 0x1e, 0xf9, 0x88, 0xc5, 0x6a, 0xc0, 0xe1, 0x4b, 0xf4, 0x80, 0xde, 0x30, 0x1f, 0x8f, 0xce,
+	0xcc, 0x3e, 0x16, 0x97, 0xd8, 0x6e, 0x31, 0x01, 0x6d, 0x42, 0x91, 0x78, 0xc6, 0xc7, 0xcb, 0x81,
+	0x69, 0x8b, 0xb5, 0x40, 0x90, 0x78, 0x27, 0x74, 0x89, 0x6a, 0x90, 0xfd, 0x68, 0x13, 0x71, 0x99,
+	0x59, 0x40, 0xff, 0xa2, 0x87, 0x50, 0x76, 0x4d, 0xcb, 0xf0, 0x1d, 0xc3, 0x23, 0x9f, 0xb0, 0x88,
+	0x18, 0xa7, 0xe4, 0x9a, 0x96, 0xee, 0xf4, 0xc8, 0x27, 0x8c, 0xb6, 0xa1, 0x3c, 0xb6, 0x3d, 0x67,
+	0x40, 0xfa, 0xc4, 0xc7, 0x96, 0xb8, 0xc2, 0xf4, 0x25, 0x49, 0xe8, 0x31, 0x54, 0xd9, 0x19, 0x8d,
+	0xc2, 0x5a, 0x29, 0xae, 0x32, 0x10, 0x3b, 0xb9, 0xa8, 0x80, 0x4a, 0x7f, 0x15, 0xa0, 0x9a, 0x0e,
+	0x20, 0x7a, 0x06, 0x39, 0xff, 0xca, 0x0d, 0x52, 0xbb, 0xba, 0xbf, 0x31, 0x23, 0xc6, 0xfa, 0x95,
+	0x8b, 0x35, 0x06, 0x42, 0x75, 0x28, 0x52, 0xf7, 0x7f, 0x72, 0x46, 0x16, 0x4f, 0xfa, 0x68, 0x8d,
+	0xd6, 0xa0, 0x40, 0xfb, 0x0f, 0xb1, 0x78, 0x92, 0xe7, 0x2f, 0xf0, 0x95, 0x6a, 0xa5, 0xdb, 0x52,
+	0x6e, 0xa2, 0x2d, 0x3d, 0x80, 0x92, 0xe3, 0xfa, 0x64, 0x48, 0x3e, 0x61, 0x8b, 0x65, 0x6c, 0x51,
+	0x8b, 0x09, 0xd2, 0x4b, 0x28, 0x86, 0x5d, 0x74, 0xe6, 0x0d, 0x7c, 0x00, 0xb9, 0x0b, 0x7c, 0x45,
+	0xaf, 0x5f, 0x36, 0xd5, 0x22, 0x19, 0x55, 0xfa, 0x9b, 0x00, 0xd9, 0xb7, 0xf8, 0x8a, 0xb7, 0x3e,
+	0x21, 0x6c, 0x7d, 0xd7, 0xfa, 0xb0, 0x05, 0xe0, 0x61, 0xdb, 0x32, 0x3c, 0xfa, 0x8e, 0x66, 0x7e,
+	0x64, 0xb5, 0x12, 0xa5, 0xb0, 0x87, 0x35, 0x0d, 0x2a, 0x63, 0x63, 0xdb, 0x62, 0xae, 0x64, 0xb5,
+	0x05, 0xba, 0x56, 0x6c, 0x0b, 0x7d, 0x06, 0x8b, 0x66, 0xbf, 0x8f, 0x5d, 0x9f, 0xcb, 0xe6, 0x19,
+	0xbb, 0x1c, 0xd0, 0x02, 0xe9, 0x2d, 0x00, 0x0e, 0xa1, 0xf2, 0x85, 0x40, 0x79, 0x40, 0x51, 0x6c,
+	0x4b, 0xfa, 0x8f, 0x00, 0x95, 0x54, 0x71, 0xbe, 0x63, 0xd5, 0xf9, 0x12, 0x56, 0x83, 0xb7, 0x9a,
+	0x91, 0xee, 0x8d, 0x41, 0x34, 0x56, 0x02, 0x5e, 0x33, 0xc9, 0xba, 0xae, 0x50, 0xe5, 0xae, 0x29,
+	0x54, 0x77, 0xaa, 0x37, 0x53, 0x17, 0xb3, 0x30, 0x7d, 0x31, 0xa5, 0x7f, 0x65, 0x20, 0xdb, 0x32,
+	0xcf, 0xef, 0xe8, 0xb1, 0x98, 0x2e, 0xe4, 0xa5, 0xa8, 0x70, 0xd3, 0xa4, 0xa3, 0x0e, 0x0d, 0x88,
+	0x7d, 0xe1, 0x71, 0x57, 0x8a, 0x43, 0x62, 0xb7, 0xe8, 0xfa, 0x3a, 0xaf, 0xf3, 0xff, 0x63, 0x79,
+	0x2e, 0xdc, 0xb9, 0x3c, 0x2f, 0xcc, 0x39, 0xae, 0xe9, 0xe2, 0x5b, 0xbc, 0x7d, 0xf1, 0x9d, 0x3a,
+	0xeb, 0xd2, 0x8c, 0xb3, 0xe6, 0x0f, 0x5c, 0xfa, 0xc3, 0x76, 0xfc, 0x51, 0xf4, 0x4f, 0x01, 0x56,
+	0xd3, 0x74, 0xde, 0x73, 0xe7, 0x7f, 0x46, 0x4e, 0xb6, 0x92, 0xcc, 0x54, 0x2b, 0x09, 0xaa, 0xe0,
+	0x90, 0xf4, 0x47, 0x8e, 0x98, 0x0d, 0xab, 0x60, 0x9b, 0x2e, 0x53, 0x05, 0x32, 0x97, 0x2e, 0x90,
+	0xa9, 0xca, 0x9a, 0x9f, 0xac, 0xac, 0xe9, 0xcf, 0xae, 0xc2, 0xed, 0x3e, 0xbb, 0x36, 0x61, 0x83,
+	0x3a, 0xa6, 0xda, 0xa7, 0xce, 0xd8, 0xb6, 0x7e, 0x18, 0xe3, 0x31, 0x8e, 0xbf, 0x06, 0xc5, 0x69,
+	0x16, 0xf7, 0x7d, 0x1d, 0x0a, 0x3f, 0x39, 0xa3, 0x0b, 0x1c, 0xbe, 0x30, 0xf9, 0x0a, 0xad, 0x42,
+	0xde, 0xc2, 0xae, 0xff, 0x81, 0xbf, 0xa8, 0x83, 0x05, 0xad, 0x2c, 0x7d, 0xd3, 0x35, 0xfb, 0xc4,
+	0xbf, 0xe2, 0xb7, 0x2e, 0x5a, 0x33, 0x89, 0x91, 0xe3, 0x06, 0xd9, 0x98, 0xd3, 0x82, 0x45, 0x68,
+	0x56, 0x33, 0x68, 0x36, 0x9a, 0x69, 0x9f, 0x47, 0x66, 0xfd, 0x59, 0x00, 0x71, 0x9a, 0x17, 0xdb,
+	0xe5, 0x8e, 0xf0, 0x19, 0xb9, 0xe4, 0x21, 0xe1, 0x2b, 0x6a, 0x81, 0x17, 0xbc, 0x78, 0x3c, 0x6e,
+	0x5a, 0xb4, 0xa6, 0x15, 0x6a, 0x68, 0x5e, 0x1a, 0x11, 0x3f, 0xb0, 0xb0, 0x3c, 0x34, 0x2f, 0x7b,
+	0x21, 0xa4, 0x0e, 0xc5, 0x11, 0xfe, 0x3d, 0xee, 0xd3, 0x26, 0x13, 0xd8, 0x19, 0xad, 0xa5, 0x33,
+	0x28, 0x45, 0x2f, 0xa4, 0x74, 0x84, 0x84, 0xc9, 0x08, 0x85, 0xef, 0xd1, 0x4c, 0xe2, 0x3d, 0x7a,
+	0xeb, 0xd7, 0x25, 0x81, 0x52, 0xf4, 0xf5, 0x19, 0x4b, 0x09, 0xd7, 0x4b, 0xd1, 0xa4, 0xb0, 0x88,
+	0x79, 0x6e, 0x3b, 0x9e, 0x4f, 0xfa, 0xfc, 0x05, 0x18, 0x24, 0x45, 0x33, 0x22, 0x37, 0x1c, 0x0b,
+	0x6b, 0x09, 0x98, 0xa4, 0x43, 0x35, 0x9d, 0x32, 0xe8, 0x17, 0x50, 0x8a, 0x92, 0x86, 0xef, 0x59,
+	0x4d, 0xa7, 0x96, 0x16, 0x03, 0x68, 0x4c, 0xfb, 0x54, 0x8c, 0xed, 0x97, 0xd3, 0x82, 0xc5, 0xd3,
+	0x6f, 0x61, 0x31, 0x69, 0x21, 0xaa, 0x02, 0xc8, 0xcd, 0xb6, 0xda, 0x31, 0x9a, 0xdd, 0x77, 0x9d,
+	0xda, 0x3d, 0x54, 0x84, 0x1c, 0xfb, 0x27, 0xd0, 0x7f, 0x6a, 0x47, 0xd5, 0x6b, 0x19, 0x54, 0x80,
+	0xcc, 0xf1, 0x51, 0x2d, 0xfb, 0xf4, 0x4f, 0x19, 0xa8, 0xa6, 0x0d, 0x46, 0xcb, 0x50, 0xe9, 0x74,
+	0x8d, 0xa6, 0x2a, 0x1f, 0x74, 0xba, 0x3d, 0x5d, 0x6d, 0xd4, 0xee, 0x21, 0x09, 0x1e, 0x36, 0xba,
+	0x1d, 0x5d, 0xeb, 0xb6, 0x8c, 0xa6, 0xa2, 0x2b, 0x0d, 0x5d, 0xed, 0x76, 0x0c, 0x5d, 0x6d, 0x2b,
+	0x86, 0xf2, 0xdb, 0x23, 0x55, 0x53, 0x9a, 0x35, 0x01, 0x89, 0xb0, 0xaa, 0x34, 0x0e, 0xbb, 0xc6,
+	0x9b, 0xe3, 0x4e, 0xc0, 0x7f, 0x23, 0xab, 0x2d, 0xa5, 0x59, 0xcb, 0x50, 0xe9, 0x8e, 0xa2, 0x1e,
+	0x1c, 0xbe, 0xee, 0x6a, 0x46, 0x4f, 0x3d, 0xe8, 0xc8, 0x2d, 0xa5, 0x69, 0xf4, 0x94, 0x5e, 0x8f,
+	0xa2, 0x98, 0x65, 0x59, 0x54, 0x87, 0xf5, 0x37, 0x5d, 0xed, 0x9d, 0xac, 0x35, 0xd5, 0xce, 0x81,
+	0x71, 0xd4, 0x92, 0x3b, 0x8a, 0xa1, 0x29, 0x3d, 0x45, 0xaf, 0xe5, 0x50, 0x05, 0x4a, 0x47, 0xb2,
+	0x7e, 0x18, 0x40, 0xf3, 0x14, 0xda, 0xe8, 0x76, 0x1a, 0xb2, 0xae, 0x74, 0x64, 0x5d, 0x69, 0x1a,
+	0x31, 0xaf, 0x80, 0x36, 0x61, 0x8d, 0xb9, 0xae, 0xf6, 0x74, 0x4d, 0xd6, 0xd5, 0x13, 0xa5, 0xf5,
+	0x3e, 0x60, 0x2d, 0x50, 0x2b, 0x34, 0xe5, 0x44, 0xd1, 0x7a, 0x8a, 0x31, 0x47, 0xbc, 0xf8, 0xf4,
+	0x2f, 0x02, 0xa0, 0xe9, 0xa7, 0x08, 0x3d, 0xb6, 0x4e, 0xb7, 0xa3, 0xd4, 0xee, 0xa1, 0x15, 0x58,
+	0xea, 0xa9, 0xed, 0xa3, 0x96, 0x62, 0x1c, 0xc9, 0xbd, 0xde, 0xbb, 0xae, 0x46, 0x3d, 0xaf, 0x40,
+	0xe9, 0xad, 0xf2, 0x5e, 0x69, 0x1a, 0xed, 0xe6, 0x8b, 0x5a, 0x86, 0x1e, 0x44, 0x5b, 0xd1, 0xd5,
+	0xc6, 0x71, 0xab, 0x7b, 0xdc, 0x33, 0x62, 0x4e, 0x96, 0x06, 0x26, 0x58, 0xf6, 0x0e, 0xe5, 0x2f,
+	0x6b, 0x39, 0x6a, 0xed, 0x14, 0x92, 0xb1, 0xf2, 0x68, 0x1b, 0x1e, 0x4c, 0xb1, 0x0e, 0xdb, 0x72,
+	0x83, 0xf2, 0xf7, 0x5f, 0x7c, 0x53, 0x2b, 0x3c, 0xfd, 0xb7, 0x00, 0xa5, 0x28, 0x49, 0xa8, 0x61,
+	0x6a, 0xe7, 0x44, 0x6e, 0xa9, 0x4d, 0x83, 0xfa, 0xa8, 0x76, 0x69, 0xe0, 0x11, 0x54, 0x43, 0x62,
+	0x4b, 0xe9, 0x1c, 0xe8, 0x87, 0x35, 0x01, 0x7d, 0x06, 0x5b, 0x21, 0x4d, 0x3e, 0xd6, 0x0f, 0x95,
+	0x8e, 0xae, 0x36, 0x64, 0x16, 0x2f, 0x0e, 0xc9, 0xa0, 0x47, 0x70, 0x7f, 0x0e, 0x44, 0x7f, 0x7f,
+	0xa4, 0x04, 0xc1, 0xfa, 0x9d, 0xa2, 0x75, 0x79, 0x2e, 0x18, 0xed, 0xe3, 0x96, 0xae, 0x1e, 0xb5,
+	0x54, 0x45, 0xab, 0xe5, 0xe8, 0x9e, 0xc1, 0xba, 0xab, 0x76, 0x74, 0x83, 0x06, 0x30, 0x4f, 0xfd,
+	0x64, 0xf8, 0xf6, 0x7b, 0xa3, 0xa9, 0xf6, 0x1a, 0x9a, 0xda, 0x56, 0x3b, 0xb2, 0xde, 0xd5, 0x6a,
+	0x05, 0x74, 0x1f, 0x36, 0x18, 0xeb, 0x7d, 0xf7, 0x58, 0x9b, 0x60, 0x2e, 0xa0, 0x75, 0x40, 0x34,
+	0x07, 0xb4, 0x13, 0xa5, 0x69, 0xbc, 0x51, 0x95, 0x56, 0x93, 0xe9, 0x2b, 0xee, 0xff, 0xa3, 0x0a,
+	0x85, 0xd7, 0x67, 0x96, 0xec, 0x12, 0xb4, 0x0f, 0xf9, 0xe0, 0xad, 0xc3, 0x6f, 0x6a, 0x62, 0x1c,
+	0x59, 0x5f, 0xdf, 0x0d, 0x86, 0x97, 0xbb, 0xe1, 0xf0, 0x72, 0x57, 0xa1, 0xc3, 0x4b, 0xb4, 0x07,
+	0x39, 0x3a, 0x78, 0x44, 0x35, 0x2e, 0xe2, 0xb8, 0x37, 0x49, 0x7c, 0x0d, 0x0b, 0x7c, 0xd4, 0x88,
+	0x78, 0x5b, 0x4c, 0x4d, 0x2a, 0xeb, 0xab, 0x69, 0x22, 0xaf, 0x99, 0x2f, 0x01, 0xe2, 0xc9, 0x23,
+	0x5a, 0x67, 0x98, 0xa9, 0x51, 0xe4, 0xdc, 0x3d, 0x5f, 0x02, 0xc4, 0xf3, 0x46, 0x2e, 0x3d, 0x35,
+	0x80, 0x9c, 0x2b, 0xfd, 0x6b, 0x28, 0x86, 0x13, 0x47, 0x14, 0x58, 0x37, 0x31, 0x93, 0xac, 0xaf,
+	0x4d, 0x50, 0x03, 0xa3, 0xf7, 0x04, 0xf4, 0x0a, 0x16, 0x93, 0x03, 0x43, 0x24, 0x32, 0xe0, 0x8c,
+	0x19, 0x62, 0x7d, 0x7d, 0x62, 0x74, 0x17, 0x3a, 0xfe, 0x0a, 0xca, 0x89, 0x39, 0x22, 0x0a, 0x9e,
+	0xf8, 0xd3, 0x93, 0xc5, 0x79, 0xf2, 0x7b, 0x02, 0xfa, 0x0e, 0xca, 0x89, 0xe1, 0x1f, 0xd7, 0x30,
+	0x3d, 0x0e, 0xbc, 0xee, 0xf0, 0xe2, 0x91, 0x20, 0x3f, 0x3c, 0xc5, 0xbe, 0xad, 0xf4, 0xaf, 0xa0,
+	0x18, 0xce, 0x09, 0xf9, 0xe1, 0x4d, 0x8c, 0x0d, 0xe7, 0x4a, 0x7e, 0x07, 0xe5, 0xc4, 0xe8, 0x90,
+	0xdb, 0x3d, 0x3d, 0x4c, 0x9c, 0x2b, 0xdf, 0x84, 0x6a, 0x7a, 0x70, 0x88, 0xea, 0x89, 0xc0, 0xdf,
+	0x56, 0x8b, 0x02, 0x8b, 0xc9, 0xd1, 0x21, 0x8f, 0xe0, 0x8c, 0x21, 0x63, 0x7d, 0x73, 0x06, 0x27,
+	0x0a, 0xc2, 0xd7, 0x50, 0x08, 0x4c, 0x47, 0x28, 0xe1, 0xc7, 0x4d, 0x9b, 0x7f, 0x0b, 0xa5, 0xc8,
+	0x5a, 0xb4, 0x96, 0xb6, 0xfe, 0x26, 0xd9, 0x43, 0xa8, 0x4d, 0x0e, 0x09, 0xd1, 0x83, 0x70, 0xef,
+	0x59, 0x53, 0xbb, 0xb9, 0x9a, 0x3a, 0xb0, 0x32, 0x63, 0x5a, 0x88, 0x1e, 0x25, 0xec, 0xb9, 0x93,
+	0x3e, 0x1d, 0x96, 0xa7, 0xa6, 0x83, 0x68, 0x2b, 0x3a, 0xbd, 0x99, 0xba, 0x1e, 0xce, 0x63, 0x47,
+	0x27, 0xdc, 0x8d, 0xfc, 0x8d, 0xbf, 0xc1, 0x52, 0xfe, 0x4e, 0xce, 0xe5, 0xea, 0x5b, 0x73, 0xb8,
+	0xfc, 0xe6, 0xa5, 0xdc, 0x8e, 0x75, 0x4e, 0xba, 0x3d, 0xa5, 0xf6, 0x16, 0x6e, 0xc7, 0xda, 0xd2,
+	0x6e, 0x4f, 0xe9, 0x7a, 0x38, 0x8f, 0x3d, 0x91, 0x58, 0xec, 0xf3, 0x2b, 0x74, 0x27, 0x1e, 0xc3,
+	0xdd, 0x9c, 0x58, 0x54, 0x30, 0x99, 0x58, 0xb7, 0x90, 0xfd, 0x06, 0x16, 0xf8, 0x64, 0x8f, 0x17,
+	0xf0, 0xf4, 0xe8, 0xaf, 0xbe, 0x9a, 0x26, 0x46, 0x96, 0xf2, 0x9b, 0x14, 0x7e, 0xa2, 0x24, 0x6e,
+	0xd2, 0xc4, 0xd7, 0x4c, 0x7d, 0x73, 0x06, 0x27, 0x52, 0xf3, 0x03, 0xd4, 0x26, 0x5f, 0xfc, 0x3c,
+	0xce, 0x73, 0xbe, 0x11, 0xea, 0x5b, 0x73, 0xb8, 0x93, 0x2a, 0x93, 0x8f, 0xf5, 0x84, 0xca, 0x19,
+	0xef, 0xfb, 0xfa, 0xd6, 0x1c, 0x6e, 0xa8, 0xf2, 0xb4, 0xc0, 0x0e, 0xed, 0xab, 0xff, 0x0e, 0x00,
+	0x3e, 0x0f, 0x63, 0xcc, 0x04, 0x1c, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// BfdApiClient is the client API for BfdApi service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BfdApiClient interface {
+	// Manage the overall server state
+	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Manage the peers of the bfd server
+	AddPeer(ctx context.Context, in *AddPeerRequest, opts ...grpc.CallOption) (*AddPeerResponse, error)
+	UpdatePeer(ctx context.Context, in *UpdatePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	DeletePeer(ctx context.Context, in *DeletePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListPeer(ctx context.Context, in *ListPeerRequest, opts ...grpc.CallOption) (BfdApi_ListPeerClient, error)
+	// Manage the state of the peers of the bfd server
+	GetPeerState(ctx context.Context, in *GetPeerStateRequest, opts ...grpc.CallOption) (*PeerStateResponse, error)
+	MonitorPeer(ctx context.Context, in *MonitorPeerRequest, opts ...grpc.CallOption) (BfdApi_MonitorPeerClient, error)
+	DisablePeer(ctx context.Context, in *DisablePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	EnablePeer(ctx context.Context, in *EnablePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	PollPeer(ctx context.Context, in *PollPeerRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Manage the authentication key chains
+	AddKeyChain(ctx context.Context, in *AddKeyChainRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	DeleteKeyChain(ctx context.Context, in *DeleteKeyChainRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListKeyChain(ctx context.Context, in *ListKeyChainRequest, opts ...grpc.CallOption) (BfdApi_ListKeyChainClient, error)
+	AddKey(ctx context.Context, in *AddKeyRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	DeleteKey(ctx context.Context, in *DeleteKeyRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	// Manage Seamless BFD (RFC7880), reflector discriminators and initiators
+	AddSbfdReflector(ctx context.Context, in *AddSbfdReflectorRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	DeleteSbfdReflector(ctx context.Context, in *DeleteSbfdReflectorRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListSbfdReflector(ctx context.Context, in *ListSbfdReflectorRequest, opts ...grpc.CallOption) (BfdApi_ListSbfdReflectorClient, error)
+	AddSbfdInitiator(ctx context.Context, in *AddSbfdInitiatorRequest, opts ...grpc.CallOption) (*AddSbfdInitiatorResponse, error)
+	DeleteSbfdInitiator(ctx context.Context, in *DeleteSbfdInitiatorRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListSbfdInitiator(ctx context.Context, in *ListSbfdInitiatorRequest, opts ...grpc.CallOption) (BfdApi_ListSbfdInitiatorClient, error)
+	// Manage micro BFD (RFC7130), one session per member link of a LAG
+	AddLag(ctx context.Context, in *AddLagRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	DeleteLag(ctx context.Context, in *DeleteLagRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ListLag(ctx context.Context, in *ListLagRequest, opts ...grpc.CallOption) (BfdApi_ListLagClient, error)
+	// Inspect the listeners and their discarded packets
+	ListListener(ctx context.Context, in *ListListenerRequest, opts ...grpc.CallOption) (BfdApi_ListListenerClient, error)
+	ListInboundQueue(ctx context.Context, in *ListInboundQueueRequest, opts ...grpc.CallOption) (BfdApi_ListInboundQueueClient, error)
+	ListDynamicRange(ctx context.Context, in *ListDynamicRangeRequest, opts ...grpc.CallOption) (BfdApi_ListDynamicRangeClient, error)
+}
+
+type bfdApiClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBfdApiClient(cc *grpc.ClientConn) BfdApiClient {
+	return &bfdApiClient{cc}
+}
+
+func (c *bfdApiClient) Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/Start", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/Stop", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) AddPeer(ctx context.Context, in *AddPeerRequest, opts ...grpc.CallOption) (*AddPeerResponse, error) {
+	out := new(AddPeerResponse)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/AddPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) UpdatePeer(ctx context.Context, in *UpdatePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/UpdatePeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) DeletePeer(ctx context.Context, in *DeletePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/DeletePeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) ListPeer(ctx context.Context, in *ListPeerRequest, opts ...grpc.CallOption) (BfdApi_ListPeerClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BfdApi_serviceDesc.Streams[0], "/api.BfdApi/ListPeer", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bfdApiListPeerClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BfdApi_ListPeerClient interface {
+	Recv() (*ListPeerResponse, error)
+	grpc.ClientStream
+}
+
+type bfdApiListPeerClient struct {
+	grpc.ClientStream
+}
+
+func (x *bfdApiListPeerClient) Recv() (*ListPeerResponse, error) {
+	m := new(ListPeerResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bfdApiClient) GetPeerState(ctx context.Context, in *GetPeerStateRequest, opts ...grpc.CallOption) (*PeerStateResponse, error) {
+	out := new(PeerStateResponse)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/GetPeerState", in, out, opts...)
+	if err != nil {
+		return nil, err
 	}
 	return out, nil
 }
 
-func (c *bfdApiClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+func (c *bfdApiClient) MonitorPeer(ctx context.Context, in *MonitorPeerRequest, opts ...grpc.CallOption) (BfdApi_MonitorPeerClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BfdApi_serviceDesc.Streams[1], "/api.BfdApi/MonitorPeer", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bfdApiMonitorPeerClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BfdApi_MonitorPeerClient interface {
+	Recv() (*PeerStateResponse, error)
+	grpc.ClientStream
+}
+
+type bfdApiMonitorPeerClient struct {
+	grpc.ClientStream
+}
+
+func (x *bfdApiMonitorPeerClient) Recv() (*PeerStateResponse, error) {
+	m := new(PeerStateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bfdApiClient) DisablePeer(ctx context.Context, in *DisablePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/api.BfdApi/Stop", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/DisablePeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) EnablePeer(ctx context.Context, in *EnablePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/EnablePeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) PollPeer(ctx context.Context, in *PollPeerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/PollPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) AddKeyChain(ctx context.Context, in *AddKeyChainRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/AddKeyChain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) DeleteKeyChain(ctx context.Context, in *DeleteKeyChainRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/DeleteKeyChain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) ListKeyChain(ctx context.Context, in *ListKeyChainRequest, opts ...grpc.CallOption) (BfdApi_ListKeyChainClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BfdApi_serviceDesc.Streams[2], "/api.BfdApi/ListKeyChain", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bfdApiListKeyChainClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BfdApi_ListKeyChainClient interface {
+	Recv() (*ListKeyChainResponse, error)
+	grpc.ClientStream
+}
+
+type bfdApiListKeyChainClient struct {
+	grpc.ClientStream
+}
+
+func (x *bfdApiListKeyChainClient) Recv() (*ListKeyChainResponse, error) {
+	m := new(ListKeyChainResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bfdApiClient) AddKey(ctx context.Context, in *AddKeyRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/AddKey", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *bfdApiClient) DeleteKey(ctx context.Context, in *DeleteKeyRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/DeleteKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) AddSbfdReflector(ctx context.Context, in *AddSbfdReflectorRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/AddSbfdReflector", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) DeleteSbfdReflector(ctx context.Context, in *DeleteSbfdReflectorRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/DeleteSbfdReflector", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) ListSbfdReflector(ctx context.Context, in *ListSbfdReflectorRequest, opts ...grpc.CallOption) (BfdApi_ListSbfdReflectorClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BfdApi_serviceDesc.Streams[3], "/api.BfdApi/ListSbfdReflector", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bfdApiListSbfdReflectorClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BfdApi_ListSbfdReflectorClient interface {
+	Recv() (*ListSbfdReflectorResponse, error)
+	grpc.ClientStream
+}
+
+type bfdApiListSbfdReflectorClient struct {
+	grpc.ClientStream
+}
+
+func (x *bfdApiListSbfdReflectorClient) Recv() (*ListSbfdReflectorResponse, error) {
+	m := new(ListSbfdReflectorResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bfdApiClient) AddSbfdInitiator(ctx context.Context, in *AddSbfdInitiatorRequest, opts ...grpc.CallOption) (*AddSbfdInitiatorResponse, error) {
+	out := new(AddSbfdInitiatorResponse)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/AddSbfdInitiator", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) DeleteSbfdInitiator(ctx context.Context, in *DeleteSbfdInitiatorRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/DeleteSbfdInitiator", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) ListSbfdInitiator(ctx context.Context, in *ListSbfdInitiatorRequest, opts ...grpc.CallOption) (BfdApi_ListSbfdInitiatorClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BfdApi_serviceDesc.Streams[4], "/api.BfdApi/ListSbfdInitiator", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bfdApiListSbfdInitiatorClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BfdApi_ListSbfdInitiatorClient interface {
+	Recv() (*ListSbfdInitiatorResponse, error)
+	grpc.ClientStream
+}
+
+type bfdApiListSbfdInitiatorClient struct {
+	grpc.ClientStream
+}
+
+func (x *bfdApiListSbfdInitiatorClient) Recv() (*ListSbfdInitiatorResponse, error) {
+	m := new(ListSbfdInitiatorResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bfdApiClient) AddLag(ctx context.Context, in *AddLagRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/AddLag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) DeleteLag(ctx context.Context, in *DeleteLagRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	out := new(empty.Empty)
+	err := c.cc.Invoke(ctx, "/api.BfdApi/DeleteLag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bfdApiClient) ListLag(ctx context.Context, in *ListLagRequest, opts ...grpc.CallOption) (BfdApi_ListLagClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BfdApi_serviceDesc.Streams[5], "/api.BfdApi/ListLag", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bfdApiListLagClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BfdApi_ListLagClient interface {
+	Recv() (*ListLagResponse, error)
+	grpc.ClientStream
+}
+
+type bfdApiListLagClient struct {
+	grpc.ClientStream
+}
+
+func (x *bfdApiListLagClient) Recv() (*ListLagResponse, error) {
+	m := new(ListLagResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *bfdApiClient) ListListener(ctx context.Context, in *ListListenerRequest, opts ...grpc.CallOption) (BfdApi_ListListenerClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BfdApi_serviceDesc.Streams[6], "/api.BfdApi/ListListener", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &bfdApiListListenerClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (c *bfdApiClient) AddPeer(ctx context.Context, in *AddPeerRequest, opts ...grpc.CallOption) (*AddPeerResponse, error) {
-	out := new(AddPeerResponse)
-	err := c.cc.Invoke(ctx, "/api.BfdApi/AddPeer", in, out, opts...)
-	if err != nil {
+type BfdApi_ListListenerClient interface {
+	Recv() (*ListListenerResponse, error)
+	grpc.ClientStream
+}
+
+type bfdApiListListenerClient struct {
+	grpc.ClientStream
+}
+
+func (x *bfdApiListListenerClient) Recv() (*ListListenerResponse, error) {
+	m := new(ListListenerResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *bfdApiClient) UpdatePeer(ctx context.Context, in *UpdatePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/api.BfdApi/UpdatePeer", in, out, opts...)
+func (c *bfdApiClient) ListInboundQueue(ctx context.Context, in *ListInboundQueueRequest, opts ...grpc.CallOption) (BfdApi_ListInboundQueueClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BfdApi_serviceDesc.Streams[7], "/api.BfdApi/ListInboundQueue", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &bfdApiListInboundQueueClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (c *bfdApiClient) DeletePeer(ctx context.Context, in *DeletePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/api.BfdApi/DeletePeer", in, out, opts...)
-	if err != nil {
+type BfdApi_ListInboundQueueClient interface {
+	Recv() (*ListInboundQueueResponse, error)
+	grpc.ClientStream
+}
+
+type bfdApiListInboundQueueClient struct {
+	grpc.ClientStream
+}
+
+func (x *bfdApiListInboundQueueClient) Recv() (*ListInboundQueueResponse, error) {
+	m := new(ListInboundQueueResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *bfdApiClient) ListPeer(ctx context.Context, in *ListPeerRequest, opts ...grpc.CallOption) (BfdApi_ListPeerClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_BfdApi_serviceDesc.Streams[0], "/api.BfdApi/ListPeer", opts...)
+func (c *bfdApiClient) ListDynamicRange(ctx context.Context, in *ListDynamicRangeRequest, opts ...grpc.CallOption) (BfdApi_ListDynamicRangeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BfdApi_serviceDesc.Streams[8], "/api.BfdApi/ListDynamicRange", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &bfdApiListPeerClient{stream}
+	x := &bfdApiListDynamicRangeClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -1035,289 +3267,597 @@ func (c *bfdApiClient) ListPeer(ctx context.Context, in *ListPeerRequest, opts .
 	return x, nil
 }
 
-type BfdApi_ListPeerClient interface {
-	Recv() (*ListPeerResponse, error)
+type BfdApi_ListDynamicRangeClient interface {
+	Recv() (*ListDynamicRangeResponse, error)
 	grpc.ClientStream
 }
 
-type bfdApiListPeerClient struct {
+type bfdApiListDynamicRangeClient struct {
 	grpc.ClientStream
 }
 
-func (x *bfdApiListPeerClient) Recv() (*ListPeerResponse, error) {
-	m := new(ListPeerResponse)
+func (x *bfdApiListDynamicRangeClient) Recv() (*ListDynamicRangeResponse, error) {
+	m := new(ListDynamicRangeResponse)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *bfdApiClient) GetPeerState(ctx context.Context, in *GetPeerStateRequest, opts ...grpc.CallOption) (*PeerStateResponse, error) {
-	out := new(PeerStateResponse)
-	err := c.cc.Invoke(ctx, "/api.BfdApi/GetPeerState", in, out, opts...)
-	if err != nil {
+// BfdApiServer is the server API for BfdApi service.
+type BfdApiServer interface {
+	// Manage the overall server state
+	Start(context.Context, *StartRequest) (*empty.Empty, error)
+	Stop(context.Context, *StopRequest) (*empty.Empty, error)
+	// Manage the peers of the bfd server
+	AddPeer(context.Context, *AddPeerRequest) (*AddPeerResponse, error)
+	UpdatePeer(context.Context, *UpdatePeerRequest) (*empty.Empty, error)
+	DeletePeer(context.Context, *DeletePeerRequest) (*empty.Empty, error)
+	ListPeer(*ListPeerRequest, BfdApi_ListPeerServer) error
+	// Manage the state of the peers of the bfd server
+	GetPeerState(context.Context, *GetPeerStateRequest) (*PeerStateResponse, error)
+	MonitorPeer(*MonitorPeerRequest, BfdApi_MonitorPeerServer) error
+	DisablePeer(context.Context, *DisablePeerRequest) (*empty.Empty, error)
+	EnablePeer(context.Context, *EnablePeerRequest) (*empty.Empty, error)
+	PollPeer(context.Context, *PollPeerRequest) (*empty.Empty, error)
+	// Manage the authentication key chains
+	AddKeyChain(context.Context, *AddKeyChainRequest) (*empty.Empty, error)
+	DeleteKeyChain(context.Context, *DeleteKeyChainRequest) (*empty.Empty, error)
+	ListKeyChain(*ListKeyChainRequest, BfdApi_ListKeyChainServer) error
+	AddKey(context.Context, *AddKeyRequest) (*empty.Empty, error)
+	DeleteKey(context.Context, *DeleteKeyRequest) (*empty.Empty, error)
+	// Manage Seamless BFD (RFC7880), reflector discriminators and initiators
+	AddSbfdReflector(context.Context, *AddSbfdReflectorRequest) (*empty.Empty, error)
+	DeleteSbfdReflector(context.Context, *DeleteSbfdReflectorRequest) (*empty.Empty, error)
+	ListSbfdReflector(*ListSbfdReflectorRequest, BfdApi_ListSbfdReflectorServer) error
+	AddSbfdInitiator(context.Context, *AddSbfdInitiatorRequest) (*AddSbfdInitiatorResponse, error)
+	DeleteSbfdInitiator(context.Context, *DeleteSbfdInitiatorRequest) (*empty.Empty, error)
+	ListSbfdInitiator(*ListSbfdInitiatorRequest, BfdApi_ListSbfdInitiatorServer) error
+	// Manage micro BFD (RFC7130), one session per member link of a LAG
+	AddLag(context.Context, *AddLagRequest) (*empty.Empty, error)
+	DeleteLag(context.Context, *DeleteLagRequest) (*empty.Empty, error)
+	ListLag(*ListLagRequest, BfdApi_ListLagServer) error
+	// Inspect the listeners and their discarded packets
+	ListListener(*ListListenerRequest, BfdApi_ListListenerServer) error
+	ListInboundQueue(*ListInboundQueueRequest, BfdApi_ListInboundQueueServer) error
+	ListDynamicRange(*ListDynamicRangeRequest, BfdApi_ListDynamicRangeServer) error
+}
+
+func RegisterBfdApiServer(s *grpc.Server, srv BfdApiServer) {
+	s.RegisterService(&_BfdApi_serviceDesc, srv)
+}
+
+func _BfdApi_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BfdApiServer).Start(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BfdApi/Start",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BfdApiServer).Start(ctx, req.(*StartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BfdApi_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BfdApiServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BfdApi/Stop",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BfdApiServer).Stop(ctx, req.(*StopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BfdApi_AddPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BfdApiServer).AddPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BfdApi/AddPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BfdApiServer).AddPeer(ctx, req.(*AddPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BfdApi_UpdatePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BfdApiServer).UpdatePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BfdApi/UpdatePeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BfdApiServer).UpdatePeer(ctx, req.(*UpdatePeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BfdApi_DeletePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BfdApiServer).DeletePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BfdApi/DeletePeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BfdApiServer).DeletePeer(ctx, req.(*DeletePeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BfdApi_ListPeer_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPeerRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BfdApiServer).ListPeer(m, &bfdApiListPeerServer{stream})
+}
+
+type BfdApi_ListPeerServer interface {
+	Send(*ListPeerResponse) error
+	grpc.ServerStream
+}
+
+type bfdApiListPeerServer struct {
+	grpc.ServerStream
+}
+
+func (x *bfdApiListPeerServer) Send(m *ListPeerResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BfdApi_GetPeerState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeerStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BfdApiServer).GetPeerState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BfdApi/GetPeerState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BfdApiServer).GetPeerState(ctx, req.(*GetPeerStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BfdApi_MonitorPeer_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MonitorPeerRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BfdApiServer).MonitorPeer(m, &bfdApiMonitorPeerServer{stream})
+}
+
+type BfdApi_MonitorPeerServer interface {
+	Send(*PeerStateResponse) error
+	grpc.ServerStream
+}
+
+type bfdApiMonitorPeerServer struct {
+	grpc.ServerStream
+}
+
+func (x *bfdApiMonitorPeerServer) Send(m *PeerStateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BfdApi_DisablePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisablePeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BfdApiServer).DisablePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BfdApi/DisablePeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BfdApiServer).DisablePeer(ctx, req.(*DisablePeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BfdApi_EnablePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnablePeerRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BfdApiServer).EnablePeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BfdApi/EnablePeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BfdApiServer).EnablePeer(ctx, req.(*EnablePeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bfdApiClient) MonitorPeer(ctx context.Context, in *MonitorPeerRequest, opts ...grpc.CallOption) (BfdApi_MonitorPeerClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_BfdApi_serviceDesc.Streams[1], "/api.BfdApi/MonitorPeer", opts...)
-	if err != nil {
+func _BfdApi_PollPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PollPeerRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &bfdApiMonitorPeerClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(BfdApiServer).PollPeer(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BfdApi/PollPeer",
 	}
-	return x, nil
-}
-
-type BfdApi_MonitorPeerClient interface {
-	Recv() (*PeerStateResponse, error)
-	grpc.ClientStream
-}
-
-type bfdApiMonitorPeerClient struct {
-	grpc.ClientStream
-}
-
-func (x *bfdApiMonitorPeerClient) Recv() (*PeerStateResponse, error) {
-	m := new(PeerStateResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BfdApiServer).PollPeer(ctx, req.(*PollPeerRequest))
 	}
-	return m, nil
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bfdApiClient) DisablePeer(ctx context.Context, in *DisablePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/api.BfdApi/DisablePeer", in, out, opts...)
-	if err != nil {
+func _BfdApi_AddKeyChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddKeyChainRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *bfdApiClient) EnablePeer(ctx context.Context, in *EnablePeerRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
-	err := c.cc.Invoke(ctx, "/api.BfdApi/EnablePeer", in, out, opts...)
-	if err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(BfdApiServer).AddKeyChain(ctx, in)
 	}
-	return out, nil
-}
-
-// BfdApiServer is the server API for BfdApi service.
-type BfdApiServer interface {
-	// Manage the overall server state
-	Start(context.Context, *StartRequest) (*empty.Empty, error)
-	Stop(context.Context, *StopRequest) (*empty.Empty, error)
-	// Manage the peers of the bfd server
-	AddPeer(context.Context, *AddPeerRequest) (*AddPeerResponse, error)
-	UpdatePeer(context.Context, *UpdatePeerRequest) (*empty.Empty, error)
-	DeletePeer(context.Context, *DeletePeerRequest) (*empty.Empty, error)
-	ListPeer(*ListPeerRequest, BfdApi_ListPeerServer) error
-	// Manage the state of the peers of the bfd server
-	GetPeerState(context.Context, *GetPeerStateRequest) (*PeerStateResponse, error)
-	MonitorPeer(*MonitorPeerRequest, BfdApi_MonitorPeerServer) error
-	DisablePeer(context.Context, *DisablePeerRequest) (*empty.Empty, error)
-	EnablePeer(context.Context, *EnablePeerRequest) (*empty.Empty, error)
-}
-
-func RegisterBfdApiServer(s *grpc.Server, srv BfdApiServer) {
-	s.RegisterService(&_BfdApi_serviceDesc, srv)
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BfdApi/AddKeyChain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BfdApiServer).AddKeyChain(ctx, req.(*AddKeyChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _BfdApi_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StartRequest)
+func _BfdApi_DeleteKeyChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteKeyChainRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BfdApiServer).Start(ctx, in)
+		return srv.(BfdApiServer).DeleteKeyChain(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.BfdApi/Start",
+		FullMethod: "/api.BfdApi/DeleteKeyChain",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BfdApiServer).Start(ctx, req.(*StartRequest))
+		return srv.(BfdApiServer).DeleteKeyChain(ctx, req.(*DeleteKeyChainRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BfdApi_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StopRequest)
+func _BfdApi_ListKeyChain_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListKeyChainRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BfdApiServer).ListKeyChain(m, &bfdApiListKeyChainServer{stream})
+}
+
+type BfdApi_ListKeyChainServer interface {
+	Send(*ListKeyChainResponse) error
+	grpc.ServerStream
+}
+
+type bfdApiListKeyChainServer struct {
+	grpc.ServerStream
+}
+
+func (x *bfdApiListKeyChainServer) Send(m *ListKeyChainResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BfdApi_AddKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BfdApiServer).Stop(ctx, in)
+		return srv.(BfdApiServer).AddKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.BfdApi/Stop",
+		FullMethod: "/api.BfdApi/AddKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BfdApiServer).Stop(ctx, req.(*StopRequest))
+		return srv.(BfdApiServer).AddKey(ctx, req.(*AddKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BfdApi_AddPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddPeerRequest)
+func _BfdApi_DeleteKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BfdApiServer).AddPeer(ctx, in)
+		return srv.(BfdApiServer).DeleteKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.BfdApi/AddPeer",
+		FullMethod: "/api.BfdApi/DeleteKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BfdApiServer).AddPeer(ctx, req.(*AddPeerRequest))
+		return srv.(BfdApiServer).DeleteKey(ctx, req.(*DeleteKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BfdApi_UpdatePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdatePeerRequest)
+func _BfdApi_AddSbfdReflector_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSbfdReflectorRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BfdApiServer).UpdatePeer(ctx, in)
+		return srv.(BfdApiServer).AddSbfdReflector(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.BfdApi/UpdatePeer",
+		FullMethod: "/api.BfdApi/AddSbfdReflector",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BfdApiServer).UpdatePeer(ctx, req.(*UpdatePeerRequest))
+		return srv.(BfdApiServer).AddSbfdReflector(ctx, req.(*AddSbfdReflectorRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BfdApi_DeletePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeletePeerRequest)
+func _BfdApi_DeleteSbfdReflector_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSbfdReflectorRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BfdApiServer).DeletePeer(ctx, in)
+		return srv.(BfdApiServer).DeleteSbfdReflector(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.BfdApi/DeletePeer",
+		FullMethod: "/api.BfdApi/DeleteSbfdReflector",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BfdApiServer).DeletePeer(ctx, req.(*DeletePeerRequest))
+		return srv.(BfdApiServer).DeleteSbfdReflector(ctx, req.(*DeleteSbfdReflectorRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BfdApi_ListPeer_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ListPeerRequest)
+func _BfdApi_ListSbfdReflector_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListSbfdReflectorRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(BfdApiServer).ListPeer(m, &bfdApiListPeerServer{stream})
+	return srv.(BfdApiServer).ListSbfdReflector(m, &bfdApiListSbfdReflectorServer{stream})
 }
 
-type BfdApi_ListPeerServer interface {
-	Send(*ListPeerResponse) error
+type BfdApi_ListSbfdReflectorServer interface {
+	Send(*ListSbfdReflectorResponse) error
 	grpc.ServerStream
 }
 
-type bfdApiListPeerServer struct {
+type bfdApiListSbfdReflectorServer struct {
 	grpc.ServerStream
 }
 
-func (x *bfdApiListPeerServer) Send(m *ListPeerResponse) error {
+func (x *bfdApiListSbfdReflectorServer) Send(m *ListSbfdReflectorResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _BfdApi_GetPeerState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetPeerStateRequest)
+func _BfdApi_AddSbfdInitiator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSbfdInitiatorRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BfdApiServer).GetPeerState(ctx, in)
+		return srv.(BfdApiServer).AddSbfdInitiator(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.BfdApi/GetPeerState",
+		FullMethod: "/api.BfdApi/AddSbfdInitiator",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BfdApiServer).GetPeerState(ctx, req.(*GetPeerStateRequest))
+		return srv.(BfdApiServer).AddSbfdInitiator(ctx, req.(*AddSbfdInitiatorRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BfdApi_MonitorPeer_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(MonitorPeerRequest)
+func _BfdApi_DeleteSbfdInitiator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSbfdInitiatorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BfdApiServer).DeleteSbfdInitiator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.BfdApi/DeleteSbfdInitiator",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BfdApiServer).DeleteSbfdInitiator(ctx, req.(*DeleteSbfdInitiatorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BfdApi_ListSbfdInitiator_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListSbfdInitiatorRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(BfdApiServer).MonitorPeer(m, &bfdApiMonitorPeerServer{stream})
+	return srv.(BfdApiServer).ListSbfdInitiator(m, &bfdApiListSbfdInitiatorServer{stream})
 }
 
-type BfdApi_MonitorPeerServer interface {
-	Send(*PeerStateResponse) error
+type BfdApi_ListSbfdInitiatorServer interface {
+	Send(*ListSbfdInitiatorResponse) error
 	grpc.ServerStream
 }
 
-type bfdApiMonitorPeerServer struct {
+type bfdApiListSbfdInitiatorServer struct {
 	grpc.ServerStream
 }
 
-func (x *bfdApiMonitorPeerServer) Send(m *PeerStateResponse) error {
+func (x *bfdApiListSbfdInitiatorServer) Send(m *ListSbfdInitiatorResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _BfdApi_DisablePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DisablePeerRequest)
+func _BfdApi_AddLag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddLagRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BfdApiServer).DisablePeer(ctx, in)
+		return srv.(BfdApiServer).AddLag(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.BfdApi/DisablePeer",
+		FullMethod: "/api.BfdApi/AddLag",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BfdApiServer).DisablePeer(ctx, req.(*DisablePeerRequest))
+		return srv.(BfdApiServer).AddLag(ctx, req.(*AddLagRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BfdApi_EnablePeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EnablePeerRequest)
+func _BfdApi_DeleteLag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLagRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BfdApiServer).EnablePeer(ctx, in)
+		return srv.(BfdApiServer).DeleteLag(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.BfdApi/EnablePeer",
+		FullMethod: "/api.BfdApi/DeleteLag",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BfdApiServer).EnablePeer(ctx, req.(*EnablePeerRequest))
+		return srv.(BfdApiServer).DeleteLag(ctx, req.(*DeleteLagRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BfdApi_ListLag_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListLagRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BfdApiServer).ListLag(m, &bfdApiListLagServer{stream})
+}
+
+type BfdApi_ListLagServer interface {
+	Send(*ListLagResponse) error
+	grpc.ServerStream
+}
+
+type bfdApiListLagServer struct {
+	grpc.ServerStream
+}
+
+func (x *bfdApiListLagServer) Send(m *ListLagResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BfdApi_ListListener_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListListenerRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BfdApiServer).ListListener(m, &bfdApiListListenerServer{stream})
+}
+
+type BfdApi_ListListenerServer interface {
+	Send(*ListListenerResponse) error
+	grpc.ServerStream
+}
+
+type bfdApiListListenerServer struct {
+	grpc.ServerStream
+}
+
+func (x *bfdApiListListenerServer) Send(m *ListListenerResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BfdApi_ListInboundQueue_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListInboundQueueRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BfdApiServer).ListInboundQueue(m, &bfdApiListInboundQueueServer{stream})
+}
+
+type BfdApi_ListInboundQueueServer interface {
+	Send(*ListInboundQueueResponse) error
+	grpc.ServerStream
+}
+
+type bfdApiListInboundQueueServer struct {
+	grpc.ServerStream
+}
+
+func (x *bfdApiListInboundQueueServer) Send(m *ListInboundQueueResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BfdApi_ListDynamicRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListDynamicRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BfdApiServer).ListDynamicRange(m, &bfdApiListDynamicRangeServer{stream})
+}
+
+type BfdApi_ListDynamicRangeServer interface {
+	Send(*ListDynamicRangeResponse) error
+	grpc.ServerStream
+}
+
+type bfdApiListDynamicRangeServer struct {
+	grpc.ServerStream
+}
+
+func (x *bfdApiListDynamicRangeServer) Send(m *ListDynamicRangeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _BfdApi_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "api.BfdApi",
 	HandlerType: (*BfdApiServer)(nil),
@@ -1354,6 +3894,50 @@ var _BfdApi_serviceDesc = grpc.ServiceDesc{
 			MethodName: "EnablePeer",
 			Handler:    _BfdApi_EnablePeer_Handler,
 		},
+		{
+			MethodName: "PollPeer",
+			Handler:    _BfdApi_PollPeer_Handler,
+		},
+		{
+			MethodName: "AddKeyChain",
+			Handler:    _BfdApi_AddKeyChain_Handler,
+		},
+		{
+			MethodName: "DeleteKeyChain",
+			Handler:    _BfdApi_DeleteKeyChain_Handler,
+		},
+		{
+			MethodName: "AddKey",
+			Handler:    _BfdApi_AddKey_Handler,
+		},
+		{
+			MethodName: "DeleteKey",
+			Handler:    _BfdApi_DeleteKey_Handler,
+		},
+		{
+			MethodName: "AddSbfdReflector",
+			Handler:    _BfdApi_AddSbfdReflector_Handler,
+		},
+		{
+			MethodName: "DeleteSbfdReflector",
+			Handler:    _BfdApi_DeleteSbfdReflector_Handler,
+		},
+		{
+			MethodName: "AddSbfdInitiator",
+			Handler:    _BfdApi_AddSbfdInitiator_Handler,
+		},
+		{
+			MethodName: "DeleteSbfdInitiator",
+			Handler:    _BfdApi_DeleteSbfdInitiator_Handler,
+		},
+		{
+			MethodName: "AddLag",
+			Handler:    _BfdApi_AddLag_Handler,
+		},
+		{
+			MethodName: "DeleteLag",
+			Handler:    _BfdApi_DeleteLag_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1366,6 +3950,41 @@ var _BfdApi_serviceDesc = grpc.ServiceDesc{
 			Handler:       _BfdApi_MonitorPeer_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ListKeyChain",
+			Handler:       _BfdApi_ListKeyChain_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListSbfdReflector",
+			Handler:       _BfdApi_ListSbfdReflector_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListSbfdInitiator",
+			Handler:       _BfdApi_ListSbfdInitiator_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListLag",
+			Handler:       _BfdApi_ListLag_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListListener",
+			Handler:       _BfdApi_ListListener_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListInboundQueue",
+			Handler:       _BfdApi_ListInboundQueue_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListDynamicRange",
+			Handler:       _BfdApi_ListDynamicRange_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api.proto",
 }
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+)
+
+var ErrUnknownAuthenticationType = errors.New("Unknown authentication type, should be one of None, SimplePassword, KeyedMD5, MeticulousKeyedMD5, KeyedSHA1, MeticulousKeyedSHA1, MeticulousKeyedHMACSHA256")
+
+// AuthenticationTypeNames maps the names used by the cli and the config file to the api type
+var AuthenticationTypeNames = map[string]AuthenticationType{
+	"None":                      AuthenticationType_NONE,
+	"SimplePassword":            AuthenticationType_SIMPLE_PASSWORD,
+	"KeyedMD5":                  AuthenticationType_KEYED_MD5,
+	"MeticulousKeyedMD5":        AuthenticationType_METICULOUS_KEYED_MD5,
+	"KeyedSHA1":                 AuthenticationType_KEYED_SHA1,
+	"MeticulousKeyedSHA1":       AuthenticationType_METICULOUS_KEYED_SHA1,
+	"MeticulousKeyedHMACSHA256": AuthenticationType_METICULOUS_KEYED_HMAC_SHA256,
+}
+
+func ParseAuthenticationType(name string) (AuthenticationType, error) {
+	if name == "" {
+		return AuthenticationType_NONE, nil
+	}
+
+	authType, ok := AuthenticationTypeNames[name]
+
+	if !ok {
+		return AuthenticationType_NONE, ErrUnknownAuthenticationType
+	}
+
+	return authType, nil
+}
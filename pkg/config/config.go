@@ -1,12 +1,23 @@
 package config
 
 import (
+	"time"
 )
 
 
 type Config struct {
 	Listen []string       `yaml:"listen"`
+	ListenMultiHop []string `yaml:"listenMultiHop"`
+	ListenVxlan []string  `yaml:"listenVxlan"`
+	KeyChains map[string][]Key `yaml:"keyChains"`
+	Echo *Echo                `yaml:"echo"`
 	Peers map[string]Peer `yaml:"peers"`
+	Dynamic []DynamicRange `yaml:"dynamic"`
+	Unsolicited map[string]Unsolicited `yaml:"unsolicited"`
+	Sbfd *Sbfd                `yaml:"sbfd"`
+	Lags map[string]Lag   `yaml:"lags"`
+	Batch *Batch          `yaml:"batch"`
+	Inbound *Inbound      `yaml:"inbound"`
 }
 
 type Peer struct {
@@ -14,4 +25,97 @@ type Peer struct {
 	Port     			int16  `yaml:"port"`
 	Interval 			int    `yaml:"interval"`  			// target interval in ms
 	DetectionMultiplier int    `yaml:"detectionMultiplier"`
-}
\ No newline at end of file
+	Authentication		*Authentication `yaml:"authentication"`
+	DemandMode			bool   `yaml:"demandMode"`
+	DemandPollInterval	int    `yaml:"demandPollInterval"`	// interval of the poll sequences in demand mode in ms
+	EchoInterval		int    `yaml:"echoInterval"`			// interval of the echo packets in ms, 0 = disabled
+	EchoReflector		bool   `yaml:"echoReflector"`		// send the echo packets to the reflector of a bfdd peer
+	MultiHop			bool   `yaml:"multiHop"`
+	LocalAddress		string `yaml:"localAddress"`			// source address, matched against the destination of received packets
+	MinTTL				uint8  `yaml:"minTTL"`				// minimum TTL of received multi hop packets
+	Passive				bool   `yaml:"passive"`				// don't send packets until the peer sent one
+	Vxlan				bool   `yaml:"vxlan"`				// send the packets inside VXLAN to the VTEP
+	Vni					uint32 `yaml:"vni"`
+	PadToSize			int    `yaml:"padToSize"`			// pads the packets to this size with DF set, 0 = disabled
+}
+
+// sessions are created for unknown peers within the prefix
+type DynamicRange struct {
+	Prefix				string `yaml:"prefix"`
+	IdleTimeout			int    `yaml:"idleTimeout"`			// seconds without packets until the session is removed, 0 = 300
+	MaxSessions			int    `yaml:"maxSessions"`			// sessions created at most, 0 = unlimited
+	Peer				Peer   `yaml:"peer"`					// settings of the created sessions
+}
+
+// unsolicited BFD (RFC9468), keyed by interface
+type Unsolicited struct {
+	Prefixes			[]string `yaml:"prefixes"`			// permitted remotes, empty = any
+	IdleTimeout			int      `yaml:"idleTimeout"`		// seconds without packets until the session is removed, 0 = 300
+	Peer				Peer     `yaml:"peer"`				// settings of the created sessions
+}
+
+type Sbfd struct {
+	Reflector			*SbfdReflector `yaml:"reflector"`
+	Initiators			map[string]SbfdInitiator `yaml:"initiators"`	// keyed by name
+}
+
+type SbfdReflector struct {
+	Listen				[]string `yaml:"listen"`				// optional reflectors for peers using echoReflector
+	Discriminators		[]uint32 `yaml:"discriminators"`
+	RequiredMinRxInterval int    `yaml:"requiredMinRxInterval"`	// minimum interval between received packets in ms
+}
+
+type SbfdInitiator struct {
+	Address				string `yaml:"address"`
+	RemoteDiscriminator	uint32 `yaml:"remoteDiscriminator"`	// reflector discriminator of the target
+	Interval			int    `yaml:"interval"`				// target interval in ms
+	DetectionMultiplier int    `yaml:"detectionMultiplier"`
+	LocalAddress		string `yaml:"localAddress"`
+}
+
+// micro BFD sessions on the members of a LAG, keyed by name
+type Lag struct {
+	Address				string   `yaml:"address"`			// address of the remote system
+	Members				[]string `yaml:"members"`			// member interfaces
+	MinLinks			int      `yaml:"minLinks"`			// members that need to be up for the LAG to be up, 0 = 1
+	Interval			int      `yaml:"interval"`			// target interval in ms
+	DetectionMultiplier int      `yaml:"detectionMultiplier"`
+	Authentication		*Authentication `yaml:"authentication"`
+	LocalAddress		string   `yaml:"localAddress"`
+}
+
+type Echo struct {
+	Listen				[]string `yaml:"listen"`
+	RequiredMinRxInterval int    `yaml:"requiredMinRxInterval"`	// minimum interval between received echo packets in ms
+}
+
+type Authentication struct {
+	Type				string `yaml:"type"`				// None, SimplePassword, KeyedMD5, MeticulousKeyedMD5, KeyedSHA1, MeticulousKeyedSHA1 or MeticulousKeyedHMACSHA256
+	KeyId				uint8  `yaml:"keyId"`
+	Password			string `yaml:"password"`			// hex encoded if it starts with 0x
+	KeyChain			string `yaml:"keyChain"`			// replaces keyId and password if set
+	Optimized			bool   `yaml:"optimized"`			// only packets changing the session are fully authenticated
+}
+
+// lifetimes are RFC3339 timestamps, if not set they are unbounded
+type Key struct {
+	Id					uint8     `yaml:"id"`
+	Password			string    `yaml:"password"`			// hex encoded if it starts with 0x
+	SendStart			time.Time `yaml:"sendStart"`
+	SendEnd				time.Time `yaml:"sendEnd"`
+	AcceptStart			time.Time `yaml:"acceptStart"`
+	AcceptEnd			time.Time `yaml:"acceptEnd"`
+}
+
+// batched socket I/O, several packets are read / written with a single syscall (recvmmsg / sendmmsg)
+type Batch struct {
+	Size				int    `yaml:"size"`				// packets per syscall, 0 = disabled
+	Sockets				int    `yaml:"sockets"`			// sockets per listener sharing the port (SO_REUSEPORT), 0 = 1
+	SharedSocket		bool   `yaml:"sharedSocket"`	// the sessions send through a shared socket, they share its source port
+}
+
+// workers handling the received packets, the sessions are spread over them
+type Inbound struct {
+	Workers				int    `yaml:"workers"`			// 0 = number of CPUs
+	QueueLength			int    `yaml:"queueLength"`		// packets queued per worker before they are dropped, 0 = 1024
+}
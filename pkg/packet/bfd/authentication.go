@@ -0,0 +1,300 @@
+package bfd
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+)
+
+const (
+	HMAC_SHA256_AUTH_LENGTH = 40 // type, length, key id, reserved, sequence number + 32 bytes digest
+	HMAC_SHA256_KEY_LENGTH  = 64 // HMAC accepts any key, longer keys are hashed to the block size anyway
+)
+
+// AuthenticationProvider creates the authentication sections of one authentication type
+type AuthenticationProvider interface {
+	GetAuthenticationType() AuthenticationType
+
+	// NewHeader returns the section for a packet signed with key, the sequence number is ignored by
+	// types without one. ControlPacket.DecodeFromBytes uses it with zero values to parse received sections.
+	NewHeader(keyId int8, key []byte, sequence uint32) AuthenticationHeader
+
+	// MaxKeyLength returns the length of the longest key the type can use
+	MaxKeyLength() int
+
+	// IsMeticulous returns true if the sequence number has to be incremented on every packet
+	IsMeticulous() bool
+}
+
+// SequencedAuthenticationHeader is implemented by authentication headers that carry a sequence number
+type SequencedAuthenticationHeader interface {
+	AuthenticationHeader
+
+	GetSequenceNumber() uint32
+}
+
+var authenticationProviders = map[AuthenticationType]AuthenticationProvider{}
+
+func init() {
+	RegisterAuthenticationProvider(simplePasswordProvider{})
+	RegisterAuthenticationProvider(keyedMD5Provider{KeyedMD5})
+	RegisterAuthenticationProvider(keyedMD5Provider{MeticulousKeyedMD5})
+	RegisterAuthenticationProvider(keyedSHA1Provider{KeyedSHA1})
+	RegisterAuthenticationProvider(keyedSHA1Provider{MeticulousKeyedSHA1})
+	RegisterAuthenticationProvider(hmacSHA256Provider{MeticulousKeyedHMACSHA256})
+	RegisterAuthenticationProvider(hmacSHA256Provider{MeticulousKeyedSecureSequence})
+}
+
+// RegisterAuthenticationProvider adds or replaces the provider of an authentication type,
+// it's not safe for concurrent use and should be called during initialization
+func RegisterAuthenticationProvider(provider AuthenticationProvider) {
+	authenticationProviders[provider.GetAuthenticationType()] = provider
+}
+
+// GetAuthenticationProvider returns the provider of an authentication type
+func GetAuthenticationProvider(authType AuthenticationType) (AuthenticationProvider, error) {
+	provider, ok := authenticationProviders[authType]
+
+	if !ok {
+		return nil, ErrInvalidAuthenticationType
+	}
+
+	return provider, nil
+}
+
+type simplePasswordProvider struct{}
+
+func (simplePasswordProvider) GetAuthenticationType() AuthenticationType {
+	return SimplePassword
+}
+
+func (simplePasswordProvider) NewHeader(keyId int8, key []byte, sequence uint32) AuthenticationHeader {
+	return &SimplePasswordHeader{
+		AuthKeyId: keyId,
+		Password:  string(key),
+	}
+}
+
+func (simplePasswordProvider) MaxKeyLength() int {
+	return MD5_KEY_LENGTH
+}
+
+func (simplePasswordProvider) IsMeticulous() bool {
+	return false
+}
+
+type keyedMD5Provider struct {
+	authType AuthenticationType
+}
+
+func (p keyedMD5Provider) GetAuthenticationType() AuthenticationType {
+	return p.authType
+}
+
+func (p keyedMD5Provider) NewHeader(keyId int8, key []byte, sequence uint32) AuthenticationHeader {
+	return &KeyedMD5Header{
+		AuthType:       p.authType,
+		AuthKeyId:      keyId,
+		SequenceNumber: sequence,
+		AuthKey:        key,
+	}
+}
+
+func (p keyedMD5Provider) MaxKeyLength() int {
+	return MD5_KEY_LENGTH
+}
+
+func (p keyedMD5Provider) IsMeticulous() bool {
+	return p.authType == MeticulousKeyedMD5
+}
+
+type keyedSHA1Provider struct {
+	authType AuthenticationType
+}
+
+func (p keyedSHA1Provider) GetAuthenticationType() AuthenticationType {
+	return p.authType
+}
+
+func (p keyedSHA1Provider) NewHeader(keyId int8, key []byte, sequence uint32) AuthenticationHeader {
+	return &KeyedSHA1Header{
+		AuthType:       p.authType,
+		AuthKeyId:      keyId,
+		SequenceNumber: sequence,
+		AuthKey:        key,
+	}
+}
+
+func (p keyedSHA1Provider) MaxKeyLength() int {
+	return SHA1_KEY_LENGTH
+}
+
+func (p keyedSHA1Provider) IsMeticulous() bool {
+	return p.authType == MeticulousKeyedSHA1
+}
+
+type hmacSHA256Provider struct {
+	authType AuthenticationType
+}
+
+func (p hmacSHA256Provider) GetAuthenticationType() AuthenticationType {
+	return p.authType
+}
+
+func (p hmacSHA256Provider) NewHeader(keyId int8, key []byte, sequence uint32) AuthenticationHeader {
+	return &HMACSHA256Header{
+		AuthType:       p.authType,
+		AuthKeyId:      keyId,
+		SequenceNumber: sequence,
+		AuthKey:        key,
+	}
+}
+
+func (p hmacSHA256Provider) MaxKeyLength() int {
+	return HMAC_SHA256_KEY_LENGTH
+}
+
+func (p hmacSHA256Provider) IsMeticulous() bool {
+	return true
+}
+
+/*
+	Meticulous Keyed HMAC-SHA256 uses the layout of Keyed SHA1 with a 32 byte
+	digest. Unlike Keyed MD5 / SHA1 the key is never part of the hashed data,
+	the digest is the HMAC-SHA256 of the packet with the digest field set to
+	zero.
+
+	Meticulous Keyed Secure Sequence (optimized authentication) has the same
+	layout, but the digest only covers the sequence number and both
+	discriminators:
+
+	  HMAC-SHA256(key, Sequence Number | My Discriminator | Your Discriminator)
+
+	It doesn't depend on the rest of the packet and can be calculated ahead of
+	time, so it's only accepted for packets that don't change the session.
+*/
+
+type HMACSHA256Header struct {
+	AuthType AuthenticationType // MeticulousKeyedHMACSHA256 or MeticulousKeyedSecureSequence
+	// Length int8 = inferred
+	AuthKeyId      int8
+	SequenceNumber uint32
+	AuthKey        []byte // the key on transmit (it's never sent) and the digest on receipt
+}
+
+func (s *HMACSHA256Header) IsValid(key []byte, packet []byte) bool {
+	if len(key) < 1 || len(key) > HMAC_SHA256_KEY_LENGTH || len(packet) < MINIMUM_SIZE+HMAC_SHA256_AUTH_LENGTH {
+		return false
+	}
+
+	received := packet[MINIMUM_SIZE+8 : MINIMUM_SIZE+HMAC_SHA256_AUTH_LENGTH]
+	digest := hmacSHA256Digest(s.AuthType, key, packet)
+
+	return subtle.ConstantTimeCompare(digest[:], received) == 1
+}
+
+func (s *HMACSHA256Header) UnmarshalBinary(buf []byte) error {
+	s.AuthKey = nil
+
+	return s.DecodeFromBytes(buf)
+}
+
+// DecodeFromBytes parses the section into s, the storage of AuthKey is reused for the digest
+func (s *HMACSHA256Header) DecodeFromBytes(buf []byte) error {
+	authType, keyId, sequence, digest, err := decodeKeyed(buf, MeticulousKeyedHMACSHA256, MeticulousKeyedSecureSequence, HMAC_SHA256_AUTH_LENGTH, s.AuthKey)
+
+	if err != nil {
+		return err
+	}
+
+	s.AuthType = authType
+	s.AuthKeyId = keyId
+	s.SequenceNumber = sequence
+	s.AuthKey = digest
+
+	return nil
+}
+
+func (s *HMACSHA256Header) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, HMAC_SHA256_AUTH_LENGTH)
+
+	if _, err := s.SerializeTo(buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func (s *HMACSHA256Header) SerializeTo(buf []byte) (int, error) {
+	if s.AuthType != MeticulousKeyedHMACSHA256 && s.AuthType != MeticulousKeyedSecureSequence {
+		return 0, ErrInvalidAuthenticationType
+	}
+
+	if len(s.AuthKey) < 1 || len(s.AuthKey) > HMAC_SHA256_KEY_LENGTH {
+		return 0, ErrKeyInvalidLength
+	}
+
+	if len(buf) < HMAC_SHA256_AUTH_LENGTH {
+		return 0, ErrBufferTooSmall
+	}
+
+	buf[0] = byte(s.AuthType)
+	buf[1] = byte(HMAC_SHA256_AUTH_LENGTH)
+	buf[2] = byte(s.AuthKeyId)
+	buf[3] = 0
+	binary.BigEndian.PutUint32(buf[4:], s.SequenceNumber)
+
+	// the digest is zero until Sign is called
+	zero(buf[8:HMAC_SHA256_AUTH_LENGTH])
+
+	return HMAC_SHA256_AUTH_LENGTH, nil
+}
+
+// Sign sets the digest field to the HMAC-SHA256 of the packet
+func (s *HMACSHA256Header) Sign(packet []byte) {
+	digest := hmacSHA256Digest(s.AuthType, s.AuthKey, packet)
+
+	copy(packet[MINIMUM_SIZE+8:MINIMUM_SIZE+HMAC_SHA256_AUTH_LENGTH], digest[:])
+}
+
+func (s *HMACSHA256Header) GetAuthenticationType() AuthenticationType {
+	return s.AuthType
+}
+
+func (s *HMACSHA256Header) GetAuthKeyId() int8 {
+	return s.AuthKeyId
+}
+
+func (s *HMACSHA256Header) GetSequenceNumber() uint32 {
+	return s.SequenceNumber
+}
+
+// hmacSHA256Digest calculates the HMAC (RFC 2104) of a marshalled packet with the digest field taken
+// as zero. The key fits into a block and a packet into MAXIMUM_LENGTH, so no state has to be allocated.
+func hmacSHA256Digest(authType AuthenticationType, key []byte, packet []byte) [sha256.Size]byte {
+	var inner [sha256.BlockSize + MAXIMUM_LENGTH]byte
+	var outer [sha256.BlockSize + sha256.Size]byte
+
+	copy(inner[:sha256.BlockSize], key)
+	copy(outer[:sha256.BlockSize], key)
+
+	for i := 0; i < sha256.BlockSize; i++ {
+		inner[i] ^= 0x36
+		outer[i] ^= 0x5c
+	}
+
+	data := inner[:sha256.BlockSize]
+
+	if authType == MeticulousKeyedSecureSequence {
+		data = append(data, packet[MINIMUM_SIZE+4:MINIMUM_SIZE+8]...)
+		data = append(data, packet[4:12]...)
+	} else {
+		data = append(data, packet...)
+		zero(data[sha256.BlockSize+MINIMUM_SIZE+8 : sha256.BlockSize+MINIMUM_SIZE+HMAC_SHA256_AUTH_LENGTH])
+	}
+
+	sum := sha256.Sum256(data)
+	copy(outer[sha256.BlockSize:], sum[:])
+
+	return sha256.Sum256(outer[:])
+}
@@ -0,0 +1,204 @@
+package bfd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newHMACSHA256Packet(authType AuthenticationType, key string) ControlPacket {
+	return ControlPacket{
+		Version:               1,
+		State:                 Up,
+		DetectMultiplier:      3,
+		MyDiscriminator:       5231466,
+		YourDiscriminator:     6934612,
+		DesiredMinTxInterval:  1000000,
+		RequiredMinRxInterval: 2000000,
+		AuthenticationHeader: &HMACSHA256Header{
+			AuthType:       authType,
+			AuthKeyId:      9,
+			SequenceNumber: 256,
+			AuthKey:        []byte(key),
+		},
+	}
+}
+
+func hmacSHA256Target(authType AuthenticationType, digest []byte) []byte {
+	target := []byte{
+		1<<5 | byte(NoDiagnostic),
+		byte(Up)<<6 | byte(No)<<5 | byte(No)<<4 |
+			byte(No)<<3 | byte(Yes)<<2 |
+			byte(No)<<1 | byte(No),
+		3,
+		64,
+		0, 79, 211, 106,
+		0, 105, 208, 84,
+		0, 15, 66, 64,
+		0, 30, 132, 128,
+		0, 0, 0, 0,
+		byte(authType), 40, 9, 0,
+		0, 0, 1, 0,
+	}
+
+	return append(target, digest...)
+}
+
+func TestControlPacketWithHMACSHA256(t *testing.T) {
+	tests := []struct {
+		authType AuthenticationType
+		digest   []byte
+	}{
+		{
+			MeticulousKeyedHMACSHA256,
+			[]byte{
+				112, 10, 99, 150, 161, 82, 141, 249, 193, 60, 224, 36, 81, 5, 205, 192,
+				250, 82, 240, 153, 113, 78, 145, 0, 198, 102, 33, 83, 3, 14, 116, 233,
+			},
+		},
+		{
+			MeticulousKeyedSecureSequence,
+			[]byte{
+				9, 115, 67, 144, 244, 48, 121, 151, 227, 112, 238, 135, 161, 219, 139, 50,
+				192, 216, 255, 63, 130, 230, 243, 43, 223, 94, 12, 167, 240, 84, 87, 115,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		packet := newHMACSHA256Packet(test.authType, "HelloWorld")
+		target := hmacSHA256Target(test.authType, test.digest)
+
+		bytes, err := packet.MarshalBinary()
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		if !reflect.DeepEqual(bytes, target) {
+			t.Errorf("%v differs from %v", bytes, target)
+		}
+
+		var parsed ControlPacket
+
+		if err := parsed.UnmarshalBinary(target); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		header, ok := parsed.AuthenticationHeader.(*HMACSHA256Header)
+
+		if !ok {
+			t.Fatalf("Expected HMACSHA256Header, got %v", parsed.AuthenticationHeader)
+		}
+
+		if header.AuthType != test.authType || header.GetAuthKeyId() != 9 || header.GetSequenceNumber() != 256 {
+			t.Errorf("%v differs from %v", header, packet.AuthenticationHeader)
+		}
+
+		if !header.IsValid([]byte("HelloWorld"), target) {
+			t.Errorf("Expected digest to be valid")
+		}
+
+		if header.IsValid([]byte("HelloWorld!"), target) {
+			t.Errorf("Expected digest to be invalid with a different key")
+		}
+	}
+}
+
+func TestSecureSequenceIgnoresPacketContent(t *testing.T) {
+	packet := newHMACSHA256Packet(MeticulousKeyedSecureSequence, "HelloWorld")
+
+	target, err := packet.MarshalBinary()
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var parsed ControlPacket
+	parsed.UnmarshalBinary(target)
+
+	// only the sequence number and the discriminators are covered
+	target[12] = 1
+
+	if !parsed.AuthenticationHeader.IsValid([]byte("HelloWorld"), target) {
+		t.Errorf("Expected the digest to only cover the sequence number and discriminators")
+	}
+
+	target[4] = 1
+
+	if parsed.AuthenticationHeader.IsValid([]byte("HelloWorld"), target) {
+		t.Errorf("Expected the digest to cover the discriminators")
+	}
+
+	// the full HMAC covers everything
+	packet = newHMACSHA256Packet(MeticulousKeyedHMACSHA256, "HelloWorld")
+	target, _ = packet.MarshalBinary()
+	parsed.UnmarshalBinary(target)
+
+	target[12] = 1
+
+	if parsed.AuthenticationHeader.IsValid([]byte("HelloWorld"), target) {
+		t.Errorf("Expected the digest to cover the complete packet")
+	}
+}
+
+func TestHMACSHA256InvalidKeyLength(t *testing.T) {
+	header := &HMACSHA256Header{
+		AuthType: MeticulousKeyedHMACSHA256,
+	}
+
+	if _, err := header.MarshalBinary(); err != ErrKeyInvalidLength {
+		t.Errorf("Expected %s Error", ErrKeyInvalidLength)
+	}
+
+	header.AuthKey = make([]byte, HMAC_SHA256_KEY_LENGTH+1)
+
+	if _, err := header.MarshalBinary(); err != ErrKeyInvalidLength {
+		t.Errorf("Expected %s Error", ErrKeyInvalidLength)
+	}
+
+	header.AuthKey = []byte("secret")
+	header.AuthType = MeticulousKeyedSHA1
+
+	if _, err := header.MarshalBinary(); err != ErrInvalidAuthenticationType {
+		t.Errorf("Expected %s Error", ErrInvalidAuthenticationType)
+	}
+}
+
+func TestGetAuthenticationProvider(t *testing.T) {
+	tests := []struct {
+		authType   AuthenticationType
+		keyLength  int
+		meticulous bool
+		header     AuthenticationHeader
+	}{
+		{SimplePassword, MD5_KEY_LENGTH, false, &SimplePasswordHeader{}},
+		{KeyedMD5, MD5_KEY_LENGTH, false, &KeyedMD5Header{}},
+		{MeticulousKeyedMD5, MD5_KEY_LENGTH, true, &KeyedMD5Header{}},
+		{KeyedSHA1, SHA1_KEY_LENGTH, false, &KeyedSHA1Header{}},
+		{MeticulousKeyedSHA1, SHA1_KEY_LENGTH, true, &KeyedSHA1Header{}},
+		{MeticulousKeyedHMACSHA256, HMAC_SHA256_KEY_LENGTH, true, &HMACSHA256Header{}},
+		{MeticulousKeyedSecureSequence, HMAC_SHA256_KEY_LENGTH, true, &HMACSHA256Header{}},
+	}
+
+	for _, test := range tests {
+		provider, err := GetAuthenticationProvider(test.authType)
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		if provider.MaxKeyLength() != test.keyLength || provider.IsMeticulous() != test.meticulous {
+			t.Errorf("Unexpected provider for %s", test.authType)
+		}
+
+		header := provider.NewHeader(1, []byte("secret"), 2)
+
+		if reflect.TypeOf(header) != reflect.TypeOf(test.header) || header.GetAuthenticationType() != test.authType {
+			t.Errorf("Unexpected header %v for %s", header, test.authType)
+		}
+	}
+
+	if _, err := GetAuthenticationProvider(Reserved); err != ErrInvalidAuthenticationType {
+		t.Errorf("Expected %v, got %v", ErrInvalidAuthenticationType, err)
+	}
+}
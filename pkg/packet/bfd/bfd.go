@@ -1,6 +1,9 @@
 package bfd
 
 import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
 	"encoding"
 	"encoding/binary"
 	"errors"
@@ -79,6 +82,11 @@ const (
 	MeticulousKeyedMD5  AuthenticationType = 3
 	KeyedSHA1           AuthenticationType = 4
 	MeticulousKeyedSHA1 AuthenticationType = 5
+
+	// the code points of the HMAC-SHA256 and optimized authentication drafts aren't assigned
+	// by IANA yet, the next free values are used until they are
+	MeticulousKeyedHMACSHA256     AuthenticationType = 6
+	MeticulousKeyedSecureSequence AuthenticationType = 7
 )
 
 func (a AuthenticationType) String() string {
@@ -95,6 +103,10 @@ func (a AuthenticationType) String() string {
 		return "Keyed SHA1"
 	case MeticulousKeyedSHA1:
 		return "Meticulous Keyed SHA1"
+	case MeticulousKeyedHMACSHA256:
+		return "Meticulous Keyed HMAC-SHA256"
+	case MeticulousKeyedSecureSequence:
+		return "Meticulous Keyed Secure Sequence"
 	default:
 		return fmt.Sprintf("AuthenticationType(%d)", a)
 	}
@@ -108,8 +120,14 @@ const (
 )
 
 const (
-	MINIMUM_SIZE = 24
-	MAXIMUM_SIZE = MINIMUM_SIZE + 28 // 24 bfd packet min size + 28 auth header max size
+	MINIMUM_SIZE   = 24
+	MAXIMUM_SIZE   = MINIMUM_SIZE + 40 // 24 bfd packet min size + 40 auth header max size (HMAC-SHA256)
+	MAXIMUM_LENGTH = 255               // the Length field is a single byte, the limit for other authentication providers
+
+	MD5_AUTH_LENGTH  = 24 // type, length, key id, reserved, sequence number + 16 bytes digest
+	MD5_KEY_LENGTH   = 16
+	SHA1_AUTH_LENGTH = 28 // type, length, key id, reserved, sequence number + 20 bytes hash
+	SHA1_KEY_LENGTH  = 20
 )
 
 type ControlPacket struct {
@@ -137,6 +155,21 @@ type AuthenticationHeader interface {
 
 	IsValid(key []byte, packet []byte) bool
 	GetAuthenticationType() AuthenticationType
+	GetAuthKeyId() int8
+}
+
+// AuthenticationHeaderCodec is implemented by authentication headers that can be
+// decoded and serialized without allocating, reusing the storage of the header
+type AuthenticationHeaderCodec interface {
+	DecodeFromBytes(buf []byte) error
+	SerializeTo(buf []byte) (int, error)
+}
+
+// AuthenticationSigner is implemented by authentication headers that carry a
+// digest calculated over the complete packet. Sign is called by
+// ControlPacket.MarshalBinary once the packet has been fully serialized.
+type AuthenticationSigner interface {
+	Sign(packet []byte)
 }
 
 type SimplePasswordHeader struct {
@@ -147,12 +180,23 @@ type SimplePasswordHeader struct {
 var ErrPasswordInvalidLength = errors.New("Password needs to be between 1 and 16")
 var ErrInvalidAuthenticationType = errors.New("AuthenticationType is invalid")
 var ErrInvalidPacketLength = errors.New("Invalid packet length")
+var ErrBufferTooSmall = errors.New("Buffer too small for the packet")
+var ErrKeyInvalidLength = errors.New("Key needs to be between 1 and 16 (MD5), 20 (SHA1) or 64 (HMAC-SHA256)")
 
 func (s *SimplePasswordHeader) IsValid(key []byte, packet []byte) bool {
 	return s.Password == string(key)
 }
 
 func (s *SimplePasswordHeader) UnmarshalBinary(buf []byte) error {
+	return s.DecodeFromBytes(buf)
+}
+
+// DecodeFromBytes parses the section into s, the password is only copied if it changed
+func (s *SimplePasswordHeader) DecodeFromBytes(buf []byte) error {
+	if len(buf) < 3 {
+		return ErrInvalidPacketLength
+	}
+
 	if AuthenticationType(buf[0]) != SimplePassword {
 		return ErrInvalidAuthenticationType
 	}
@@ -164,46 +208,299 @@ func (s *SimplePasswordHeader) UnmarshalBinary(buf []byte) error {
 	}
 
 	s.AuthKeyId = int8(buf[2])
-	s.Password = string(buf[3:])
+
+	// comparing doesn't allocate, the password rarely changes
+	if s.Password != string(buf[3:]) {
+		s.Password = string(buf[3:])
+	}
 
 	return nil
 }
 
 func (s *SimplePasswordHeader) MarshalBinary() ([]byte, error) {
-	buf := make([]byte, 3)
+	buf := make([]byte, 3+len(s.Password))
+
+	if _, err := s.SerializeTo(buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
 
+func (s *SimplePasswordHeader) SerializeTo(buf []byte) (int, error) {
 	if len(s.Password) < 1 || len(s.Password) > 16 {
-		return nil, ErrPasswordInvalidLength
+		return 0, ErrPasswordInvalidLength
 	}
 
-	buf[0] = byte(SimplePassword)
-	buf[2] = byte(s.AuthKeyId)
+	l := 3 + len(s.Password)
 
-	buf = append(buf, []byte(s.Password)...)
+	if len(buf) < l {
+		return 0, ErrBufferTooSmall
+	}
 
-	buf[1] = byte(len(buf))
+	buf[0] = byte(SimplePassword)
+	buf[1] = byte(l)
+	buf[2] = byte(s.AuthKeyId)
+	copy(buf[3:], s.Password)
 
-	return buf, nil
+	return l, nil
 }
 
 func (s *SimplePasswordHeader) GetAuthenticationType() AuthenticationType {
 	return SimplePassword
 }
 
+func (s *SimplePasswordHeader) GetAuthKeyId() int8 {
+	return s.AuthKeyId
+}
+
 type KeyedMD5Header struct {
 	AuthType AuthenticationType
 	// Length int8 = inferred
 	AuthKeyId      int8
-	SequenceNumber int32
-	AuthKey        []byte // 16 bytes long
+	SequenceNumber uint32
+	AuthKey        []byte // 16 bytes long, the key on transmit and the digest on receipt
+}
+
+func (s *KeyedMD5Header) IsValid(key []byte, packet []byte) bool {
+	/*
+		RFC5880 6.7.3
+		The receiving system replaces the Auth Key/Digest field with the key
+		(padded with zeros to 16 bytes) and calculates the MD5 digest over the
+		whole packet. If it doesn't match the received digest, the packet
+		MUST be discarded.
+	*/
+	return isDigestValid(key, packet, MD5_KEY_LENGTH)
+}
+
+func (s *KeyedMD5Header) UnmarshalBinary(buf []byte) error {
+	s.AuthKey = nil
+
+	return s.DecodeFromBytes(buf)
+}
+
+// DecodeFromBytes parses the section into s, the storage of AuthKey is reused for the digest
+func (s *KeyedMD5Header) DecodeFromBytes(buf []byte) error {
+	authType, keyId, sequence, digest, err := decodeKeyed(buf, KeyedMD5, MeticulousKeyedMD5, MD5_AUTH_LENGTH, s.AuthKey)
+
+	if err != nil {
+		return err
+	}
+
+	s.AuthType = authType
+	s.AuthKeyId = keyId
+	s.SequenceNumber = sequence
+	s.AuthKey = digest
+
+	return nil
+}
+
+func (s *KeyedMD5Header) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, MD5_AUTH_LENGTH)
+
+	if _, err := s.SerializeTo(buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func (s *KeyedMD5Header) SerializeTo(buf []byte) (int, error) {
+	if s.AuthType != KeyedMD5 && s.AuthType != MeticulousKeyedMD5 {
+		return 0, ErrInvalidAuthenticationType
+	}
+
+	return serializeKeyed(buf, s.AuthType, s.AuthKeyId, s.SequenceNumber, s.AuthKey, MD5_AUTH_LENGTH)
+}
+
+// Sign replaces the key in the Auth Key/Digest field with the MD5 digest of the packet
+func (s *KeyedMD5Header) Sign(packet []byte) {
+	sum := md5.Sum(packet)
+
+	copy(packet[MINIMUM_SIZE+8:MINIMUM_SIZE+MD5_AUTH_LENGTH], sum[:])
+}
+
+func (s *KeyedMD5Header) GetAuthenticationType() AuthenticationType {
+	return s.AuthType
+}
+
+func (s *KeyedMD5Header) GetAuthKeyId() int8 {
+	return s.AuthKeyId
+}
+
+func (s *KeyedMD5Header) GetSequenceNumber() uint32 {
+	return s.SequenceNumber
 }
 
 type KeyedSHA1Header struct {
 	AuthType AuthenticationType
 	// Length int8 = inferred
 	AuthKeyId      int8
-	SequenceNumber int32
-	AuthKey        []byte // 20 bytes long
+	SequenceNumber uint32
+	AuthKey        []byte // 20 bytes long, the key on transmit and the hash on receipt
+}
+
+func (s *KeyedSHA1Header) IsValid(key []byte, packet []byte) bool {
+	/*
+		RFC5880 6.7.4
+		The receiving system replaces the Auth Key/Hash field with the key
+		(padded with zeros to 20 bytes) and calculates the SHA1 hash over the
+		whole packet. If it doesn't match the received hash, the packet
+		MUST be discarded.
+	*/
+	return isDigestValid(key, packet, SHA1_KEY_LENGTH)
+}
+
+func (s *KeyedSHA1Header) UnmarshalBinary(buf []byte) error {
+	s.AuthKey = nil
+
+	return s.DecodeFromBytes(buf)
+}
+
+// DecodeFromBytes parses the section into s, the storage of AuthKey is reused for the hash
+func (s *KeyedSHA1Header) DecodeFromBytes(buf []byte) error {
+	authType, keyId, sequence, hash, err := decodeKeyed(buf, KeyedSHA1, MeticulousKeyedSHA1, SHA1_AUTH_LENGTH, s.AuthKey)
+
+	if err != nil {
+		return err
+	}
+
+	s.AuthType = authType
+	s.AuthKeyId = keyId
+	s.SequenceNumber = sequence
+	s.AuthKey = hash
+
+	return nil
+}
+
+func (s *KeyedSHA1Header) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, SHA1_AUTH_LENGTH)
+
+	if _, err := s.SerializeTo(buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func (s *KeyedSHA1Header) SerializeTo(buf []byte) (int, error) {
+	if s.AuthType != KeyedSHA1 && s.AuthType != MeticulousKeyedSHA1 {
+		return 0, ErrInvalidAuthenticationType
+	}
+
+	return serializeKeyed(buf, s.AuthType, s.AuthKeyId, s.SequenceNumber, s.AuthKey, SHA1_AUTH_LENGTH)
+}
+
+// Sign replaces the key in the Auth Key/Hash field with the SHA1 hash of the packet
+func (s *KeyedSHA1Header) Sign(packet []byte) {
+	sum := sha1.Sum(packet)
+
+	copy(packet[MINIMUM_SIZE+8:MINIMUM_SIZE+SHA1_AUTH_LENGTH], sum[:])
+}
+
+func (s *KeyedSHA1Header) GetAuthenticationType() AuthenticationType {
+	return s.AuthType
+}
+
+func (s *KeyedSHA1Header) GetAuthKeyId() int8 {
+	return s.AuthKeyId
+}
+
+func (s *KeyedSHA1Header) GetSequenceNumber() uint32 {
+	return s.SequenceNumber
+}
+
+/*
+	Keyed MD5 and Keyed SHA1 share the same layout, only the length of the
+	Auth Key/Digest field differs (16 bytes for MD5, 20 bytes for SHA1)
+
+    0                   1                   2                   3
+    0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |   Auth Type   |   Auth Len    |  Auth Key ID  |   Reserved    |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |                        Sequence Number                        |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |                      Auth Key/Digest...                       |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+*/
+
+// decodeKeyed parses a keyed section, the digest is copied into the storage of digest
+func decodeKeyed(buf []byte, keyed, meticulous AuthenticationType, length int, digest []byte) (AuthenticationType, int8, uint32, []byte, error) {
+	if len(buf) < 2 {
+		return 0, 0, 0, nil, ErrInvalidPacketLength
+	}
+
+	authType := AuthenticationType(buf[0])
+
+	if authType != keyed && authType != meticulous {
+		return 0, 0, 0, nil, ErrInvalidAuthenticationType
+	}
+
+	l := int(buf[1])
+
+	if len(buf) != l || l != length {
+		return 0, 0, 0, nil, ErrInvalidPacketLength
+	}
+
+	// buf[3] is reserved and ignored on receipt
+	digest = append(digest[:0], buf[8:length]...)
+
+	return authType, int8(buf[2]), binary.BigEndian.Uint32(buf[4:]), digest, nil
+}
+
+func serializeKeyed(buf []byte, authType AuthenticationType, keyId int8, sequence uint32, key []byte, length int) (int, error) {
+	if len(key) < 1 || len(key) > length-8 {
+		return 0, ErrKeyInvalidLength
+	}
+
+	if len(buf) < length {
+		return 0, ErrBufferTooSmall
+	}
+
+	buf[0] = byte(authType)
+	buf[1] = byte(length)
+	buf[2] = byte(keyId)
+	buf[3] = 0
+	binary.BigEndian.PutUint32(buf[4:], sequence)
+
+	// the key is padded with zeros and replaced by the digest in Sign
+	n := copy(buf[8:length], key)
+	zero(buf[8+n : length])
+
+	return length, nil
+}
+
+func zero(buf []byte) {
+	for i := range buf {
+		buf[i] = 0
+	}
+}
+
+// isDigestValid compares the MD5 (16 byte key) or SHA1 (20 byte key) digest of packet, with the key in the
+// digest field, to the received one. The packet is copied to the stack, as it's limited to MAXIMUM_LENGTH.
+func isDigestValid(key []byte, packet []byte, keyLength int) bool {
+	if len(key) > keyLength || len(packet) < MINIMUM_SIZE+8+keyLength || len(packet) > MAXIMUM_LENGTH {
+		return false
+	}
+
+	var buf [MAXIMUM_LENGTH]byte
+	copy(buf[:], packet)
+
+	digest := buf[MINIMUM_SIZE+8 : MINIMUM_SIZE+8+keyLength]
+	received := packet[MINIMUM_SIZE+8 : MINIMUM_SIZE+8+keyLength]
+
+	zero(digest)
+	copy(digest, key)
+
+	if keyLength == MD5_KEY_LENGTH {
+		sum := md5.Sum(buf[:len(packet)])
+		return subtle.ConstantTimeCompare(sum[:], received) == 1
+	}
+
+	sum := sha1.Sum(buf[:len(packet)])
+
+	return subtle.ConstantTimeCompare(sum[:], received) == 1
 }
 
 /*
@@ -233,16 +530,39 @@ type KeyedSHA1Header struct {
 */
 
 func (c *ControlPacket) UnmarshalBinary(buf []byte) error {
+	c.AuthenticationHeader = nil
+
+	return c.DecodeFromBytes(buf)
+}
+
+// DecodeFromBytes parses buf into c without keeping a reference to it. An authentication
+// header of the same type is reused, which makes decoding the packets of a session allocation free.
+func (c *ControlPacket) DecodeFromBytes(buf []byte) error {
 	if len(buf) < MINIMUM_SIZE {
 		return ErrInvalidPacketLength
 	}
 
 	l := int(buf[3])
 
-	if len(buf) != l {
+	/*
+		RFC5880 6.8.6
+		If the Length field is less than the minimum correct value (24 if
+		the A bit is clear, or 26 if the A bit is set), the packet MUST be
+		discarded.
+
+		If the Length field is greater than the payload of the
+		encapsulating protocol, the packet MUST be discarded.
+
+		RFC9764
+		A payload larger than the Length field is padding, which is sent
+		to verify the path MTU and ignored.
+	*/
+	if l < MINIMUM_SIZE || l > len(buf) {
 		return ErrInvalidPacketLength
 	}
 
+	buf = buf[:l]
+
 	c.Version = int8(buf[0] & 0xE0 >> 5)
 	c.DiagnosticCode = DiagnosticCode(buf[0] & 0x1F)
 	c.State = SessionState((buf[1] & 0xC0) >> 6)
@@ -260,26 +580,63 @@ func (c *ControlPacket) UnmarshalBinary(buf []byte) error {
 	c.RequiredMinEchoInterval = binary.BigEndian.Uint32(buf[20:])
 
 	if ((buf[1] >> 2) & 1) == 1 {
+		// The authentication section needs at least the type and length
+		if len(buf) < MINIMUM_SIZE+2 {
+			return ErrInvalidPacketLength
+		}
+
 		// Parse authentication header
-		switch AuthenticationType(buf[24]) {
-		case SimplePassword:
-			var pw SimplePasswordHeader
+		authType := AuthenticationType(buf[24])
+		header := c.AuthenticationHeader
+
+		if header == nil || header.GetAuthenticationType() != authType {
+			provider, err := GetAuthenticationProvider(authType)
 
-			if err := pw.UnmarshalBinary(buf[24:]); err != nil {
+			if err != nil {
 				return err
 			}
 
-			c.AuthenticationHeader = &pw
-		default:
-			return ErrInvalidAuthenticationType
+			header = provider.NewHeader(0, nil, 0)
+		}
+
+		var err error
+
+		if codec, ok := header.(AuthenticationHeaderCodec); ok {
+			err = codec.DecodeFromBytes(buf[24:])
+		} else {
+			err = header.UnmarshalBinary(buf[24:])
 		}
+
+		if err != nil {
+			return err
+		}
+
+		c.AuthenticationHeader = header
+	} else {
+		c.AuthenticationHeader = nil
 	}
 
 	return nil
 }
 
 func (c *ControlPacket) MarshalBinary() ([]byte, error) {
-	buf := make([]byte, 24)
+	buf := make([]byte, MAXIMUM_LENGTH)
+
+	n, err := c.SerializeTo(buf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buf[:n], nil
+}
+
+// SerializeTo writes the packet to the start of buf and returns its length,
+// nothing is allocated unless the authentication header needs to
+func (c *ControlPacket) SerializeTo(buf []byte) (int, error) {
+	if len(buf) < MINIMUM_SIZE {
+		return 0, ErrBufferTooSmall
+	}
 
 	buf[0] = byte(c.Version)<<5 | byte(c.DiagnosticCode)
 	buf[1] = byte(c.State)<<6 | byte(c.Poll)<<5 | byte(c.Final)<<4 |
@@ -293,22 +650,62 @@ func (c *ControlPacket) MarshalBinary() ([]byte, error) {
 	binary.BigEndian.PutUint32(buf[16:], uint32(c.RequiredMinRxInterval))
 	binary.BigEndian.PutUint32(buf[20:], uint32(c.RequiredMinEchoInterval))
 
+	n := MINIMUM_SIZE
+
 	if c.AuthenticationHeader != nil {
 		// Set AuthenticationHeader present flag
 		buf[1] = buf[1] | byte(Yes)<<2
-		bytes, err := c.AuthenticationHeader.MarshalBinary()
+
+		l, err := serializeAuthenticationHeader(c.AuthenticationHeader, buf[MINIMUM_SIZE:])
 
 		if err != nil {
-			return nil, err
+			return 0, err
 		}
 
-		buf = append(buf, bytes...)
+		n += l
+	}
+
+	if n > MAXIMUM_LENGTH {
+		return 0, ErrInvalidPacketLength
 	}
 
 	// Set the length in the end
-	buf[3] = byte(len(buf))
+	buf[3] = byte(n)
 
-	return buf, nil
+	// The digest covers the complete packet, so it is calculated last
+	if signer, ok := c.AuthenticationHeader.(AuthenticationSigner); ok {
+		signer.Sign(buf[:n])
+	}
+
+	return n, nil
+}
+
+func serializeAuthenticationHeader(header AuthenticationHeader, buf []byte) (int, error) {
+	if codec, ok := header.(AuthenticationHeaderCodec); ok {
+		return codec.SerializeTo(buf)
+	}
+
+	bytes, err := header.MarshalBinary()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(buf) < len(bytes) {
+		return 0, ErrBufferTooSmall
+	}
+
+	return copy(buf, bytes), nil
+}
+
+// Pad appends zero padding to a marshalled packet until it's size bytes long (RFC9764),
+// the Length field and the authentication only cover the packet itself
+func Pad(buf []byte, size int) []byte {
+	if len(buf) >= size {
+		return buf
+	}
+
+	return append(buf, make([]byte, size-len(buf))...)
 }
 
 func (c *ControlPacket) GetAuthenticationType() AuthenticationType {
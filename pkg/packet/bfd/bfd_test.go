@@ -1,6 +1,7 @@
 package bfd
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
 )
@@ -94,7 +95,15 @@ func TestAuthenticationTypeString(t *testing.T) {
 		t.Fail()
 	}
 
-	if "AuthenticationType(6)" != AuthenticationType(6).String() {
+	if "Meticulous Keyed HMAC-SHA256" != MeticulousKeyedHMACSHA256.String() {
+		t.Fail()
+	}
+
+	if "Meticulous Keyed Secure Sequence" != MeticulousKeyedSecureSequence.String() {
+		t.Fail()
+	}
+
+	if "AuthenticationType(8)" != AuthenticationType(8).String() {
 		t.Fail()
 	}
 }
@@ -387,3 +396,546 @@ func TestSimplePasswordValidation(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestControlPacketWithKeyedMD5(t *testing.T) {
+	target := []byte{
+		1<<5 | byte(NoDiagnostic),
+		byte(Up)<<6 | byte(No)<<5 | byte(No)<<4 |
+			byte(No)<<3 | byte(Yes)<<2 |
+			byte(No)<<1 | byte(No),
+		3,
+		48,
+		0, 79, 211, 106,
+		0, 105, 208, 84,
+		0, 15, 66, 64,
+		0, 30, 132, 128,
+		0, 0, 0, 0,
+		3, 24, 7, 0,
+		0, 18, 214, 135,
+		118, 9, 230, 255, 6, 211, 219, 229,
+		58, 112, 32, 91, 0, 136, 187, 194,
+	}
+
+	packet := ControlPacket{
+		Version:               1,
+		State:                 Up,
+		DetectMultiplier:      3,
+		MyDiscriminator:       5231466,
+		YourDiscriminator:     6934612,
+		DesiredMinTxInterval:  1000000,
+		RequiredMinRxInterval: 2000000,
+		AuthenticationHeader: &KeyedMD5Header{
+			AuthType:       MeticulousKeyedMD5,
+			AuthKeyId:      7,
+			SequenceNumber: 1234567,
+			AuthKey:        []byte("HelloWorld"),
+		},
+	}
+
+	bytes, err := packet.MarshalBinary()
+
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(bytes, target) {
+		t.Errorf("%v differs from %v", bytes, target)
+	}
+
+	// Validate Unmarshal code path
+	var parsed ControlPacket
+	err = parsed.UnmarshalBinary(target)
+
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	header, ok := parsed.AuthenticationHeader.(*KeyedMD5Header)
+
+	if !ok {
+		t.Errorf("Expected KeyedMD5Header, got %v", parsed.AuthenticationHeader)
+		return
+	}
+
+	if header.AuthType != MeticulousKeyedMD5 || header.AuthKeyId != 7 || header.SequenceNumber != 1234567 {
+		t.Errorf("%v differs from %v", header, packet.AuthenticationHeader)
+	}
+
+	if !reflect.DeepEqual(header.AuthKey, target[32:]) {
+		t.Errorf("Digest %v differs from %v", header.AuthKey, target[32:])
+	}
+
+	if parsed.GetAuthenticationType() != MeticulousKeyedMD5 {
+		t.Fail()
+	}
+}
+
+func TestKeyedMD5Validation(t *testing.T) {
+	packet := ControlPacket{
+		Version:          1,
+		State:            Down,
+		DetectMultiplier: 3,
+		MyDiscriminator:  5231466,
+		AuthenticationHeader: &KeyedMD5Header{
+			AuthType:       KeyedMD5,
+			AuthKeyId:      1,
+			SequenceNumber: 42,
+			AuthKey:        []byte("secret"),
+		},
+	}
+
+	bytes, err := packet.MarshalBinary()
+
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	var parsed ControlPacket
+
+	if err := parsed.UnmarshalBinary(bytes); err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if !parsed.AuthenticationHeader.IsValid([]byte("secret"), bytes) {
+		t.Errorf("Expected digest to be valid")
+	}
+
+	if parsed.AuthenticationHeader.IsValid([]byte("another secret"), bytes) {
+		t.Errorf("Expected digest to be invalid with a different key")
+	}
+
+	// flipping any bit of the packet needs to invalidate the digest
+	bytes[2] = 4
+
+	if parsed.AuthenticationHeader.IsValid([]byte("secret"), bytes) {
+		t.Errorf("Expected digest to be invalid for a modified packet")
+	}
+
+	if parsed.AuthenticationHeader.IsValid([]byte("secret"), bytes[:30]) {
+		t.Errorf("Expected digest to be invalid for a truncated packet")
+	}
+}
+
+func TestKeyedMD5InvalidKeyLength(t *testing.T) {
+	header := &KeyedMD5Header{
+		AuthType: KeyedMD5,
+	}
+
+	if _, err := header.MarshalBinary(); err != ErrKeyInvalidLength {
+		t.Errorf("Expected %s Error", ErrKeyInvalidLength)
+	}
+
+	header.AuthKey = []byte("HelloWorldWithALongKey")
+
+	if _, err := header.MarshalBinary(); err != ErrKeyInvalidLength {
+		t.Errorf("Expected %s Error", ErrKeyInvalidLength)
+	}
+
+	header.AuthType = SimplePassword
+
+	if _, err := header.MarshalBinary(); err != ErrInvalidAuthenticationType {
+		t.Errorf("Expected %s Error", ErrInvalidAuthenticationType)
+	}
+}
+
+func TestKeyedMD5InvalidPacketLength(t *testing.T) {
+	target := []byte{
+		2, 20, 5, 0,
+		0, 0, 0, 1,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	}
+
+	header := KeyedMD5Header{}
+
+	if err := header.UnmarshalBinary(target); err != ErrInvalidPacketLength {
+		t.Fail()
+	}
+}
+
+func TestKeyedMD5InvalidAuthenticationType(t *testing.T) {
+	target := []byte{
+		4, 24, 5, 0,
+		0, 0, 0, 1,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	}
+
+	header := KeyedMD5Header{}
+
+	if err := header.UnmarshalBinary(target); err != ErrInvalidAuthenticationType {
+		t.Fail()
+	}
+}
+
+func TestControlPacketMissingAuthenticationSection(t *testing.T) {
+	target := []byte{
+		1<<5 | byte(NoDiagnostic),
+		byte(Down)<<6 | byte(Yes)<<2,
+		3,
+		24,
+		0, 79, 211, 106,
+		0, 0, 0, 0,
+		0, 15, 66, 64,
+		0, 30, 132, 128,
+		0, 0, 0, 0,
+	}
+
+	var parsed ControlPacket
+
+	if err := parsed.UnmarshalBinary(target); err != ErrInvalidPacketLength {
+		t.Fail()
+	}
+}
+
+func TestControlPacketWithKeyedSHA1(t *testing.T) {
+	target := []byte{
+		1<<5 | byte(NoDiagnostic),
+		byte(Up)<<6 | byte(No)<<5 | byte(No)<<4 |
+			byte(No)<<3 | byte(Yes)<<2 |
+			byte(No)<<1 | byte(No),
+		3,
+		52,
+		0, 79, 211, 106,
+		0, 105, 208, 84,
+		0, 15, 66, 64,
+		0, 30, 132, 128,
+		0, 0, 0, 0,
+		5, 28, 9, 0,
+		0, 0, 1, 0,
+		27, 127, 40, 26, 37, 147, 81, 136, 46, 48,
+		255, 38, 183, 109, 11, 9, 131, 130, 132, 129,
+	}
+
+	packet := ControlPacket{
+		Version:               1,
+		State:                 Up,
+		DetectMultiplier:      3,
+		MyDiscriminator:       5231466,
+		YourDiscriminator:     6934612,
+		DesiredMinTxInterval:  1000000,
+		RequiredMinRxInterval: 2000000,
+		AuthenticationHeader: &KeyedSHA1Header{
+			AuthType:       MeticulousKeyedSHA1,
+			AuthKeyId:      9,
+			SequenceNumber: 256,
+			AuthKey:        []byte("HelloWorld"),
+		},
+	}
+
+	bytes, err := packet.MarshalBinary()
+
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if !reflect.DeepEqual(bytes, target) {
+		t.Errorf("%v differs from %v", bytes, target)
+	}
+
+	// Validate Unmarshal code path
+	var parsed ControlPacket
+	err = parsed.UnmarshalBinary(target)
+
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	header, ok := parsed.AuthenticationHeader.(*KeyedSHA1Header)
+
+	if !ok {
+		t.Errorf("Expected KeyedSHA1Header, got %v", parsed.AuthenticationHeader)
+		return
+	}
+
+	if header.AuthType != MeticulousKeyedSHA1 || header.AuthKeyId != 9 || header.SequenceNumber != 256 {
+		t.Errorf("%v differs from %v", header, packet.AuthenticationHeader)
+	}
+
+	if !reflect.DeepEqual(header.AuthKey, target[32:]) {
+		t.Errorf("Hash %v differs from %v", header.AuthKey, target[32:])
+	}
+
+	if !header.IsValid([]byte("HelloWorld"), target) {
+		t.Errorf("Expected hash to be valid")
+	}
+
+	if header.IsValid([]byte("HelloWorld!"), target) {
+		t.Errorf("Expected hash to be invalid with a different key")
+	}
+
+	if parsed.GetAuthenticationType() != MeticulousKeyedSHA1 {
+		t.Fail()
+	}
+}
+
+func TestKeyedSHA1InvalidKeyLength(t *testing.T) {
+	header := &KeyedSHA1Header{
+		AuthType: KeyedSHA1,
+	}
+
+	if _, err := header.MarshalBinary(); err != ErrKeyInvalidLength {
+		t.Errorf("Expected %s Error", ErrKeyInvalidLength)
+	}
+
+	// 20 bytes are allowed for SHA1
+	header.AuthKey = []byte("01234567890123456789")
+
+	if _, err := header.MarshalBinary(); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	header.AuthKey = []byte("012345678901234567890")
+
+	if _, err := header.MarshalBinary(); err != ErrKeyInvalidLength {
+		t.Errorf("Expected %s Error", ErrKeyInvalidLength)
+	}
+
+	header.AuthType = KeyedMD5
+
+	if _, err := header.MarshalBinary(); err != ErrInvalidAuthenticationType {
+		t.Errorf("Expected %s Error", ErrInvalidAuthenticationType)
+	}
+}
+
+func TestKeyedSHA1InvalidPacketLength(t *testing.T) {
+	target := []byte{
+		4, 24, 5, 0,
+		0, 0, 0, 1,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	}
+
+	header := KeyedSHA1Header{}
+
+	if err := header.UnmarshalBinary(target); err != ErrInvalidPacketLength {
+		t.Fail()
+	}
+}
+
+func TestKeyedSHA1InvalidAuthenticationType(t *testing.T) {
+	target := []byte{
+		2, 28, 5, 0,
+		0, 0, 0, 1,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	}
+
+	header := KeyedSHA1Header{}
+
+	if err := header.UnmarshalBinary(target); err != ErrInvalidAuthenticationType {
+		t.Fail()
+	}
+}
+
+func TestControlPacketPadding(t *testing.T) {
+	pkt := &ControlPacket{
+		Version:               1,
+		State:                 Up,
+		DetectMultiplier:      3,
+		MyDiscriminator:       1,
+		YourDiscriminator:     2,
+		DesiredMinTxInterval:  1000000,
+		RequiredMinRxInterval: 1000000,
+		AuthenticationHeader: &KeyedSHA1Header{
+			AuthType:       KeyedSHA1,
+			AuthKeyId:      1,
+			SequenceNumber: 5,
+			AuthKey:        []byte("secret"),
+		},
+	}
+
+	data, err := pkt.MarshalBinary()
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	padded := Pad(data, 1400)
+
+	if len(padded) != 1400 || !bytes.Equal(padded[:len(data)], data) {
+		t.Fatalf("Expected the packet to be padded to 1400 bytes, got %d", len(padded))
+	}
+
+	var parsed ControlPacket
+
+	if err := parsed.UnmarshalBinary(padded); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if parsed.YourDiscriminator != 2 || parsed.GetAuthenticationType() != KeyedSHA1 {
+		t.Errorf("Unexpected packet %v", parsed)
+	}
+
+	// the digest doesn't cover the padding
+	if !parsed.AuthenticationHeader.IsValid([]byte("secret"), padded[:padded[3]]) {
+		t.Errorf("Expected the digest to be valid")
+	}
+
+	// packets larger than the target aren't cut
+	if len(Pad(data, 10)) != len(data) {
+		t.Errorf("Expected the packet to stay unchanged")
+	}
+}
+
+func TestControlPacketLengthBelowMinimum(t *testing.T) {
+	target := make([]byte, 48)
+	target[0] = 1 << 5
+	target[3] = 20
+
+	var parsed ControlPacket
+
+	if err := parsed.UnmarshalBinary(target); err != ErrInvalidPacketLength {
+		t.Errorf("Expected %v, got %v", ErrInvalidPacketLength, err)
+	}
+}
+
+var codecPackets = []struct {
+	name   string
+	header AuthenticationHeader
+}{
+	{"None", nil},
+	{"SimplePassword", &SimplePasswordHeader{AuthKeyId: 1, Password: "secret"}},
+	{"MeticulousKeyedMD5", &KeyedMD5Header{AuthType: MeticulousKeyedMD5, AuthKeyId: 1, SequenceNumber: 7, AuthKey: []byte("secret")}},
+	{"MeticulousKeyedSHA1", &KeyedSHA1Header{AuthType: MeticulousKeyedSHA1, AuthKeyId: 1, SequenceNumber: 7, AuthKey: []byte("secret")}},
+}
+
+func newCodecPacket(header AuthenticationHeader) *ControlPacket {
+	return &ControlPacket{
+		Version:               1,
+		State:                 Up,
+		DetectMultiplier:      3,
+		MyDiscriminator:       5231466,
+		YourDiscriminator:     6934612,
+		DesiredMinTxInterval:  10000,
+		RequiredMinRxInterval: 10000,
+		AuthenticationHeader:  header,
+	}
+}
+
+func TestControlPacketDecodeFromBytes(t *testing.T) {
+	for _, test := range codecPackets {
+		data, err := newCodecPacket(test.header).MarshalBinary()
+
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		var parsed ControlPacket
+
+		if err := parsed.DecodeFromBytes(data); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		header := parsed.AuthenticationHeader
+
+		// the header of the previous packet is reused
+		allocs := testing.AllocsPerRun(100, func() {
+			parsed.DecodeFromBytes(data)
+		})
+
+		if allocs != 0 {
+			t.Errorf("%s: expected no allocations, got %v", test.name, allocs)
+		}
+
+		if parsed.AuthenticationHeader != header {
+			t.Errorf("%s: expected the header to be reused", test.name)
+		}
+
+		var unmarshalled ControlPacket
+		unmarshalled.UnmarshalBinary(data)
+
+		if !reflect.DeepEqual(parsed, unmarshalled) {
+			t.Errorf("%s: %v differs from %v", test.name, parsed, unmarshalled)
+		}
+	}
+}
+
+func TestControlPacketDecodeFromBytesHeaderChange(t *testing.T) {
+	var parsed ControlPacket
+
+	for _, test := range codecPackets {
+		data, _ := newCodecPacket(test.header).MarshalBinary()
+
+		if err := parsed.DecodeFromBytes(data); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if parsed.GetAuthenticationType() != newCodecPacket(test.header).GetAuthenticationType() {
+			t.Errorf("%s: unexpected authentication type %s", test.name, parsed.GetAuthenticationType())
+		}
+	}
+}
+
+func TestControlPacketSerializeTo(t *testing.T) {
+	buf := make([]byte, MAXIMUM_SIZE)
+
+	for _, test := range codecPackets {
+		packet := newCodecPacket(test.header)
+		target, _ := packet.MarshalBinary()
+
+		// leftovers of a previous packet don't end up in the serialized one
+		for i := range buf {
+			buf[i] = 0xff
+		}
+
+		n, err := packet.SerializeTo(buf)
+
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if !bytes.Equal(buf[:n], target) {
+			t.Errorf("%s: %v differs from %v", test.name, buf[:n], target)
+		}
+
+		allocs := testing.AllocsPerRun(100, func() {
+			packet.SerializeTo(buf)
+		})
+
+		if allocs != 0 {
+			t.Errorf("%s: expected no allocations, got %v", test.name, allocs)
+		}
+
+		if _, err := packet.SerializeTo(buf[:n-1]); err != ErrBufferTooSmall {
+			t.Errorf("%s: expected %v, got %v", test.name, ErrBufferTooSmall, err)
+		}
+	}
+}
+
+func BenchmarkDecodeFromBytes(b *testing.B) {
+	for _, test := range codecPackets {
+		data, _ := newCodecPacket(test.header).MarshalBinary()
+
+		b.Run(test.name, func(b *testing.B) {
+			var packet ControlPacket
+
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if err := packet.DecodeFromBytes(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSerializeTo(b *testing.B) {
+	for _, test := range codecPackets {
+		packet := newCodecPacket(test.header)
+		buf := make([]byte, MAXIMUM_SIZE)
+
+		b.Run(test.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := packet.SerializeTo(buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
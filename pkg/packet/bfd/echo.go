@@ -0,0 +1,56 @@
+package bfd
+
+import (
+	"encoding/binary"
+)
+
+const (
+	ECHO_PACKET_LENGTH = 12
+)
+
+/*
+	RFC5880 4.4 / RFC5881 4
+	The payload of a BFD Echo packet is a local matter, since only the
+	sending system ever processes the content.  The only requirement is
+	that sufficient information is included to demultiplex the received
+	packet to the correct BFD session after it is looped back to the
+	sender.
+
+    0                   1                   2                   3
+    0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |    Version    |                   Reserved                    |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |                       My Discriminator                        |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |                        Sequence Number                        |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+*/
+
+type EchoPacket struct {
+	Version         uint8
+	MyDiscriminator uint32 // the discriminator of the session that sent the packet
+	SequenceNumber  uint32
+}
+
+func (e *EchoPacket) UnmarshalBinary(buf []byte) error {
+	if len(buf) != ECHO_PACKET_LENGTH {
+		return ErrInvalidPacketLength
+	}
+
+	e.Version = buf[0]
+	e.MyDiscriminator = binary.BigEndian.Uint32(buf[4:])
+	e.SequenceNumber = binary.BigEndian.Uint32(buf[8:])
+
+	return nil
+}
+
+func (e *EchoPacket) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, ECHO_PACKET_LENGTH)
+
+	buf[0] = e.Version
+	binary.BigEndian.PutUint32(buf[4:], e.MyDiscriminator)
+	binary.BigEndian.PutUint32(buf[8:], e.SequenceNumber)
+
+	return buf, nil
+}
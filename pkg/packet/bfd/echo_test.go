@@ -0,0 +1,48 @@
+package bfd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEchoPacketMarshal(t *testing.T) {
+	target := []byte{
+		1, 0, 0, 0,
+		0, 0, 0, 42,
+		0, 0, 1, 2,
+	}
+
+	pkt := &EchoPacket{
+		Version:         1,
+		MyDiscriminator: 42,
+		SequenceNumber:  258,
+	}
+
+	data, err := pkt.MarshalBinary()
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if !bytes.Equal(data, target) {
+		t.Errorf("Expected %v, got %v", target, data)
+	}
+
+	parsed := &EchoPacket{}
+
+	if err := parsed.UnmarshalBinary(data); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if *parsed != *pkt {
+		t.Errorf("Expected %v, got %v", pkt, parsed)
+	}
+}
+
+func TestEchoPacketInvalidLength(t *testing.T) {
+	pkt := &EchoPacket{}
+
+	if err := pkt.UnmarshalBinary([]byte{1, 0, 0, 0}); err != ErrInvalidPacketLength {
+		t.Errorf("Expected %s Error", ErrInvalidPacketLength)
+	}
+}
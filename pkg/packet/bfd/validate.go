@@ -0,0 +1,251 @@
+package bfd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// Violation is a reason to discard a received packet
+type Violation int8
+
+const (
+	InvalidVersion              Violation = 0
+	InvalidLength               Violation = 1
+	InvalidAuthenticationLength Violation = 2 // Auth Len doesn't match the Length field, the A bit or the authentication type
+	InvalidAuthenticationType   Violation = 3
+	ZeroDetectMultiplier        Violation = 4
+	MultipointSet               Violation = 5
+	ZeroMyDiscriminator         Violation = 6
+	ZeroYourDiscriminator       Violation = 7 // while the state isn't Down or AdminDown
+	ReservedFieldSet            Violation = 8 // ignored on receipt, reported to find misbehaving implementations
+
+	VIOLATION_TYPES = 9 // number of violation types, the size of per violation counters
+)
+
+func (v Violation) String() string {
+	switch v {
+	case InvalidVersion:
+		return "Invalid Version"
+	case InvalidLength:
+		return "Invalid Length"
+	case InvalidAuthenticationLength:
+		return "Invalid Authentication Length"
+	case InvalidAuthenticationType:
+		return "Invalid Authentication Type"
+	case ZeroDetectMultiplier:
+		return "Zero Detect Multiplier"
+	case MultipointSet:
+		return "Multipoint Set"
+	case ZeroMyDiscriminator:
+		return "Zero My Discriminator"
+	case ZeroYourDiscriminator:
+		return "Zero Your Discriminator"
+	case ReservedFieldSet:
+		return "Reserved Field Set"
+	default:
+		return fmt.Sprintf("Violation(%d)", v)
+	}
+}
+
+// Violations is the set of violations found in a packet, the zero value is a valid packet
+type Violations uint16
+
+func (v Violations) Has(violation Violation) bool {
+	return v&(1<<uint(violation)) != 0
+}
+
+func (v *Violations) add(violation Violation) {
+	*v |= 1 << uint(violation)
+}
+
+// Discard returns true if the packet MUST be discarded, reserved fields are only reported
+func (v Violations) Discard() bool {
+	return v&^(1<<uint(ReservedFieldSet)) != 0
+}
+
+// List returns the violations ordered by their value
+func (v Violations) List() []Violation {
+	var list []Violation
+
+	for violation := Violation(0); violation < VIOLATION_TYPES; violation++ {
+		if v.Has(violation) {
+			list = append(list, violation)
+		}
+	}
+
+	return list
+}
+
+func (v Violations) String() string {
+	names := []string{}
+
+	for _, violation := range v.List() {
+		names = append(names, violation.String())
+	}
+
+	return strings.Join(names, ", ")
+}
+
+/*
+	RFC5880 6.8.6
+	If the version number is not correct (1), the packet MUST be
+	discarded.
+
+	If the Length field is less than the minimum correct value (24 if
+	the A bit is clear, or 26 if the A bit is set), the packet MUST be
+	discarded.
+
+	If the Length field is greater than the payload of the
+	encapsulating protocol, the packet MUST be discarded.
+
+	If the Detect Mult field is zero, the packet MUST be discarded.
+
+	If the Multipoint (M) bit is nonzero, the packet MUST be discarded.
+
+	If the My Discriminator field is zero, the packet MUST be
+	discarded.
+
+	If the Your Discriminator field is zero and the State field is not
+	Down or AdminDown, the packet MUST be discarded.
+
+	The checks that depend on the session (the discriminator lookup and
+	the authentication itself) are left to the receiver.
+*/
+
+// Validate checks a received packet and returns every violation instead of only the first,
+// unlike DecodeFromBytes it also reports broken packets that can't be decoded.
+// Padding after the Length field is ignored (RFC9764).
+func Validate(buf []byte) Violations {
+	var v Violations
+
+	if len(buf) < 4 {
+		v.add(InvalidLength)
+
+		if len(buf) > 0 && buf[0]>>5 != 1 {
+			v.add(InvalidVersion)
+		}
+
+		return v
+	}
+
+	l := int(buf[3])
+	authenticated := (buf[1]>>2)&1 == 1
+
+	if l < MINIMUM_SIZE || l > len(buf) || (authenticated && l < MINIMUM_SIZE+2) {
+		v.add(InvalidLength)
+	}
+
+	// a broken Length field doesn't prevent checking the other fields
+	if l >= MINIMUM_SIZE && l <= len(buf) {
+		buf = buf[:l]
+	}
+
+	if buf[0]>>5 != 1 {
+		v.add(InvalidVersion)
+	}
+
+	// the fields are only checked if they were received
+	if len(buf) < MINIMUM_SIZE {
+		return v
+	}
+
+	v |= validateFields(
+		SessionState(buf[1]>>6),
+		Bool(buf[1]&1),
+		buf[2],
+		binary.BigEndian.Uint32(buf[4:]),
+		binary.BigEndian.Uint32(buf[8:]),
+	)
+
+	if !authenticated {
+		// a section without the A bit is never verified
+		if l > MINIMUM_SIZE {
+			v.add(InvalidAuthenticationLength)
+		}
+
+		return v
+	}
+
+	// the A bit is set without room for an authentication section
+	if l < MINIMUM_SIZE+2 || len(buf) < MINIMUM_SIZE+2 {
+		v.add(InvalidAuthenticationLength)
+		return v
+	}
+
+	authType := AuthenticationType(buf[24])
+	authLength := int(buf[25])
+
+	if MINIMUM_SIZE+authLength != l {
+		v.add(InvalidAuthenticationLength)
+	}
+
+	if _, err := GetAuthenticationProvider(authType); err != nil {
+		v.add(InvalidAuthenticationType)
+		return v
+	}
+
+	switch authType {
+	case SimplePassword:
+		// the password is between 1 and 16 bytes
+		if authLength < 4 || authLength > 19 {
+			v.add(InvalidAuthenticationLength)
+		}
+	case KeyedMD5, MeticulousKeyedMD5:
+		v |= validateKeyed(buf[MINIMUM_SIZE:], authLength, MD5_AUTH_LENGTH)
+	case KeyedSHA1, MeticulousKeyedSHA1:
+		v |= validateKeyed(buf[MINIMUM_SIZE:], authLength, SHA1_AUTH_LENGTH)
+	case MeticulousKeyedHMACSHA256, MeticulousKeyedSecureSequence:
+		v |= validateKeyed(buf[MINIMUM_SIZE:], authLength, HMAC_SHA256_AUTH_LENGTH)
+	}
+
+	return v
+}
+
+func validateKeyed(section []byte, authLength, length int) Violations {
+	var v Violations
+
+	if authLength != length {
+		v.add(InvalidAuthenticationLength)
+	}
+
+	// The Reserved field MUST be set to zero on transmit, and ignored on receipt.
+	if len(section) > 3 && section[3] != 0 {
+		v.add(ReservedFieldSet)
+	}
+
+	return v
+}
+
+func validateFields(state SessionState, multipoint Bool, detectMultiplier uint8, myDiscriminator, yourDiscriminator uint32) Violations {
+	var v Violations
+
+	if detectMultiplier == 0 {
+		v.add(ZeroDetectMultiplier)
+	}
+
+	if multipoint != No {
+		v.add(MultipointSet)
+	}
+
+	if myDiscriminator == 0 {
+		v.add(ZeroMyDiscriminator)
+	}
+
+	if yourDiscriminator == 0 && state != Down && state != AdminDown {
+		v.add(ZeroYourDiscriminator)
+	}
+
+	return v
+}
+
+// Validate checks the fields of a decoded packet, the length checks need the received bytes (see Validate)
+func (c *ControlPacket) Validate() Violations {
+	v := validateFields(c.State, c.Multipoint, c.DetectMultiplier, c.MyDiscriminator, c.YourDiscriminator)
+
+	if c.Version != 1 {
+		v.add(InvalidVersion)
+	}
+
+	return v
+}
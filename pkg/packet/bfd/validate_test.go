@@ -0,0 +1,117 @@
+package bfd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newValidatePacket(t *testing.T, header AuthenticationHeader) []byte {
+	buf, err := (&ControlPacket{
+		Version:              1,
+		State:                Up,
+		DetectMultiplier:     3,
+		MyDiscriminator:      1,
+		YourDiscriminator:    2,
+		AuthenticationHeader: header,
+	}).MarshalBinary()
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return buf
+}
+
+func violations(list ...Violation) Violations {
+	var v Violations
+
+	for _, violation := range list {
+		v.add(violation)
+	}
+
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	md5 := &KeyedMD5Header{AuthType: KeyedMD5, AuthKey: []byte("secret")}
+
+	tests := []struct {
+		name   string
+		header AuthenticationHeader
+		modify func(buf []byte) []byte
+		result Violations
+	}{
+		{"valid", nil, func(buf []byte) []byte { return buf }, 0},
+		{"valid authenticated", md5, func(buf []byte) []byte { return buf }, 0},
+		{"padded", nil, func(buf []byte) []byte { return Pad(buf, 100) }, 0},
+		{"too short", nil, func(buf []byte) []byte { return buf[:2] }, violations(InvalidLength)},
+		{
+			"everything wrong",
+			nil,
+			func(buf []byte) []byte {
+				buf[0] = 2 << 5
+				buf[1] = byte(Up)<<6 | 1
+				buf[2] = 0
+				copy(buf[4:12], make([]byte, 8))
+				return buf
+			},
+			violations(InvalidVersion, ZeroDetectMultiplier, MultipointSet, ZeroMyDiscriminator, ZeroYourDiscriminator),
+		},
+		{"length exceeds payload", nil, func(buf []byte) []byte { buf[3] = 30; return buf }, violations(InvalidLength, InvalidAuthenticationLength)},
+		{"length too small", nil, func(buf []byte) []byte { buf[3] = 20; return buf }, violations(InvalidLength)},
+		{"A bit without section", nil, func(buf []byte) []byte { buf[1] |= 1 << 2; return buf }, violations(InvalidLength, InvalidAuthenticationLength)},
+		{"section without A bit", md5, func(buf []byte) []byte { buf[1] &^= 1 << 2; return buf }, violations(InvalidAuthenticationLength)},
+		{"auth length", md5, func(buf []byte) []byte { buf[25] = 20; return buf }, violations(InvalidAuthenticationLength)},
+		{"auth type", md5, func(buf []byte) []byte { buf[24] = 100; return buf }, violations(InvalidAuthenticationType)},
+		{"reserved", md5, func(buf []byte) []byte { buf[27] = 1; return buf }, violations(ReservedFieldSet)},
+	}
+
+	for _, test := range tests {
+		result := Validate(test.modify(newValidatePacket(t, test.header)))
+
+		if result != test.result {
+			t.Errorf("%s: expected %s, got %s", test.name, test.result, result)
+		}
+	}
+}
+
+func TestViolationsDiscard(t *testing.T) {
+	if violations(ReservedFieldSet).Discard() {
+		t.Errorf("Expected reserved fields to be ignored")
+	}
+
+	if !violations(ReservedFieldSet, InvalidVersion).Discard() {
+		t.Errorf("Expected an invalid version to be discarded")
+	}
+
+	list := violations(ZeroMyDiscriminator, InvalidVersion).List()
+
+	if !reflect.DeepEqual(list, []Violation{InvalidVersion, ZeroMyDiscriminator}) {
+		t.Errorf("Unexpected list %v", list)
+	}
+
+	if s := violations(InvalidVersion, MultipointSet).String(); s != "Invalid Version, Multipoint Set" {
+		t.Errorf("Unexpected string %s", s)
+	}
+}
+
+func TestControlPacketValidate(t *testing.T) {
+	packet := &ControlPacket{
+		Version: 2,
+		State:   Init,
+	}
+
+	expected := violations(InvalidVersion, ZeroDetectMultiplier, ZeroMyDiscriminator, ZeroYourDiscriminator)
+
+	if v := packet.Validate(); v != expected {
+		t.Errorf("Expected %s, got %s", expected, v)
+	}
+}
+
+func TestValidateAllocations(t *testing.T) {
+	buf := newValidatePacket(t, &KeyedSHA1Header{AuthType: KeyedSHA1, AuthKey: []byte("secret")})
+
+	if allocs := testing.AllocsPerRun(100, func() { Validate(buf) }); allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
@@ -0,0 +1,319 @@
+package bfd
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+)
+
+const (
+	VXLAN_PORT = 4789
+
+	VXLAN_HEADER_LENGTH    = 8
+	ETHERNET_HEADER_LENGTH = 14
+	IPV4_HEADER_LENGTH     = 20
+	IPV6_HEADER_LENGTH     = 40
+	UDP_HEADER_LENGTH      = 8
+
+	IPPROTO_UDP = 17
+
+	// the VNI is a 24 bit value
+	VXLAN_MAX_VNI = 1<<24 - 1
+
+	vxlanFlagVni      = 0x08
+	etherTypeIPv4     = 0x0800
+	etherTypeIPv6     = 0x86dd
+	ipv4VersionLength = 0x45 // version 4, header length 5 * 4 bytes
+)
+
+// VxlanMAC is the inner destination MAC of BFD for VXLAN, assigned by IANA to RFC8971
+var VxlanMAC = net.HardwareAddr{0x00, 0x00, 0x5e, 0x00, 0x52, 0x02}
+
+var ErrInvalidVNI = errors.New("Invalid VNI, should not exceed 24 bits")
+var ErrInvalidVxlanHeader = errors.New("Invalid VXLAN header, the VNI flag isn't set")
+var ErrInvalidEtherType = errors.New("Invalid EtherType, only IPv4 and IPv6 are supported")
+var ErrInvalidIPHeader = errors.New("Invalid IP header")
+var ErrInvalidProtocol = errors.New("Invalid protocol, only UDP is supported")
+var ErrInvalidChecksum = errors.New("Invalid checksum")
+
+/*
+	RFC8971 3 / RFC7348 5
+	BFD Control packets are sent inside VXLAN, as if they were sent over the
+	Ethernet segment connecting the two VTEPs. The outer IP / UDP headers
+	are added by the socket, the VXLAN header is followed by the inner
+	Ethernet, IP and UDP headers:
+
+    0                   1                   2                   3
+    0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |R|R|R|R|I|R|R|R|            Reserved                           |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |                VXLAN Network Identifier (VNI) |   Reserved    |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |            Inner Ethernet Header (destination, source MAC,    |
+   |            EtherType)                                         |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |            Inner IPv4 / IPv6 Header                           |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |            Inner UDP Header                                   |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+   |            BFD Control Packet                                 |
+   +-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+-+
+
+	The inner destination address is taken from 127/8 (IPv4) or
+	::ffff:127.0.0.0/104 (IPv6), so the packet is never forwarded once it
+	left the tunnel, and the TTL / Hop Limit is 255.
+*/
+
+type VxlanPacket struct {
+	VNI uint32
+
+	DstMAC net.HardwareAddr
+	SrcMAC net.HardwareAddr
+
+	SrcIP net.IP
+	DstIP net.IP
+	TTL   uint8 // TTL / Hop Limit
+
+	SrcPort uint16
+	DstPort uint16
+
+	Payload []byte // the encapsulated BFD Control packet
+}
+
+// Encapsulate sets the payload to the control packet
+func (v *VxlanPacket) Encapsulate(c *ControlPacket) error {
+	payload, err := c.MarshalBinary()
+
+	if err != nil {
+		return err
+	}
+
+	v.Payload = payload
+
+	return nil
+}
+
+// Decapsulate parses the payload as control packet
+func (v *VxlanPacket) Decapsulate() (*ControlPacket, error) {
+	c := &ControlPacket{}
+
+	if err := c.UnmarshalBinary(v.Payload); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (v *VxlanPacket) UnmarshalBinary(buf []byte) error {
+	if len(buf) < VXLAN_HEADER_LENGTH+ETHERNET_HEADER_LENGTH {
+		return ErrInvalidPacketLength
+	}
+
+	if buf[0]&vxlanFlagVni == 0 {
+		return ErrInvalidVxlanHeader
+	}
+
+	v.VNI = binary.BigEndian.Uint32(buf[4:]) >> 8
+
+	eth := buf[VXLAN_HEADER_LENGTH:]
+
+	v.DstMAC = net.HardwareAddr(copyBytes(eth[0:6]))
+	v.SrcMAC = net.HardwareAddr(copyBytes(eth[6:12]))
+
+	ip := eth[ETHERNET_HEADER_LENGTH:]
+
+	var udp []byte
+	var pseudo []byte
+
+	switch binary.BigEndian.Uint16(eth[12:]) {
+	case etherTypeIPv4:
+		if len(ip) < IPV4_HEADER_LENGTH {
+			return ErrInvalidPacketLength
+		}
+
+		// options aren't used by BFD
+		if ip[0] != ipv4VersionLength {
+			return ErrInvalidIPHeader
+		}
+
+		if Checksum(ip[:IPV4_HEADER_LENGTH], 0) != 0 {
+			return ErrInvalidChecksum
+		}
+
+		if ip[9] != IPPROTO_UDP {
+			return ErrInvalidProtocol
+		}
+
+		length := int(binary.BigEndian.Uint16(ip[2:]))
+
+		if length < IPV4_HEADER_LENGTH+UDP_HEADER_LENGTH || length > len(ip) {
+			return ErrInvalidPacketLength
+		}
+
+		v.TTL = ip[8]
+		v.SrcIP = net.IP(copyBytes(ip[12:16]))
+		v.DstIP = net.IP(copyBytes(ip[16:20]))
+
+		udp = ip[IPV4_HEADER_LENGTH:length]
+		pseudo = ip[12:20]
+	case etherTypeIPv6:
+		if len(ip) < IPV6_HEADER_LENGTH {
+			return ErrInvalidPacketLength
+		}
+
+		if ip[0]>>4 != 6 {
+			return ErrInvalidIPHeader
+		}
+
+		if ip[6] != IPPROTO_UDP {
+			return ErrInvalidProtocol
+		}
+
+		length := int(binary.BigEndian.Uint16(ip[4:]))
+
+		if length < UDP_HEADER_LENGTH || IPV6_HEADER_LENGTH+length > len(ip) {
+			return ErrInvalidPacketLength
+		}
+
+		v.TTL = ip[7]
+		v.SrcIP = net.IP(copyBytes(ip[8:24]))
+		v.DstIP = net.IP(copyBytes(ip[24:40]))
+
+		udp = ip[IPV6_HEADER_LENGTH : IPV6_HEADER_LENGTH+length]
+		pseudo = ip[8:40]
+	default:
+		return ErrInvalidEtherType
+	}
+
+	if int(binary.BigEndian.Uint16(udp[4:])) != len(udp) {
+		return ErrInvalidPacketLength
+	}
+
+	// a zero checksum means none was calculated (IPv4 only, but tolerated for tunnels on IPv6 as well)
+	if binary.BigEndian.Uint16(udp[6:]) != 0 && UDPChecksum(pseudo, udp) != 0 {
+		return ErrInvalidChecksum
+	}
+
+	v.SrcPort = binary.BigEndian.Uint16(udp[0:])
+	v.DstPort = binary.BigEndian.Uint16(udp[2:])
+	v.Payload = copyBytes(udp[UDP_HEADER_LENGTH:])
+
+	return nil
+}
+
+func (v *VxlanPacket) MarshalBinary() ([]byte, error) {
+	if v.VNI > VXLAN_MAX_VNI {
+		return nil, ErrInvalidVNI
+	}
+
+	src4, dst4 := v.SrcIP.To4(), v.DstIP.To4()
+	ipv4 := src4 != nil && dst4 != nil
+
+	if !ipv4 && (len(v.SrcIP) != net.IPv6len || len(v.DstIP) != net.IPv6len) {
+		return nil, ErrInvalidIPHeader
+	}
+
+	ipLength := IPV6_HEADER_LENGTH
+
+	if ipv4 {
+		ipLength = IPV4_HEADER_LENGTH
+	}
+
+	udpLength := UDP_HEADER_LENGTH + len(v.Payload)
+
+	buf := make([]byte, VXLAN_HEADER_LENGTH+ETHERNET_HEADER_LENGTH+ipLength+udpLength)
+
+	buf[0] = vxlanFlagVni
+	binary.BigEndian.PutUint32(buf[4:], v.VNI<<8)
+
+	eth := buf[VXLAN_HEADER_LENGTH:]
+
+	copy(eth[0:6], v.DstMAC)
+	copy(eth[6:12], v.SrcMAC)
+
+	ip := eth[ETHERNET_HEADER_LENGTH:]
+	udp := ip[ipLength:]
+
+	var pseudo []byte
+
+	if ipv4 {
+		binary.BigEndian.PutUint16(eth[12:], etherTypeIPv4)
+
+		ip[0] = ipv4VersionLength
+		binary.BigEndian.PutUint16(ip[2:], uint16(ipLength+udpLength))
+		ip[8] = v.TTL
+		ip[9] = IPPROTO_UDP
+		copy(ip[12:16], src4)
+		copy(ip[16:20], dst4)
+		binary.BigEndian.PutUint16(ip[10:], Checksum(ip[:IPV4_HEADER_LENGTH], 0))
+
+		pseudo = ip[12:20]
+	} else {
+		binary.BigEndian.PutUint16(eth[12:], etherTypeIPv6)
+
+		ip[0] = 6 << 4
+		binary.BigEndian.PutUint16(ip[4:], uint16(udpLength))
+		ip[6] = IPPROTO_UDP
+		ip[7] = v.TTL
+		copy(ip[8:24], v.SrcIP)
+		copy(ip[24:40], v.DstIP)
+
+		pseudo = ip[8:40]
+	}
+
+	binary.BigEndian.PutUint16(udp[0:], v.SrcPort)
+	binary.BigEndian.PutUint16(udp[2:], v.DstPort)
+	binary.BigEndian.PutUint16(udp[4:], uint16(udpLength))
+	copy(udp[UDP_HEADER_LENGTH:], v.Payload)
+
+	sum := UDPChecksum(pseudo, udp)
+
+	// a calculated checksum of 0 is sent as all ones
+	if sum == 0 {
+		sum = 0xffff
+	}
+
+	binary.BigEndian.PutUint16(udp[6:], sum)
+
+	return buf, nil
+}
+
+// UDPChecksum calculates the checksum over the pseudo header (source and destination address) and the datagram
+func UDPChecksum(addresses []byte, udp []byte) uint16 {
+	sum := uint32(IPPROTO_UDP) + uint32(len(udp))
+
+	return Checksum(udp, sum+partialChecksum(addresses))
+}
+
+// Checksum returns the internet checksum (RFC1071) of buf, initial is added to the sum
+func Checksum(buf []byte, initial uint32) uint16 {
+	sum := initial + partialChecksum(buf)
+
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+
+	return ^uint16(sum)
+}
+
+func partialChecksum(buf []byte) uint32 {
+	var sum uint32
+
+	for i := 0; i+1 < len(buf); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(buf[i:]))
+	}
+
+	if len(buf)%2 == 1 {
+		sum += uint32(buf[len(buf)-1]) << 8
+	}
+
+	return sum
+}
+
+func copyBytes(buf []byte) []byte {
+	c := make([]byte, len(buf))
+	copy(c, buf)
+
+	return c
+}
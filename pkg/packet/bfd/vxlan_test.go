@@ -0,0 +1,185 @@
+package bfd
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+var vxlanControlPacket = &ControlPacket{
+	Version:               1,
+	State:                 Down,
+	DetectMultiplier:      3,
+	MyDiscriminator:       1,
+	DesiredMinTxInterval:  1000000,
+	RequiredMinRxInterval: 1000000,
+}
+
+var vxlanSrcMAC = net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+
+// VNI 1000, 192.0.2.1:49152 -> 127.0.0.1:3784, TTL 1
+var vxlanIPv4Golden = []byte{
+	0x08, 0x00, 0x00, 0x00, 0x00, 0x03, 0xe8, 0x00,
+	0x00, 0x00, 0x5e, 0x00, 0x52, 0x02, 0x02, 0x00,
+	0x00, 0x00, 0x00, 0x01, 0x08, 0x00, 0x45, 0x00,
+	0x00, 0x34, 0x00, 0x00, 0x00, 0x00, 0x01, 0x11,
+	0x78, 0xb7, 0xc0, 0x00, 0x02, 0x01, 0x7f, 0x00,
+	0x00, 0x01, 0xc0, 0x00, 0x0e, 0xc8, 0x00, 0x20,
+	0x47, 0xeb, 0x20, 0x40, 0x03, 0x18, 0x00, 0x00,
+	0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0f,
+	0x42, 0x40, 0x00, 0x0f, 0x42, 0x40, 0x00, 0x00,
+	0x00, 0x00,
+}
+
+// VNI 0xabcdef, [2001:db8::1]:49152 -> [::ffff:127.0.0.1]:3784, Hop Limit 1
+var vxlanIPv6Golden = []byte{
+	0x08, 0x00, 0x00, 0x00, 0xab, 0xcd, 0xef, 0x00,
+	0x00, 0x00, 0x5e, 0x00, 0x52, 0x02, 0x02, 0x00,
+	0x00, 0x00, 0x00, 0x01, 0x86, 0xdd, 0x60, 0x00,
+	0x00, 0x00, 0x00, 0x20, 0x11, 0x01, 0x20, 0x01,
+	0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0x7f, 0x00, 0x00, 0x01, 0xc0, 0x00,
+	0x0e, 0xc8, 0x00, 0x20, 0xdc, 0x32, 0x20, 0x40,
+	0x03, 0x18, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x0f, 0x42, 0x40, 0x00, 0x0f,
+	0x42, 0x40, 0x00, 0x00, 0x00, 0x00,
+}
+
+func TestVxlanPacketMarshal(t *testing.T) {
+	tests := []struct {
+		pkt    *VxlanPacket
+		target []byte
+	}{
+		{
+			&VxlanPacket{
+				VNI:     1000,
+				DstMAC:  VxlanMAC,
+				SrcMAC:  vxlanSrcMAC,
+				SrcIP:   net.ParseIP("192.0.2.1"),
+				DstIP:   net.ParseIP("127.0.0.1"),
+				TTL:     1,
+				SrcPort: 49152,
+				DstPort: 3784,
+			},
+			vxlanIPv4Golden,
+		},
+		{
+			&VxlanPacket{
+				VNI:     0xabcdef,
+				DstMAC:  VxlanMAC,
+				SrcMAC:  vxlanSrcMAC,
+				SrcIP:   net.ParseIP("2001:db8::1"),
+				DstIP:   net.ParseIP("::ffff:127.0.0.1"),
+				TTL:     1,
+				SrcPort: 49152,
+				DstPort: 3784,
+			},
+			vxlanIPv6Golden,
+		},
+	}
+
+	for _, test := range tests {
+		if err := test.pkt.Encapsulate(vxlanControlPacket); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		data, err := test.pkt.MarshalBinary()
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		if !bytes.Equal(data, test.target) {
+			t.Errorf("Expected %v, got %v", test.target, data)
+		}
+	}
+}
+
+func TestVxlanPacketUnmarshal(t *testing.T) {
+	tests := []struct {
+		data  []byte
+		vni   uint32
+		srcIP net.IP
+		dstIP net.IP
+	}{
+		{vxlanIPv4Golden, 1000, net.ParseIP("192.0.2.1"), net.ParseIP("127.0.0.1")},
+		{vxlanIPv6Golden, 0xabcdef, net.ParseIP("2001:db8::1"), net.ParseIP("::ffff:127.0.0.1")},
+	}
+
+	for _, test := range tests {
+		pkt := &VxlanPacket{}
+
+		if err := pkt.UnmarshalBinary(test.data); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		if pkt.VNI != test.vni || !pkt.SrcIP.Equal(test.srcIP) || !pkt.DstIP.Equal(test.dstIP) {
+			t.Errorf("Unexpected addressing %v", pkt)
+		}
+
+		if !bytes.Equal(pkt.DstMAC, VxlanMAC) || !bytes.Equal(pkt.SrcMAC, vxlanSrcMAC) {
+			t.Errorf("Unexpected MAC addresses %v -> %v", pkt.SrcMAC, pkt.DstMAC)
+		}
+
+		if pkt.TTL != 1 || pkt.SrcPort != 49152 || pkt.DstPort != 3784 {
+			t.Errorf("Unexpected TTL / ports %v", pkt)
+		}
+
+		control, err := pkt.Decapsulate()
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		if *control != *vxlanControlPacket {
+			t.Errorf("Expected %v, got %v", vxlanControlPacket, control)
+		}
+	}
+}
+
+func TestVxlanPacketUnmarshalInvalid(t *testing.T) {
+	corrupt := func(data []byte, idx int, value byte) []byte {
+		c := make([]byte, len(data))
+		copy(c, data)
+		c[idx] = value
+
+		return c
+	}
+
+	tests := []struct {
+		data []byte
+		err  error
+	}{
+		{vxlanIPv4Golden[:20], ErrInvalidPacketLength},
+		{corrupt(vxlanIPv4Golden, 0, 0x00), ErrInvalidVxlanHeader},
+		{corrupt(vxlanIPv4Golden, 21, 0x06), ErrInvalidEtherType},
+		{corrupt(vxlanIPv4Golden, 22, 0x46), ErrInvalidIPHeader},
+		{corrupt(vxlanIPv4Golden, 30, 0x02), ErrInvalidChecksum},
+		{corrupt(vxlanIPv4Golden, 73, 0x01), ErrInvalidChecksum},
+		{vxlanIPv4Golden[:60], ErrInvalidPacketLength},
+		{corrupt(vxlanIPv6Golden, 28, 0x06), ErrInvalidProtocol},
+		{corrupt(vxlanIPv6Golden, 93, 0x01), ErrInvalidChecksum},
+	}
+
+	for _, test := range tests {
+		pkt := &VxlanPacket{}
+
+		if err := pkt.UnmarshalBinary(test.data); err != test.err {
+			t.Errorf("Expected %v, got %v", test.err, err)
+		}
+	}
+}
+
+func TestVxlanPacketInvalidVNI(t *testing.T) {
+	pkt := &VxlanPacket{
+		VNI:   VXLAN_MAX_VNI + 1,
+		SrcIP: net.ParseIP("192.0.2.1"),
+		DstIP: net.ParseIP("127.0.0.1"),
+	}
+
+	if _, err := pkt.MarshalBinary(); err != ErrInvalidVNI {
+		t.Errorf("Expected %v, got %v", ErrInvalidVNI, err)
+	}
+}
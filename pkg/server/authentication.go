@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+var ErrInvalidAuthenticationKey = errors.New("Invalid authentication key, SimplePassword and MD5 need 1 to 16 bytes, SHA1 needs 1 to 20 bytes, HMAC-SHA256 1 to 64 bytes")
+var ErrInvalidAuthenticationKeyId = errors.New("Invalid authentication key id, should be between 0 and 255")
+var ErrInvalidOptimizedAuthentication = errors.New("Optimized authentication needs a keyed authentication type")
+
+// parseAuthentication converts the api authentication into the type, key id and key used by a peer
+func parseAuthentication(auth *api.Authentication) (bfd.AuthenticationType, int8, []byte, error) {
+	if auth == nil || auth.Type == api.AuthenticationType_NONE {
+		return bfd.Reserved, 0, nil, nil
+	}
+
+	authType := bfd.AuthenticationType(auth.Type)
+	provider, err := bfd.GetAuthenticationProvider(authType)
+
+	// the secure sequence type only authenticates the packets of optimized authentication
+	if err != nil || authType == bfd.MeticulousKeyedSecureSequence {
+		return bfd.Reserved, 0, nil, bfd.ErrInvalidAuthenticationType
+	}
+
+	// the secure sequence number continues the sequence of the keyed packets
+	if auth.Optimized && authType == bfd.SimplePassword {
+		return bfd.Reserved, 0, nil, ErrInvalidOptimizedAuthentication
+	}
+
+	// the keys are taken from the chain, they are validated when the peer is added
+	if auth.KeyChain != "" {
+		return authType, 0, nil, nil
+	}
+
+	if auth.KeyId > 255 {
+		return bfd.Reserved, 0, nil, ErrInvalidAuthenticationKeyId
+	}
+
+	key, err := parseAuthenticationKey(auth.Password)
+
+	if err != nil {
+		return bfd.Reserved, 0, nil, err
+	}
+
+	if len(key) < 1 || len(key) > provider.MaxKeyLength() {
+		return bfd.Reserved, 0, nil, ErrInvalidAuthenticationKey
+	}
+
+	return authType, int8(auth.KeyId), key, nil
+}
+
+// parseAuthenticationKey decodes a password, passwords starting with 0x are hex encoded
+func parseAuthenticationKey(password string) ([]byte, error) {
+	if strings.HasPrefix(password, "0x") {
+		return hex.DecodeString(password[2:])
+	}
+
+	return []byte(password), nil
+}
+
+func maxAuthenticationKeyLength(authType bfd.AuthenticationType) int {
+	provider, err := bfd.GetAuthenticationProvider(authType)
+
+	if err != nil {
+		return 0
+	}
+
+	return provider.MaxKeyLength()
+}
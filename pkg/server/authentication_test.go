@@ -0,0 +1,136 @@
+package server
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+func TestParseAuthenticationNone(t *testing.T) {
+	authType, _, key, err := parseAuthentication(nil)
+
+	if err != nil || authType != bfd.Reserved || key != nil {
+		t.Fail()
+	}
+
+	authType, _, key, err = parseAuthentication(&api.Authentication{
+		Type:     api.AuthenticationType_NONE,
+		Password: "ignored",
+	})
+
+	if err != nil || authType != bfd.Reserved || key != nil {
+		t.Fail()
+	}
+}
+
+func TestParseAuthenticationPassword(t *testing.T) {
+	authType, keyId, key, err := parseAuthentication(&api.Authentication{
+		Type:     api.AuthenticationType_METICULOUS_KEYED_MD5,
+		Password: "secret",
+		KeyId:    200,
+	})
+
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	if authType != bfd.MeticulousKeyedMD5 || uint8(keyId) != 200 || string(key) != "secret" {
+		t.Fail()
+	}
+}
+
+func TestParseAuthenticationHex(t *testing.T) {
+	_, _, key, err := parseAuthentication(&api.Authentication{
+		Type:     api.AuthenticationType_KEYED_SHA1,
+		Password: "0x000102ff",
+	})
+
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	if !reflect.DeepEqual(key, []byte{0, 1, 2, 255}) {
+		t.Errorf("%v", key)
+	}
+
+	_, _, _, err = parseAuthentication(&api.Authentication{
+		Type:     api.AuthenticationType_KEYED_SHA1,
+		Password: "0xzz",
+	})
+
+	if err == nil {
+		t.Errorf("Expected hex decoding error")
+	}
+}
+
+func TestParseAuthenticationKeyLength(t *testing.T) {
+	cases := []struct {
+		authType api.AuthenticationType
+		password string
+		err      error
+	}{
+		{api.AuthenticationType_SIMPLE_PASSWORD, "", ErrInvalidAuthenticationKey},
+		{api.AuthenticationType_SIMPLE_PASSWORD, "0123456789abcdef", nil},
+		{api.AuthenticationType_SIMPLE_PASSWORD, "0123456789abcdefg", ErrInvalidAuthenticationKey},
+		{api.AuthenticationType_KEYED_MD5, "0123456789abcdef", nil},
+		{api.AuthenticationType_KEYED_MD5, "0123456789abcdefg", ErrInvalidAuthenticationKey},
+		{api.AuthenticationType_METICULOUS_KEYED_SHA1, "0123456789abcdefghij", nil},
+		{api.AuthenticationType_METICULOUS_KEYED_SHA1, "0123456789abcdefghijk", ErrInvalidAuthenticationKey},
+		{api.AuthenticationType_METICULOUS_KEYED_HMAC_SHA256, "0123456789abcdefghij0123456789abcdefghij", nil},
+		{api.AuthenticationType_METICULOUS_KEYED_HMAC_SHA256, "0x" + strings.Repeat("00", 65), ErrInvalidAuthenticationKey},
+		{api.AuthenticationType(bfd.MeticulousKeyedSecureSequence), "secret", bfd.ErrInvalidAuthenticationType},
+		{api.AuthenticationType(9), "secret", bfd.ErrInvalidAuthenticationType},
+	}
+
+	for _, c := range cases {
+		_, _, _, err := parseAuthentication(&api.Authentication{
+			Type:     c.authType,
+			Password: c.password,
+		})
+
+		if err != c.err {
+			t.Errorf("%s with %q: expected %v, got %v", c.authType, c.password, c.err, err)
+		}
+	}
+}
+
+func TestParseAuthenticationKeyId(t *testing.T) {
+	_, _, _, err := parseAuthentication(&api.Authentication{
+		Type:     api.AuthenticationType_KEYED_MD5,
+		Password: "secret",
+		KeyId:    256,
+	})
+
+	if err != ErrInvalidAuthenticationKeyId {
+		t.Fail()
+	}
+}
+
+func TestParseAuthenticationOptimized(t *testing.T) {
+	cases := []struct {
+		authType api.AuthenticationType
+		err      error
+	}{
+		{api.AuthenticationType_METICULOUS_KEYED_HMAC_SHA256, nil},
+		{api.AuthenticationType_METICULOUS_KEYED_MD5, nil},
+		{api.AuthenticationType_KEYED_SHA1, nil},
+		{api.AuthenticationType_SIMPLE_PASSWORD, ErrInvalidOptimizedAuthentication},
+	}
+
+	for _, c := range cases {
+		_, _, _, err := parseAuthentication(&api.Authentication{
+			Type:      c.authType,
+			Password:  "secret",
+			Optimized: true,
+		})
+
+		if err != c.err {
+			t.Errorf("%s: expected %v, got %v", c.authType, c.err, err)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"syscall"
+
+	"github.com/golang/glog"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+const (
+	// largest number of packets read or written with one syscall
+	MAX_BATCH_SIZE = 1024
+
+	// buffer per packet of a batched read, jumbo frames fit, sessions can't be padded beyond
+	BATCH_BUFFER_SIZE = 9216
+
+	// packets queued for a shared socket before the sessions wait for it
+	BATCH_QUEUE_LENGTH = 4096
+)
+
+/*
+	By default every listener reads one packet per syscall and every session
+	writes through a connected socket of its own. With batched I/O the
+	listeners read up to a batch of packets with one recvmmsg, optionally on
+	several sockets sharing the address with SO_REUSEPORT, each of them read
+	by its own goroutine. The kernel picks the socket by hashing the source
+	and destination, so the packets of a session always arrive in order.
+
+	Optionally the sessions share one unconnected socket per source address
+	and family, the packets queued while a sendmmsg is in progress go out
+	with the next one. The replies of the remotes arrive on the listeners,
+	what arrives on the shared socket is discarded. Micro BFD, VXLAN and
+	padded sessions depend on options of their own socket, they keep the
+	connected one. The padding of their packets is limited to the buffer of
+	a batched read, larger packets are discarded by the listeners.
+
+	The shared socket is opt-in, as its sessions share the source port:
+
+	RFC5881 4
+	The source port number SHOULD be unique among all BFD
+	sessions on the system.
+
+	The remotes still tell the sessions apart by their discriminators, but
+	they can't demultiplex them by the source port before a discriminator
+	is known.
+*/
+
+var ErrInvalidBatchSize = errors.New("Invalid batch size, should be between 0 and 1024")
+var ErrInvalidListenerSockets = errors.New("Invalid number of sockets per listener, should not be negative")
+var ErrReusePortNotSupported = errors.New("Sharing a port between sockets isn't supported on this platform")
+var ErrBatchPadToSize = errors.New("Invalid padding size, should not exceed 9216 bytes with batched I/O")
+var ErrTruncatedPacket = errors.New("Discarded Packet: Exceeds the buffer of a batched read")
+
+// batchReadWriter is implemented by ipv4.PacketConn and ipv6.PacketConn, their messages are the same type
+type batchReadWriter interface {
+	ReadBatch(ms []ipv4.Message, flags int) (int, error)
+	WriteBatch(ms []ipv4.Message, flags int) (int, error)
+}
+
+func newBatchReadWriter(conn *net.UDPConn, ip net.IP) batchReadWriter {
+	if ip.To4() != nil {
+		return ipv4.NewPacketConn(conn)
+	}
+
+	return ipv6.NewPacketConn(conn)
+}
+
+// SetBatchIO reads up to size packets per syscall (recvmmsg), every listener opens sockets sharing
+// its address with SO_REUSEPORT, 0 = 1. With shared the sessions also write through a socket shared
+// per source address (sendmmsg), so they share its source port. It applies to the listeners and
+// sessions added afterwards, a size of 0 reads and writes a single packet per syscall.
+func (s *BfdServer) SetBatchIO(size, sockets int, shared bool) error {
+	if size < 0 || size > MAX_BATCH_SIZE {
+		return ErrInvalidBatchSize
+	}
+
+	if sockets < 0 {
+		return ErrInvalidListenerSockets
+	}
+
+	if sockets == 0 {
+		sockets = 1
+	}
+
+	s.Lock()
+	s.batchSize = size
+	s.listenerSockets = sockets
+	s.sharedSender = shared
+	s.Unlock()
+
+	return nil
+}
+
+func (s *BfdServer) getBatchIO() (int, int, bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.batchSize, s.listenerSockets, s.sharedSender
+}
+
+// listenReusePort opens count kernel sockets bound to addr, which share the port with SO_REUSEPORT,
+// port 0 picks a free port for all of them
+func listenReusePort(addr *net.UDPAddr, count int) ([]UDPConn, error) {
+	lc := net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			return setReusePort(c)
+		},
+	}
+
+	conns := []UDPConn{}
+
+	for i := 0; i < count; i++ {
+		conn, err := lc.ListenPacket(context.Background(), "udp", addr.String())
+
+		// Setup so that we receive the TTL / Hop Limit, destination and interface of incoming packets
+		if err == nil {
+			conns = append(conns, conn.(*net.UDPConn))
+			err = setControlMessages(conn.(*net.UDPConn), addr.IP)
+
+			// the other sockets share the port picked for the first one
+			addr = &net.UDPAddr{IP: addr.IP, Port: conn.LocalAddr().(*net.UDPAddr).Port, Zone: addr.Zone}
+		}
+
+		if err != nil {
+			for _, c := range conns {
+				c.Close()
+			}
+
+			return nil, err
+		}
+	}
+
+	return conns, nil
+}
+
+// handleIncomingBatches reads the packets of one socket of a listener in batches
+func (s *BfdServer) handleIncomingBatches(l *listener, conn batchReadWriter, size int) {
+	defer s.readers.Done()
+
+	msgs := make([]ipv4.Message, size)
+
+	for i := range msgs {
+		msgs[i].Buffers = [][]byte{make([]byte, BATCH_BUFFER_SIZE)}
+		msgs[i].OOB = make([]byte, 256)
+	}
+
+	for {
+		err := s.readIncomingBatch(l, conn, msgs)
+
+		if err != nil {
+			// the sockets are closed on shutdown
+			if s.isStopped() {
+				return
+			}
+
+			glog.Errorf("%v", err)
+		}
+	}
+}
+
+// readIncomingBatch reads up to len(msgs) packets with a single syscall and handles each of them
+// like a packet of a single read, an invalid packet doesn't discard the rest of the batch
+func (s *BfdServer) readIncomingBatch(l *listener, conn batchReadWriter, msgs []ipv4.Message) error {
+	n, err := conn.ReadBatch(msgs, 0)
+
+	if err != nil {
+		return err
+	}
+
+	for _, m := range msgs[:n] {
+		addr, _ := m.Addr.(*net.UDPAddr)
+
+		if isTruncated(m.Flags) {
+			glog.Errorf("%v from %v", ErrTruncatedPacket, addr)
+			continue
+		}
+
+		err = s.handleDatagram(l, m.Buffers[0][:m.N], m.OOB[:m.NN], addr)
+
+		if err != nil {
+			glog.Errorf("%v", err)
+		}
+	}
+
+	return nil
+}
+
+// batchSender writes the packets of the sessions sharing its socket in batches
+type batchSender struct {
+	conn    *net.UDPConn
+	writer  batchReadWriter
+	queue   chan outgoing
+	control chan bool
+	stopped sync.Once
+}
+
+// outgoing is a packet queued for a batchSender
+type outgoing struct {
+	buf  *sendBuffer
+	n    int
+	addr *net.UDPAddr
+}
+
+// sendBuffer holds a copy of a queued packet, padded packets aren't sent in batches
+type sendBuffer [bfd.MAXIMUM_LENGTH]byte
+
+var sendBuffers = sync.Pool{
+	New: func() interface{} {
+		return &sendBuffer{}
+	},
+}
+
+// newBatchSender starts writing the packets queued for conn in batches of up to size packets
+func newBatchSender(conn *net.UDPConn, writer batchReadWriter, size int) *batchSender {
+	b := &batchSender{
+		conn:    conn,
+		writer:  writer,
+		queue:   make(chan outgoing, BATCH_QUEUE_LENGTH),
+		control: make(chan bool),
+	}
+
+	go b.run(size)
+	go b.drain()
+
+	return b
+}
+
+// drain discards the packets received on the shared socket, so that they don't fill its buffer
+func (b *batchSender) drain() {
+	buf := make([]byte, 64)
+
+	for {
+		if _, _, err := b.conn.ReadFromUDP(buf); err != nil {
+			// the socket is closed by close
+			select {
+			case <-b.control:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// send queues a copy of p for addr, errors of the write are only logged
+func (b *batchSender) send(p []byte, addr *net.UDPAddr) (int, error) {
+	if len(p) > bfd.MAXIMUM_LENGTH {
+		return 0, bfd.ErrBufferTooSmall
+	}
+
+	buf := sendBuffers.Get().(*sendBuffer)
+	n := copy(buf[:], p)
+
+	select {
+	case b.queue <- outgoing{buf: buf, n: n, addr: addr}:
+		return n, nil
+	case <-b.control:
+		sendBuffers.Put(buf)
+		return 0, net.ErrClosed
+	}
+}
+
+func (b *batchSender) run(size int) {
+	msgs := make([]ipv4.Message, size)
+	batch := make([]outgoing, 0, size)
+
+	for i := range msgs {
+		msgs[i].Buffers = make([][]byte, 1)
+	}
+
+	for {
+		select {
+		case o := <-b.queue:
+			batch = append(batch[:0], o)
+		case <-b.control:
+			return
+		}
+
+		// the packets queued in the meantime go out with the same syscall
+	fill:
+		for len(batch) < size {
+			select {
+			case o := <-b.queue:
+				batch = append(batch, o)
+			default:
+				break fill
+			}
+		}
+
+		b.write(batch, msgs)
+	}
+}
+
+// write sends a batch, a packet that can't be sent is skipped so that the rest still goes out
+func (b *batchSender) write(batch []outgoing, msgs []ipv4.Message) {
+	for i, o := range batch {
+		msgs[i].Buffers[0] = o.buf[:o.n]
+		msgs[i].Addr = o.addr
+	}
+
+	for sent := 0; sent < len(batch); {
+		n, err := b.writer.WriteBatch(msgs[sent:len(batch)], 0)
+
+		if err != nil || n == 0 {
+			glog.Infof("Error on write to %s: %v", batch[sent].addr, err)
+			n = 1
+		}
+
+		sent += n
+	}
+
+	for i, o := range batch {
+		sendBuffers.Put(o.buf)
+		msgs[i].Addr = nil
+	}
+}
+
+// close stops the sender and closes its socket, queued packets aren't sent anymore
+func (b *batchSender) close() {
+	b.stopped.Do(func() {
+		close(b.control)
+		b.conn.Close()
+	})
+}
+
+// batchConn is the connection of a session sending through the shared socket of a batchSender
+type batchConn struct {
+	*net.UDPConn
+
+	sender *batchSender
+	remote *net.UDPAddr
+}
+
+func (c *batchConn) Write(b []byte) (int, error) {
+	return c.sender.send(b, c.remote)
+}
+
+// Close leaves the shared socket open, it's closed with the sender
+func (c *batchConn) Close() error {
+	return nil
+}
+
+// getSender returns the shared socket for the sessions from local to addresses of the family of remote,
+// it's opened on first use with sourcePort
+func (s *BfdServer) getSender(local, remote net.IP, sourcePort, size int) (*batchSender, error) {
+	network := "udp4"
+
+	if remote.To4() == nil {
+		network = "udp6"
+	}
+
+	key := network + " " + local.String()
+
+	s.Lock()
+	defer s.Unlock()
+
+	if sender, ok := s.senders[key]; ok {
+		return sender, nil
+	}
+
+	laddr := &net.UDPAddr{IP: local, Port: sourcePort}
+
+	// the control messages are set up for the family of the address
+	if local == nil && network == "udp4" {
+		laddr.IP = net.IPv4zero
+	} else if local == nil {
+		laddr.IP = net.IPv6unspecified
+	}
+
+	conn, err := s.transport.ListenUDP(network, laddr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// sendmmsg needs a kernel socket
+	udp, err := kernelConn(conn)
+
+	if err == nil {
+		err = setMaxHopLimit(udp, remote)
+	}
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	sender := newBatchSender(udp, newBatchReadWriter(udp, remote), size)
+	s.senders[key] = sender
+
+	return sender, nil
+}
@@ -0,0 +1,308 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+
+	"golang.org/x/net/ipv4"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+// fakeBatchConn returns msgs on ReadBatch and records the written batches
+type fakeBatchConn struct {
+	msgs    []ipv4.Message
+	written [][]byte
+	fail    int // the write of the packet at this index fails, -1 = none
+}
+
+func (f *fakeBatchConn) ReadBatch(ms []ipv4.Message, flags int) (int, error) {
+	n := 0
+
+	for ; n < len(f.msgs) && n < len(ms); n++ {
+		ms[n].N = copy(ms[n].Buffers[0], f.msgs[n].Buffers[0])
+		ms[n].NN = copy(ms[n].OOB, f.msgs[n].OOB)
+		ms[n].Addr = f.msgs[n].Addr
+		ms[n].Flags = f.msgs[n].Flags
+	}
+
+	return n, nil
+}
+
+func (f *fakeBatchConn) WriteBatch(ms []ipv4.Message, flags int) (int, error) {
+	for i, m := range ms {
+		// like sendmmsg, the packets before the failing one are sent, the error is returned next
+		if len(f.written) == f.fail {
+			if i > 0 {
+				return i, nil
+			}
+
+			f.written = append(f.written, nil)
+
+			return 0, errors.New("unreachable")
+		}
+
+		f.written = append(f.written, append([]byte{}, m.Buffers[0]...))
+	}
+
+	return len(ms), nil
+}
+
+func TestSetBatchIO(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	if err := server.SetBatchIO(-1, 1, false); err != ErrInvalidBatchSize {
+		t.Errorf("Expected %v, got %v", ErrInvalidBatchSize, err)
+	}
+
+	if err := server.SetBatchIO(MAX_BATCH_SIZE+1, 1, false); err != ErrInvalidBatchSize {
+		t.Errorf("Expected %v, got %v", ErrInvalidBatchSize, err)
+	}
+
+	if err := server.SetBatchIO(32, -1, false); err != ErrInvalidListenerSockets {
+		t.Errorf("Expected %v, got %v", ErrInvalidListenerSockets, err)
+	}
+
+	if err := server.SetBatchIO(32, 0, true); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if size, sockets, shared := server.getBatchIO(); size != 32 || sockets != 1 || !shared {
+		t.Errorf("Expected 32 packets, 1 socket and a shared sender, got %d, %d and %v", size, sockets, shared)
+	}
+}
+
+func TestReadIncomingBatch(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	valid, _ := (&bfd.ControlPacket{
+		Version:          1,
+		State:            bfd.Down,
+		DetectMultiplier: 3,
+		MyDiscriminator:  1,
+	}).MarshalBinary()
+
+	truncated, _ := (&bfd.ControlPacket{
+		Version:          1,
+		State:            bfd.Down,
+		DetectMultiplier: 3,
+		MyDiscriminator:  2,
+	}).MarshalBinary()
+
+	addr := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 49152}
+	oob := hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 255)
+
+	fake := &fakeBatchConn{
+		msgs: []ipv4.Message{
+			{Buffers: [][]byte{{255, 255}}, OOB: oob, Addr: addr},
+			{Buffers: [][]byte{truncated}, OOB: oob, Addr: addr, Flags: syscall.MSG_TRUNC},
+			{Buffers: [][]byte{valid}, OOB: oob, Addr: addr},
+		},
+	}
+
+	l := &listener{}
+	msgs := make([]ipv4.Message, 4)
+
+	for i := range msgs {
+		msgs[i].Buffers = [][]byte{make([]byte, BATCH_BUFFER_SIZE)}
+		msgs[i].OOB = make([]byte, 256)
+	}
+
+	if err := server.readIncomingBatch(l, fake, msgs); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// the invalid packet is counted, the truncated one discarded, the valid one is still handled
+	if l.violations.get(bfd.InvalidLength) != 1 {
+		t.Errorf("Expected the invalid packet to be counted, got %v", l.violations)
+	}
+
+	pkt := queuedPacket(t, server)
+	defer pkt.release()
+
+	if pkt.packet.MyDiscriminator != 1 || !pkt.addr.IP.Equal(addr.IP) || pkt.ttl != 255 {
+		t.Errorf("Unexpected packet %v", pkt)
+	}
+}
+
+func TestBatchSenderSkipsFailedPackets(t *testing.T) {
+	fake := &fakeBatchConn{fail: 1}
+	sender := &batchSender{writer: fake}
+
+	addr := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: BFD_PORT}
+	batch := []outgoing{}
+	msgs := make([]ipv4.Message, 3)
+
+	for i := range msgs {
+		buf := sendBuffers.Get().(*sendBuffer)
+		buf[0] = byte(i)
+
+		batch = append(batch, outgoing{buf: buf, n: 1, addr: addr})
+		msgs[i].Buffers = make([][]byte, 1)
+	}
+
+	sender.write(batch, msgs)
+
+	if len(fake.written) != 3 || fake.written[0][0] != 0 || fake.written[1] != nil || fake.written[2][0] != 2 {
+		t.Errorf("Expected the second packet to be skipped, got %v", fake.written)
+	}
+
+	if msgs[0].Addr != nil {
+		t.Errorf("Expected the messages to be cleared")
+	}
+}
+
+func TestBatchIO(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	if err := server.SetBatchIO(8, 2, true); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// multi hop, the TTL of the packets isn't checked
+	if err := server.ListenMultiHop("127.0.0.1:14785"); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	peer, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1:14785",
+		IsMultiHop:       true,
+		DetectMultiplier: 3,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	other, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1:14785",
+		IsMultiHop:       true,
+		DetectMultiplier: 3,
+		PadToSize:        300,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// the listeners can't read packets padded beyond their buffers
+	_, err = server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1:14785",
+		IsMultiHop:       true,
+		DetectMultiplier: 3,
+		PadToSize:        BATCH_BUFFER_SIZE + 1,
+	})
+
+	if err != ErrBatchPadToSize {
+		t.Errorf("Expected %v, got %v", ErrBatchPadToSize, err)
+	}
+
+	if _, ok := peer.conn.(*batchConn); !ok {
+		t.Errorf("Expected the session to use the shared socket, got %T", peer.conn)
+	}
+
+	if _, ok := other.conn.(*batchConn); ok {
+		t.Errorf("Expected the padded session to keep its own socket")
+	}
+
+	if err := peer.Send(peer.NewPacket(bfd.No, bfd.No)); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pkt := queuedPacket(t, server)
+	defer pkt.release()
+
+	if pkt.packet.MyDiscriminator != peer.GetLocal().discriminator || pkt.addr.Port != peer.SourcePort || !pkt.multiHop {
+		t.Errorf("Unexpected packet %v from %v", pkt.packet, pkt.addr)
+	}
+}
+
+func TestBatchIOOwnSockets(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	if err := server.SetBatchIO(8, 1, false); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	peer, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1:14786",
+		IsMultiHop:       true,
+		DetectMultiplier: 3,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// every session keeps a source port of its own (RFC5881 4)
+	if _, ok := peer.conn.(*batchConn); ok || len(server.senders) != 0 {
+		t.Errorf("Expected the session to keep its own socket")
+	}
+}
+
+// closingTransport counts the sockets opened and not closed again
+type closingTransport struct {
+	Transport
+
+	open int
+}
+
+type closingConn struct {
+	UDPConn
+
+	transport *closingTransport
+}
+
+func (t *closingTransport) ListenUDP(network string, laddr *net.UDPAddr) (UDPConn, error) {
+	conn, err := t.Transport.ListenUDP(network, laddr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	t.open++
+
+	return &closingConn{UDPConn: conn, transport: t}, nil
+}
+
+func (c *closingConn) Close() error {
+	c.transport.open--
+
+	return c.UDPConn.Close()
+}
+
+func TestBatchSenderKernelSocket(t *testing.T) {
+	network := NewVirtualNetwork(1)
+	transport := &closingTransport{Transport: network.Transport(net.ParseIP("10.0.0.1"))}
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	if err := server.SetTransport(transport); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := server.SetBatchIO(8, 1, true); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// the shared socket is opened by the transport, sendmmsg needs a kernel socket
+	_, err := server.AddPeer(&api.Peer{
+		Address:          "10.0.0.2",
+		DetectMultiplier: 3,
+	})
+
+	if err != ErrKernelSocketRequired {
+		t.Errorf("Expected %v, got %v", ErrKernelSocketRequired, err)
+	}
+
+	if transport.open != 0 || len(server.senders) != 0 {
+		t.Errorf("Expected the socket to be closed, got %d open and %v", transport.open, server.senders)
+	}
+}
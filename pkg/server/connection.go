@@ -1,8 +1,14 @@
 package server
 
 import (
+	"errors"
 	"net"
 	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
 )
 
 type Connection interface {
@@ -10,3 +16,134 @@ type Connection interface {
 	ReadMsgUDP(b, oob []byte) (n, oobn, flags int, addr *net.UDPAddr, err error)
 	Write(b []byte) (int, error)
 }
+
+// parseHostPort splits an address like 10.0.0.1, 10.0.0.1:3784, 2001:db8::1,
+// [2001:db8::1]:3784 or [fe80::1%eth0]:3784 into ip, zone and port.
+// The ip is nil if the host isn't a valid address, defaultPort is used if no port is passed
+func parseHostPort(address string, defaultPort int) (net.IP, string, int, error) {
+	var err error
+
+	port := defaultPort
+
+	host, str_port, splitErr := net.SplitHostPort(address)
+
+	if splitErr != nil {
+		// no port passed, an IPv6 address may still be enclosed in brackets
+		host = strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
+	} else {
+		port, err = strconv.Atoi(str_port)
+
+		if err != nil {
+			return nil, "", 0, err
+		}
+	}
+
+	zone := ""
+
+	if i := strings.LastIndex(host, "%"); i >= 0 {
+		host, zone = host[:i], host[i+1:]
+	}
+
+	ip := net.ParseIP(host)
+
+	// zones are only valid for IPv6 addresses
+	if ip != nil && zone != "" && ip.To4() != nil {
+		ip = nil
+	}
+
+	return ip, zone, port, nil
+}
+
+// setMaxHopLimit sends all packets of conn with a TTL / Hop Limit of 255,
+// a wildcard address can be dual stack, so both families are set
+func setMaxHopLimit(conn *net.UDPConn, ip net.IP) error {
+	if ip.IsUnspecified() {
+		ipv4.NewConn(conn).SetTTL(255)
+		ipv6.NewConn(conn).SetHopLimit(255)
+
+		return nil
+	}
+
+	if ip.To4() != nil {
+		return ipv4.NewConn(conn).SetTTL(255)
+	}
+
+	return ipv6.NewConn(conn).SetHopLimit(255)
+}
+
+// setControlMessages requests the TTL / Hop Limit, destination address and interface
+// of received packets as control messages
+func setControlMessages(conn *net.UDPConn, ip net.IP) error {
+	var err error
+
+	if ip.To4() != nil || ip.IsUnspecified() {
+		err = ipv4.NewPacketConn(conn).SetControlMessage(ipv4.FlagTTL|ipv4.FlagDst|ipv4.FlagInterface, true)
+	}
+
+	if ip.To4() == nil || ip.IsUnspecified() {
+		v6Err := ipv6.NewPacketConn(conn).SetControlMessage(ipv6.FlagHopLimit|ipv6.FlagDst|ipv6.FlagInterface, true)
+
+		// a wildcard socket is only dual stack if the host supports IPv6
+		if !ip.IsUnspecified() {
+			err = v6Err
+		}
+	}
+
+	return err
+}
+
+var ErrInvalidControlMessage = errors.New("Invalid control message received")
+
+// controlMessage is the context of a received packet taken from its control messages
+type controlMessage struct {
+	ttl     uint8  // TTL / Hop Limit
+	dst     net.IP // destination address, nil if unknown, it may refer to the buffer of the read
+	ifIndex int    // incoming interface, 0 if unknown
+}
+
+// packetReader is implemented by sockets that report the context of a received packet
+// themselves instead of in control messages, like the sockets of a VirtualNetwork
+type packetReader interface {
+	readPacket(b []byte) (int, *controlMessage, *net.UDPAddr, error)
+}
+
+// zoneIndex returns the interface index of an IPv6 zone, 0 if it's unknown
+func zoneIndex(zone string) int {
+	if zone == "" {
+		return 0
+	}
+
+	if index, err := strconv.Atoi(zone); err == nil {
+		return index
+	}
+
+	iface, err := net.InterfaceByName(zone)
+
+	if err != nil {
+		return 0
+	}
+
+	return iface.Index
+}
+
+// bindSocketToDevice restricts a connected socket to the interface device
+func bindSocketToDevice(conn *net.UDPConn, device string) error {
+	c, err := conn.SyscallConn()
+
+	if err != nil {
+		return err
+	}
+
+	return bindToDevice(c, device)
+}
+
+// setSocketDontFragment prevents the fragmentation of the packets sent by a connected socket
+func setSocketDontFragment(conn *net.UDPConn, ip net.IP) error {
+	c, err := conn.SyscallConn()
+
+	if err != nil {
+		return err
+	}
+
+	return setDontFragment(c, ip.To4() == nil)
+}
@@ -0,0 +1,123 @@
+//go:build linux
+// +build linux
+
+package server
+
+import (
+	"net"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// bindToDevice restricts the socket to the interface device (SO_BINDTODEVICE),
+// packets are only sent and received via that interface
+func bindToDevice(c syscall.RawConn, device string) error {
+	var err error
+
+	ctrlErr := c.Control(func(fd uintptr) {
+		err = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, device)
+	})
+
+	if ctrlErr != nil {
+		return ctrlErr
+	}
+
+	return err
+}
+
+// setDontFragment sends the packets with the DF bit set (IPv4) / without fragmentation (IPv6),
+// packets exceeding the path MTU fail instead of being fragmented
+func setDontFragment(c syscall.RawConn, ipv6 bool) error {
+	var err error
+
+	ctrlErr := c.Control(func(fd uintptr) {
+		if ipv6 {
+			err = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MTU_DISCOVER, syscall.IPV6_PMTUDISC_DO)
+		} else {
+			err = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MTU_DISCOVER, syscall.IP_PMTUDISC_DO)
+		}
+	})
+
+	if ctrlErr != nil {
+		return ctrlErr
+	}
+
+	return err
+}
+
+// setReusePort allows other sockets to bind to the same address and port (SO_REUSEPORT),
+// the kernel spreads the received packets over them
+func setReusePort(c syscall.RawConn) error {
+	var err error
+
+	ctrlErr := c.Control(func(fd uintptr) {
+		err = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+	})
+
+	if ctrlErr != nil {
+		return ctrlErr
+	}
+
+	return err
+}
+
+// isTruncated returns true if the flags of a received message report a packet exceeding its buffer
+func isTruncated(flags int) bool {
+	return flags&unix.MSG_TRUNC != 0
+}
+
+// parseControlMessage parses the control messages of a received packet into cm without allocating,
+// the destination refers to oob. IPv4 packets can be received on dual stack sockets too, so both
+// families are checked. It returns the same as the ControlMessage parsers of x/net, which allocate
+// the list of messages for every packet.
+func parseControlMessage(oob []byte, cm *controlMessage) error {
+	var v4, v6 controlMessage
+
+	for len(oob) >= unix.CmsgLen(0) {
+		header := (*unix.Cmsghdr)(unsafe.Pointer(&oob[0]))
+		length := int(header.Len)
+
+		if length < unix.CmsgLen(0) || length > len(oob) {
+			return ErrInvalidControlMessage
+		}
+
+		data := oob[unix.CmsgLen(0):length]
+
+		switch {
+		case header.Level == unix.IPPROTO_IP && header.Type == unix.IP_TTL && len(data) >= 4:
+			v4.ttl = uint8(*(*int32)(unsafe.Pointer(&data[0])))
+		case header.Level == unix.IPPROTO_IP && header.Type == unix.IP_PKTINFO && len(data) >= unix.SizeofInet4Pktinfo:
+			// struct in_pktinfo { int ifindex; in_addr spec_dst; in_addr addr }
+			v4.ifIndex = int(*(*int32)(unsafe.Pointer(&data[0])))
+			v4.dst = net.IP(data[8:12])
+		case header.Level == unix.IPPROTO_IPV6 && header.Type == unix.IPV6_HOPLIMIT && len(data) >= 4:
+			v6.ttl = uint8(*(*int32)(unsafe.Pointer(&data[0])))
+		case header.Level == unix.IPPROTO_IPV6 && header.Type == unix.IPV6_PKTINFO && len(data) >= unix.SizeofInet6Pktinfo:
+			// struct in6_pktinfo { in6_addr addr; int ifindex }
+			v6.dst = net.IP(data[:16])
+			v6.ifIndex = int(*(*int32)(unsafe.Pointer(&data[16])))
+		}
+
+		next := unix.CmsgSpace(length - unix.CmsgLen(0))
+
+		if next >= len(oob) {
+			break
+		}
+
+		oob = oob[next:]
+	}
+
+	if v6.ttl > 0 {
+		*cm = v6
+		return nil
+	}
+
+	if v4.ttl > 0 {
+		*cm = v4
+		return nil
+	}
+
+	return ErrInvalidTTL
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"math/rand"
+	"net"
+	"syscall"
+	"testing"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// parseControlMessageXnet is the parser of x/net, which parseControlMessage needs to agree with.
+// It panics for IPPROTO_IP messages of type 0, the options linux doesn't have are looked up by it.
+func parseControlMessageXnet(oob []byte) (cm *controlMessage, panicked bool, err error) {
+	defer func() {
+		if recover() != nil {
+			panicked = true
+		}
+	}()
+
+	v6 := &ipv6.ControlMessage{}
+
+	if err := v6.Parse(oob); err != nil {
+		return nil, false, err
+	}
+
+	if v6.HopLimit > 0 {
+		return &controlMessage{ttl: uint8(v6.HopLimit), dst: v6.Dst, ifIndex: v6.IfIndex}, false, nil
+	}
+
+	v4 := &ipv4.ControlMessage{}
+
+	if err := v4.Parse(oob); err != nil {
+		return nil, false, err
+	}
+
+	if v4.TTL > 0 {
+		return &controlMessage{ttl: uint8(v4.TTL), dst: v4.Dst, ifIndex: v4.IfIndex}, false, nil
+	}
+
+	return nil, false, ErrInvalidTTL
+}
+
+// compareControlMessageParsers checks that both parsers reject oob or return the same context
+func compareControlMessageParsers(t *testing.T, name string, oob []byte) {
+	cm := &controlMessage{}
+	err := parseControlMessage(oob, cm)
+	expected, panicked, expectedErr := parseControlMessageXnet(oob)
+
+	// nothing to compare with, it's enough that parseControlMessage returns
+	if panicked {
+		return
+	}
+
+	if (err != nil) != (expectedErr != nil) {
+		t.Errorf("%s %x: got %v, x/net %v", name, oob, err, expectedErr)
+		return
+	}
+
+	if err == nil && (cm.ttl != expected.ttl || !cm.dst.Equal(expected.dst) || cm.ifIndex != expected.ifIndex) {
+		t.Errorf("%s %x: got %v, x/net %v", name, oob, cm, expected)
+	}
+}
+
+func TestParseControlMessageXnet(t *testing.T) {
+	messages := [][]byte{
+		hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 255),
+		hopLimitCmsg(syscall.IPPROTO_IPV6, syscall.IPV6_HOPLIMIT, 64),
+		pktInfoCmsg(net.ParseIP("10.0.0.1"), 3),
+		pktInfoCmsg(net.ParseIP("2001:db8::1"), 4),
+		hopLimitCmsg(syscall.IPPROTO_IPV6, syscall.IPV6_TCLASS, 0),
+		// data too short for the type and data that isn't a multiple of the alignment
+		cmsg(syscall.IPPROTO_IP, syscall.IP_PKTINFO, make([]byte, 8)),
+		cmsg(syscall.IPPROTO_IP, syscall.IP_TTL, []byte{1, 2, 3, 4, 5}),
+	}
+
+	for _, first := range messages {
+		for _, second := range messages {
+			for _, third := range messages {
+				oob := append(append(append([]byte{}, first...), second...), third...)
+
+				// truncated by the buffer of the read at every length
+				for n := 0; n <= len(oob); n++ {
+					compareControlMessageParsers(t, "truncated", oob[:n])
+				}
+
+				// not aligned within the buffer
+				unaligned := append([]byte{0}, oob...)[1:]
+				compareControlMessageParsers(t, "unaligned", unaligned)
+			}
+		}
+	}
+
+	// corrupted lengths and contents
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		oob := append(append([]byte{}, messages[r.Intn(len(messages))]...), messages[r.Intn(len(messages))]...)
+		oob[r.Intn(len(oob))] = byte(r.Intn(256))
+
+		compareControlMessageParsers(t, "corrupted", oob)
+	}
+}
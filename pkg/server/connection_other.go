@@ -0,0 +1,69 @@
+//go:build !linux
+// +build !linux
+
+package server
+
+import (
+	"syscall"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// bindToDevice needs SO_BINDTODEVICE, which is only available on linux
+func bindToDevice(c syscall.RawConn, device string) error {
+	return ErrBindToDeviceNotSupported
+}
+
+// setDontFragment needs IP_MTU_DISCOVER, which is only available on linux
+func setDontFragment(c syscall.RawConn, ipv6 bool) error {
+	return ErrDontFragmentNotSupported
+}
+
+// setReusePort is only implemented for linux, where the packets are spread over the sockets
+func setReusePort(c syscall.RawConn) error {
+	return ErrReusePortNotSupported
+}
+
+// isTruncated relies on the flags of recvmmsg, batched reads are only supported on linux
+func isTruncated(flags int) bool {
+	return false
+}
+
+// parseControlMessage parses the control messages of a received packet into cm, IPv4 packets
+// can be received on dual stack sockets too, so both families are checked
+func parseControlMessage(oob []byte, cm *controlMessage) error {
+	v6 := &ipv6.ControlMessage{}
+
+	if err := v6.Parse(oob); err != nil {
+		return err
+	}
+
+	if v6.HopLimit > 0 {
+		*cm = controlMessage{
+			ttl:     uint8(v6.HopLimit),
+			dst:     v6.Dst,
+			ifIndex: v6.IfIndex,
+		}
+
+		return nil
+	}
+
+	v4 := &ipv4.ControlMessage{}
+
+	if err := v4.Parse(oob); err != nil {
+		return err
+	}
+
+	if v4.TTL > 0 {
+		*cm = controlMessage{
+			ttl:     uint8(v4.TTL),
+			dst:     v4.Dst,
+			ifIndex: v4.IfIndex,
+		}
+
+		return nil
+	}
+
+	return ErrInvalidTTL
+}
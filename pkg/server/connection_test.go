@@ -0,0 +1,82 @@
+package server
+
+import (
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestParseHostPort(t *testing.T) {
+	tests := []struct {
+		address string
+		ip      net.IP
+		zone    string
+		port    int
+	}{
+		{"127.0.0.1", net.ParseIP("127.0.0.1"), "", BFD_PORT},
+		{"127.0.0.1:4000", net.ParseIP("127.0.0.1"), "", 4000},
+		{"2001:db8::1", net.ParseIP("2001:db8::1"), "", BFD_PORT},
+		{"[2001:db8::1]", net.ParseIP("2001:db8::1"), "", BFD_PORT},
+		{"[2001:db8::1]:4000", net.ParseIP("2001:db8::1"), "", 4000},
+		{"fe80::1%eth0", net.ParseIP("fe80::1"), "eth0", BFD_PORT},
+		{"[fe80::1%eth0]:4000", net.ParseIP("fe80::1"), "eth0", 4000},
+		{"127.0.0.1%eth0", nil, "eth0", BFD_PORT},
+		{"300.300.300.300", nil, "", BFD_PORT},
+	}
+
+	for _, test := range tests {
+		ip, zone, port, err := parseHostPort(test.address, BFD_PORT)
+
+		if err != nil {
+			t.Errorf("%s: %v", test.address, err)
+			continue
+		}
+
+		if !ip.Equal(test.ip) || zone != test.zone || port != test.port {
+			t.Errorf("%s: got %v %s %d", test.address, ip, zone, port)
+		}
+	}
+
+	if _, _, _, err := parseHostPort("[::1]:asdf", BFD_PORT); err == nil {
+		t.Errorf("Expected an error for an invalid port")
+	}
+}
+
+func TestParseControlMessage(t *testing.T) {
+	cm := &controlMessage{}
+	err := parseControlMessage(hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 64), cm)
+
+	if err != nil || cm.ttl != 64 || cm.dst != nil || cm.ifIndex != 0 {
+		t.Errorf("Expected TTL 64, got %v %v", cm, err)
+	}
+
+	cm = &controlMessage{}
+	err = parseControlMessage(hopLimitCmsg(syscall.IPPROTO_IPV6, syscall.IPV6_HOPLIMIT, 255), cm)
+
+	if err != nil || cm.ttl != 255 {
+		t.Errorf("Expected Hop Limit 255, got %v %v", cm, err)
+	}
+
+	// the destination and interface are taken from the family of the TTL
+	oob := append(hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 255), pktInfoCmsg(net.ParseIP("10.0.0.1"), 3)...)
+	oob = append(oob, pktInfoCmsg(net.ParseIP("2001:db8::1"), 4)...)
+
+	cm = &controlMessage{}
+	err = parseControlMessage(oob, cm)
+
+	if err != nil || cm.ttl != 255 || !cm.dst.Equal(net.ParseIP("10.0.0.1")) || cm.ifIndex != 3 {
+		t.Errorf("Expected the IPv4 destination and interface, got %v %v", cm, err)
+	}
+
+	err = parseControlMessage(hopLimitCmsg(syscall.IPPROTO_IPV6, syscall.IPV6_TCLASS, 0), &controlMessage{})
+
+	if err != ErrInvalidTTL {
+		t.Errorf("Expected %v, got %v", ErrInvalidTTL, err)
+	}
+}
+
+func TestZoneIndex(t *testing.T) {
+	if zoneIndex("") != 0 || zoneIndex("7") != 7 || zoneIndex("does-not-exist") != 0 {
+		t.Fail()
+	}
+}
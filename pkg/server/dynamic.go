@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+const (
+	// idle timeout of dynamic sessions if none is configured
+	DYNAMIC_IDLE_TIMEOUT = 5 * time.Minute
+
+	// how often dynamic sessions are checked for their idle timeout
+	DYNAMIC_EXPIRY_INTERVAL = time.Second
+)
+
+var ErrInvalidPrefix = errors.New("Invalid prefix passed")
+var ErrInvalidMaxSessions = errors.New("Invalid maximum of sessions passed")
+var ErrDynamicSessionLimit = errors.New("Maximum of dynamic sessions reached")
+
+// DynamicRange allows sessions to be created passively for unknown peers within Prefix
+type DynamicRange struct {
+	Prefix      *net.IPNet
+	Template    *api.Peer     // timers and authentication of the created sessions, the address is ignored
+	IdleTimeout time.Duration // sessions are removed if no packet was received for this long
+	MaxSessions int           // sessions created at most, 0 = unlimited
+
+	sessions int    // created sessions, guarded by the lock of the server
+	rejected uint64 // Down packets dropped because MaxSessions was reached
+}
+
+// AddDynamicRange creates sessions for unknown peers within prefix once they send a Down packet,
+// up to maxSessions at a time (0 = unlimited)
+func (s *BfdServer) AddDynamicRange(prefix string, template *api.Peer, idleTimeout time.Duration, maxSessions int) error {
+	_, ipNet, err := net.ParseCIDR(prefix)
+
+	if err != nil {
+		return ErrInvalidPrefix
+	}
+
+	if maxSessions < 0 {
+		return ErrInvalidMaxSessions
+	}
+
+	if template == nil || template.DetectMultiplier == 0 {
+		return ErrInvalidDetectionMultiplierSupplied
+	}
+
+	// the remaining settings are validated once a session is created,
+	// a broken authentication would silently drop every peer though
+	if _, _, _, err := parseAuthentication(template.Authentication); err != nil {
+		return err
+	}
+
+	if idleTimeout == 0 {
+		idleTimeout = DYNAMIC_IDLE_TIMEOUT
+	}
+
+	s.Lock()
+	s.dynamicRanges = append(s.dynamicRanges, &DynamicRange{
+		Prefix:      ipNet,
+		Template:    template,
+		IdleTimeout: idleTimeout,
+		MaxSessions: maxSessions,
+	})
+
+	s.startDynamicExpiry()
+	s.Unlock()
+
+	return nil
+}
+
+// startDynamicExpiry starts removing idle dynamic sessions unless it's already running, s needs to be locked
+func (s *BfdServer) startDynamicExpiry() {
+	if s.dynamicExpiry != nil || s.stopped {
+		return
+	}
+
+	s.dynamicExpiry = s.clock.AfterFunc(DYNAMIC_EXPIRY_INTERVAL, s.expireDynamicPeers)
+}
+
+// dynamicRange returns the range of an unknown peer, nil if no session may be created for it
+func (s *BfdServer) dynamicRange(ip net.IP, multiHop bool) *DynamicRange {
+	s.RLock()
+	defer s.RUnlock()
+
+	for _, r := range s.dynamicRanges {
+		if r.Template.IsMultiHop == multiHop && r.Prefix.Contains(ip) {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// createDynamicPeer creates a session for a packet that didn't match any session
+func (s *BfdServer) createDynamicPeer(pkt *packet) (*Peer, error) {
+	/*
+		RFC5880 6.8.6
+		If a matching session is
+		not found, a new session MAY be created, or the packet MAY be
+		discarded.
+
+		Only a Down packet can start a session, anything else belongs to a
+		session we don't know of.
+	*/
+	if pkt.packet.State != bfd.Down {
+		return nil, ErrPeerNotFound
+	}
+
+	// micro BFD and VXLAN sessions only exist for configured LAG members / VNIs
+	if pkt.micro || pkt.vxlan {
+		return nil, ErrPeerNotFound
+	}
+
+	// unsolicited BFD is enabled per interface and takes precedence over the ranges
+	if u := s.unsolicitedInterface(pkt); u != nil {
+		return s.createUnsolicitedPeer(pkt, u)
+	}
+
+	r := s.dynamicRange(pkt.addr.IP, pkt.multiHop)
+
+	if r == nil {
+		return nil, ErrPeerNotFound
+	}
+
+	// a flood of spoofed sources must not exhaust the sessions
+	if !s.reserveDynamicSession(r) {
+		atomic.AddUint64(&r.rejected, 1)
+
+		return nil, ErrDynamicSessionLimit
+	}
+
+	peer, err := s.addDynamicPeer(pkt, r.Template)
+
+	if err != nil {
+		s.Lock()
+		r.sessions--
+		s.Unlock()
+
+		return nil, err
+	}
+
+	peer.Lock()
+	peer.dynamic = r
+	peer.Unlock()
+
+	glog.Infof("Created dynamic session for %s", peer.Address)
+
+	return peer, nil
+}
+
+// reserveDynamicSession counts a session about to be created for r, false if r has no room left
+func (s *BfdServer) reserveDynamicSession(r *DynamicRange) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if r.MaxSessions > 0 && r.sessions >= r.MaxSessions {
+		return false
+	}
+
+	r.sessions++
+
+	return true
+}
+
+// ListDynamicRange calls cb for every dynamic range with its sessions and rejected packets
+func (s *BfdServer) ListDynamicRange(ctx context.Context, cb func(*api.ListDynamicRangeResponse) error) error {
+	s.RLock()
+	responses := make([]*api.ListDynamicRangeResponse, 0, len(s.dynamicRanges))
+
+	for _, r := range s.dynamicRanges {
+		responses = append(responses, &api.ListDynamicRangeResponse{
+			Prefix:      r.Prefix.String(),
+			Sessions:    uint32(r.sessions),
+			MaxSessions: uint32(r.MaxSessions),
+			Rejected:    atomic.LoadUint64(&r.rejected),
+		})
+	}
+	s.RUnlock()
+
+	for _, response := range responses {
+		if err := cb(response); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+
+	return nil
+}
+
+// addDynamicPeer creates a session with the settings of template for the sender of pkt
+func (s *BfdServer) addDynamicPeer(pkt *packet, template *api.Peer) (*Peer, error) {
+	address := pkt.addr.IP.String()
+
+	if pkt.addr.Zone != "" {
+		address += "%" + pkt.addr.Zone
+	}
+
+	api_peer := proto.Clone(template).(*api.Peer)
+	api_peer.Address = address
+
+	if api_peer.Name == "" {
+		api_peer.Name = address
+	}
+
+	// answer from the address the peer sent its packet to
+	if api_peer.LocalAddress == "" && pkt.dst != nil {
+		api_peer.LocalAddress = pkt.dst.String()
+	}
+
+	peer, err := s.AddPeer(api_peer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	peer.Lock()
+	peer.lastPacket = s.clock.Now()
+	peer.Unlock()
+
+	return peer, nil
+}
+
+// expireDynamicPeers removes dynamic sessions once they exceed their idle timeout, it's run every
+// DYNAMIC_EXPIRY_INTERVAL by the clock until the server is shut down
+func (s *BfdServer) expireDynamicPeers() {
+	for _, peer := range s.idleDynamicPeers(s.clock.Now()) {
+		glog.Infof("Removing idle dynamic session %s", peer.Address)
+
+		s.DeletePeer(peer.uuid)
+	}
+
+	s.Lock()
+	if !s.stopped {
+		s.dynamicExpiry.Reset(DYNAMIC_EXPIRY_INTERVAL)
+	}
+	s.Unlock()
+}
+
+// idleDynamicPeers returns the dynamic sessions that didn't receive a packet within their idle timeout
+func (s *BfdServer) idleDynamicPeers(now time.Time) []*Peer {
+	s.RLock()
+	defer s.RUnlock()
+
+	idle := make([]*Peer, 0)
+
+	for _, peer := range s.Sessions {
+		peer.RLock()
+		timeout := peer.idleTimeout()
+
+		if timeout > 0 && now.Sub(peer.lastPacket) > timeout {
+			idle = append(idle, peer)
+		}
+		peer.RUnlock()
+	}
+
+	return idle
+}
+
+// idleTimeout returns the idle timeout of a session created for an unknown peer, 0 for configured sessions.
+// p needs to be locked
+func (p *Peer) idleTimeout() time.Duration {
+	if p.dynamic != nil {
+		return p.dynamic.IdleTimeout
+	}
+
+	if p.unsolicited != nil {
+		return p.unsolicited.IdleTimeout
+	}
+
+	return 0
+}
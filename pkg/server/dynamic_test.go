@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+func newDynamicPacket(src string, state bfd.SessionState) packet {
+	return packet{
+		addr: &net.UDPAddr{IP: net.ParseIP(src), Port: 49152},
+		packet: &bfd.ControlPacket{
+			Version:               1,
+			DetectMultiplier:      3,
+			MyDiscriminator:       60,
+			State:                 state,
+			RequiredMinRxInterval: 100000,
+		},
+		dst: net.ParseIP("127.0.0.1"),
+		ttl: 255,
+	}
+}
+
+func TestAddDynamicRangeInvalid(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	if err := server.AddDynamicRange("127.0.0.0", &api.Peer{DetectMultiplier: 3}, 0, 0); err != ErrInvalidPrefix {
+		t.Errorf("Expected %v, got %v", ErrInvalidPrefix, err)
+	}
+
+	if err := server.AddDynamicRange("127.0.0.0/8", &api.Peer{}, 0, 0); err != ErrInvalidDetectionMultiplierSupplied {
+		t.Errorf("Expected %v, got %v", ErrInvalidDetectionMultiplierSupplied, err)
+	}
+
+	err := server.AddDynamicRange("127.0.0.0/8", &api.Peer{
+		DetectMultiplier: 3,
+		Authentication: &api.Authentication{
+			Type: api.AuthenticationType_SIMPLE_PASSWORD,
+		},
+	}, 0, 0)
+
+	if err != ErrInvalidAuthenticationKey {
+		t.Errorf("Expected %v, got %v", ErrInvalidAuthenticationKey, err)
+	}
+
+	if err := server.AddDynamicRange("127.0.0.0/8", &api.Peer{DetectMultiplier: 3}, 0, -1); err != ErrInvalidMaxSessions {
+		t.Errorf("Expected %v, got %v", ErrInvalidMaxSessions, err)
+	}
+}
+
+func TestHandlePacketDynamicPeer(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	err := server.AddDynamicRange("127.0.0.0/24", &api.Peer{
+		DesiredMinTxInterval:  300,
+		RequiredMinRxInterval: 300,
+		DetectMultiplier:      3,
+	}, time.Minute, 0)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := server.handlePacket(newDynamicPacket("127.0.0.9", bfd.Down)); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(server.Sessions) != 1 {
+		t.Fatalf("Expected a dynamic session, got %d sessions", len(server.Sessions))
+	}
+
+	var peer *Peer
+
+	for _, p := range server.Sessions {
+		peer = p
+	}
+
+	if peer.dynamic == nil || peer.Address.String() != "127.0.0.9:3784" || !peer.LocalAddress.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("Unexpected dynamic session %v", peer)
+	}
+
+	if peer.GetRemote().GetRequiredMinRxInterval() != 100000 {
+		t.Errorf("The packet creating the session should be handled by it")
+	}
+
+	// further packets use the session
+	if err := server.handlePacket(newDynamicPacket("127.0.0.9", bfd.Down)); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	// only Down packets create sessions
+	if err := server.handlePacket(newDynamicPacket("127.0.0.10", bfd.AdminDown)); err != ErrPeerNotFound {
+		t.Errorf("Expected %v, got %v", ErrPeerNotFound, err)
+	}
+
+	if err := server.handlePacket(newDynamicPacket("127.0.1.1", bfd.Down)); err != ErrPeerNotFound {
+		t.Errorf("Expected %v, got %v", ErrPeerNotFound, err)
+	}
+
+	if len(server.Sessions) != 1 {
+		t.Errorf("Expected a single dynamic session, got %d sessions", len(server.Sessions))
+	}
+}
+
+func TestHandlePacketDynamicPeerAuthenticationFailed(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	err := server.AddDynamicRange("127.0.0.0/24", &api.Peer{
+		DetectMultiplier: 3,
+		Authentication: &api.Authentication{
+			Type:     api.AuthenticationType_SIMPLE_PASSWORD,
+			Password: "secret",
+		},
+	}, time.Minute, 0)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := server.handlePacket(newDynamicPacket("127.0.0.9", bfd.Down)); err != bfd.ErrInvalidAuthenticationType {
+		t.Errorf("Expected %v, got %v", bfd.ErrInvalidAuthenticationType, err)
+	}
+
+	if len(server.Sessions) != 0 {
+		t.Errorf("Sessions shouldn't be kept for discarded packets")
+	}
+}
+
+func TestIdleDynamicPeers(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	server.AddDynamicRange("127.0.0.0/24", &api.Peer{DetectMultiplier: 3}, time.Minute, 0)
+
+	if err := server.handlePacket(newDynamicPacket("127.0.0.9", bfd.Down)); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// configured peers never expire
+	if _, err := server.AddPeer(&api.Peer{Address: "127.0.0.10", DetectMultiplier: 3}); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if idle := server.idleDynamicPeers(time.Now()); len(idle) != 0 {
+		t.Errorf("Expected no idle sessions, got %d", len(idle))
+	}
+
+	idle := server.idleDynamicPeers(time.Now().Add(2 * time.Minute))
+
+	if len(idle) != 1 || !idle[0].Address.IP.Equal(net.ParseIP("127.0.0.9")) {
+		t.Errorf("Expected the dynamic session to be idle, got %v", idle)
+	}
+}
+
+func TestDynamicRangeMaxSessions(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	if err := server.AddDynamicRange("127.0.0.0/24", &api.Peer{DetectMultiplier: 3}, time.Minute, 2); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for _, ip := range []string{"127.0.0.9", "127.0.0.10"} {
+		if err := server.handlePacket(newDynamicPacket(ip, bfd.Down)); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	// the range is full, further peers are rejected and counted
+	for i := 0; i < 3; i++ {
+		if err := server.handlePacket(newDynamicPacket("127.0.0.11", bfd.Down)); err != ErrDynamicSessionLimit {
+			t.Errorf("Expected %v, got %v", ErrDynamicSessionLimit, err)
+		}
+	}
+
+	var ranges []*api.ListDynamicRangeResponse
+
+	server.ListDynamicRange(context.Background(), func(r *api.ListDynamicRangeResponse) error {
+		ranges = append(ranges, r)
+
+		return nil
+	})
+
+	if len(ranges) != 1 || ranges[0].Sessions != 2 || ranges[0].MaxSessions != 2 || ranges[0].Rejected != 3 {
+		t.Fatalf("Unexpected ranges %v", ranges)
+	}
+
+	// a removed session makes room for the next peer
+	for _, peer := range server.idleDynamicPeers(time.Now().Add(2 * time.Minute))[:1] {
+		if err := server.DeletePeer(peer.uuid); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	if err := server.handlePacket(newDynamicPacket("127.0.0.11", bfd.Down)); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if len(server.Sessions) != 2 {
+		t.Errorf("Expected 2 dynamic sessions, got %d", len(server.Sessions))
+	}
+}
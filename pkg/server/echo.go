@@ -0,0 +1,498 @@
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/golang/glog"
+
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+/*
+
+https://tools.ietf.org/html/rfc5880#section-6.4
+https://tools.ietf.org/html/rfc5881#section-4
+
+Echo packets are addressed to the local system and sent to the link layer
+address of the remote, whose forwarding plane sends them back to the echo
+port (RFC5881 4). They're received like any other packet addressed to the
+local system and matched to their session by the discriminator they carry.
+The remote only needs to forward them, the local system needs to accept
+packets with its own source address though (accept_local on linux).
+
+The source is the local address as well, so the remote may send ICMP
+Redirects for them. Peers that don't forward the packets, like a host
+running bfdd, can use the echo reflector instead: the packets are sent to
+the echo port of the remote, whose reflector sends them back from
+userspace.
+
+*/
+
+const (
+	ECHO_PORT = 3785
+
+	// control packets are slowed down to this interval while echo is active
+	ECHO_CONTROL_INTERVAL = 1000000
+)
+
+var ErrEchoLoopbackNotSupported = errors.New("Looping back echo packets through the remote isn't supported on this platform")
+var ErrEchoNoInterface = errors.New("The echo peer isn't reachable via a directly connected interface")
+var ErrNeighborNotFound = errors.New("The link layer address of the echo peer is unknown")
+
+// echoInterval returns the negotiated echo transmit interval, the lock needs to be held by the caller
+func (p *Peer) echoInterval() uint32 {
+	/*
+		RFC5880 6.8.9
+		BFD Echo packets MUST NOT be transmitted at a rate faster than
+		the Required Min Echo RX Interval of the remote system.
+	*/
+	return max(p.EchoInterval, p.remote.requiredMinEchoRxInterval)
+}
+
+// updateEcho starts or stops the echo function based on the current session state
+func (p *Peer) updateEcho() {
+	snapshot := p.Snapshot()
+
+	p.RLock()
+	enabled := p.EchoInterval > 0 && p.echoConn != nil
+	active := p.echoActive
+	p.RUnlock()
+
+	/*
+		RFC5880 6.8.9
+		When bfd.SessionState is not Up, the system MUST NOT transmit BFD
+		Echo packets.
+
+		If the Required Min Echo RX Interval field is zero, the
+		transmission of Echo packets, if any, MUST cease.
+	*/
+	local := snapshot.local
+	remote := snapshot.remote
+	run := enabled && local.sessionState == bfd.Up && remote.sessionState == bfd.Up && remote.requiredMinEchoRxInterval > 0
+
+	if run && !active {
+		p.startEcho()
+	} else if !run && active {
+		p.stopEcho()
+	}
+}
+
+func (p *Peer) startEcho() {
+	controlRx := p.getPollState().requiredMinRxInterval
+
+	p.Lock()
+	p.echoActive = true
+	p.echoRxInterval = controlRx
+
+	interval := p.echoInterval()
+	p.echoDetectionTime = time.Duration(interval) * time.Duration(p.local.detectMultiplier) * time.Microsecond
+
+	p.echoTicker.Reset(time.Duration(interval) * time.Microsecond)
+	p.echoExpiry.Reset(p.echoDetectionTime)
+	p.Unlock()
+
+	/*
+		RFC5880 6.8.3
+		When the Echo function is active, a system SHOULD set
+		bfd.RequiredMinRxInterval to a value of not less than one second
+		(1,000,000 microseconds).
+	*/
+	if controlRx < ECHO_CONTROL_INTERVAL {
+		p.SetRequiredMinRxInterval(ECHO_CONTROL_INTERVAL)
+	}
+}
+
+func (p *Peer) stopEcho() {
+	p.Lock()
+
+	if !p.echoActive {
+		p.Unlock()
+		return
+	}
+
+	p.echoActive = false
+	p.echoTicker.Stop()
+	p.echoExpiry.Stop()
+	controlRx := p.echoRxInterval
+	p.Unlock()
+
+	// the control packets take over the detection again
+	p.SetRequiredMinRxInterval(controlRx)
+}
+
+// sendEcho sends the next echo packet and schedules the one after
+func (p *Peer) sendEcho() error {
+	p.Lock()
+
+	if !p.echoActive {
+		p.Unlock()
+		return nil
+	}
+
+	p.echoSequence++
+
+	packet := &bfd.EchoPacket{
+		Version:         1,
+		MyDiscriminator: p.local.discriminator,
+		SequenceNumber:  p.echoSequence,
+	}
+
+	p.echoTicker.Reset(time.Duration(p.echoInterval()) * time.Microsecond)
+	conn := p.echoConn
+	p.Unlock()
+
+	b, err := packet.MarshalBinary()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Write(b)
+
+	if err != nil {
+		glog.Infof("Error on echo write: %s", err)
+	}
+
+	return err
+}
+
+// handleEcho processes an echo packet that was looped back by the remote
+func (p *Peer) handleEcho(packet *bfd.EchoPacket) error {
+	p.Lock()
+	defer p.Unlock()
+
+	if packet.MyDiscriminator != p.local.discriminator {
+		return ErrInvalidMyDiscriminator
+	}
+
+	if p.echoActive {
+		p.echoExpiry.Reset(p.echoDetectionTime)
+	}
+
+	return nil
+}
+
+// echoFailed takes the session down once no echo packets are looped back within the detection time
+func (p *Peer) echoFailed() {
+	p.RLock()
+	failed := p.echoActive && p.local.sessionState == bfd.Up
+	p.RUnlock()
+
+	if !failed {
+		return
+	}
+
+	/*
+		RFC5880 6.8.5
+		If the Echo function is active and a period of time equal to the
+		Detection Time passes without receiving an Echo packet, the session
+		has gone down -- the local system MUST set bfd.SessionState to Down
+		and bfd.LocalDiag to 2 (Echo Function Failed).
+	*/
+	p.ApplyLocalState([]PeerStateUpdate{
+		setDiagnosticCode(bfd.EchoFunctionFailed),
+		setSessionState(bfd.Down),
+	})
+
+	p.stopEcho()
+}
+
+// handleEchoPackets reads the echo packets sent back by the reflector of the remote until the connection is closed
+func (p *Peer) handleEchoPackets(conn io.Reader) {
+	b := make([]byte, 256)
+
+	for {
+		n, err := conn.Read(b)
+
+		if err != nil {
+			return
+		}
+
+		packet := &bfd.EchoPacket{}
+
+		if err := packet.UnmarshalBinary(b[:n]); err != nil {
+			glog.Infof("%s", err.Error())
+			continue
+		}
+
+		if err := p.handleEcho(packet); err != nil {
+			glog.Infof("%s", err.Error())
+		}
+	}
+}
+
+// dialEcho creates the connection used to send echo packets to the reflector of the remote
+func (s *BfdServer) dialEcho(address *net.UDPAddr) (UDPConn, error) {
+	//  49152 through 65535
+	sourcePort := 49152 + int(s.rand.Intn(65535-49152))
+
+	return s.transport.DialUDP("udp", &net.UDPAddr{Port: sourcePort}, &net.UDPAddr{IP: address.IP, Zone: address.Zone, Port: ECHO_PORT})
+}
+
+// connectEcho opens the echo socket of a session, the peer lock needs to be held by the caller
+func (s *BfdServer) connectEcho(peer *Peer) error {
+	if peer.EchoReflector {
+		conn, err := s.dialEcho(peer.Address)
+
+		if err != nil {
+			return err
+		}
+
+		peer.echoConn = conn
+
+		return nil
+	}
+
+	conn, err := s.transport.DialEcho(peer.LocalAddress, peer.Address, s.clock)
+
+	if err != nil {
+		return err
+	}
+
+	peer.echoConn = conn
+	peer.echoLocal = conn.LocalAddr().(*net.UDPAddr).IP
+
+	// the looped back packets are addressed to the echo port of the local system
+	return s.receiveEcho(peer.echoLocal)
+}
+
+// echoListener is a socket on the echo port, it receives the looped back echo packets of
+// the sessions and, if it's a reflector, sends the echo packets of the peers back
+type echoListener struct {
+	conn    UDPConn
+	reflect bool
+}
+
+// listenEcho returns the socket of the echo port on ip, it's opened on first use
+func (s *BfdServer) listenEcho(ip net.IP, port int, zone string) (*echoListener, error) {
+	addr := &net.UDPAddr{IP: ip, Port: port, Zone: zone}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if l, ok := s.echoConns[addr.String()]; ok {
+		return l, nil
+	}
+
+	conn, err := s.transport.ListenUDP("udp", addr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	l := &echoListener{conn: conn}
+	s.echoConns[addr.String()] = l
+
+	s.readers.Add(1)
+	go s.handleEchoSocket(l)
+
+	return l, nil
+}
+
+// receiveEcho makes sure the echo packets looped back to ip are received
+func (s *BfdServer) receiveEcho(ip net.IP) error {
+	s.RLock()
+
+	for _, l := range s.echoConns {
+		local := l.conn.LocalAddr().(*net.UDPAddr)
+
+		if local.Port == ECHO_PORT && (local.IP.Equal(ip) || local.IP == nil || local.IP.IsUnspecified()) {
+			s.RUnlock()
+			return nil
+		}
+	}
+	s.RUnlock()
+
+	_, err := s.listenEcho(ip, ECHO_PORT, "")
+
+	return err
+}
+
+// SetEchoRequiredMinRxInterval sets the Required Min Echo RX Interval (in µs) advertised to the peers,
+// the forwarding plane loops their echo packets back, 0 = echo isn't supported
+func (s *BfdServer) SetEchoRequiredMinRxInterval(requiredMinRx uint32) {
+	s.Lock()
+	s.echoRequiredMinRx = requiredMinRx
+	sessions := make([]*Peer, 0, len(s.Sessions))
+
+	for _, peer := range s.Sessions {
+		sessions = append(sessions, peer)
+	}
+	s.Unlock()
+
+	// let the existing sessions know that they can use echo
+	for _, peer := range sessions {
+		peer.ApplyLocalState([]PeerStateUpdate{setRequiredMinEchoRxInterval(requiredMinRx)})
+	}
+}
+
+// ListenEcho starts an echo reflector for the peers using it, requiredMinRx (in µs) is advertised
+// to the peers as Required Min Echo RX Interval
+func (s *BfdServer) ListenEcho(address string, requiredMinRx uint32) error {
+	ip, zone, port, err := parseHostPort(address, ECHO_PORT)
+
+	if err != nil || port < 1 || port > 65535 {
+		return ErrInvalidPort
+	}
+
+	if ip == nil {
+		return ErrInvalidIP
+	}
+
+	l, err := s.listenEcho(ip, port, zone)
+
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	l.reflect = true
+	s.Unlock()
+
+	s.SetEchoRequiredMinRxInterval(requiredMinRx)
+
+	return nil
+}
+
+// handleEchoSocket hands the looped back echo packets to their session and reflects the
+// echo packets of the peers using the reflector
+func (s *BfdServer) handleEchoSocket(l *echoListener) {
+	defer s.readers.Done()
+
+	b := make([]byte, 256)
+	packet := &bfd.EchoPacket{}
+
+	for {
+		n, addr, err := l.conn.ReadFromUDP(b)
+
+		if err != nil {
+			return
+		}
+
+		if peer := s.findEchoSession(b[:n], addr, packet); peer != nil {
+			if err := peer.handleEcho(packet); err != nil {
+				glog.Infof("%s", err.Error())
+			}
+
+			continue
+		}
+
+		s.RLock()
+		reflect := l.reflect && s.peerAddresses[addr.IP.String()] > 0
+		s.RUnlock()
+
+		// echo packets are only reflected to configured peers
+		if !reflect {
+			glog.Infof("Discarded echo packet from %s", addr.IP)
+			continue
+		}
+
+		if _, err := l.conn.WriteToUDP(b[:n], addr); err != nil {
+			glog.Infof("Error on echo reflect: %s", err)
+		}
+	}
+}
+
+// findEchoSession returns the session of a looped back echo packet, they're sent from the local echo address of the session
+func (s *BfdServer) findEchoSession(b []byte, addr *net.UDPAddr, packet *bfd.EchoPacket) *Peer {
+	if err := packet.UnmarshalBinary(b); err != nil {
+		return nil
+	}
+
+	s.RLock()
+	peer, ok := s.Sessions[packet.MyDiscriminator]
+	s.RUnlock()
+
+	// echoLocal doesn't change once the session is added
+	if !ok || peer.echoLocal == nil || !peer.echoLocal.Equal(addr.IP) {
+		return nil
+	}
+
+	return peer
+}
+
+// echoInterface returns the index and address of the interface the next hop is directly connected to
+func echoInterface(nextHop *net.UDPAddr) (int, net.IP, error) {
+	ifaces, err := net.Interfaces()
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	zone := zoneIndex(nextHop.Zone)
+
+	for _, iface := range ifaces {
+		if zone != 0 && iface.Index != zone {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			network, ok := addr.(*net.IPNet)
+
+			if ok && network.Contains(nextHop.IP) && (network.IP.To4() == nil) == (nextHop.IP.To4() == nil) {
+				return iface.Index, network.IP, nil
+			}
+		}
+	}
+
+	return 0, nil, ErrEchoNoInterface
+}
+
+// appendEchoDatagram appends the IP and UDP headers of an echo packet from src to dst on the echo port and the payload to b
+func appendEchoDatagram(b []byte, src, dst net.IP, payload []byte) []byte {
+	udpLength := bfd.UDP_HEADER_LENGTH + len(payload)
+	var addresses []byte
+
+	if src4, dst4 := src.To4(), dst.To4(); src4 != nil && dst4 != nil {
+		header := make([]byte, bfd.IPV4_HEADER_LENGTH)
+		header[0] = 0x45
+		binary.BigEndian.PutUint16(header[2:], uint16(bfd.IPV4_HEADER_LENGTH+udpLength))
+		header[8] = 255
+		header[9] = bfd.IPPROTO_UDP
+		copy(header[12:16], src4)
+		copy(header[16:20], dst4)
+		binary.BigEndian.PutUint16(header[10:], bfd.Checksum(header, 0))
+
+		b = append(b, header...)
+		addresses = header[12:20]
+	} else {
+		header := make([]byte, bfd.IPV6_HEADER_LENGTH)
+		header[0] = 0x60
+		binary.BigEndian.PutUint16(header[4:], uint16(udpLength))
+		header[6] = bfd.IPPROTO_UDP
+		header[7] = 255
+		copy(header[8:24], src.To16())
+		copy(header[24:40], dst.To16())
+
+		b = append(b, header...)
+		addresses = header[8:40]
+	}
+
+	udp := len(b)
+	b = append(b, make([]byte, bfd.UDP_HEADER_LENGTH)...)
+	binary.BigEndian.PutUint16(b[udp:], ECHO_PORT)
+	binary.BigEndian.PutUint16(b[udp+2:], ECHO_PORT)
+	binary.BigEndian.PutUint16(b[udp+4:], uint16(udpLength))
+	b = append(b, payload...)
+
+	checksum := bfd.UDPChecksum(addresses, b[udp:])
+
+	// a checksum of 0 means no checksum, it's sent as all ones
+	if checksum == 0 {
+		checksum = 0xffff
+	}
+
+	binary.BigEndian.PutUint16(b[udp+6:], checksum)
+
+	return b
+}
@@ -0,0 +1,182 @@
+//go:build linux
+// +build linux
+
+package server
+
+import (
+	"net"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	// the link layer address of the next hop is looked up again after this time
+	NEIGHBOR_REFRESH = time.Second
+
+	ndaDst    = 1 // NDA_DST
+	ndaLLAddr = 2 // NDA_LLADDR
+
+	nudIncomplete = 0x01 // NUD_INCOMPLETE
+	nudFailed     = 0x20 // NUD_FAILED
+)
+
+// rawEchoConn sends echo packets on a packet socket of the interface towards the next hop,
+// the kernel adds the link layer header for the address of the next hop
+type rawEchoConn struct {
+	sync.Mutex
+
+	fd       int
+	ifIndex  int
+	local    net.IP
+	nextHop  net.IP
+	hwAddr   net.HardwareAddr
+	clock    Clock
+	resolved time.Time
+	buf      []byte
+}
+
+// dialEchoLoopback opens a packet socket, which doesn't receive any packets as its protocol is 0
+func dialEchoLoopback(local net.IP, nextHop *net.UDPAddr, clock Clock) (EchoConn, error) {
+	ifIndex, address, err := echoInterface(nextHop)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if local == nil {
+		local = address
+	}
+
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &rawEchoConn{
+		fd:      fd,
+		ifIndex: ifIndex,
+		local:   local,
+		nextHop: nextHop.IP,
+		clock:   clock,
+		buf:     make([]byte, 0, 128),
+	}, nil
+}
+
+func (c *rawEchoConn) Write(b []byte) (int, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	// the neighbor entry may have changed, e.g. after a failover of the remote
+	if c.hwAddr == nil || c.clock.Now().Sub(c.resolved) > NEIGHBOR_REFRESH {
+		hwAddr, err := lookupNeighbor(c.ifIndex, c.nextHop)
+
+		if err != nil {
+			return 0, err
+		}
+
+		c.hwAddr = hwAddr
+		c.resolved = c.clock.Now()
+	}
+
+	protocol := uint16(syscall.ETH_P_IP)
+
+	if c.local.To4() == nil {
+		protocol = syscall.ETH_P_IPV6
+	}
+
+	addr := &syscall.SockaddrLinklayer{
+		Protocol: protocol<<8 | protocol>>8,
+		Ifindex:  c.ifIndex,
+		Halen:    uint8(len(c.hwAddr)),
+	}
+	copy(addr.Addr[:], c.hwAddr)
+
+	c.buf = appendEchoDatagram(c.buf[:0], c.local, c.local, b)
+
+	if err := syscall.Sendto(c.fd, c.buf, 0, addr); err != nil {
+		c.hwAddr = nil
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+func (c *rawEchoConn) LocalAddr() net.Addr {
+	return &net.UDPAddr{IP: c.local, Port: ECHO_PORT}
+}
+
+func (c *rawEchoConn) Close() error {
+	return syscall.Close(c.fd)
+}
+
+// lookupNeighbor returns the link layer address of ip on the interface from the neighbor table of the kernel
+func lookupNeighbor(ifIndex int, ip net.IP) (net.HardwareAddr, error) {
+	family := syscall.AF_INET
+
+	if ip.To4() == nil {
+		family = syscall.AF_INET6
+	}
+
+	rib, err := syscall.NetlinkRIB(syscall.RTM_GETNEIGH, family)
+
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := syscall.ParseNetlinkMessage(rib)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range msgs {
+		// struct ndmsg: family, pad, pad, ifindex, state, flags, type
+		if m.Header.Type != syscall.RTM_NEWNEIGH || len(m.Data) < 12 {
+			continue
+		}
+
+		index := int(*(*int32)(unsafe.Pointer(&m.Data[4])))
+		state := *(*uint16)(unsafe.Pointer(&m.Data[8]))
+
+		if index != ifIndex || state&(nudIncomplete|nudFailed) != 0 {
+			continue
+		}
+
+		var dst net.IP
+		var hwAddr net.HardwareAddr
+
+		for attrs := m.Data[12:]; len(attrs) >= 4; {
+			length := int(*(*uint16)(unsafe.Pointer(&attrs[0])))
+			kind := *(*uint16)(unsafe.Pointer(&attrs[2]))
+
+			if length < 4 || length > len(attrs) {
+				break
+			}
+
+			switch kind {
+			case ndaDst:
+				dst = net.IP(attrs[4:length])
+			case ndaLLAddr:
+				hwAddr = net.HardwareAddr(attrs[4:length])
+			}
+
+			// attributes are aligned to 4 bytes
+			length = (length + 3) &^ 3
+
+			if length > len(attrs) {
+				break
+			}
+
+			attrs = attrs[length:]
+		}
+
+		if dst.Equal(ip) && len(hwAddr) > 0 {
+			return append(net.HardwareAddr(nil), hwAddr...), nil
+		}
+	}
+
+	return nil, ErrNeighborNotFound
+}
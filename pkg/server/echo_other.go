@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package server
+
+import (
+	"net"
+)
+
+// dialEchoLoopback needs packet sockets and the neighbor table, which are only used on linux
+func dialEchoLoopback(local net.IP, nextHop *net.UDPAddr, clock Clock) (EchoConn, error) {
+	return nil, ErrEchoLoopbackNotSupported
+}
@@ -0,0 +1,324 @@
+package server
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+type fakeEchoConn struct {
+	written  [][]byte
+	incoming chan []byte
+}
+
+func newFakeEchoConn() *fakeEchoConn {
+	return &fakeEchoConn{
+		incoming: make(chan []byte, 8),
+	}
+}
+
+func (f *fakeEchoConn) Read(b []byte) (int, error) {
+	data, ok := <-f.incoming
+
+	if !ok {
+		return 0, io.EOF
+	}
+
+	return copy(b, data), nil
+}
+
+func (f *fakeEchoConn) Write(b []byte) (int, error) {
+	f.written = append(f.written, b)
+
+	return len(b), nil
+}
+
+func (f *fakeEchoConn) Close() error {
+	close(f.incoming)
+
+	return nil
+}
+
+func setupEchoPeer(t *testing.T) (*Peer, *fakeEchoConn) {
+	p, _ := setupUpPeer(t)
+
+	echo := newFakeEchoConn()
+
+	p.EchoInterval = 50000
+	p.echoConn = echo
+	p.ApplyRemoteState([]PeerStateUpdate{
+		setSessionState(bfd.Up),
+		setRequiredMinRxInterval(100000),
+		setRequiredMinEchoRxInterval(100000),
+	})
+
+	return p, echo
+}
+
+func TestEchoStartStop(t *testing.T) {
+	p, _ := setupEchoPeer(t)
+	defer p.Shutdown()
+
+	p.updateEcho()
+
+	if !p.echoActive {
+		t.Fatalf("Expected echo to be active")
+	}
+
+	// the remote limits the echo rate
+	if p.echoDetectionTime != 3*100000*time.Microsecond {
+		t.Errorf("Unexpected echo detection time %v", p.echoDetectionTime)
+	}
+
+	// control packets are slowed down while echo is active
+	if p.GetLocal().requiredMinRxInterval != ECHO_CONTROL_INTERVAL {
+		t.Errorf("Expected a slower control rate, got %d", p.GetLocal().requiredMinRxInterval)
+	}
+
+	// a zero Required Min Echo RX Interval stops echo
+	p.ApplyRemoteState([]PeerStateUpdate{setRequiredMinEchoRxInterval(0)})
+	p.updateEcho()
+
+	if p.echoActive {
+		t.Errorf("Expected echo to be stopped")
+	}
+
+	// the faster control rate is restored once the poll sequence terminates
+	p.terminatePoll()
+
+	if p.GetLocal().requiredMinRxInterval != 100000 {
+		t.Errorf("Expected the control rate to be restored, got %d", p.GetLocal().requiredMinRxInterval)
+	}
+}
+
+func TestEchoNotStartedWithoutRemoteSupport(t *testing.T) {
+	p, _ := setupEchoPeer(t)
+	defer p.Shutdown()
+
+	p.ApplyRemoteState([]PeerStateUpdate{setRequiredMinEchoRxInterval(0)})
+	p.updateEcho()
+
+	if p.echoActive {
+		t.Errorf("Expected echo to stay inactive")
+	}
+}
+
+func TestSendEcho(t *testing.T) {
+	p, echo := setupEchoPeer(t)
+	defer p.Shutdown()
+
+	if err := p.sendEcho(); err != nil || len(echo.written) != 0 {
+		t.Errorf("Expected no echo packets while echo is inactive")
+	}
+
+	p.updateEcho()
+
+	if err := p.sendEcho(); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(echo.written) != 1 {
+		t.Fatalf("Expected an echo packet to be sent")
+	}
+
+	packet := &bfd.EchoPacket{}
+
+	if err := packet.UnmarshalBinary(echo.written[0]); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if packet.MyDiscriminator != 50 || packet.SequenceNumber != 1 {
+		t.Errorf("Unexpected echo packet %v", packet)
+	}
+}
+
+func TestHandleEcho(t *testing.T) {
+	p, _ := setupEchoPeer(t)
+	defer p.Shutdown()
+
+	p.updateEcho()
+
+	if err := p.handleEcho(&bfd.EchoPacket{MyDiscriminator: 51}); err != ErrInvalidMyDiscriminator {
+		t.Errorf("Expected %v, got %v", ErrInvalidMyDiscriminator, err)
+	}
+
+	if err := p.handleEcho(&bfd.EchoPacket{MyDiscriminator: 50}); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestEchoFailed(t *testing.T) {
+	p, _ := setupEchoPeer(t)
+	defer p.Shutdown()
+
+	p.updateEcho()
+	p.echoFailed()
+
+	local := p.GetLocal()
+
+	if local.sessionState != bfd.Down || local.diagnosticCode != bfd.EchoFunctionFailed {
+		t.Errorf("Expected the session to be down with Echo Function Failed, got %v", local)
+	}
+
+	if p.echoActive {
+		t.Errorf("Expected echo to be stopped")
+	}
+}
+
+func TestListenEcho(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	p := Setup(t)
+	p.Address.IP = net.ParseIP("127.0.0.1")
+	p.Start()
+
+	server.Lock()
+	server.addSession(p)
+	server.Unlock()
+
+	if err := server.ListenEcho("127.0.0.1:0", 50000); err != ErrInvalidPort {
+		t.Errorf("Expected %v, got %v", ErrInvalidPort, err)
+	}
+
+	if err := server.ListenEcho("127.0.0.1:53785", 50000); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// existing sessions advertise the echo interval
+	if p.GetLocal().requiredMinEchoRxInterval != 50000 {
+		t.Errorf("Expected the Required Min Echo RX Interval to be advertised")
+	}
+
+	conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 53785})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer conn.Close()
+
+	data := []byte{1, 0, 0, 0, 0, 0, 0, 50, 0, 0, 0, 1}
+	conn.Write(data)
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	b := make([]byte, 64)
+	n, err := conn.Read(b)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if string(b[:n]) != string(data) {
+		t.Errorf("Expected the echo packet to be reflected unchanged")
+	}
+}
+
+func TestEchoDatagram(t *testing.T) {
+	payload := []byte{1, 0, 0, 0, 0, 0, 0, 50, 0, 0, 0, 1}
+
+	for _, local := range []string{"10.0.0.1", "2001:db8::1"} {
+		ip := net.ParseIP(local)
+		b := appendEchoDatagram(nil, ip, ip, payload)
+
+		header := bfd.IPV4_HEADER_LENGTH
+		addresses := b[12:20]
+
+		if ip.To4() == nil {
+			header = bfd.IPV6_HEADER_LENGTH
+			addresses = b[8:40]
+
+			if b[0] != 0x60 || b[6] != bfd.IPPROTO_UDP || b[7] != 255 {
+				t.Errorf("Unexpected IPv6 header %v", b[:header])
+			}
+		} else {
+			if b[0] != 0x45 || b[8] != 255 || b[9] != bfd.IPPROTO_UDP || bfd.Checksum(b[:header], 0) != 0 {
+				t.Errorf("Unexpected IPv4 header %v", b[:header])
+			}
+		}
+
+		udp := b[header:]
+
+		if binary.BigEndian.Uint16(udp[2:]) != ECHO_PORT || int(binary.BigEndian.Uint16(udp[4:])) != len(udp) {
+			t.Errorf("Unexpected UDP header %v", udp[:bfd.UDP_HEADER_LENGTH])
+		}
+
+		if bfd.UDPChecksum(addresses, udp) != 0 {
+			t.Errorf("Invalid UDP checksum of %s", local)
+		}
+
+		if string(udp[bfd.UDP_HEADER_LENGTH:]) != string(payload) {
+			t.Errorf("Expected the payload to follow the headers")
+		}
+	}
+}
+
+func TestEchoLoopback(t *testing.T) {
+	network := NewVirtualNetwork(1)
+
+	local, err := network.NewServer("10.0.0.1")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer local.Shutdown()
+
+	remote, err := network.NewServer("10.0.0.2")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer remote.Shutdown()
+
+	// the forwarding plane of the remote loops the packets back, it only advertises the interval
+	remote.SetEchoRequiredMinRxInterval(50000)
+
+	p, err := local.AddPeer(&api.Peer{
+		Address:               "10.0.0.2",
+		DesiredMinTxInterval:  100000,
+		RequiredMinRxInterval: 100,
+		DetectMultiplier:      3,
+		EchoInterval:          50,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	_, err = remote.AddPeer(&api.Peer{
+		Address:               "10.0.0.1",
+		DesiredMinTxInterval:  100000,
+		RequiredMinRxInterval: 100,
+		DetectMultiplier:      3,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	network.Advance(5 * time.Second)
+
+	p.RLock()
+	active := p.echoActive
+	p.RUnlock()
+
+	if !active || p.GetLocal().GetSessionState() != bfd.Up || p.GetLocal().requiredMinRxInterval != ECHO_CONTROL_INTERVAL {
+		t.Fatalf("Expected echo to be active, got %v", p.GetLocal())
+	}
+
+	// echo detects the failure long before the slowed down control packets
+	network.SetLoss("10.0.0.1", "10.0.0.2", 1)
+	network.Advance(300 * time.Millisecond)
+
+	if p.GetLocal().GetSessionState() != bfd.Down || p.GetLocal().diagnosticCode != bfd.EchoFunctionFailed {
+		t.Errorf("Expected the echo function to fail, got %v", p.GetLocal())
+	}
+}
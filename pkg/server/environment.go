@@ -0,0 +1,229 @@
+package server
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+/*
+	A server takes its time, randomness and sockets from a Clock, a Rand
+	and a Transport. By default they're the system clock, the global
+	math/rand source and kernel UDP sockets. A VirtualNetwork replaces all
+	three, so complete sessions can be simulated without real sockets and
+	faster than real time.
+
+	Micro BFD members bind their listener to the member interface and
+	batched reads depend on recvmmsg, both always use kernel sockets. The
+	shared socket of batched writes is opened by the transport, sendmmsg
+	needs it to be a kernel socket.
+*/
+
+var ErrServerInUse = errors.New("The clock, random source and transport can't be changed once a session or listener is added")
+var ErrKernelSocketRequired = errors.New("The socket options of the session type need a kernel socket")
+
+// Clock is the source of time of a server, its sessions and their timers
+type Clock interface {
+	Now() time.Time
+
+	// AfterFunc calls f once d passed, on a goroutine of the clock like time.AfterFunc
+	AfterFunc(d time.Duration, f func()) ClockTimer
+}
+
+// ClockTimer is a timer of a Clock, *time.Timer implements it
+type ClockTimer interface {
+	Reset(d time.Duration) bool
+	Stop() bool
+}
+
+// Rand is the source of discriminators, source ports, sequence numbers and jitter, it's used concurrently
+type Rand interface {
+	Uint32() uint32
+	Intn(n int) int
+}
+
+// Transport opens the UDP sockets of a server
+type Transport interface {
+	// ListenUDP opens a socket bound to laddr, the TTL / Hop Limit, destination and
+	// interface of the received packets need to be reported
+	ListenUDP(network string, laddr *net.UDPAddr) (UDPConn, error)
+
+	// DialUDP opens a socket connected to raddr, its packets are sent with a TTL / Hop Limit of 255
+	DialUDP(network string, laddr, raddr *net.UDPAddr) (UDPConn, error)
+
+	// DialEcho opens a socket sending echo packets addressed to local through the forwarding plane
+	// of the directly connected nextHop, a nil local is the address of the interface towards it.
+	// The link layer address of the next hop is looked up again as the time of clock passes.
+	DialEcho(local net.IP, nextHop *net.UDPAddr, clock Clock) (EchoConn, error)
+}
+
+// EchoConn sends echo packets from and to its local address via the next hop
+type EchoConn interface {
+	Write(b []byte) (int, error)
+	LocalAddr() net.Addr
+	Close() error
+}
+
+// UDPConn is the part of a *net.UDPConn used by the server
+type UDPConn interface {
+	Connection
+
+	Read(b []byte) (int, error)
+	ReadFromUDP(b []byte) (int, *net.UDPAddr, error)
+	WriteToUDP(b []byte, addr *net.UDPAddr) (int, error)
+	LocalAddr() net.Addr
+	Close() error
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) ClockTimer {
+	return time.AfterFunc(d, f)
+}
+
+// globalRand uses the global source of math/rand, which is seeded by the application
+type globalRand struct{}
+
+func (globalRand) Uint32() uint32 {
+	return rand.Uint32()
+}
+
+func (globalRand) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+// lockedRand guards a source of math/rand, which isn't safe for concurrent use
+type lockedRand struct {
+	sync.Mutex
+
+	r *rand.Rand
+}
+
+// NewRand returns a Rand with a fixed seed, it always returns the same sequence
+func NewRand(seed int64) Rand {
+	return &lockedRand{r: rand.New(rand.NewSource(seed))}
+}
+
+func (l *lockedRand) Uint32() uint32 {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.r.Uint32()
+}
+
+func (l *lockedRand) Intn(n int) int {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.r.Intn(n)
+}
+
+// kernelTransport opens UDP sockets of the operating system
+type kernelTransport struct{}
+
+func (kernelTransport) ListenUDP(network string, laddr *net.UDPAddr) (UDPConn, error) {
+	conn, err := net.ListenUDP(network, laddr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup so that we receive the TTL / Hop Limit, destination and interface of incoming packets
+	if err = setControlMessages(conn, laddr.IP); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// reflectors answer from the listener
+	setMaxHopLimit(conn, laddr.IP)
+
+	return conn, nil
+}
+
+func (kernelTransport) DialUDP(network string, laddr, raddr *net.UDPAddr) (UDPConn, error) {
+	conn, err := net.DialUDP(network, laddr, raddr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	setMaxHopLimit(conn, raddr.IP)
+
+	return conn, nil
+}
+
+func (kernelTransport) DialEcho(local net.IP, nextHop *net.UDPAddr, clock Clock) (EchoConn, error) {
+	return dialEchoLoopback(local, nextHop, clock)
+}
+
+// SetClock replaces the system clock, it needs to be called before the first session or listener is added
+func (s *BfdServer) SetClock(clock Clock) error {
+	return s.setClock(clock, s.scheduler.workers())
+}
+
+// setClock replaces the scheduler by one running on clock, 0 workers run the timers on the goroutine of the clock
+func (s *BfdServer) setClock(clock Clock, workers int) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.inUse() {
+		return ErrServerInUse
+	}
+
+	s.scheduler.stop()
+	s.scheduler = newScheduler(clock, workers)
+	s.clock = clock
+
+	return nil
+}
+
+// SetRand replaces the global math/rand source, it needs to be called before the first session or listener is added
+func (s *BfdServer) SetRand(r Rand) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.inUse() {
+		return ErrServerInUse
+	}
+
+	s.rand = r
+
+	return nil
+}
+
+// SetTransport replaces the kernel sockets, it needs to be called before the first session or listener is added
+func (s *BfdServer) SetTransport(transport Transport) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.inUse() {
+		return ErrServerInUse
+	}
+
+	s.transport = transport
+
+	return nil
+}
+
+// inUse returns true once a session or listener was added, the lock needs to be held by the caller
+func (s *BfdServer) inUse() bool {
+	return len(s.Sessions) > 0 || len(s.conns) > 0 || len(s.echoConns) > 0 ||
+		len(s.sbfdConns) > 0 || len(s.sbfdInitiators) > 0 || len(s.lags) > 0
+}
+
+// kernelConn returns the socket of the operating system behind conn, if there is one
+func kernelConn(conn UDPConn) (*net.UDPConn, error) {
+	udp, ok := conn.(*net.UDPConn)
+
+	if !ok {
+		return nil, ErrKernelSocketRequired
+	}
+
+	return udp, nil
+}
@@ -16,8 +16,25 @@ type BfdServerApi interface {
 	AddPeer(*api.Peer) (*Peer, error)
 	GetPeerByUuid([]byte) (*Peer, error)
 	DeletePeer([]byte) error
-	ListPeer(context.Context, func([]byte, *api.Peer) error) error
+	ListPeer(context.Context, func([]byte, *api.Peer, []*api.ViolationCount) error) error
 	MonitorPeer(context.Context, []byte, func(*api.PeerStateResponse) error) error
+	AddKeyChain(*api.KeyChain) error
+	DeleteKeyChain(string) error
+	ListKeyChain(context.Context, func(*api.KeyChain) error) error
+	AddKey(string, *api.Key) error
+	DeleteKey(string, uint32) error
+	AddSbfdReflector(uint32) error
+	DeleteSbfdReflector(uint32) error
+	ListSbfdReflector(context.Context, func(uint32) error) error
+	AddSbfdInitiator(*api.SbfdInitiator) (*SbfdInitiator, error)
+	DeleteSbfdInitiator([]byte) error
+	ListSbfdInitiator(context.Context, func([]byte, *api.SbfdInitiator, api.SessionState) error) error
+	AddLag(*api.Lag) (*Lag, error)
+	DeleteLag(string) error
+	ListLag(context.Context, func(*api.Lag, api.SessionState, []*api.LagMember) error) error
+	ListListener(context.Context, func(*api.ListListenerResponse) error) error
+	ListInboundQueue(context.Context, func(*api.ListInboundQueueResponse) error) error
+	ListDynamicRange(context.Context, func(*api.ListDynamicRangeResponse) error) error
 }
 
 var ErrAddressNotChangeable = errors.New("Unable to change peer address")
@@ -123,11 +140,12 @@ func (a *BfdApiServer) ListPeer(req *api.ListPeerRequest, stream api.BfdApi_List
 	defer cancel()
 	var err error
 
-	return a.bfdServer.ListPeer(ctx, func(uuid []byte, peer *api.Peer) error {
+	return a.bfdServer.ListPeer(ctx, func(uuid []byte, peer *api.Peer, violations []*api.ViolationCount) error {
 		// wrap in func with callback
 		err = stream.Send(&api.ListPeerResponse{
-			Uuid: uuid,
-			Peer: peer,
+			Uuid:       uuid,
+			Peer:       peer,
+			Violations: violations,
 		})
 
 		if err != nil {
@@ -146,10 +164,7 @@ func (a *BfdApiServer) GetPeerState(ctx context.Context, req *api.GetPeerStateRe
 		return nil, err
 	}
 
-	return &api.PeerStateResponse{
-		Local:  peer.GetLocal().ToApi(),
-		Remote: peer.GetRemote().ToApi(),
-	}, nil
+	return peer.Snapshot().ToApi(), nil
 }
 
 func (a *BfdApiServer) MonitorPeer(req *api.MonitorPeerRequest, stream api.BfdApi_MonitorPeerServer) error {
@@ -194,3 +209,203 @@ func (a *BfdApiServer) EnablePeer(ctx context.Context, req *api.EnablePeerReques
 
 	return &empty.Empty{}, nil
 }
+
+func (a *BfdApiServer) PollPeer(ctx context.Context, req *api.PollPeerRequest) (*empty.Empty, error) {
+	peer, err := a.bfdServer.GetPeerByUuid(req.Uuid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &empty.Empty{}, peer.Poll()
+}
+
+func (a *BfdApiServer) AddKeyChain(ctx context.Context, req *api.AddKeyChainRequest) (*empty.Empty, error) {
+	if req.KeyChain == nil {
+		return nil, ErrInvalidKeyChainName
+	}
+
+	return &empty.Empty{}, a.bfdServer.AddKeyChain(req.KeyChain)
+}
+
+func (a *BfdApiServer) DeleteKeyChain(ctx context.Context, req *api.DeleteKeyChainRequest) (*empty.Empty, error) {
+	return &empty.Empty{}, a.bfdServer.DeleteKeyChain(req.Name)
+}
+
+func (a *BfdApiServer) ListKeyChain(req *api.ListKeyChainRequest, stream api.BfdApi_ListKeyChainServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	return a.bfdServer.ListKeyChain(ctx, func(chain *api.KeyChain) error {
+		err := stream.Send(&api.ListKeyChainResponse{
+			KeyChain: chain,
+		})
+
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (a *BfdApiServer) AddKey(ctx context.Context, req *api.AddKeyRequest) (*empty.Empty, error) {
+	if req.Key == nil {
+		return nil, ErrKeyNotFound
+	}
+
+	return &empty.Empty{}, a.bfdServer.AddKey(req.KeyChain, req.Key)
+}
+
+func (a *BfdApiServer) DeleteKey(ctx context.Context, req *api.DeleteKeyRequest) (*empty.Empty, error) {
+	return &empty.Empty{}, a.bfdServer.DeleteKey(req.KeyChain, req.Id)
+}
+
+func (a *BfdApiServer) AddSbfdReflector(ctx context.Context, req *api.AddSbfdReflectorRequest) (*empty.Empty, error) {
+	return &empty.Empty{}, a.bfdServer.AddSbfdReflector(req.Discriminator)
+}
+
+func (a *BfdApiServer) DeleteSbfdReflector(ctx context.Context, req *api.DeleteSbfdReflectorRequest) (*empty.Empty, error) {
+	return &empty.Empty{}, a.bfdServer.DeleteSbfdReflector(req.Discriminator)
+}
+
+func (a *BfdApiServer) ListSbfdReflector(req *api.ListSbfdReflectorRequest, stream api.BfdApi_ListSbfdReflectorServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	return a.bfdServer.ListSbfdReflector(ctx, func(discriminator uint32) error {
+		err := stream.Send(&api.ListSbfdReflectorResponse{
+			Discriminator: discriminator,
+		})
+
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (a *BfdApiServer) AddSbfdInitiator(ctx context.Context, req *api.AddSbfdInitiatorRequest) (*api.AddSbfdInitiatorResponse, error) {
+	if req.Initiator == nil {
+		return nil, ErrInvalidReflectorDiscriminator
+	}
+
+	initiator, err := a.bfdServer.AddSbfdInitiator(req.Initiator)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AddSbfdInitiatorResponse{
+		Uuid: initiator.GetUuid(),
+	}, nil
+}
+
+func (a *BfdApiServer) DeleteSbfdInitiator(ctx context.Context, req *api.DeleteSbfdInitiatorRequest) (*empty.Empty, error) {
+	return &empty.Empty{}, a.bfdServer.DeleteSbfdInitiator(req.Uuid)
+}
+
+func (a *BfdApiServer) ListSbfdInitiator(req *api.ListSbfdInitiatorRequest, stream api.BfdApi_ListSbfdInitiatorServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	return a.bfdServer.ListSbfdInitiator(ctx, func(uuid []byte, initiator *api.SbfdInitiator, state api.SessionState) error {
+		err := stream.Send(&api.ListSbfdInitiatorResponse{
+			Uuid:      uuid,
+			Initiator: initiator,
+			State:     state,
+		})
+
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (a *BfdApiServer) AddLag(ctx context.Context, req *api.AddLagRequest) (*empty.Empty, error) {
+	if req.Lag == nil {
+		return nil, ErrNoLagMembers
+	}
+
+	_, err := a.bfdServer.AddLag(req.Lag)
+
+	return &empty.Empty{}, err
+}
+
+func (a *BfdApiServer) DeleteLag(ctx context.Context, req *api.DeleteLagRequest) (*empty.Empty, error) {
+	return &empty.Empty{}, a.bfdServer.DeleteLag(req.Name)
+}
+
+func (a *BfdApiServer) ListLag(req *api.ListLagRequest, stream api.BfdApi_ListLagServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	return a.bfdServer.ListLag(ctx, func(lag *api.Lag, state api.SessionState, members []*api.LagMember) error {
+		err := stream.Send(&api.ListLagResponse{
+			Lag:     lag,
+			State:   state,
+			Members: members,
+		})
+
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (a *BfdApiServer) ListListener(req *api.ListListenerRequest, stream api.BfdApi_ListListenerServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	return a.bfdServer.ListListener(ctx, func(response *api.ListListenerResponse) error {
+		err := stream.Send(response)
+
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (a *BfdApiServer) ListInboundQueue(req *api.ListInboundQueueRequest, stream api.BfdApi_ListInboundQueueServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	return a.bfdServer.ListInboundQueue(ctx, func(response *api.ListInboundQueueResponse) error {
+		err := stream.Send(response)
+
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (a *BfdApiServer) ListDynamicRange(req *api.ListDynamicRangeRequest, stream api.BfdApi_ListDynamicRangeServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	return a.bfdServer.ListDynamicRange(ctx, func(response *api.ListDynamicRangeResponse) error {
+		err := stream.Send(response)
+
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		return nil
+	})
+}
@@ -22,6 +22,19 @@ type fakeApiServer struct {
 
 	listChannel    chan uuidPeer
 	monitorChannel chan *api.PeerStateResponse
+
+	keyChains []*api.KeyChain
+
+	reflectors []uint32
+	initiator  *SbfdInitiator
+
+	lags []*api.Lag
+
+	listeners []*api.ListListenerResponse
+
+	queues []*api.ListInboundQueueResponse
+
+	dynamicRanges []*api.ListDynamicRangeResponse
 }
 
 func NewFakeApiServer() *fakeApiServer {
@@ -59,9 +72,9 @@ func (s *fakeApiServer) DeletePeer([]byte) error {
 	return nil
 }
 
-func (s *fakeApiServer) ListPeer(ctx context.Context, cb func([]byte, *api.Peer) error) error {
+func (s *fakeApiServer) ListPeer(ctx context.Context, cb func([]byte, *api.Peer, []*api.ViolationCount) error) error {
 	for p := range s.listChannel {
-		err := cb(p.uuid, p.peer)
+		err := cb(p.uuid, p.peer, nil)
 		if err != nil {
 			return err
 		}
@@ -82,6 +95,116 @@ func (s *fakeApiServer) MonitorPeer(ctx context.Context, uuid []byte, cb func(*a
 	return s.err
 }
 
+func (s *fakeApiServer) AddKeyChain(chain *api.KeyChain) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.keyChains = append(s.keyChains, chain)
+
+	return nil
+}
+
+func (s *fakeApiServer) DeleteKeyChain(string) error {
+	return s.err
+}
+
+func (s *fakeApiServer) ListKeyChain(ctx context.Context, cb func(*api.KeyChain) error) error {
+	for _, chain := range s.keyChains {
+		err := cb(chain)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *fakeApiServer) AddKey(string, *api.Key) error {
+	return s.err
+}
+
+func (s *fakeApiServer) DeleteKey(string, uint32) error {
+	return s.err
+}
+
+func (s *fakeApiServer) AddSbfdReflector(discriminator uint32) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.reflectors = append(s.reflectors, discriminator)
+
+	return nil
+}
+
+func (s *fakeApiServer) DeleteSbfdReflector(uint32) error {
+	return s.err
+}
+
+func (s *fakeApiServer) ListSbfdReflector(ctx context.Context, cb func(uint32) error) error {
+	for _, discriminator := range s.reflectors {
+		err := cb(discriminator)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *fakeApiServer) AddSbfdInitiator(*api.SbfdInitiator) (*SbfdInitiator, error) {
+	return s.initiator, s.err
+}
+
+func (s *fakeApiServer) DeleteSbfdInitiator([]byte) error {
+	return s.err
+}
+
+func (s *fakeApiServer) ListSbfdInitiator(ctx context.Context, cb func([]byte, *api.SbfdInitiator, api.SessionState) error) error {
+	return s.err
+}
+
+func (s *fakeApiServer) AddLag(lag *api.Lag) (*Lag, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.lags = append(s.lags, lag)
+
+	return &Lag{Name: lag.Name}, nil
+}
+
+func (s *fakeApiServer) DeleteLag(string) error {
+	return s.err
+}
+
+func (s *fakeApiServer) ListLag(ctx context.Context, cb func(*api.Lag, api.SessionState, []*api.LagMember) error) error {
+	for _, lag := range s.lags {
+		members := []*api.LagMember{
+			{Interface: lag.Members[0], State: api.SessionState_UP},
+		}
+
+		err := cb(lag, api.SessionState_UP, members)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.err
+}
+
+func (s *fakeApiServer) ListListener(ctx context.Context, cb func(*api.ListListenerResponse) error) error {
+	for _, listener := range s.listeners {
+		if err := cb(listener); err != nil {
+			return err
+		}
+	}
+
+	return s.err
+}
+
 type fakeSendList struct {
 	grpc.ServerStream
 	responses chan *api.ListPeerResponse
@@ -100,6 +223,162 @@ func (s *fakeSendList) Send(d *api.ListPeerResponse) error {
 	return s.sendError
 }
 
+func (s *fakeSendList) Context() context.Context {
+	return context.Background()
+}
+
+type fakeSendKeyChainList struct {
+	grpc.ServerStream
+	responses chan *api.ListKeyChainResponse
+	sendError error
+}
+
+func newFakeSendKeyChainList() *fakeSendKeyChainList {
+	return &fakeSendKeyChainList{
+		responses: make(chan *api.ListKeyChainResponse, 8),
+	}
+}
+
+func (s *fakeSendKeyChainList) Send(d *api.ListKeyChainResponse) error {
+	s.responses <- d
+
+	return s.sendError
+}
+
+func (s *fakeSendKeyChainList) Context() context.Context {
+	return context.Background()
+}
+
+type fakeSendSbfdReflectorList struct {
+	grpc.ServerStream
+	responses chan *api.ListSbfdReflectorResponse
+	sendError error
+}
+
+func newFakeSendSbfdReflectorList() *fakeSendSbfdReflectorList {
+	return &fakeSendSbfdReflectorList{
+		responses: make(chan *api.ListSbfdReflectorResponse, 8),
+	}
+}
+
+func (s *fakeSendSbfdReflectorList) Send(d *api.ListSbfdReflectorResponse) error {
+	s.responses <- d
+
+	return s.sendError
+}
+
+func (s *fakeSendSbfdReflectorList) Context() context.Context {
+	return context.Background()
+}
+
+type fakeSendLagList struct {
+	grpc.ServerStream
+	responses chan *api.ListLagResponse
+	sendError error
+}
+
+func newFakeSendLagList() *fakeSendLagList {
+	return &fakeSendLagList{
+		responses: make(chan *api.ListLagResponse, 8),
+	}
+}
+
+func (s *fakeSendLagList) Send(d *api.ListLagResponse) error {
+	s.responses <- d
+
+	return s.sendError
+}
+
+func (s *fakeSendLagList) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeApiServer) ListInboundQueue(ctx context.Context, cb func(*api.ListInboundQueueResponse) error) error {
+	for _, queue := range s.queues {
+		if err := cb(queue); err != nil {
+			return err
+		}
+	}
+
+	return s.err
+}
+
+func (s *fakeApiServer) ListDynamicRange(ctx context.Context, cb func(*api.ListDynamicRangeResponse) error) error {
+	for _, r := range s.dynamicRanges {
+		if err := cb(r); err != nil {
+			return err
+		}
+	}
+
+	return s.err
+}
+
+type fakeSendListenerList struct {
+	grpc.ServerStream
+	responses chan *api.ListListenerResponse
+	sendError error
+}
+
+func newFakeSendListenerList() *fakeSendListenerList {
+	return &fakeSendListenerList{
+		responses: make(chan *api.ListListenerResponse, 8),
+	}
+}
+
+func (s *fakeSendListenerList) Send(d *api.ListListenerResponse) error {
+	s.responses <- d
+
+	return s.sendError
+}
+
+func (s *fakeSendListenerList) Context() context.Context {
+	return context.Background()
+}
+
+type fakeSendInboundQueueList struct {
+	grpc.ServerStream
+	responses chan *api.ListInboundQueueResponse
+	sendError error
+}
+
+func newFakeSendInboundQueueList() *fakeSendInboundQueueList {
+	return &fakeSendInboundQueueList{
+		responses: make(chan *api.ListInboundQueueResponse, 8),
+	}
+}
+
+func (s *fakeSendInboundQueueList) Send(d *api.ListInboundQueueResponse) error {
+	s.responses <- d
+
+	return s.sendError
+}
+
+func (s *fakeSendInboundQueueList) Context() context.Context {
+	return context.Background()
+}
+
+type fakeSendDynamicRangeList struct {
+	grpc.ServerStream
+	responses chan *api.ListDynamicRangeResponse
+	sendError error
+}
+
+func newFakeSendDynamicRangeList() *fakeSendDynamicRangeList {
+	return &fakeSendDynamicRangeList{
+		responses: make(chan *api.ListDynamicRangeResponse, 8),
+	}
+}
+
+func (s *fakeSendDynamicRangeList) Send(d *api.ListDynamicRangeResponse) error {
+	s.responses <- d
+
+	return s.sendError
+}
+
+func (s *fakeSendDynamicRangeList) Context() context.Context {
+	return context.Background()
+}
+
 type fakeSendMonitor struct {
 	grpc.ServerStream
 	responses chan *api.PeerStateResponse
@@ -500,3 +779,303 @@ func TestGrpcEnablePeerSuccess(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestGrpcPollPeerError(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, grpc.NewServer())
+
+	fake.err = ErrFake
+
+	_, err := server.PollPeer(context.Background(), &api.PollPeerRequest{
+		Uuid: []byte{0, 0, 0},
+	})
+
+	if err != ErrFake {
+		t.Fail()
+	}
+}
+
+func TestGrpcPollPeerNotUp(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, grpc.NewServer())
+
+	fake.peer, _ = NewPeer(net.ParseIP("127.0.0.1"), 16200)
+
+	_, err := server.PollPeer(context.Background(), &api.PollPeerRequest{
+		Uuid: []byte{0, 0, 0},
+	})
+
+	if err != ErrSessionNotUp {
+		t.Errorf("Expected %v, got %v", ErrSessionNotUp, err)
+	}
+}
+
+func TestGrpcAddKeyChainWithoutChain(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, grpc.NewServer())
+
+	_, err := server.AddKeyChain(context.Background(), &api.AddKeyChainRequest{})
+
+	if err != ErrInvalidKeyChainName {
+		t.Errorf("Expected %v, got %v", ErrInvalidKeyChainName, err)
+	}
+}
+
+func TestGrpcAddKeyChainWithErr(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, grpc.NewServer())
+
+	fake.err = ErrFake
+
+	_, err := server.AddKeyChain(context.Background(), &api.AddKeyChainRequest{
+		KeyChain: &api.KeyChain{Name: "core"},
+	})
+
+	if err != ErrFake {
+		t.Errorf("Expected %v, got %v", ErrFake, err)
+	}
+}
+
+func TestGrpcListKeyChainSingleResult(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, grpc.NewServer())
+
+	_, err := server.AddKeyChain(context.Background(), &api.AddKeyChainRequest{
+		KeyChain: &api.KeyChain{Name: "core"},
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	fakeResponse := newFakeSendKeyChainList()
+
+	err = server.ListKeyChain(&api.ListKeyChainRequest{}, fakeResponse)
+
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+
+	select {
+	case resp := <-fakeResponse.responses:
+		if resp.KeyChain.Name != "core" {
+			t.Errorf("Expected key chain core, got %s", resp.KeyChain.Name)
+		}
+	default:
+		t.Errorf("Did not receive a key chain via list key chains")
+	}
+}
+
+func TestGrpcAddKeyWithoutKey(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, grpc.NewServer())
+
+	_, err := server.AddKey(context.Background(), &api.AddKeyRequest{
+		KeyChain: "core",
+	})
+
+	if err != ErrKeyNotFound {
+		t.Errorf("Expected %v, got %v", ErrKeyNotFound, err)
+	}
+}
+
+func TestGrpcDeleteKeyWithErr(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, grpc.NewServer())
+
+	fake.err = ErrFake
+
+	_, err := server.DeleteKey(context.Background(), &api.DeleteKeyRequest{
+		KeyChain: "core",
+		Id:       1,
+	})
+
+	if err != ErrFake {
+		t.Errorf("Expected %v, got %v", ErrFake, err)
+	}
+}
+
+func TestGrpcListSbfdReflectorSingleResult(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, nil)
+
+	server.AddSbfdReflector(context.Background(), &api.AddSbfdReflectorRequest{
+		Discriminator: 7,
+	})
+
+	stream := newFakeSendSbfdReflectorList()
+
+	err := server.ListSbfdReflector(&api.ListSbfdReflectorRequest{}, stream)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	response := <-stream.responses
+
+	if response.Discriminator != 7 {
+		t.Errorf("Expected discriminator 7, got %d", response.Discriminator)
+	}
+}
+
+func TestGrpcAddSbfdInitiatorWithoutInitiator(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, nil)
+
+	_, err := server.AddSbfdInitiator(context.Background(), &api.AddSbfdInitiatorRequest{})
+
+	if err != ErrInvalidReflectorDiscriminator {
+		t.Errorf("Expected %v, got %v", ErrInvalidReflectorDiscriminator, err)
+	}
+}
+
+func TestGrpcAddSbfdInitiator(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, nil)
+
+	fake.initiator = &SbfdInitiator{uuid: []byte{1, 2}}
+
+	response, err := server.AddSbfdInitiator(context.Background(), &api.AddSbfdInitiatorRequest{
+		Initiator: &api.SbfdInitiator{},
+	})
+
+	if err != nil || len(response.Uuid) != 2 {
+		t.Errorf("Expected the uuid of the initiator, got %v %v", response, err)
+	}
+
+	fake.err = ErrFake
+
+	if _, err := server.AddSbfdInitiator(context.Background(), &api.AddSbfdInitiatorRequest{
+		Initiator: &api.SbfdInitiator{},
+	}); err != ErrFake {
+		t.Errorf("Expected %v, got %v", ErrFake, err)
+	}
+}
+
+func TestGrpcAddLagWithoutLag(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, nil)
+
+	_, err := server.AddLag(context.Background(), &api.AddLagRequest{})
+
+	if err != ErrNoLagMembers {
+		t.Errorf("Expected %v, got %v", ErrNoLagMembers, err)
+	}
+}
+
+func TestGrpcListLagSingleResult(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, nil)
+
+	_, err := server.AddLag(context.Background(), &api.AddLagRequest{
+		Lag: &api.Lag{Name: "bond0", Members: []string{"eth0", "eth1"}},
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	stream := newFakeSendLagList()
+
+	err = server.ListLag(&api.ListLagRequest{}, stream)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	response := <-stream.responses
+
+	if response.Lag.Name != "bond0" || response.State != api.SessionState_UP {
+		t.Errorf("Expected bond0 to be up, got %v", response)
+	}
+
+	if len(response.Members) != 1 || response.Members[0].Interface != "eth0" {
+		t.Errorf("Expected the member states, got %v", response.Members)
+	}
+}
+
+func TestGrpcListListener(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, nil)
+
+	fake.listeners = []*api.ListListenerResponse{
+		{
+			Address: "0.0.0.0:3784",
+			Violations: []*api.ViolationCount{
+				{Violation: api.Violation_ZERO_MY_DISCRIMINATOR, Count: 2},
+			},
+		},
+	}
+
+	stream := newFakeSendListenerList()
+
+	if err := server.ListListener(&api.ListListenerRequest{}, stream); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	response := <-stream.responses
+
+	if response.Address != "0.0.0.0:3784" || len(response.Violations) != 1 {
+		t.Errorf("Unexpected listener %v", response)
+	}
+
+	stream.sendError = ErrFake
+
+	if err := server.ListListener(&api.ListListenerRequest{}, stream); err != ErrFake {
+		t.Errorf("Expected %v, got %v", ErrFake, err)
+	}
+}
+
+func TestGrpcListInboundQueue(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, nil)
+
+	fake.queues = []*api.ListInboundQueueResponse{
+		{Worker: 0, Depth: 3, Capacity: 1024, Drops: 7},
+	}
+
+	stream := newFakeSendInboundQueueList()
+
+	if err := server.ListInboundQueue(&api.ListInboundQueueRequest{}, stream); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	response := <-stream.responses
+
+	if response.Depth != 3 || response.Drops != 7 {
+		t.Errorf("Unexpected queue %v", response)
+	}
+
+	stream.sendError = ErrFake
+
+	if err := server.ListInboundQueue(&api.ListInboundQueueRequest{}, stream); err != ErrFake {
+		t.Errorf("Expected %v, got %v", ErrFake, err)
+	}
+}
+
+func TestGrpcListDynamicRange(t *testing.T) {
+	fake := NewFakeApiServer()
+	server := NewBfdApiServer(fake, nil)
+
+	fake.dynamicRanges = []*api.ListDynamicRangeResponse{
+		{Prefix: "10.0.0.0/24", Sessions: 2, MaxSessions: 2, Rejected: 5},
+	}
+
+	stream := newFakeSendDynamicRangeList()
+
+	if err := server.ListDynamicRange(&api.ListDynamicRangeRequest{}, stream); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	response := <-stream.responses
+
+	if response.Prefix != "10.0.0.0/24" || response.Rejected != 5 {
+		t.Errorf("Unexpected range %v", response)
+	}
+
+	stream.sendError = ErrFake
+
+	if err := server.ListDynamicRange(&api.ListDynamicRangeRequest{}, stream); err != ErrFake {
+		t.Errorf("Expected %v, got %v", ErrFake, err)
+	}
+}
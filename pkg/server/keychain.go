@@ -0,0 +1,218 @@
+package server
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+var ErrKeyChainNotFound = errors.New("Unable to find key chain")
+var ErrKeyChainExists = errors.New("Key chain already exists")
+var ErrKeyChainInUse = errors.New("Key chain is still used by a peer")
+var ErrInvalidKeyChainName = errors.New("Invalid key chain name")
+var ErrKeyNotFound = errors.New("Unable to find key in key chain")
+var ErrInvalidKeyLifetime = errors.New("Invalid key lifetime, end needs to be after start")
+var ErrNoActiveKey = errors.New("No key with a valid send lifetime in key chain")
+
+// Key is a single authentication key of a key chain, a zero start or end time means the lifetime is unbounded
+type Key struct {
+	Id          uint8
+	Secret      []byte
+	SendStart   time.Time
+	SendEnd     time.Time
+	AcceptStart time.Time
+	AcceptEnd   time.Time
+}
+
+// KeyChain is a named set of keys used to rotate the authentication keys of live sessions
+type KeyChain struct {
+	sync.RWMutex
+
+	Name string
+	keys []*Key // sorted by id
+}
+
+func NewKeyChain(name string) *KeyChain {
+	return &KeyChain{
+		Name: name,
+		keys: make([]*Key, 0),
+	}
+}
+
+func inLifetime(now, start, end time.Time) bool {
+	if !start.IsZero() && now.Before(start) {
+		return false
+	}
+
+	if !end.IsZero() && !now.Before(end) {
+		return false
+	}
+
+	return true
+}
+
+// CanSend returns true if the key should be used to send packets at the passed time
+func (k *Key) CanSend(now time.Time) bool {
+	return inLifetime(now, k.SendStart, k.SendEnd)
+}
+
+// CanAccept returns true if packets signed with the key are accepted at the passed time
+func (k *Key) CanAccept(now time.Time) bool {
+	return inLifetime(now, k.AcceptStart, k.AcceptEnd)
+}
+
+// SetKey adds a key to the chain or replaces the key with the same id
+func (c *KeyChain) SetKey(key *Key) error {
+	if len(key.Secret) < 1 || len(key.Secret) > bfd.HMAC_SHA256_KEY_LENGTH {
+		return ErrInvalidAuthenticationKey
+	}
+
+	if (!key.SendEnd.IsZero() && !key.SendEnd.After(key.SendStart)) ||
+		(!key.AcceptEnd.IsZero() && !key.AcceptEnd.After(key.AcceptStart)) {
+		return ErrInvalidKeyLifetime
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	for idx, k := range c.keys {
+		if k.Id == key.Id {
+			c.keys[idx] = key
+			return nil
+		}
+	}
+
+	c.keys = append(c.keys, key)
+
+	sort.Slice(c.keys, func(i, j int) bool {
+		return c.keys[i].Id < c.keys[j].Id
+	})
+
+	return nil
+}
+
+func (c *KeyChain) DeleteKey(id uint8) error {
+	c.Lock()
+	defer c.Unlock()
+
+	for idx, k := range c.keys {
+		if k.Id == id {
+			c.keys = append(c.keys[:idx], c.keys[idx+1:]...)
+			return nil
+		}
+	}
+
+	return ErrKeyNotFound
+}
+
+func (c *KeyChain) Keys() []*Key {
+	c.RLock()
+	defer c.RUnlock()
+
+	keys := make([]*Key, len(c.keys))
+	copy(keys, c.keys)
+
+	return keys
+}
+
+// SendKey returns the active key, which is the most recently activated key with a valid send lifetime
+func (c *KeyChain) SendKey(now time.Time) *Key {
+	c.RLock()
+	defer c.RUnlock()
+
+	var active *Key
+
+	for _, k := range c.keys {
+		if !k.CanSend(now) {
+			continue
+		}
+
+		if active == nil || !k.SendStart.Before(active.SendStart) {
+			active = k
+		}
+	}
+
+	return active
+}
+
+// AcceptKey returns the key with the passed id if it's within its accept lifetime
+func (c *KeyChain) AcceptKey(id uint8, now time.Time) *Key {
+	c.RLock()
+	defer c.RUnlock()
+
+	for _, k := range c.keys {
+		if k.Id == id && k.CanAccept(now) {
+			return k
+		}
+	}
+
+	return nil
+}
+
+func unixTime(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(seconds, 0)
+}
+
+func unixSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
+
+// NewKeyFromApi converts an api key, the password follows the same rules as for a peer (0x prefix = hex)
+func NewKeyFromApi(api_key *api.Key) (*Key, error) {
+	if api_key.Id > 255 {
+		return nil, ErrInvalidAuthenticationKeyId
+	}
+
+	secret, err := parseAuthenticationKey(api_key.Password)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{
+		Id:          uint8(api_key.Id),
+		Secret:      secret,
+		SendStart:   unixTime(api_key.SendStart),
+		SendEnd:     unixTime(api_key.SendEnd),
+		AcceptStart: unixTime(api_key.AcceptStart),
+		AcceptEnd:   unixTime(api_key.AcceptEnd),
+	}, nil
+}
+
+// ToApi converts the key to the api representation, the secret is never handed out
+func (k *Key) ToApi() *api.Key {
+	return &api.Key{
+		Id:          uint32(k.Id),
+		SendStart:   unixSeconds(k.SendStart),
+		SendEnd:     unixSeconds(k.SendEnd),
+		AcceptStart: unixSeconds(k.AcceptStart),
+		AcceptEnd:   unixSeconds(k.AcceptEnd),
+	}
+}
+
+func (c *KeyChain) ToApi() *api.KeyChain {
+	keys := c.Keys()
+
+	api_chain := &api.KeyChain{
+		Name: c.Name,
+		Keys: make([]*api.Key, len(keys)),
+	}
+
+	for idx, k := range keys {
+		api_chain.Keys[idx] = k.ToApi()
+	}
+
+	return api_chain
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+)
+
+func TestKeyChainSendKeyRollover(t *testing.T) {
+	now := time.Now()
+	chain := NewKeyChain("core")
+
+	chain.SetKey(&Key{Id: 1, Secret: []byte("old"), SendEnd: now.Add(time.Hour)})
+	chain.SetKey(&Key{Id: 2, Secret: []byte("new"), SendStart: now.Add(time.Minute)})
+
+	if key := chain.SendKey(now); key == nil || key.Id != 1 {
+		t.Errorf("Expected key 1 to be active, got %v", key)
+	}
+
+	// both keys are valid, the most recently activated one wins
+	if key := chain.SendKey(now.Add(2 * time.Minute)); key == nil || key.Id != 2 {
+		t.Errorf("Expected key 2 to be active, got %v", key)
+	}
+
+	if key := chain.SendKey(now.Add(2 * time.Hour)); key == nil || key.Id != 2 {
+		t.Errorf("Expected key 2 to be active, got %v", key)
+	}
+}
+
+func TestKeyChainSendKeyNoneActive(t *testing.T) {
+	now := time.Now()
+	chain := NewKeyChain("core")
+
+	chain.SetKey(&Key{Id: 1, Secret: []byte("future"), SendStart: now.Add(time.Hour)})
+
+	if key := chain.SendKey(now); key != nil {
+		t.Errorf("Expected no active key, got %v", key)
+	}
+}
+
+func TestKeyChainAcceptKey(t *testing.T) {
+	now := time.Now()
+	chain := NewKeyChain("core")
+
+	chain.SetKey(&Key{Id: 1, Secret: []byte("old"), AcceptEnd: now.Add(time.Hour)})
+	chain.SetKey(&Key{Id: 2, Secret: []byte("new")})
+
+	if key := chain.AcceptKey(1, now); key == nil || string(key.Secret) != "old" {
+		t.Errorf("Expected key 1 to be accepted, got %v", key)
+	}
+
+	if key := chain.AcceptKey(1, now.Add(time.Hour)); key != nil {
+		t.Errorf("Expected key 1 to be expired, got %v", key)
+	}
+
+	if key := chain.AcceptKey(3, now); key != nil {
+		t.Errorf("Expected unknown key to be rejected, got %v", key)
+	}
+}
+
+func TestKeyChainSetKey(t *testing.T) {
+	now := time.Now()
+	chain := NewKeyChain("core")
+
+	if err := chain.SetKey(&Key{Id: 1}); err != ErrInvalidAuthenticationKey {
+		t.Errorf("Expected %v, got %v", ErrInvalidAuthenticationKey, err)
+	}
+
+	if err := chain.SetKey(&Key{Id: 1, Secret: []byte("secret"), SendStart: now, SendEnd: now}); err != ErrInvalidKeyLifetime {
+		t.Errorf("Expected %v, got %v", ErrInvalidKeyLifetime, err)
+	}
+
+	chain.SetKey(&Key{Id: 2, Secret: []byte("two")})
+	chain.SetKey(&Key{Id: 1, Secret: []byte("one")})
+	chain.SetKey(&Key{Id: 2, Secret: []byte("replaced")})
+
+	keys := chain.Keys()
+
+	if len(keys) != 2 || keys[0].Id != 1 || string(keys[1].Secret) != "replaced" {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+
+	if err := chain.DeleteKey(1); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if err := chain.DeleteKey(1); err != ErrKeyNotFound {
+		t.Errorf("Expected %v, got %v", ErrKeyNotFound, err)
+	}
+}
+
+func TestKeyChainToApi(t *testing.T) {
+	key, err := NewKeyFromApi(&api.Key{
+		Id:        7,
+		Password:  "0x0102",
+		SendStart: 1500000000,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if key.Id != 7 || len(key.Secret) != 2 || key.SendStart.Unix() != 1500000000 || !key.SendEnd.IsZero() {
+		t.Errorf("Unexpected key %v", key)
+	}
+
+	chain := NewKeyChain("core")
+	chain.SetKey(key)
+
+	api_chain := chain.ToApi()
+
+	if api_chain.Name != "core" || len(api_chain.Keys) != 1 {
+		t.Fatalf("Unexpected key chain %v", api_chain)
+	}
+
+	// the secret is never handed out
+	if api_chain.Keys[0].Password != "" || api_chain.Keys[0].SendStart != 1500000000 || api_chain.Keys[0].SendEnd != 0 {
+		t.Errorf("Unexpected key %v", api_chain.Keys[0])
+	}
+
+	if _, err := NewKeyFromApi(&api.Key{Id: 256, Password: "secret"}); err != ErrInvalidAuthenticationKeyId {
+		t.Errorf("Expected %v, got %v", ErrInvalidAuthenticationKeyId, err)
+	}
+}
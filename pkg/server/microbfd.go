@@ -0,0 +1,347 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"syscall"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+/*
+
+https://tools.ietf.org/html/rfc7130
+
+Micro BFD runs an independent session on every member link of a LAG, so a
+failing member can be taken out of the bundle. The sessions share the
+address of the remote system and are told apart by their interface.
+
+*/
+
+const (
+	BFD_MICRO_PORT = 6784
+)
+
+var ErrLagExists = errors.New("LAG already exists")
+var ErrLagNotFound = errors.New("Unable to find LAG")
+var ErrNoLagMembers = errors.New("A LAG needs at least one member interface")
+var ErrDuplicateLagMember = errors.New("Member interface is used more than once")
+var ErrInvalidMinLinks = errors.New("Invalid minimum links, should not exceed the number of members")
+var ErrInterfaceNotFound = errors.New("Unable to find interface")
+var ErrBindToDeviceNotSupported = errors.New("Binding sockets to an interface isn't supported on this platform")
+var ErrLagMemberSession = errors.New("Micro BFD sessions are removed along with their LAG")
+
+// Lag rolls the micro BFD sessions of its member links up into one state
+type Lag struct {
+	sync.RWMutex
+
+	Name     string
+	MinLinks int // members that need to be up for the LAG to be up
+
+	config  *api.Lag
+	members map[string]*Peer // micro BFD session by member interface
+}
+
+// Members returns the sessions of the LAG keyed by their member interface
+func (l *Lag) Members() map[string]*Peer {
+	l.RLock()
+	defer l.RUnlock()
+
+	members := make(map[string]*Peer, len(l.members))
+
+	for member, peer := range l.members {
+		members[member] = peer
+	}
+
+	return members
+}
+
+// UpLinks returns the number of members with an Up session
+func (l *Lag) UpLinks() int {
+	up := 0
+
+	for _, peer := range l.Members() {
+		if peer.GetLocal().GetSessionState() == bfd.Up {
+			up++
+		}
+	}
+
+	return up
+}
+
+// GetState returns Up as long as at least MinLinks members are Up
+func (l *Lag) GetState() bfd.SessionState {
+	if l.UpLinks() >= l.MinLinks {
+		return bfd.Up
+	}
+
+	return bfd.Down
+}
+
+// AddLag starts a micro BFD session on each member interface of the LAG
+func (s *BfdServer) AddLag(api_lag *api.Lag) (*Lag, error) {
+	if len(api_lag.Members) == 0 {
+		return nil, ErrNoLagMembers
+	}
+
+	if int(api_lag.MinLinks) > len(api_lag.Members) {
+		return nil, ErrInvalidMinLinks
+	}
+
+	ip, _, _, err := parseHostPort(api_lag.Address, BFD_MICRO_PORT)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+
+	seen := make(map[string]bool, len(api_lag.Members))
+
+	for _, member := range api_lag.Members {
+		if seen[member] {
+			return nil, ErrDuplicateLagMember
+		}
+
+		seen[member] = true
+	}
+
+	lag := &Lag{
+		Name:     api_lag.Name,
+		MinLinks: int(api_lag.MinLinks),
+		config:   api_lag,
+		members:  make(map[string]*Peer, len(api_lag.Members)),
+	}
+
+	if lag.MinLinks == 0 {
+		lag.MinLinks = 1
+	}
+
+	s.Lock()
+	if _, ok := s.lags[lag.Name]; ok {
+		s.Unlock()
+		return nil, ErrLagExists
+	}
+
+	s.lags[lag.Name] = lag
+	s.Unlock()
+
+	for _, member := range api_lag.Members {
+		err := s.listenMicro(member, ip)
+
+		if err == nil {
+			err = s.addMicroPeer(lag, member)
+		}
+
+		if err != nil {
+			s.DeleteLag(lag.Name)
+			return nil, err
+		}
+	}
+
+	return lag, nil
+}
+
+// addMicroPeer creates the session of a member link
+func (s *BfdServer) addMicroPeer(lag *Lag, member string) error {
+	// the members run in Asynchronous mode, without Demand mode or the echo function
+	peer, err := s.addPeer(&api.Peer{
+		Name:                  lag.Name + "/" + member,
+		Address:               lag.config.Address,
+		DesiredMinTxInterval:  lag.config.DesiredMinTxInterval,
+		RequiredMinRxInterval: lag.config.RequiredMinRxInterval,
+		DetectMultiplier:      lag.config.DetectMultiplier,
+		Authentication:        lag.config.Authentication,
+		LocalAddress:          lag.config.LocalAddress,
+	}, member)
+
+	if err != nil {
+		return err
+	}
+
+	lag.Lock()
+	lag.members[member] = peer
+	lag.Unlock()
+
+	return nil
+}
+
+// listenMicro starts the listener of a member interface, unless it's already running
+func (s *BfdServer) listenMicro(member string, ip net.IP) error {
+	iface, err := net.InterfaceByName(member)
+
+	if err != nil {
+		return ErrInterfaceNotFound
+	}
+
+	wildcard := net.IPv4zero
+
+	if ip.To4() == nil {
+		wildcard = net.IPv6unspecified
+	}
+
+	s.RLock()
+	addr := &net.UDPAddr{
+		IP:   wildcard,
+		Port: s.microPort,
+	}
+
+	key := addr.String() + "%" + member
+	_, ok := s.conns[key]
+	s.RUnlock()
+
+	if ok {
+		return nil
+	}
+
+	/*
+		Every member gets its own socket, received packets are then
+		known to belong to that member. The device needs to be bound
+		before the port, otherwise the listeners of the members conflict.
+	*/
+	lc := net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			return bindToDevice(c, member)
+		},
+	}
+
+	pc, err := lc.ListenPacket(context.Background(), "udp", addr.String())
+
+	if err != nil {
+		return err
+	}
+
+	conn := pc.(*net.UDPConn)
+
+	err = setControlMessages(conn, wildcard)
+
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	l := &listener{
+		conn:    conn,
+		sockets: []UDPConn{conn},
+		local:   addr,
+		ifIndex: iface.Index,
+		micro:   true,
+	}
+
+	s.Lock()
+
+	// the listeners of the other members use the port picked for the first one
+	if addr.Port == 0 {
+		addr.Port = conn.LocalAddr().(*net.UDPAddr).Port
+		key = addr.String() + "%" + member
+		s.microPort = addr.Port
+	}
+
+	s.conns[key] = l
+	s.Unlock()
+
+	s.readers.Add(1)
+	go s.handleIncomingPackets(l)
+
+	return nil
+}
+
+func (s *BfdServer) GetLag(name string) (*Lag, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	lag, ok := s.lags[name]
+
+	if !ok {
+		return nil, ErrLagNotFound
+	}
+
+	return lag, nil
+}
+
+// DeleteLag stops the sessions of all members, the listeners are kept for a LAG using the interfaces later on
+func (s *BfdServer) DeleteLag(name string) error {
+	s.Lock()
+	lag, ok := s.lags[name]
+
+	if !ok {
+		s.Unlock()
+		return ErrLagNotFound
+	}
+
+	delete(s.lags, name)
+	s.Unlock()
+
+	for _, peer := range lag.Members() {
+		s.removePeer(peer)
+	}
+
+	return nil
+}
+
+func (s *BfdServer) ListLag(ctx context.Context, cb func(*api.Lag, api.SessionState, []*api.LagMember) error) error {
+	s.RLock()
+	lags := make([]*Lag, 0, len(s.lags))
+
+	for _, lag := range s.lags {
+		lags = append(lags, lag)
+	}
+	s.RUnlock()
+
+	for _, lag := range lags {
+		peers := lag.Members()
+		members := make([]*api.LagMember, 0, len(peers))
+
+		for _, member := range lag.config.Members {
+			peer, ok := peers[member]
+
+			if !ok {
+				continue
+			}
+
+			members = append(members, &api.LagMember{
+				Interface: member,
+				Uuid:      peer.GetUuid(),
+				State:     api.SessionState(peer.GetLocal().GetSessionState()),
+			})
+		}
+
+		api_lag := &api.Lag{
+			Name:                  lag.config.Name,
+			Address:               lag.config.Address,
+			Members:               lag.config.Members,
+			MinLinks:              uint32(lag.MinLinks),
+			DesiredMinTxInterval:  lag.config.DesiredMinTxInterval,
+			RequiredMinRxInterval: lag.config.RequiredMinRxInterval,
+			DetectMultiplier:      lag.config.DetectMultiplier,
+			LocalAddress:          lag.config.LocalAddress,
+		}
+
+		// the password is never handed out
+		if auth := lag.config.Authentication; auth != nil {
+			api_lag.Authentication = &api.Authentication{
+				Type:      auth.Type,
+				KeyId:     auth.KeyId,
+				KeyChain:  auth.KeyChain,
+				Optimized: auth.Optimized,
+			}
+		}
+
+		if err := cb(api_lag, api.SessionState(lag.GetState()), members); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+
+	return nil
+}
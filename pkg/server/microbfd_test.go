@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+func lagMember(state bfd.SessionState) *Peer {
+	p := &Peer{
+		local:  &PeerState{sessionState: state},
+		remote: &PeerState{},
+	}
+	p.publish()
+
+	return p
+}
+
+func TestLagGetState(t *testing.T) {
+	lag := &Lag{
+		MinLinks: 2,
+		members: map[string]*Peer{
+			"eth0": lagMember(bfd.Up),
+			"eth1": lagMember(bfd.Down),
+			"eth2": lagMember(bfd.Init),
+		},
+	}
+
+	if lag.UpLinks() != 1 || lag.GetState() != bfd.Down {
+		t.Errorf("Expected the LAG to be down with 1 of 2 links, got %d %v", lag.UpLinks(), lag.GetState())
+	}
+
+	lag.members["eth2"] = lagMember(bfd.Up)
+
+	if lag.UpLinks() != 2 || lag.GetState() != bfd.Up {
+		t.Errorf("Expected the LAG to be up with 2 of 2 links, got %d %v", lag.UpLinks(), lag.GetState())
+	}
+}
+
+func TestAddLagInvalid(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	tests := []struct {
+		lag *api.Lag
+		err error
+	}{
+		{&api.Lag{Name: "bond0", Address: "127.0.0.1"}, ErrNoLagMembers},
+		{&api.Lag{Name: "bond0", Address: "127.0.0.1", Members: []string{"lo"}, MinLinks: 2}, ErrInvalidMinLinks},
+		{&api.Lag{Name: "bond0", Address: "127.0.0.1", Members: []string{"lo", "lo"}}, ErrDuplicateLagMember},
+		{&api.Lag{Name: "bond0", Address: "127.0.0.300", Members: []string{"lo"}}, ErrInvalidIP},
+		{&api.Lag{Name: "bond0", Address: "127.0.0.1", Members: []string{"bfd-missing0"}, DetectMultiplier: 1}, ErrInterfaceNotFound},
+	}
+
+	for _, test := range tests {
+		if _, err := server.AddLag(test.lag); err != test.err {
+			t.Errorf("Expected %v, got %v", test.err, err)
+		}
+	}
+
+	// failed LAGs don't leave anything behind
+	if _, err := server.GetLag("bond0"); err != ErrLagNotFound {
+		t.Errorf("Expected %v, got %v", ErrLagNotFound, err)
+	}
+}
+
+func TestHandlePacketLagMember(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	peer, err := server.AddPeer(&api.Peer{
+		Address:          "192.0.2.1",
+		DetectMultiplier: 1,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	peer.Lock()
+	peer.Interface = "eth0"
+	peer.IfIndex = 2
+	peer.Unlock()
+
+	pkt := packet{
+		addr: &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 49152},
+		packet: &bfd.ControlPacket{
+			Version:           1,
+			DetectMultiplier:  3,
+			MyDiscriminator:   20,
+			YourDiscriminator: peer.GetLocal().GetDiscriminator(),
+			State:             bfd.Init,
+		},
+		ifIndex: 3,
+	}
+
+	// single hop packets never reach a micro BFD session
+	if err := server.handlePacket(pkt); err != ErrSessionTypeMismatch {
+		t.Errorf("Expected %v, got %v", ErrSessionTypeMismatch, err)
+	}
+
+	// the packet of another member
+	pkt.micro = true
+
+	if err := server.handlePacket(pkt); err != ErrAddressMismatch {
+		t.Errorf("Expected %v, got %v", ErrAddressMismatch, err)
+	}
+
+	// no session is created for unknown members
+	pkt.packet.YourDiscriminator = 0
+	pkt.packet.State = bfd.Down
+
+	if err := server.handlePacket(pkt); err != ErrPeerNotFound {
+		t.Errorf("Expected %v, got %v", ErrPeerNotFound, err)
+	}
+}
+
+func TestHandleIncomingPacketsMember(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	fake := &FakeConn{}
+
+	fake.data, _ = (&bfd.ControlPacket{
+		Version: 1,
+	}).MarshalBinary()
+
+	// the kernel reports the interface of the bond, the listener knows the member
+	fake.oob = append(hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 255), pktInfoCmsg(net.ParseIP("192.0.2.2"), 7)...)
+
+	l := &listener{
+		conn:    fake,
+		local:   &net.UDPAddr{IP: net.IPv4zero, Port: BFD_MICRO_PORT},
+		ifIndex: 3,
+		micro:   true,
+	}
+
+	if err := server.readIncomingPacket(l, make([]byte, 256), make([]byte, 256)); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pkt := queuedPacket(t, server)
+
+	if !pkt.micro || pkt.multiHop || pkt.ifIndex != 3 {
+		t.Errorf("Unexpected packet context %v", pkt)
+	}
+}
+
+func TestAddLagLoopback(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	clock := NewVirtualClock(time.Unix(0, 0))
+
+	if err := server.SetClock(clock); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// the listeners bind a free port, the sessions send to it
+	server.microPort = 0
+
+	server.inbound.start(server.handlePacket)
+
+	// the session on lo receives its own packets, which brings it up like a looped back link,
+	// packets are sent once per second while the session is down
+	lag, err := server.AddLag(&api.Lag{
+		Name:                  "bond0",
+		Address:               "127.0.0.1",
+		Members:               []string{"lo"},
+		DesiredMinTxInterval:  10,
+		RequiredMinRxInterval: 10,
+		DetectMultiplier:      3,
+		Authentication: &api.Authentication{
+			Type:      api.AuthenticationType_METICULOUS_KEYED_HMAC_SHA256,
+			KeyId:     1,
+			Password:  "secret",
+			Optimized: true,
+		},
+	})
+
+	if err == syscall.EPERM || err == ErrBindToDeviceNotSupported {
+		t.Skipf("Unable to bind to lo: %v", err)
+	}
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	peer := lag.Members()["lo"]
+
+	if peer == nil || peer.Interface != "lo" || peer.IfIndex == 0 {
+		t.Fatalf("Expected a session bound to lo, got %v", peer)
+	}
+
+	if !advanceUntilUp(clock, peer) || lag.GetState() != bfd.Up {
+		t.Errorf("Expected the LAG to be up")
+	}
+
+	if server.microPort == 0 || peer.Address.Port != server.microPort {
+		t.Errorf("Expected the session to use the picked port %d, got %v", server.microPort, peer.Address)
+	}
+
+	server.ListLag(context.Background(), func(l *api.Lag, state api.SessionState, members []*api.LagMember) error {
+		if l.Authentication == nil || !l.Authentication.Optimized || l.Authentication.Password != "" {
+			t.Errorf("Expected the authentication without the password, got %v", l.Authentication)
+		}
+
+		return nil
+	})
+
+	if _, err := server.AddLag(&api.Lag{Name: "bond0", Address: "127.0.0.1", Members: []string{"lo"}}); err != ErrLagExists {
+		t.Errorf("Expected %v, got %v", ErrLagExists, err)
+	}
+
+	// member sessions can't be removed on their own
+	if err := server.DeletePeer(peer.GetUuid()); err != ErrLagMemberSession {
+		t.Errorf("Expected %v, got %v", ErrLagMemberSession, err)
+	}
+
+	if err := server.DeleteLag("bond0"); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if _, err := server.GetPeerByUuid(peer.GetUuid()); err != ErrPeerNotFound {
+		t.Errorf("Expected the member session to be removed, got %v", err)
+	}
+
+	peer.RLock()
+	conn := peer.conn
+	peer.RUnlock()
+
+	if _, err := conn.Write([]byte{0}); err == nil {
+		t.Errorf("Expected the socket of the member session to be closed")
+	}
+}
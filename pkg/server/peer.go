@@ -2,9 +2,10 @@ package server
 
 import (
 	"errors"
-	"math/rand"
+	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -23,8 +24,13 @@ var ErrInvalidAddress = errors.New("Invalid address passed")
 var ErrInvalidPort = errors.New("Invalid port passed, should be between 1 and 65535")
 var ErrSessionAdminDown = errors.New("Peer is state is admin down")
 var ErrNotImplemented = errors.New("Function not implemented")
+var ErrAuthenticationFailed = errors.New("Discarded Packet: Authentication failed")
+var ErrInvalidAuthSequence = errors.New("Discarded Packet: Authentication sequence number out of range")
+var ErrSessionNotUp = errors.New("Peer session is not up")
 
 type Peer struct {
+	violations violationCounters // invalid packets received for the session, first for the alignment of the counters
+
 	sync.RWMutex
 
 	uuid []byte
@@ -36,33 +42,70 @@ type Peer struct {
 	// Set up interval
 	Interval uint32
 
-	local  *PeerState
-	remote *PeerState
+	local       *PeerState   // changed under the lock, readers use the snapshot
+	remote      *PeerState   // changed under the lock, readers use the snapshot
+	snapshot    atomic.Value // *Snapshot, published on every change of the session
+	transitions uint64       // changes of the local session state
+	received    uint64       // received packets applied to the session
 
 	AuthType             bfd.AuthenticationType // 0 = no authentication
+	AuthKeyId            int8
+	AuthKey              []byte    // password or key, depending on AuthType
+	KeyChain             *KeyChain // if set, the keys of the chain are used instead of AuthKeyId / AuthKey
+	OptimizedAuth        bool      // packets that don't change the session only carry a secure sequence number
 	ReceivedAuthSequence uint32
 	XmitAuthSeq          uint32 // needs to be initialized with random 32 bit value
 	AuthSequenceKnown    uint32 // reset to 0 if no packets are received in 2 * DetectionTime (Interval * Multiplier)
 	PollActive           bool
+	pollUpdates          []PeerStateUpdate // timer changes held back until the poll sequence terminates
+	lastPoll             time.Time
+	DemandPollInterval   time.Duration // verifies the session with a poll sequence while Demand mode is active, 0 = disabled
 	IsMultiHop           bool
+	LocalAddress         net.IP // source address of the session, received packets need to be sent to it
+	IfIndex              int    // interface of link local and micro BFD sessions, 0 = any
+	Interface            string // member link of a micro BFD session, empty for all other sessions
+	IsVxlan              bool   // the packets are sent inside VXLAN to the VTEP at Address
+	Vni                  uint32 // VNI of VXLAN sessions
+	PadToSize            int    // control packets are padded to this size with DF set (RFC9764), 0 = disabled
+	Passive              bool   // no packets are sent until the remote discriminator is known
+	MinTTL               uint8  // minimum TTL of received multi hop packets
+
+	clock Clock
+	rand  Rand
 
 	// control channels
-	conn       Connection  // sending udp connection
-	ticker     *time.Timer // timer for control packets
-	expiry     *time.Timer // timer for expiry of the session
-	lastPacket time.Time
-	control    chan bool
-	updater    chan *mgmtOp
+	conn          Connection            // sending udp connection
+	sendLock      sync.Mutex            // guards sendBuf
+	sendBuf       []byte                // reused for every sent packet
+	ticker        *timer                // timer for control packets
+	expiry        *timer                // timer for expiry of the session
+	detectionTime time.Duration         // last scheduled expiry interval
+	lastPacket    time.Time             // last packet that passed authentication
+	dynamic       *DynamicRange         // set if the session was created for an unknown peer
+	unsolicited   *UnsolicitedInterface // set if the session was created by unsolicited BFD
+
+	// echo function
+	EchoInterval      uint32         // desired echo transmit interval in µs, 0 = disabled
+	EchoReflector     bool           // echo packets go to the reflector of the remote instead of its forwarding plane
+	echoConn          io.WriteCloser // sends the echo packets
+	echoLocal         net.IP         // address the echo packets are looped back to, nil for the reflector
+	echoActive        bool
+	echoSequence      uint32
+	echoRxInterval    uint32 // requiredMinRxInterval restored once echo stops
+	echoTicker        *timer // timer for echo packets
+	echoExpiry        *timer // timer for expiry of the echo function
+	echoDetectionTime time.Duration
 
 	watchers []*watcher
 }
 
-type mgmtOp struct {
-	errCh chan error
-	f     func() error
+func NewPeer(address net.IP, port int) (*Peer, error) {
+	return newPeer(address, port, sharedScheduler(), globalRand{})
 }
 
-func NewPeer(address net.IP, port int) (*Peer, error) {
+// newPeer creates a peer whose timers run on s and whose sequence numbers and jitter are taken from r,
+// the timers are only created once
+func newPeer(address net.IP, port int, s *scheduler, r Rand) (*Peer, error) {
 
 	if address == nil {
 		return nil, ErrInvalidAddress
@@ -82,13 +125,19 @@ func NewPeer(address net.IP, port int) (*Peer, error) {
 			Port: port,
 		},
 
-		// Initialize with a long time - will be updated by the config
-		ticker: time.NewTimer(time.Duration(5) * time.Hour),
-		expiry: time.NewTimer(time.Duration(5) * time.Hour),
+		XmitAuthSeq: r.Uint32(),
 
-		updater: make(chan *mgmtOp, 8),
+		clock: s.clock,
+		rand:  r,
 	}
 
+	shard := s.shard()
+
+	p.ticker = s.newTimer(shard, p.transmit)
+	p.expiry = s.newTimer(shard, p.expire)
+	p.echoTicker = s.newTimer(shard, func() { p.sendEcho() })
+	p.echoExpiry = s.newTimer(shard, p.echoFailed)
+
 	// Setup an initial state
 	p.local = &PeerState{
 		sessionState:          bfd.Down,
@@ -103,30 +152,159 @@ func NewPeer(address net.IP, port int) (*Peer, error) {
 		requiredMinRxInterval: 1,
 	}
 
+	p.publish()
+
 	return p, nil
 }
 
 func (p *Peer) NewPacket(poll bfd.Bool, final bfd.Bool) *bfd.ControlPacket {
-	local := p.GetLocal()
-	remote := p.GetRemote()
+	// the timer values being polled for are advertised before they're used locally
+	snapshot := p.Snapshot()
+	local := snapshot.polled
+	remote := snapshot.remote
+
+	// Demand mode is only requested once the session is up
+	demand := bfd.No
+
+	if local.demandMode && local.sessionState == bfd.Up {
+		demand = bfd.Yes
+	}
+
+	// nothing in the packet changes the session once both sides are up
+	steady := poll == bfd.No && final == bfd.No && local.sessionState == bfd.Up && remote.sessionState == bfd.Up
 
 	return &bfd.ControlPacket{
 		Version:                 1,
 		State:                   local.sessionState,
 		Poll:                    poll,
 		Final:                   final,
-		Demand:                  bfd.No,
+		Demand:                  demand,
 		DetectMultiplier:        local.detectMultiplier,
 		MyDiscriminator:         local.discriminator,
 		YourDiscriminator:       remote.discriminator,
 		DesiredMinTxInterval:    local.desiredMinTxInterval,
 		RequiredMinRxInterval:   local.requiredMinRxInterval,
-		RequiredMinEchoInterval: 0,
+		RequiredMinEchoInterval: local.requiredMinEchoRxInterval,
+		AuthenticationHeader:    p.newAuthenticationHeader(steady),
+	}
+}
+
+// newAuthenticationHeader creates the authentication section for the next packet, or nil if none is in use.
+// steady packets don't change the session and are only sent with a secure sequence number in optimized mode.
+func (p *Peer) newAuthenticationHeader(steady bool) bfd.AuthenticationHeader {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.AuthType == bfd.Reserved {
+		return nil
+	}
+
+	keyId, key, ok := p.sendKey()
+
+	if !ok {
+		return nil
+	}
+
+	authType := p.AuthType
+
+	if p.OptimizedAuth && steady {
+		authType = bfd.MeticulousKeyedSecureSequence
+	}
+
+	provider, err := bfd.GetAuthenticationProvider(authType)
+
+	if err != nil {
+		return nil
+	}
+
+	return provider.NewHeader(keyId, key, p.nextXmitAuthSeq())
+}
+
+// sendKey returns the key used to sign outgoing packets, the lock needs to be held by the caller
+func (p *Peer) sendKey() (int8, []byte, bool) {
+	if p.KeyChain == nil {
+		return p.AuthKeyId, p.AuthKey, true
+	}
+
+	key := p.KeyChain.SendKey(p.clock.Now())
+
+	if key == nil {
+		return 0, nil, false
+	}
+
+	return int8(key.Id), key.Secret, true
+}
+
+// acceptKey returns the key to verify a received packet with, the lock needs to be held by the caller
+func (p *Peer) acceptKey(keyId int8) ([]byte, bool) {
+	if p.KeyChain == nil {
+		return p.AuthKey, keyId == p.AuthKeyId
+	}
+
+	key := p.KeyChain.AcceptKey(uint8(keyId), p.clock.Now())
+
+	if key == nil {
+		return nil, false
+	}
+
+	return key.Secret, true
+}
+
+// nextXmitAuthSeq returns the sequence number for the next packet, the lock needs to be held by the caller
+func (p *Peer) nextXmitAuthSeq() uint32 {
+	/*
+		RFC5880 6.7.3 / 6.7.4
+		For Keyed MD5 / SHA1 Authentication, bfd.XmitAuthSeq MAY be
+		incremented in a circular fashion (when treated as an unsigned 32-bit
+		value).  For Meticulous Keyed MD5 / SHA1 authentication,
+		bfd.XmitAuthSeq MUST be incremented in a circular fashion on every
+		packet.
+
+		With optimized authentication the packets carrying a secure sequence
+		number are checked like meticulous ones, so the sequence always advances.
+	*/
+	if p.OptimizedAuth || isMeticulous(p.AuthType) {
+		p.XmitAuthSeq++
 	}
+
+	return p.XmitAuthSeq
+}
+
+func isMeticulous(authType bfd.AuthenticationType) bool {
+	provider, err := bfd.GetAuthenticationProvider(authType)
+
+	return err == nil && provider.IsMeticulous()
 }
 
 func (p *Peer) Send(packet *bfd.ControlPacket) error {
-	b, err := packet.MarshalBinary()
+	// without an active key the packet would be discarded by the remote anyway
+	if packet.AuthenticationHeader == nil && p.GetAuthenticationType() != bfd.Reserved {
+		glog.Errorf("Not sending packet to %s: %s", p.Address, ErrNoActiveKey)
+		return ErrNoActiveKey
+	}
+
+	p.sendLock.Lock()
+	defer p.sendLock.Unlock()
+
+	// large enough for any packet and its padding, so sending doesn't allocate
+	if p.sendBuf == nil {
+		size := bfd.MAXIMUM_LENGTH
+
+		if p.PadToSize > size {
+			size = p.PadToSize
+		}
+
+		p.sendBuf = make([]byte, size)
+	}
+
+	n, err := packet.SerializeTo(p.sendBuf)
+
+	if err != nil {
+		return err
+	}
+
+	// detects paths that can't carry packets of the full size
+	b := bfd.Pad(p.sendBuf[:n], p.PadToSize)
 
 	_, err = p.conn.Write(b)
 
@@ -137,18 +315,41 @@ func (p *Peer) Send(packet *bfd.ControlPacket) error {
 	return err
 }
 
-func (p *Peer) GetLocal() *PeerState {
-	p.RLock()
-	defer p.RUnlock()
+// matchesSessionType checks that the packet was received on the port of the session type
+func (p *Peer) matchesSessionType(pkt *packet) bool {
+	return p.IsMultiHop == pkt.multiHop && (p.Interface != "") == pkt.micro && p.IsVxlan == pkt.vxlan
+}
+
+// matchesPacket checks if the source, destination and interface of a received packet match the session
+func (p *Peer) matchesPacket(pkt *packet) bool {
+	if !p.Address.IP.Equal(pkt.addr.IP) {
+		return false
+	}
+
+	// the same link local address can be used on different interfaces
+	if p.IfIndex != 0 && pkt.ifIndex != 0 && p.IfIndex != pkt.ifIndex {
+		return false
+	}
 
-	return p.local
+	// VXLAN sessions to the same VTEP are told apart by their VNI
+	if p.IsVxlan && p.Vni != pkt.vni {
+		return false
+	}
+
+	// multi hop sessions are identified by the (source, destination) pair
+	if p.LocalAddress != nil && pkt.dst != nil && !p.LocalAddress.Equal(pkt.dst) {
+		return false
+	}
+
+	return true
 }
 
-func (p *Peer) GetRemote() *PeerState {
-	p.RLock()
-	defer p.RUnlock()
+func (p *Peer) GetLocal() *PeerState {
+	return p.Snapshot().local
+}
 
-	return p.remote
+func (p *Peer) GetRemote() *PeerState {
+	return p.Snapshot().remote
 }
 
 func (p *Peer) GetUuid() []byte {
@@ -163,6 +364,10 @@ func (p *Peer) GetAuthenticationType() bfd.AuthenticationType {
 }
 
 func (p *Peer) SetDesiredMinTxInterval(desiredMinTx uint32) {
+	if desiredMinTx == p.getPollState().desiredMinTxInterval {
+		return
+	}
+
 	state := p.GetLocal()
 
 	/*
@@ -173,14 +378,18 @@ func (p *Peer) SetDesiredMinTxInterval(desiredMinTx uint32) {
 		interval increases.
 	*/
 	if desiredMinTx > state.desiredMinTxInterval && state.sessionState == bfd.Up {
-		// delayed apply
-		panic("Not implemented")
+		p.startPoll(setDesiredMinTxInterval(desiredMinTx), true)
 	} else {
 		p.ApplyLocalState([]PeerStateUpdate{setDesiredMinTxInterval(desiredMinTx)})
+		p.startPoll(setDesiredMinTxInterval(desiredMinTx), false)
 	}
 }
 
 func (p *Peer) SetRequiredMinRxInterval(requiredMinRx uint32) {
+	if requiredMinRx == p.getPollState().requiredMinRxInterval {
+		return
+	}
+
 	state := p.GetLocal()
 
 	/*
@@ -193,26 +402,135 @@ func (p *Peer) SetRequiredMinRxInterval(requiredMinRx uint32) {
 		reduced.
 	*/
 	if requiredMinRx < state.requiredMinRxInterval && state.sessionState == bfd.Up {
-		// delayed apply
-		panic("Not implemented")
+		p.startPoll(setRequiredMinRxInterval(requiredMinRx), true)
 	} else {
 		p.ApplyLocalState([]PeerStateUpdate{setRequiredMinRxInterval(requiredMinRx)})
+		p.startPoll(setRequiredMinRxInterval(requiredMinRx), false)
 	}
 }
 
-func (p *Peer) SetDetectMultiplier(detectMultiplier uint8) {
-	p.ApplyLocalState([]PeerStateUpdate{setDetectMultiplier(detectMultiplier)})
+// startPoll initiates a poll sequence for a timer change of an Up session, delayed updates are applied once it terminates
+func (p *Peer) startPoll(update PeerStateUpdate, delayed bool) {
+	/*
+		RFC5880 6.8.3
+		If either bfd.DesiredMinTxInterval or bfd.RequiredMinRxInterval is
+		changed, a Poll Sequence MUST be initiated (see section 6.5).
+	*/
+	p.Lock()
+
+	if p.local.sessionState != bfd.Up {
+		p.Unlock()
+		return
+	}
+
+	/*
+		While a poll sequence is in progress, changes that were applied
+		right away are still queued so they aren't overwritten by an
+		older delayed value once the sequence terminates.
+	*/
+	if update != nil && (delayed || p.PollActive) {
+		p.pollUpdates = append(p.pollUpdates, update)
+	}
+
+	p.PollActive = true
+	p.lastPoll = p.clock.Now()
+	p.publish()
+	p.Unlock()
+
+	/*
+		RFC5880 6.8.4
+		When Demand mode is active, if a Poll Sequence is being transmitted
+		by the local system and the Detection Time passes without receipt
+		of a BFD Control packet with the Final (F) bit set, the session has
+		gone down.
+
+		If Demand mode is active, the Detection Time calculated in the local
+		system is equal to bfd.DetectMult, multiplied by the agreed transmit
+		interval of the local system.
+	*/
+	if p.isDemandActive() {
+		snapshot := p.Snapshot()
+
+		p.scheduleExpiry(uint32(snapshot.local.detectMultiplier) * snapshot.GetTxInterval())
+	}
+
+	// the periodic packets keep the poll bit set until it's answered
+	p.Send(p.NewPacket(bfd.Yes, bfd.No))
+}
+
+// Poll verifies the connectivity of an Up session with a poll sequence, needed while Demand mode is active
+func (p *Peer) Poll() error {
+	if p.GetLocal().sessionState != bfd.Up {
+		return ErrSessionNotUp
+	}
+
+	p.startPoll(nil, false)
+
+	return nil
+}
+
+// isDemandActive returns true if the remote stopped sending periodic packets because of our Demand mode
+func (p *Peer) isDemandActive() bool {
+	snapshot := p.Snapshot()
+
+	return snapshot.local.demandMode && snapshot.local.sessionState == bfd.Up && snapshot.remote.sessionState == bfd.Up
+}
+
+// isRemoteDemandActive returns true if the remote asked us to stop sending periodic packets
+func (p *Peer) isRemoteDemandActive() bool {
+	snapshot := p.Snapshot()
+
+	return snapshot.remote.demandMode && snapshot.local.sessionState == bfd.Up && snapshot.remote.sessionState == bfd.Up
+}
+
+// isPollDue returns true if the scheduled poll sequence verifying a session in Demand mode needs to be sent
+func (p *Peer) isPollDue() bool {
+	if !p.isDemandActive() {
+		return false
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.DemandPollInterval > 0 && !p.PollActive && p.clock.Now().Sub(p.lastPoll) >= p.DemandPollInterval
+}
+
+// terminatePoll ends an active poll sequence and applies the held back timer changes
+func (p *Peer) terminatePoll() {
+	p.Lock()
+
+	if !p.PollActive {
+		p.Unlock()
+		return
+	}
+
+	updates := p.pollUpdates
+	p.pollUpdates = nil
+	p.PollActive = false
+	p.publish()
+	p.Unlock()
+
+	if len(updates) > 0 {
+		p.ApplyLocalState(updates)
+	}
 }
 
-func (p *Peer) mgmt(f func() error) error {
-	ch := make(chan error)
+// getPollState returns the local state including the changes of an active poll sequence
+func (p *Peer) getPollState() *PeerState {
+	return p.Snapshot().polled
+}
 
-	p.updater <- &mgmtOp{
-		f:     f,
-		errCh: ch,
+// pollBit returns whether packets need to be sent with the poll bit set
+func (p *Peer) pollBit() bfd.Bool {
+	if p.Snapshot().pollActive {
+		return bfd.Yes
 	}
 
-	return <-ch
+	return bfd.No
+}
+
+func (p *Peer) SetDetectMultiplier(detectMultiplier uint8) {
+	p.ApplyLocalState([]PeerStateUpdate{setDetectMultiplier(detectMultiplier)})
 }
 
 func (p *Peer) NotifyWatchers(state *api.PeerStateResponse) {
@@ -235,74 +553,146 @@ func (p *Peer) Watch() *watcher {
 }
 
 func (p *Peer) ApplyLocalState(updates []PeerStateUpdate) {
+	p.applyState(updates, nil, false)
+}
+
+func (p *Peer) ApplyRemoteState(updates []PeerStateUpdate) {
+	p.applyState(nil, updates, false)
+}
+
+// applyState changes the local and remote state at once and publishes them in a single snapshot,
+// received counts a packet that was applied to the session
+func (p *Peer) applyState(local []PeerStateUpdate, remote []PeerStateUpdate, received bool) {
+	p.transition(func(*PeerState, *PeerState) ([]PeerStateUpdate, []PeerStateUpdate, error) {
+		return local, remote, nil
+	}, received)
+}
+
+// transition computes the updates from the current state under the lock and applies them, so they
+// can't overwrite a concurrent change like Disable the way updates based on an older snapshot could.
+// Nothing is changed if next returns an error.
+func (p *Peer) transition(next func(local *PeerState, remote *PeerState) ([]PeerStateUpdate, []PeerStateUpdate, error), received bool) (*Snapshot, error) {
 	sessionStateUpdated := false
+	polled := false
+
+	p.Lock()
+	local, remote, err := next(p.local, p.remote)
 
-	p.mgmt(func() error {
-		p.Lock()
-		defer p.Unlock()
-		old_state := p.local
-		p.local = p.local.Clone(updates)
+	if err != nil {
+		p.Unlock()
+		return nil, err
+	}
 
-		if old_state.sessionState != p.local.sessionState {
-			sessionStateUpdated = true
+	old_state := p.local
 
-			if p.local.sessionState == bfd.Up {
-				// Update the desiredMinTxInterval
-				p.local = p.local.Clone([]PeerStateUpdate{setDesiredMinTxInterval(p.Interval)})
-			}
+	if len(local) > 0 {
+		p.local = p.local.Clone(local)
+	}
+
+	if len(remote) > 0 {
+		p.remote = p.remote.Clone(remote)
+	}
+
+	if received {
+		p.received++
+	}
+
+	if old_state.sessionState != p.local.sessionState {
+		sessionStateUpdated = true
+		p.transitions++
+
+		// a poll sequence is only needed while Up, changes held back are applied right away
+		if p.PollActive && p.local.sessionState != bfd.Up {
+			p.local = p.local.Clone(p.pollUpdates)
+			p.pollUpdates = nil
+			p.PollActive = false
 		}
 
-		return nil
-	})
+		if p.local.sessionState == bfd.Up && p.local.desiredMinTxInterval != p.Interval {
+			// Update the desiredMinTxInterval, announced with a poll sequence (RFC5880 6.8.3)
+			p.local = p.local.Clone([]PeerStateUpdate{setDesiredMinTxInterval(p.Interval)})
+			p.PollActive = true
+			p.lastPoll = p.clock.Now()
+			polled = true
+		}
+	}
 
-	if sessionStateUpdated {
-		p.NotifyWatchers(&api.PeerStateResponse{
-			Local:  p.GetLocal().ToApi(),
-			Remote: p.GetRemote().ToApi(),
-		})
+	snapshot := p.publish()
+	p.Unlock()
+
+	// the next packet is still due after the slow interval of the Down state
+	if polled {
+		p.scheduleSend(0)
 	}
-}
 
-func (p *Peer) ApplyRemoteState(updates []PeerStateUpdate) {
-	p.mgmt(func() error {
-		p.Lock()
-		defer p.Unlock()
-		p.remote = p.remote.Clone(updates)
+	// the watchers see the state of the snapshot, not whatever changed after it
+	if sessionStateUpdated {
+		p.NotifyWatchers(snapshot.ToApi())
+	}
 
-		return nil
-	})
+	return snapshot, nil
 }
 
 func (p *Peer) Enable() {
-	local := p.GetLocal()
+	p.transition(func(local *PeerState, remote *PeerState) ([]PeerStateUpdate, []PeerStateUpdate, error) {
+		if local.sessionState != bfd.AdminDown {
+			return nil, nil, nil
+		}
 
-	if local.sessionState == bfd.AdminDown {
-		p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Down)})
-	}
+		return []PeerStateUpdate{setSessionState(bfd.Down)}, nil, nil
+	}, false)
 }
 
 func (p *Peer) Disable() {
-	local := p.GetLocal()
+	p.transition(func(local *PeerState, remote *PeerState) ([]PeerStateUpdate, []PeerStateUpdate, error) {
+		if local.sessionState == bfd.AdminDown {
+			return nil, nil, nil
+		}
 
-	if local.sessionState != bfd.AdminDown {
-		p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.AdminDown)})
-	}
+		return []PeerStateUpdate{setSessionState(bfd.AdminDown)}, nil, nil
+	}, false)
 }
 
+// Start runs the timers scheduled so far, they're held until then
 func (p *Peer) Start() {
-	p.control = make(chan bool, 1)
-
-	go p.handleUpdates()
-	go p.handle()
+	p.ticker.release()
+	p.expiry.release()
+	p.echoTicker.release()
+	p.echoExpiry.release()
+
+	// the reflector sends the packets back to the socket they were sent from
+	if r, ok := p.echoConn.(io.Reader); ok && p.EchoReflector {
+		go p.handleEchoPackets(r)
+	}
 }
 
 func (p *Peer) Shutdown() {
-	close(p.control)
+	glog.Infof("Shutdown Peer")
+
+	p.ticker.close()
+	p.expiry.close()
+	p.echoTicker.close()
+	p.echoExpiry.close()
+
+	if c, ok := p.conn.(io.Closer); ok {
+		c.Close()
+	}
+
+	if p.echoConn != nil {
+		p.echoConn.Close()
+	}
 }
 
 func (p *Peer) scheduleExpiry(interval uint32) {
+	detectionTime := time.Duration(interval) * time.Microsecond
+
 	p.Lock()
-	p.expiry.Reset(time.Duration(interval) * time.Microsecond)
+	p.expiry.Reset(detectionTime)
+
+	if detectionTime != p.detectionTime {
+		p.detectionTime = detectionTime
+		p.publish()
+	}
 	p.Unlock()
 }
 
@@ -313,108 +703,242 @@ func (p *Peer) scheduleSend(interval uint32) {
 	p.Unlock()
 }
 
-// handleUpdates handles all updates that need to be applied to the state of a peer
-func (p *Peer) handleUpdates() {
-	for {
-		select {
-		case mgmtOp := <-p.updater:
-			mgmtOp.errCh <- mgmtOp.f()
-		case <-p.control:
-			return
+// transmit sends the periodic control packet and schedules the next one, it's run by the ticker
+func (peer *Peer) transmit() {
+	snapshot := peer.Snapshot()
+	local := snapshot.local
+	remote := snapshot.remote
+
+	/*
+		RFC5880 6.8.7
+		A system MUST NOT periodically transmit BFD Control packets if Demand
+		mode is active on the remote system (bfd.RemoteDemandMode is 1,
+		bfd.SessionState is Up, and bfd.RemoteSessionState is Up) and a Poll
+		Sequence is not being transmitted.
+	*/
+	polling := snapshot.pollActive
+
+	/*
+		RFC5880 6.8.7
+		If bfd.RemoteDiscr is zero and the system is taking the Passive
+		role, the local system MUST NOT transmit BFD Control packets.
+	*/
+	waiting := peer.Passive && remote.GetDiscriminator() == 0
+
+	peer.updateEcho()
+
+	if peer.isPollDue() {
+		peer.Poll()
+	} else if !waiting && remote.requiredMinRxInterval > 0 && (polling || !peer.isRemoteDemandActive()) {
+		// send a packet, an unanswered poll sequence is retransmitted with every packet
+		packet := peer.NewPacket(peer.pollBit(), bfd.No)
+		peer.Send(packet)
+	}
+
+	if local.sessionState != bfd.Up {
+		peer.SetDesiredMinTxInterval(1000000)
+		local = local.Clone([]PeerStateUpdate{setDesiredMinTxInterval(1000000)})
+	}
+
+	/*
+		RFC5880 6.8.7
+		The periodic transmission of BFD Control packets MUST be jittered on
+		a per-packet basis by up to 25%, that is, the interval MUST be
+		reduced by a random value of 0 to 25%, in order to avoid self-
+		synchronization with other systems on the same subnetwork.  Thus, the
+		average interval between packets will be roughly 12.5% less than that
+		negotiated.
+	*/
+
+	preSendInterval := max(local.desiredMinTxInterval, remote.requiredMinRxInterval)
+	sendInterval := preSendInterval - (preSendInterval * uint32(peer.rand.Intn(25)) / 100)
+
+	/*
+		RFC5880 6.8.7
+		If bfd.DetectMult is equal to 1, the interval between transmitted BFD
+		Control packets MUST be no more than 90% of the negotiated
+		transmission interval, and MUST be no less than 75% of the negotiated
+		transmission interval.  This is to ensure that, on the remote system,
+		the calculated Detection Time does not pass prior to the receipt of
+		the next BFD Control packet.
+	*/
+	if local.detectMultiplier == 1 {
+		sendInterval = min(sendInterval, preSendInterval*90/100)
+	}
+
+	peer.scheduleSend(sendInterval)
+}
+
+// expire takes the session down once the detection time passed, it's run by the expiry timer
+func (peer *Peer) expire() {
+	/*
+		If Demand mode is not active, and a period of time equal to the
+		Detection Time passes without receiving a BFD Control packet from the
+		remote system, and bfd.SessionState is Init or Up, the session has
+		gone down -- the local system MUST set bfd.SessionState to Down and
+		bfd.LocalDiag to 1 (Control Detection Time Expired).
+	*/
+	peer.transition(func(local *PeerState, remote *PeerState) ([]PeerStateUpdate, []PeerStateUpdate, error) {
+		// while Demand mode is active only an unanswered poll sequence takes the session down
+		demand := local.demandMode && local.sessionState == bfd.Up && remote.sessionState == bfd.Up
+		suspended := demand && !peer.PollActive
+
+		if suspended {
+			return nil, nil, nil
 		}
+
+		/*
+			RFC5880 6.8.1
+			bfd.RemoteDiscr: If a period of a Detection Time passes without the
+			receipt of a valid, authenticated BFD packet from the remote system,
+			this variable MUST be set to zero.
+		*/
+		if local.sessionState != bfd.Init && local.sessionState != bfd.Up {
+			return nil, []PeerStateUpdate{setDiscriminator(0)}, nil
+		}
+
+		/*
+				So long as the local system continues to transmit BFD Control
+			    packets, the remote system is obligated to obey the value carried in
+			    Required Min RX Interval.  If the remote system does not receive any
+			    BFD Control packets for a Detection Time, it SHOULD reset
+			    bfd.RemoteMinRxInterval to its initial value of 1 (per section 6.8.1,
+			    since it is no longer required to maintain previous session state)
+			    and then can transmit at its own rate.
+		*/
+		return []PeerStateUpdate{
+			setDiagnosticCode(bfd.ControlDetectionTimeExpired),
+			setSessionState(bfd.Down),
+		}, []PeerStateUpdate{
+			setDiscriminator(0),
+			setRequiredMinRxInterval(1),
+		}, nil
+	}, false)
+}
+
+// authenticate validates the authentication section of a received packet under the rules of RFC5880 6.7
+func (peer *Peer) authenticate(packet *bfd.ControlPacket, raw []byte) error {
+	peer.Lock()
+	defer peer.Unlock()
+
+	header := packet.AuthenticationHeader
+
+	// there's no provider that could have created the header
+	if _, err := bfd.GetAuthenticationProvider(header.GetAuthenticationType()); err != nil {
+		return ErrNotImplemented
+	}
+
+	key, ok := peer.acceptKey(header.GetAuthKeyId())
+
+	if !ok {
+		return ErrAuthenticationFailed
+	}
+
+	if sequenced, ok := header.(bfd.SequencedAuthenticationHeader); ok {
+		return peer.authenticateSequenced(sequenced, key, packet.DetectMultiplier, raw)
+	}
+
+	if !header.IsValid(key, raw) {
+		return ErrAuthenticationFailed
 	}
+
+	return nil
 }
 
-func (peer *Peer) handle() {
+// authenticateSequenced validates a section with a sequence number, the lock needs to be held by the caller
+func (peer *Peer) authenticateSequenced(header bfd.SequencedAuthenticationHeader, key []byte, detectMultiplier uint8, raw []byte) error {
+	sequence := header.GetSequenceNumber()
 
-	for {
-		select {
-		case <-peer.ticker.C:
-			local := peer.GetLocal()
-			remote := peer.GetRemote()
+	/*
+		bfd.AuthSeqKnown MUST be set to 0 if no packets are received on
+		the session for at least twice the Detection Time.
+	*/
+	if peer.AuthSequenceKnown == 1 && peer.clock.Now().Sub(peer.lastPacket) > 2*peer.detectionTime {
+		peer.AuthSequenceKnown = 0
+	}
 
-			if remote.requiredMinRxInterval > 0 {
-				// send a packet
-				packet := peer.NewPacket(bfd.No, bfd.No)
-				peer.Send(packet)
-			}
+	if !peer.isAuthSequenceValid(sequence, isMeticulous(header.GetAuthenticationType()), detectMultiplier) {
+		return ErrInvalidAuthSequence
+	}
 
-			if local.sessionState != bfd.Up {
-				// we need to unlock since otherwise we got a deadlock here
-				peer.SetDesiredMinTxInterval(1000000)
-				local = local.Clone([]PeerStateUpdate{setDesiredMinTxInterval(1000000)})
-			}
+	if !header.IsValid(key, raw) {
+		return ErrAuthenticationFailed
+	}
 
-			/*
-				RFC5880 6.8.7
-				The periodic transmission of BFD Control packets MUST be jittered on
-				a per-packet basis by up to 25%, that is, the interval MUST be
-				reduced by a random value of 0 to 25%, in order to avoid self-
-				synchronization with other systems on the same subnetwork.  Thus, the
-				average interval between packets will be roughly 12.5% less than that
-				negotiated.
-			*/
-
-			preSendInterval := max(local.desiredMinTxInterval, remote.requiredMinRxInterval)
-			sendInterval := preSendInterval - (preSendInterval * uint32(rand.Intn(25)) / 100)
-
-			/*
-				RFC5880 6.8.7
-				If bfd.DetectMult is equal to 1, the interval between transmitted BFD
-				Control packets MUST be no more than 90% of the negotiated
-				transmission interval, and MUST be no less than 75% of the negotiated
-				transmission interval.  This is to ensure that, on the remote system,
-				the calculated Detection Time does not pass prior to the receipt of
-				the next BFD Control packet.
-			*/
-			if local.detectMultiplier == 1 {
-				sendInterval = min(sendInterval, preSendInterval*90/100)
-			}
+	peer.ReceivedAuthSequence = sequence
+	peer.AuthSequenceKnown = 1
 
-			peer.scheduleSend(sendInterval)
-
-		// expiry placed here, since it's 1 goroutine per neighbor
-		case <-peer.expiry.C:
-			/*
-				If Demand mode is not active, and a period of time equal to the
-				Detection Time passes without receiving a BFD Control packet from the
-				remote system, and bfd.SessionState is Init or Up, the session has
-				gone down -- the local system MUST set bfd.SessionState to Down and
-				bfd.LocalDiag to 1 (Control Detection Time Expired).
-			*/
-			local := peer.GetLocal()
-
-			// glog.Infof("Expired: %v", time.Since(peer.lastPacket))
-
-			if local.sessionState == bfd.Init || local.sessionState == bfd.Up {
-				/*
-						So long as the local system continues to transmit BFD Control
-					    packets, the remote system is obligated to obey the value carried in
-					    Required Min RX Interval.  If the remote system does not receive any
-					    BFD Control packets for a Detection Time, it SHOULD reset
-					    bfd.RemoteMinRxInterval to its initial value of 1 (per section 6.8.1,
-					    since it is no longer required to maintain previous session state)
-					    and then can transmit at its own rate.
-				*/
-
-				peer.ApplyLocalState([]PeerStateUpdate{
-					setDiagnosticCode(bfd.ControlDetectionTimeExpired),
-					setSessionState(bfd.Down),
-				})
-
-				peer.ApplyRemoteState([]PeerStateUpdate{
-					setRequiredMinRxInterval(1),
-				})
-			}
+	return nil
+}
 
-		case <-peer.control:
-			glog.Infof("Shutdown Peer")
-			return
-		}
+func (peer *Peer) isAuthSequenceValid(sequence uint32, meticulous bool, detectMultiplier uint8) bool {
+	/*
+		RFC5880 6.7.3 / 6.7.4
+		If bfd.AuthSeqKnown is 1, examine the Sequence Number field.  For
+		Keyed MD5 / SHA1, if the sequence number lies outside of the range of
+		bfd.RcvAuthSeq to bfd.RcvAuthSeq+(3*Detect Mult) inclusive (when
+		treated as an unsigned 32-bit circular number space), the received
+		packet MUST be discarded.  For Meticulous Keyed MD5 / SHA1, if the
+		sequence number lies outside of the range of bfd.RcvAuthSeq+1 to
+		bfd.RcvAuthSeq+(3*Detect Mult) inclusive (when treated as an unsigned
+		32-bit circular number space) the received packet MUST be discarded.
+	*/
+	if peer.AuthSequenceKnown == 0 {
+		return true
+	}
+
+	lower := peer.ReceivedAuthSequence
+	upper := peer.ReceivedAuthSequence + 3*uint32(detectMultiplier)
+
+	if meticulous {
+		lower++
+	}
+
+	// circular comparison, wraps around correctly with unsigned arithmetic
+	return sequence-lower <= upper-lower
+}
+
+// acceptsAuthenticationType checks that a packet uses the authentication type of the session
+func (peer *Peer) acceptsAuthenticationType(packet *bfd.ControlPacket) bool {
+	authType := peer.GetAuthenticationType()
+
+	if packet.GetAuthenticationType() == authType {
+		return true
+	}
+
+	peer.RLock()
+	optimized := peer.OptimizedAuth
+	peer.RUnlock()
+
+	if !optimized || packet.GetAuthenticationType() != bfd.MeticulousKeyedSecureSequence {
+		return false
 	}
+
+	/*
+		Optimized authentication (BFD working group drafts)
+		Packets that change the state of the session or its parameters
+		are fully authenticated, the others only carry a secure sequence
+		number. It doesn't cover the packet contents, so it's only accepted
+		for packets that leave an up session as it is. The Desired Min TX
+		Interval stays the one of the last fully authenticated packet, it
+		sets the detection time, changing it needs a Poll Sequence.
+	*/
+	snapshot := peer.Snapshot()
+	local := snapshot.local
+	remote := snapshot.remote
+
+	return local.sessionState == bfd.Up &&
+		packet.State == bfd.Up &&
+		packet.Poll == bfd.No &&
+		packet.Final == bfd.No &&
+		(packet.Demand == bfd.Yes) == remote.demandMode &&
+		packet.DetectMultiplier == remote.detectMultiplier &&
+		packet.DesiredMinTxInterval == remote.desiredMinTxInterval &&
+		packet.RequiredMinRxInterval == remote.requiredMinRxInterval &&
+		packet.RequiredMinEchoInterval == remote.requiredMinEchoRxInterval
 }
 
-func (peer *Peer) handlePacket(packet *bfd.ControlPacket) error {
+func (peer *Peer) handlePacket(packet *bfd.ControlPacket, raw []byte) error {
 	/*
 		If the A bit is set and no authentication is in use (bfd.AuthType is zero),
 		the packet MUST be discarded.
@@ -423,7 +947,7 @@ func (peer *Peer) handlePacket(packet *bfd.ControlPacket) error {
 		the packet MUST be discarded.
 	*/
 
-	if peer.GetAuthenticationType() != packet.GetAuthenticationType() {
+	if !peer.acceptsAuthenticationType(packet) {
 		return bfd.ErrInvalidAuthenticationType
 	}
 
@@ -433,15 +957,15 @@ func (peer *Peer) handlePacket(packet *bfd.ControlPacket) error {
 		(bfd.AuthType).  This may cause the packet to be discarded.
 	*/
 	if packet.GetAuthenticationType() != bfd.Reserved {
-		// Do authentication
-		return ErrNotImplemented
+		if err := peer.authenticate(packet, raw); err != nil {
+			return err
+		}
 	}
 
-	local := peer.GetLocal()
-	remote := peer.GetRemote()
+	peer.Lock()
+	peer.lastPacket = peer.clock.Now()
+	peer.Unlock()
 
-	// local updates
-	lu := make([]PeerStateUpdate, 0)
 	// remote updates
 	ru := make([]PeerStateUpdate, 0)
 
@@ -455,116 +979,62 @@ func (peer *Peer) handlePacket(packet *bfd.ControlPacket) error {
 	ru = append(ru, setDiscriminator(packet.MyDiscriminator))
 
 	// Set bfd.RemoteMinRxInterval to the value of Required Min RX Interval.
-	if remote.requiredMinRxInterval > packet.RequiredMinRxInterval {
-		// define a new timeout for the ticker
-		// maybe define a custom timer with the "end time"
-		// or define a timer with 0 (for immediate change)
 
-		// RFC5880 6.8.3
-		// If this interval has already passed
-		// since the last transmission (because the new interval is
-		// significantly shorter), the local system MUST send the next periodic
-		// BFD Control packet as soon as practicable.
+	// RFC5880 6.8.3
+	// If this interval has already passed
+	// since the last transmission (because the new interval is
+	// significantly shorter), the local system MUST send the next periodic
+	// BFD Control packet as soon as practicable.
 
-		// TODO something?
-		// peer.scheduleSend(peer.desiredMinTxInterval)
-	}
+	// TODO something?
+	// peer.scheduleSend(peer.desiredMinTxInterval)
 
 	ru = append(ru, setRequiredMinRxInterval(packet.RequiredMinRxInterval))
 	ru = append(ru, setDetectMultiplier(packet.DetectMultiplier))
 
+	// the last received Desired Min TX Interval, optimized authentication keeps it unchanged
+	ru = append(ru, setDesiredMinTxInterval(packet.DesiredMinTxInterval))
+
 	// If the Required Min Echo RX Interval field is zero, the transmission of Echo packets, if any, MUST cease.
-	// => handle in sending code (Locks?!)
+	// => handled by updateEcho once the remote state is applied
+	ru = append(ru, setRequiredMinEchoRxInterval(packet.RequiredMinEchoInterval))
 
 	/*
 		If a Poll Sequence is being transmitted by the local system and
 		the Final (F) bit in the received packet is set, the Poll Sequence
 		MUST be terminated.
 	*/
-	if peer.PollActive && packet.Final == bfd.Yes {
-		peer.Lock()
-		peer.PollActive = false
-		peer.Unlock()
+	if packet.Final == bfd.Yes {
+		peer.terminatePoll()
 	}
 
 	// Update the transmit interval as described in section 6.8.2.
 	// Update the Detection Time as described in section 6.8.4.
 
-	// If bfd.SessionState is AdminDown
-	//     Discard the packet
-
-	if local.sessionState == bfd.AdminDown {
-		return ErrSessionAdminDown
-	}
+	// the transition is based on the current state, a snapshot could be older than a concurrent Disable
+	snapshot, err := peer.transition(func(local *PeerState, remote *PeerState) ([]PeerStateUpdate, []PeerStateUpdate, error) {
+		lu, err := receivedState(local, packet)
 
-	/*
-		If received state is AdminDown
-		  If bfd.SessionState is not Down
-			  Set bfd.LocalDiag to 3 (Neighbor signaled
-				  session down)
-			  Set bfd.SessionState to Down
+		return lu, ru, err
+	}, true)
 
-		Else
-	*/
-	if packet.State == bfd.AdminDown {
-		if local.sessionState != bfd.Down {
-			lu = append(lu, setDiagnosticCode(bfd.NeighborSignaledSessionDown))
-			lu = append(lu, setSessionState(bfd.Down))
-		}
-	} else {
-		/*
-			If bfd.SessionState is Down
-			  If received State is Down
-				  Set bfd.SessionState to Init
-			  Else if received State is Init
-				  Set bfd.SessionState to Up
-			Else if bfd.SessionState is Init
-				If received State is Init or Up
-					Set bfd.SessionState to Up
-
-			Else (bfd.SessionState is Up)
-				If received State is Down
-					Set bfd.LocalDiag to 3 (Neighbor signaled
-						session down)
-					Set bfd.SessionState to Down
-		*/
-		if local.sessionState == bfd.Down {
-			if packet.State == bfd.Down {
-				lu = append(lu, setDiagnosticCode(bfd.NoDiagnostic))
-				lu = append(lu, setSessionState(bfd.Init))
-			} else if packet.State == bfd.Init {
-				lu = append(lu, setDiagnosticCode(bfd.NoDiagnostic))
-				lu = append(lu, setSessionState(bfd.Up))
-			}
-		} else if local.sessionState == bfd.Init {
-			if packet.State == bfd.Init || packet.State == bfd.Up {
-				lu = append(lu, setDiagnosticCode(bfd.NoDiagnostic))
-				lu = append(lu, setSessionState(bfd.Up))
-			}
-		} else {
-			if packet.State == bfd.Down {
-				lu = append(lu, setDiagnosticCode(bfd.NeighborSignaledSessionDown))
-				lu = append(lu, setSessionState(bfd.Down))
-			}
-		}
+	if err != nil {
+		return err
 	}
 
-	// Apply any changes locally and then on the peer
-	local = local.Clone(lu)
-	remote = remote.Clone(ru)
+	local := snapshot.local
+	remote := snapshot.remote
 
-	peer.ApplyLocalState(lu)
-	peer.ApplyRemoteState(ru)
+	peer.updateEcho()
 
 	// Check to see if Demand mode should become active or not (see section 6.6).
-	// => not supported for now
 
 	/*
 	  If bfd.RemoteDemandMode is 1, bfd.SessionState is Up, and
 	  bfd.RemoteSessionState is Up, Demand mode is active on the remote
 	  system and the local system MUST cease the periodic transmission
 	  of BFD Control packets (see section 6.8.7).
-	  => not supported for now
+	  => handled on the transmit timer
 	*/
 
 	/*
@@ -612,3 +1082,70 @@ func (peer *Peer) handlePacket(packet *bfd.ControlPacket) error {
 
 	return nil
 }
+
+// receivedState returns the local updates of a received packet for the current local state
+func receivedState(local *PeerState, packet *bfd.ControlPacket) ([]PeerStateUpdate, error) {
+	// If bfd.SessionState is AdminDown
+	//     Discard the packet
+
+	if local.sessionState == bfd.AdminDown {
+		return nil, ErrSessionAdminDown
+	}
+
+	// local updates
+	lu := make([]PeerStateUpdate, 0)
+
+	/*
+		If received state is AdminDown
+		  If bfd.SessionState is not Down
+			  Set bfd.LocalDiag to 3 (Neighbor signaled
+				  session down)
+			  Set bfd.SessionState to Down
+
+		Else
+	*/
+	if packet.State == bfd.AdminDown {
+		if local.sessionState != bfd.Down {
+			lu = append(lu, setDiagnosticCode(bfd.NeighborSignaledSessionDown))
+			lu = append(lu, setSessionState(bfd.Down))
+		}
+	} else {
+		/*
+			If bfd.SessionState is Down
+			  If received State is Down
+				  Set bfd.SessionState to Init
+			  Else if received State is Init
+				  Set bfd.SessionState to Up
+			Else if bfd.SessionState is Init
+				If received State is Init or Up
+					Set bfd.SessionState to Up
+
+			Else (bfd.SessionState is Up)
+				If received State is Down
+					Set bfd.LocalDiag to 3 (Neighbor signaled
+						session down)
+					Set bfd.SessionState to Down
+		*/
+		if local.sessionState == bfd.Down {
+			if packet.State == bfd.Down {
+				lu = append(lu, setDiagnosticCode(bfd.NoDiagnostic))
+				lu = append(lu, setSessionState(bfd.Init))
+			} else if packet.State == bfd.Init {
+				lu = append(lu, setDiagnosticCode(bfd.NoDiagnostic))
+				lu = append(lu, setSessionState(bfd.Up))
+			}
+		} else if local.sessionState == bfd.Init {
+			if packet.State == bfd.Init || packet.State == bfd.Up {
+				lu = append(lu, setDiagnosticCode(bfd.NoDiagnostic))
+				lu = append(lu, setSessionState(bfd.Up))
+			}
+		} else {
+			if packet.State == bfd.Down {
+				lu = append(lu, setDiagnosticCode(bfd.NeighborSignaledSessionDown))
+				lu = append(lu, setSessionState(bfd.Down))
+			}
+		}
+	}
+
+	return lu, nil
+}
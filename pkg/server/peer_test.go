@@ -1,10 +1,12 @@
 package server
 
 import (
-	"errors"
+	"io"
 	"net"
 	"os"
 	"reflect"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 	//	"fmt"
@@ -13,14 +15,79 @@ import (
 	"github.com/Thoro/bfd/pkg/packet/bfd"
 )
 
-func Setup(t *testing.T) *Peer {
-	p, err := NewPeer(net.ParseIP("2ac9::22"), 3278)
+// Setup returns a peer whose timers never run, they're held by a virtual clock that isn't advanced
+func Setup(t testing.TB) *Peer {
+	p, _ := setupClockPeer(t)
+
+	return p
+}
+
+// setupClockPeer returns a peer whose timers run when the virtual clock is advanced
+func setupClockPeer(t testing.TB) (*Peer, *VirtualClock) {
+	clock := NewVirtualClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	p, err := newPeer(net.ParseIP("2ac9::22"), 3278, newScheduler(clock, 0), NewRand(1))
 
 	if err != nil {
-		t.Fail()
+		t.Fatalf("%v", err)
 	}
 
-	return p
+	return p, clock
+}
+
+// virtualRemote is the remote system of a peer on a virtual network, it keeps the packets sent by the peer
+type virtualRemote struct {
+	network *VirtualNetwork
+	conn    *virtualConn // dialed, the packets queue up until they're taken
+}
+
+// setupNetworkPeer returns a peer at 10.0.0.1 sending to its remote at 10.0.0.2, the timers
+// of the peer run and its packets are delivered when the network is advanced
+func setupNetworkPeer(t testing.TB) (*Peer, *virtualRemote) {
+	network := NewVirtualNetwork(1)
+
+	p, err := newPeer(net.ParseIP("10.0.0.2"), 3784, newScheduler(network.Clock(), 0), NewRand(1))
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	network.SetLatency(0)
+
+	local := &net.UDPAddr{IP: net.ParseIP("10.0.0.1"), Port: 49152}
+
+	conn, err := network.Transport(local.IP).DialUDP("udp", local, p.Address)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	p.conn = conn
+
+	remote, err := network.Transport(p.Address.IP).DialUDP("udp", p.Address, local)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return p, &virtualRemote{network: network, conn: remote.(*virtualConn)}
+}
+
+// packets delivers the packets sent by the peer so far and takes them from the queue
+func (r *virtualRemote) packets() [][]byte {
+	r.network.Advance(0)
+
+	r.conn.Lock()
+	defer r.conn.Unlock()
+
+	packets := make([][]byte, 0, len(r.conn.queue))
+
+	for _, pkt := range r.conn.queue {
+		packets = append(packets, pkt.data)
+	}
+
+	r.conn.queue = nil
+
+	return packets
 }
 
 func TestNewPeerInvalidAddress(t *testing.T) {
@@ -70,6 +137,8 @@ func TestNewPacket(t *testing.T) {
 }
 
 type FakeConn struct {
+	sync.Mutex // the packets are written by the timers of the peer
+
 	file  *os.File
 	n     int
 	oobn  int
@@ -103,20 +172,21 @@ func (f *FakeConn) ReadMsgUDP(b, oob []byte) (n, oobn, flags int, addr *net.UDPA
 }
 
 func (f *FakeConn) Write(b []byte) (int, error) {
+	f.Lock()
+	defer f.Unlock()
+
 	if f.err != nil {
 		return 0, f.err
 	}
 
-	f.lastData = b
+	// the buffer of the peer is reused for the next packet
+	f.lastData = append([]byte(nil), b...)
 
 	return len(b), nil
 }
 
 func TestSendPacket(t *testing.T) {
-	p := Setup(t)
-
-	fake := &FakeConn{}
-	p.conn = fake
+	p, remote := setupNetworkPeer(t)
 
 	err := p.Send(p.NewPacket(bfd.No, bfd.No))
 
@@ -124,11 +194,61 @@ func TestSendPacket(t *testing.T) {
 		t.Fail()
 	}
 
-	fake.err = errors.New("Fake error")
+	if len(remote.packets()) != 1 {
+		t.Errorf("Expected the packet to reach the remote")
+	}
+
+	p.conn.(io.Closer).Close()
 	err = p.Send(p.NewPacket(bfd.No, bfd.No))
 
-	if err != fake.err {
-		t.Fail()
+	if err != net.ErrClosed {
+		t.Errorf("Expected %v, got %v", net.ErrClosed, err)
+	}
+}
+
+// discardConn drops written packets without keeping a copy
+type discardConn struct {
+	FakeConn
+}
+
+func (d *discardConn) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func BenchmarkPeerSend(b *testing.B) {
+	p := Setup(b)
+	p.conn = &discardConn{}
+
+	packet := p.NewPacket(bfd.No, bfd.No)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := p.Send(packet); err != nil {
+			b.Fatalf("%v", err)
+		}
+	}
+}
+
+func TestSendPacketPadded(t *testing.T) {
+	p, remote := setupNetworkPeer(t)
+	p.PadToSize = 1400
+
+	if err := p.Send(p.NewPacket(bfd.No, bfd.No)); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	packets := remote.packets()
+
+	if len(packets) != 1 || len(packets[0]) != 1400 {
+		t.Fatalf("Expected a packet of 1400 bytes, got %d packets", len(packets))
+	}
+
+	pkt := &bfd.ControlPacket{}
+
+	if err := pkt.UnmarshalBinary(packets[0]); err != nil {
+		t.Errorf("Expected the padded packet to be valid, got %v", err)
 	}
 }
 
@@ -153,7 +273,7 @@ func TestSetDesiredMinTxInterval(t *testing.T) {
 	p.Start()
 	p.SetDesiredMinTxInterval(50)
 
-	p.local.sessionState = bfd.Up
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Up)})
 	p.SetDesiredMinTxInterval(50)
 
 	p.SetDesiredMinTxInterval(40)
@@ -178,7 +298,7 @@ func TestSetRequiredMinRxInterval(t *testing.T) {
 
 	p.SetRequiredMinRxInterval(100)
 
-	p.local.sessionState = bfd.Up
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Up)})
 
 	p.SetRequiredMinRxInterval(150)
 
@@ -238,11 +358,11 @@ func TestPeerEnable(t *testing.T) {
 
 	p.Start()
 
-	p.local.sessionState = bfd.AdminDown
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.AdminDown)})
 
 	p.Enable()
 
-	if p.local.sessionState != bfd.Down {
+	if p.GetLocal().sessionState != bfd.Down {
 		t.Fail()
 	}
 }
@@ -253,101 +373,136 @@ func TestPeerDisable(t *testing.T) {
 
 	p.Start()
 
-	p.local.sessionState = bfd.Up
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Up)})
 
 	p.Disable()
 
-	if p.local.sessionState != bfd.AdminDown {
+	if p.GetLocal().sessionState != bfd.AdminDown {
 		t.Fail()
 	}
 }
 
-func TestPeerScheduleExpiry(t *testing.T) {
+func TestPeerDisableWhileReceiving(t *testing.T) {
 	p := Setup(t)
+	defer p.Shutdown()
 
-	p.scheduleExpiry(0)
+	p.conn = &discardConn{}
 
-	expiry := time.NewTimer(time.Duration(100) * time.Millisecond)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
 
-	select {
-	case <-p.expiry.C:
-	case <-expiry.C:
-		t.Fail()
+	// the remote keeps taking the session down and up again
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				for _, state := range []bfd.SessionState{bfd.Down, bfd.Init, bfd.Up} {
+					select {
+					case <-done:
+						return
+					default:
+					}
+
+					p.handlePacket(&bfd.ControlPacket{State: state, MyDiscriminator: 60}, nil)
+				}
+
+				runtime.Gosched()
+			}
+		}()
+	}
+
+	defer wg.Wait()
+	defer close(done)
+
+	for i := 0; i < 10000; i++ {
+		p.Enable()
+		p.Disable()
+		runtime.Gosched()
+
+		// packets handled concurrently must not bring the session back up
+		if state := p.GetLocal().sessionState; state != bfd.AdminDown {
+			t.Fatalf("Expected the session to stay AdminDown, got %v", state)
+		}
+	}
+}
+
+func TestPeerScheduleExpiry(t *testing.T) {
+	p, clock := setupClockPeer(t)
+
+	before := clock.Now()
+	p.scheduleExpiry(1000)
+
+	if p.detectionTime != time.Millisecond || !p.expiry.deadline().Equal(before.Add(time.Millisecond)) {
+		t.Errorf("Unexpected expiry %v at %v", p.detectionTime, p.expiry.deadline())
 	}
 }
 
 func TestPeerScheduleSend(t *testing.T) {
-	p := Setup(t)
+	p, remote := setupNetworkPeer(t)
+	defer p.Shutdown()
 
+	p.ApplyRemoteState([]PeerStateUpdate{setRequiredMinRxInterval(1000)})
+
+	// the timers are held until the peer is started
 	p.scheduleSend(0)
+	remote.network.Advance(10 * time.Millisecond)
+
+	if len(remote.packets()) != 0 {
+		t.Fatalf("Expected no packets before the peer is started")
+	}
 
-	expiry := time.NewTimer(time.Duration(100) * time.Millisecond)
+	p.Start()
+	remote.network.Advance(10 * time.Millisecond)
 
-	select {
-	case <-p.ticker.C:
-	case <-expiry.C:
-		t.Fail()
+	if len(remote.packets()) == 0 {
+		t.Errorf("Expected a packet once the peer is started")
 	}
 }
 
 func TestPeerHandleExpiry(t *testing.T) {
-	p := Setup(t)
+	p, remote := setupNetworkPeer(t)
 	defer p.Shutdown()
 
-	fake := &FakeConn{}
-	p.conn = fake
-
-	expiry := time.NewTimer(time.Duration(100) * time.Millisecond)
-
 	p.Start()
 
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Up)})
+
 	watcher := p.Watch()
 	defer watcher.Stop()
 
-	p.local.sessionState = bfd.Up
-
 	p.scheduleSend(2000000)
 	p.scheduleExpiry(0)
+	remote.network.Advance(time.Millisecond)
 
+	// the event is passed on by the goroutine of the watcher
 	select {
 	case ev := <-watcher.Event():
 
 		if ev.Local.State != api.SessionState_DOWN {
 			t.Fail()
 		}
-	case <-expiry.C:
-		t.Fail()
+	case <-time.After(time.Second):
+		t.Errorf("Expected the session to expire")
 	}
 }
 
 func TestPeerHandleSend(t *testing.T) {
-	p := Setup(t)
+	p, remote := setupNetworkPeer(t)
 	defer p.Shutdown()
 
-	fake := &FakeConn{}
-	p.conn = fake
-
 	p.Start()
 
-	watcher := p.Watch()
-	defer watcher.Stop()
-
-	p.local.sessionState = bfd.Down
-	p.local.detectMultiplier = 1
-	p.remote.requiredMinRxInterval = 20
+	p.ApplyLocalState([]PeerStateUpdate{setDetectMultiplier(1)})
+	p.ApplyRemoteState([]PeerStateUpdate{setRequiredMinRxInterval(20)})
 
 	p.scheduleSend(0)
-	// p.scheduleExpiry(20000000)
+	remote.network.Advance(10 * time.Millisecond)
 
-	expiry := time.NewTimer(time.Duration(10) * time.Millisecond)
-
-	select {
-	case <-expiry.C:
-		if fake.lastData != nil {
-			// we know that data was sent out
-		} else {
-			t.Fail()
-		}
+	if len(remote.packets()) == 0 {
+		t.Errorf("Expected a packet to be sent")
 	}
 }
 
@@ -361,6 +516,10 @@ func (s *FakeHeader) GetAuthenticationType() bfd.AuthenticationType {
 	return bfd.AuthenticationType(10)
 }
 
+func (s *FakeHeader) GetAuthKeyId() int8 {
+	return 0
+}
+
 func (s *FakeHeader) UnmarshalBinary(buf []byte) error {
 	return ErrNotImplemented
 }
@@ -379,7 +538,7 @@ func TestPeerHandlePacketWithWrongAuthenticationType(t *testing.T) {
 
 	pkt := &bfd.ControlPacket{}
 
-	if p.handlePacket(pkt) != bfd.ErrInvalidAuthenticationType {
+	if p.handlePacket(pkt, nil) != bfd.ErrInvalidAuthenticationType {
 		t.Fail()
 	}
 }
@@ -396,7 +555,7 @@ func TestPeerHandlePacketNotImplementedAuthenticationHeader(t *testing.T) {
 		AuthenticationHeader: &FakeHeader{},
 	}
 
-	if err := p.handlePacket(pkt); err != ErrNotImplemented {
+	if err := p.handlePacket(pkt, nil); err != ErrNotImplemented {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
@@ -412,7 +571,7 @@ func TestHandlePacketOfflineTimeout(t *testing.T) {
 
 	pkt := &bfd.ControlPacket{}
 
-	if err := p.handlePacket(pkt); err != nil {
+	if err := p.handlePacket(pkt, nil); err != nil {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
@@ -424,13 +583,13 @@ func TestHandlePacketUp(t *testing.T) {
 
 	p.Start()
 
-	p.local.sessionState = bfd.Up
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Up)})
 
 	pkt := &bfd.ControlPacket{
 		State: bfd.Up,
 	}
 
-	if err := p.handlePacket(pkt); err != nil {
+	if err := p.handlePacket(pkt, nil); err != nil {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
@@ -442,13 +601,13 @@ func TestHandlePacketAdminDown(t *testing.T) {
 
 	p.Start()
 
-	p.local.sessionState = bfd.AdminDown
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.AdminDown)})
 
 	pkt := &bfd.ControlPacket{
 		State: bfd.Up,
 	}
 
-	if err := p.handlePacket(pkt); err != ErrSessionAdminDown {
+	if err := p.handlePacket(pkt, nil); err != ErrSessionAdminDown {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
@@ -460,13 +619,13 @@ func TestHandlePacketInit(t *testing.T) {
 
 	p.Start()
 
-	p.local.sessionState = bfd.Init
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Init)})
 
 	pkt := &bfd.ControlPacket{
 		State: bfd.Up,
 	}
 
-	if err := p.handlePacket(pkt); err != nil {
+	if err := p.handlePacket(pkt, nil); err != nil {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
@@ -478,18 +637,18 @@ func TestHandlePacketDownAndDown(t *testing.T) {
 
 	p.Start()
 
-	p.local.sessionState = bfd.Down
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Down)})
 
 	pkt := &bfd.ControlPacket{
 		State: bfd.Down,
 	}
 
-	if err := p.handlePacket(pkt); err != nil {
+	if err := p.handlePacket(pkt, nil); err != nil {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
 
-	if p.local.sessionState != bfd.Init || p.local.diagnosticCode != bfd.NoDiagnostic {
+	if p.GetLocal().sessionState != bfd.Init || p.GetLocal().diagnosticCode != bfd.NoDiagnostic {
 		t.Fail()
 	}
 }
@@ -500,18 +659,18 @@ func TestHandlePacketDownAndInit(t *testing.T) {
 
 	p.Start()
 
-	p.local.sessionState = bfd.Down
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Down)})
 
 	pkt := &bfd.ControlPacket{
 		State: bfd.Init,
 	}
 
-	if err := p.handlePacket(pkt); err != nil {
+	if err := p.handlePacket(pkt, nil); err != nil {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
 
-	if p.local.sessionState != bfd.Up || p.local.diagnosticCode != bfd.NoDiagnostic {
+	if p.GetLocal().sessionState != bfd.Up || p.GetLocal().diagnosticCode != bfd.NoDiagnostic {
 		t.Fail()
 	}
 }
@@ -522,18 +681,18 @@ func TestHandlePacketDownAndUp(t *testing.T) {
 
 	p.Start()
 
-	p.local.sessionState = bfd.Down
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Down)})
 
 	pkt := &bfd.ControlPacket{
 		State: bfd.Up,
 	}
 
-	if err := p.handlePacket(pkt); err != nil {
+	if err := p.handlePacket(pkt, nil); err != nil {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
 
-	if p.local.sessionState != bfd.Down || p.local.diagnosticCode != bfd.NoDiagnostic {
+	if p.GetLocal().sessionState != bfd.Down || p.GetLocal().diagnosticCode != bfd.NoDiagnostic {
 		t.Fail()
 	}
 }
@@ -544,18 +703,18 @@ func TestHandlePacketUpAndDown(t *testing.T) {
 
 	p.Start()
 
-	p.local.sessionState = bfd.Up
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Up)})
 
 	pkt := &bfd.ControlPacket{
 		State: bfd.Down,
 	}
 
-	if err := p.handlePacket(pkt); err != nil {
+	if err := p.handlePacket(pkt, nil); err != nil {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
 
-	if p.local.sessionState != bfd.Down || p.local.diagnosticCode != bfd.NeighborSignaledSessionDown {
+	if p.GetLocal().sessionState != bfd.Down || p.GetLocal().diagnosticCode != bfd.NeighborSignaledSessionDown {
 		t.Fail()
 	}
 }
@@ -566,39 +725,41 @@ func TestHandlePacketUpAndAdminDown(t *testing.T) {
 
 	p.Start()
 
-	p.local.sessionState = bfd.Up
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Up)})
 
 	pkt := &bfd.ControlPacket{
 		State: bfd.AdminDown,
 	}
 
-	if err := p.handlePacket(pkt); err != nil {
+	if err := p.handlePacket(pkt, nil); err != nil {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
 
-	if p.local.sessionState != bfd.Down || p.local.diagnosticCode != bfd.NeighborSignaledSessionDown {
+	if p.GetLocal().sessionState != bfd.Down || p.GetLocal().diagnosticCode != bfd.NeighborSignaledSessionDown {
 		t.Fail()
 	}
 }
 
 func TestHandlePacketPollInit(t *testing.T) {
-	p := Setup(t)
+	p, remote := setupNetworkPeer(t)
 	defer p.Shutdown()
 
 	p.Start()
 
-	fake := &FakeConn{}
-	p.conn = fake
-
 	pkt := &bfd.ControlPacket{
 		Poll: bfd.Yes,
 	}
 
-	if err := p.handlePacket(pkt); err != nil {
+	if err := p.handlePacket(pkt, nil); err != nil {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
+
+	// a poll is answered right away
+	if pkt := lastSentPacket(t, remote); pkt.Final != bfd.Yes {
+		t.Errorf("Expected a final packet, got %v", pkt)
+	}
 }
 
 func TestHandlePacketPollActive(t *testing.T) {
@@ -613,7 +774,7 @@ func TestHandlePacketPollActive(t *testing.T) {
 		Final: bfd.Yes,
 	}
 
-	if err := p.handlePacket(pkt); err != nil {
+	if err := p.handlePacket(pkt, nil); err != nil {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
@@ -622,3 +783,842 @@ func TestHandlePacketPollActive(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func newAuthenticatedPacket(t *testing.T, header bfd.AuthenticationHeader) (*bfd.ControlPacket, []byte) {
+	pkt := &bfd.ControlPacket{
+		Version:              1,
+		State:                bfd.Down,
+		DetectMultiplier:     1,
+		MyDiscriminator:      60,
+		AuthenticationHeader: header,
+	}
+
+	return reparsePacket(t, pkt)
+}
+
+// reparsePacket returns the packet as it's seen by the receiver, along with the received bytes
+func reparsePacket(t *testing.T, pkt *bfd.ControlPacket) (*bfd.ControlPacket, []byte) {
+	raw, err := pkt.MarshalBinary()
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	parsed := &bfd.ControlPacket{}
+
+	if err := parsed.UnmarshalBinary(raw); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return parsed, raw
+}
+
+func TestNewPacketWithKeyedMD5(t *testing.T) {
+	p := Setup(t)
+
+	p.AuthType = bfd.MeticulousKeyedMD5
+	p.AuthKeyId = 3
+	p.AuthKey = []byte("secret")
+	p.XmitAuthSeq = 10
+
+	pkt := p.NewPacket(bfd.No, bfd.No)
+
+	header, ok := pkt.AuthenticationHeader.(*bfd.KeyedMD5Header)
+
+	if !ok || header.SequenceNumber != 11 || header.AuthKeyId != 3 {
+		t.Errorf("Unexpected header %v", pkt.AuthenticationHeader)
+	}
+
+	// Meticulous Keyed MD5 increments on every packet
+	pkt = p.NewPacket(bfd.No, bfd.No)
+
+	if pkt.AuthenticationHeader.(*bfd.KeyedMD5Header).SequenceNumber != 12 {
+		t.Fail()
+	}
+
+	p.AuthType = bfd.KeyedMD5
+
+	pkt = p.NewPacket(bfd.No, bfd.No)
+
+	if pkt.AuthenticationHeader.(*bfd.KeyedMD5Header).SequenceNumber != 12 {
+		t.Fail()
+	}
+}
+
+func TestHandlePacketSimplePassword(t *testing.T) {
+	p := Setup(t)
+	defer p.Shutdown()
+
+	p.Start()
+
+	p.AuthType = bfd.SimplePassword
+	p.AuthKeyId = 2
+	p.AuthKey = []byte("secret")
+
+	pkt, raw := newAuthenticatedPacket(t, &bfd.SimplePasswordHeader{AuthKeyId: 2, Password: "secret"})
+
+	if err := p.handlePacket(pkt, raw); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	pkt, raw = newAuthenticatedPacket(t, &bfd.SimplePasswordHeader{AuthKeyId: 2, Password: "wrong"})
+
+	if err := p.handlePacket(pkt, raw); err != ErrAuthenticationFailed {
+		t.Errorf("%v", err)
+	}
+
+	pkt, raw = newAuthenticatedPacket(t, &bfd.SimplePasswordHeader{AuthKeyId: 1, Password: "secret"})
+
+	if err := p.handlePacket(pkt, raw); err != ErrAuthenticationFailed {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestHandlePacketKeyedMD5(t *testing.T) {
+	p := Setup(t)
+	defer p.Shutdown()
+
+	p.Start()
+
+	p.AuthType = bfd.KeyedMD5
+	p.AuthKeyId = 1
+	p.AuthKey = []byte("secret")
+
+	header := &bfd.KeyedMD5Header{
+		AuthType:       bfd.KeyedMD5,
+		AuthKeyId:      1,
+		SequenceNumber: 100,
+		AuthKey:        []byte("secret"),
+	}
+
+	pkt, raw := newAuthenticatedPacket(t, header)
+
+	if err := p.handlePacket(pkt, raw); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if p.AuthSequenceKnown != 1 || p.ReceivedAuthSequence != 100 {
+		t.Errorf("Sequence not tracked: %d %d", p.AuthSequenceKnown, p.ReceivedAuthSequence)
+	}
+
+	// Keyed MD5 accepts the same sequence number again
+	if err := p.handlePacket(pkt, raw); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	// wrong key
+	header.AuthKey = []byte("wrong")
+	pkt, raw = newAuthenticatedPacket(t, header)
+
+	if err := p.handlePacket(pkt, raw); err != ErrAuthenticationFailed {
+		t.Errorf("%v", err)
+	}
+
+	// wrong key id
+	header.AuthKey = []byte("secret")
+	header.AuthKeyId = 2
+	pkt, raw = newAuthenticatedPacket(t, header)
+
+	if err := p.handlePacket(pkt, raw); err != ErrAuthenticationFailed {
+		t.Errorf("%v", err)
+	}
+
+	// outside of the window of 3 * Detect Mult
+	header.AuthKeyId = 1
+	header.SequenceNumber = 104
+	pkt, raw = newAuthenticatedPacket(t, header)
+
+	if err := p.handlePacket(pkt, raw); err != ErrInvalidAuthSequence {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestHandlePacketMeticulousKeyedMD5(t *testing.T) {
+	p, clock := setupClockPeer(t)
+	defer p.Shutdown()
+
+	p.Start()
+
+	p.AuthType = bfd.MeticulousKeyedMD5
+	p.AuthKeyId = 1
+	p.AuthKey = []byte("secret")
+
+	header := &bfd.KeyedMD5Header{
+		AuthType:       bfd.MeticulousKeyedMD5,
+		AuthKeyId:      1,
+		SequenceNumber: 0xFFFFFFFF,
+		AuthKey:        []byte("secret"),
+	}
+
+	pkt, raw := newAuthenticatedPacket(t, header)
+
+	if err := p.handlePacket(pkt, raw); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	// replayed packets are discarded
+	if err := p.handlePacket(pkt, raw); err != ErrInvalidAuthSequence {
+		t.Errorf("%v", err)
+	}
+
+	// the sequence number space wraps around
+	header.SequenceNumber = 1
+	pkt, raw = newAuthenticatedPacket(t, header)
+
+	if err := p.handlePacket(pkt, raw); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	// sequence is no longer known after 2 * detection time without packets
+	p.scheduleExpiry(1)
+	clock.Advance(time.Millisecond)
+
+	header.SequenceNumber = 50
+	pkt, raw = newAuthenticatedPacket(t, header)
+
+	if err := p.handlePacket(pkt, raw); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestNewPacketWithKeyedSHA1(t *testing.T) {
+	p := Setup(t)
+
+	p.AuthType = bfd.MeticulousKeyedSHA1
+	p.AuthKeyId = 4
+	p.AuthKey = []byte("secret")
+	p.XmitAuthSeq = 0xFFFFFFFF
+
+	pkt := p.NewPacket(bfd.No, bfd.No)
+
+	header, ok := pkt.AuthenticationHeader.(*bfd.KeyedSHA1Header)
+
+	if !ok || header.SequenceNumber != 0 || header.AuthKeyId != 4 {
+		t.Errorf("Unexpected header %v", pkt.AuthenticationHeader)
+	}
+
+	if _, err := pkt.MarshalBinary(); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestHandlePacketKeyedSHA1(t *testing.T) {
+	p := Setup(t)
+	defer p.Shutdown()
+
+	p.Start()
+
+	p.AuthType = bfd.MeticulousKeyedSHA1
+	p.AuthKeyId = 1
+	p.AuthKey = []byte("01234567890123456789")
+
+	header := &bfd.KeyedSHA1Header{
+		AuthType:       bfd.MeticulousKeyedSHA1,
+		AuthKeyId:      1,
+		SequenceNumber: 10,
+		AuthKey:        []byte("01234567890123456789"),
+	}
+
+	pkt, raw := newAuthenticatedPacket(t, header)
+
+	if err := p.handlePacket(pkt, raw); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	// replayed packets are discarded
+	if err := p.handlePacket(pkt, raw); err != ErrInvalidAuthSequence {
+		t.Errorf("%v", err)
+	}
+
+	header.SequenceNumber = 11
+	header.AuthKey = []byte("wrong")
+	pkt, raw = newAuthenticatedPacket(t, header)
+
+	if err := p.handlePacket(pkt, raw); err != ErrAuthenticationFailed {
+		t.Errorf("%v", err)
+	}
+
+	// a failed packet doesn't advance the sequence number
+	header.AuthKey = []byte("01234567890123456789")
+	pkt, raw = newAuthenticatedPacket(t, header)
+
+	if err := p.handlePacket(pkt, raw); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if p.ReceivedAuthSequence != 11 {
+		t.Fail()
+	}
+}
+
+func TestNewPacketOptimizedAuthentication(t *testing.T) {
+	p := Setup(t)
+	defer p.Shutdown()
+
+	p.Start()
+
+	p.AuthType = bfd.MeticulousKeyedHMACSHA256
+	p.AuthKey = []byte("secret")
+	p.OptimizedAuth = true
+
+	if pkt := p.NewPacket(bfd.No, bfd.No); pkt.GetAuthenticationType() != bfd.MeticulousKeyedHMACSHA256 {
+		t.Errorf("Expected packets of a session that isn't up to be fully authenticated, got %s", pkt.GetAuthenticationType())
+	}
+
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Up)})
+	p.ApplyRemoteState([]PeerStateUpdate{setSessionState(bfd.Up)})
+
+	pkt := p.NewPacket(bfd.No, bfd.No)
+
+	if pkt.GetAuthenticationType() != bfd.MeticulousKeyedSecureSequence {
+		t.Errorf("Expected a secure sequence number, got %s", pkt.GetAuthenticationType())
+	}
+
+	if _, err := pkt.MarshalBinary(); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if pkt := p.NewPacket(bfd.Yes, bfd.No); pkt.GetAuthenticationType() != bfd.MeticulousKeyedHMACSHA256 {
+		t.Errorf("Expected poll sequences to be fully authenticated, got %s", pkt.GetAuthenticationType())
+	}
+}
+
+func TestHandlePacketOptimizedAuthentication(t *testing.T) {
+	p := Setup(t)
+	defer p.Shutdown()
+
+	p.Start()
+
+	p.AuthType = bfd.MeticulousKeyedHMACSHA256
+	p.AuthKey = []byte("secret")
+	p.OptimizedAuth = true
+
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Up)})
+	p.ApplyRemoteState([]PeerStateUpdate{setSessionState(bfd.Up), setDetectMultiplier(1)})
+
+	desiredMinTx := uint32(0)
+
+	newPacket := func(authType bfd.AuthenticationType, state bfd.SessionState, sequence uint32) (*bfd.ControlPacket, []byte) {
+		return reparsePacket(t, &bfd.ControlPacket{
+			Version:               1,
+			State:                 state,
+			DetectMultiplier:      1,
+			MyDiscriminator:       60,
+			DesiredMinTxInterval:  desiredMinTx,
+			RequiredMinRxInterval: 1,
+			AuthenticationHeader: &bfd.HMACSHA256Header{
+				AuthType:       authType,
+				SequenceNumber: sequence,
+				AuthKey:        []byte("secret"),
+			},
+		})
+	}
+
+	// packets that leave the session as it is only need the secure sequence number
+	if err := p.handlePacket(newPacket(bfd.MeticulousKeyedSecureSequence, bfd.Up, 10)); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	// state changes need to be fully authenticated
+	if err := p.handlePacket(newPacket(bfd.MeticulousKeyedSecureSequence, bfd.Down, 11)); err != bfd.ErrInvalidAuthenticationType {
+		t.Errorf("Expected %v, got %v", bfd.ErrInvalidAuthenticationType, err)
+	}
+
+	if err := p.handlePacket(newPacket(bfd.MeticulousKeyedHMACSHA256, bfd.Down, 11)); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	// the sequence numbers of both types are shared
+	if err := p.handlePacket(newPacket(bfd.MeticulousKeyedHMACSHA256, bfd.Down, 11)); err != ErrInvalidAuthSequence {
+		t.Errorf("Expected %v, got %v", ErrInvalidAuthSequence, err)
+	}
+
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Up)})
+	p.ApplyRemoteState([]PeerStateUpdate{setSessionState(bfd.Up)})
+
+	// a longer Desired Min TX Interval would stretch the detection time
+	desiredMinTx = 60000000
+
+	if err := p.handlePacket(newPacket(bfd.MeticulousKeyedSecureSequence, bfd.Up, 12)); err != bfd.ErrInvalidAuthenticationType {
+		t.Errorf("Expected %v, got %v", bfd.ErrInvalidAuthenticationType, err)
+	}
+
+	if err := p.handlePacket(newPacket(bfd.MeticulousKeyedHMACSHA256, bfd.Up, 12)); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if err := p.handlePacket(newPacket(bfd.MeticulousKeyedSecureSequence, bfd.Up, 13)); err != nil {
+		t.Errorf("Expected the fully authenticated interval to be kept, got %v", err)
+	}
+
+	p.Lock()
+	p.OptimizedAuth = false
+	p.Unlock()
+
+	if err := p.handlePacket(newPacket(bfd.MeticulousKeyedSecureSequence, bfd.Up, 14)); err != bfd.ErrInvalidAuthenticationType {
+		t.Errorf("Expected %v, got %v", bfd.ErrInvalidAuthenticationType, err)
+	}
+}
+
+func TestHandlePacketKeyChainRollover(t *testing.T) {
+	p := Setup(t)
+	defer p.Shutdown()
+
+	p.Start()
+
+	now := p.clock.Now()
+	chain := NewKeyChain("core")
+	chain.SetKey(&Key{Id: 1, Secret: []byte("old"), SendEnd: now.Add(-time.Minute)})
+	chain.SetKey(&Key{Id: 2, Secret: []byte("new"), SendStart: now.Add(-time.Minute)})
+
+	p.AuthType = bfd.MeticulousKeyedMD5
+	p.KeyChain = chain
+
+	// the active key is used to send
+	header, ok := p.NewPacket(bfd.No, bfd.No).AuthenticationHeader.(*bfd.KeyedMD5Header)
+
+	if !ok || header.AuthKeyId != 2 || string(header.AuthKey) != "new" {
+		t.Errorf("Unexpected header %v", header)
+	}
+
+	// while the remote still sends with the old key, it's accepted
+	pkt, raw := newAuthenticatedPacket(t, &bfd.KeyedMD5Header{
+		AuthType:       bfd.MeticulousKeyedMD5,
+		AuthKeyId:      1,
+		SequenceNumber: 10,
+		AuthKey:        []byte("old"),
+	})
+
+	if err := p.handlePacket(pkt, raw); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	pkt, raw = newAuthenticatedPacket(t, &bfd.KeyedMD5Header{
+		AuthType:       bfd.MeticulousKeyedMD5,
+		AuthKeyId:      2,
+		SequenceNumber: 11,
+		AuthKey:        []byte("new"),
+	})
+
+	if err := p.handlePacket(pkt, raw); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	// a key which is not part of the chain is rejected
+	pkt, raw = newAuthenticatedPacket(t, &bfd.KeyedMD5Header{
+		AuthType:       bfd.MeticulousKeyedMD5,
+		AuthKeyId:      3,
+		SequenceNumber: 12,
+		AuthKey:        []byte("new"),
+	})
+
+	if err := p.handlePacket(pkt, raw); err != ErrAuthenticationFailed {
+		t.Errorf("Expected %v, got %v", ErrAuthenticationFailed, err)
+	}
+}
+
+func TestSendWithoutActiveKey(t *testing.T) {
+	p := Setup(t)
+
+	chain := NewKeyChain("core")
+	chain.SetKey(&Key{Id: 1, Secret: []byte("future"), SendStart: p.clock.Now().Add(time.Hour)})
+
+	p.AuthType = bfd.KeyedSHA1
+	p.KeyChain = chain
+
+	pkt := p.NewPacket(bfd.No, bfd.No)
+
+	if pkt.AuthenticationHeader != nil {
+		t.Errorf("Expected no authentication header, got %v", pkt.AuthenticationHeader)
+	}
+
+	if err := p.Send(pkt); err != ErrNoActiveKey {
+		t.Errorf("Expected %v, got %v", ErrNoActiveKey, err)
+	}
+}
+
+func setupUpPeer(t *testing.T) (*Peer, *virtualRemote) {
+	p, remote := setupNetworkPeer(t)
+	p.Start()
+
+	p.Interval = 100000
+	p.ApplyLocalState([]PeerStateUpdate{
+		setSessionState(bfd.Up),
+		setDiscriminator(50),
+		setDesiredMinTxInterval(100000),
+		setRequiredMinRxInterval(100000),
+		setDetectMultiplier(3),
+	})
+
+	return p, remote
+}
+
+// lastSentPacket returns the last packet the peer sent to remote
+func lastSentPacket(t *testing.T, remote *virtualRemote) *bfd.ControlPacket {
+	packets := remote.packets()
+
+	if len(packets) == 0 {
+		t.Fatalf("Expected a packet to be sent")
+	}
+
+	pkt := &bfd.ControlPacket{}
+
+	if err := pkt.UnmarshalBinary(packets[len(packets)-1]); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	return pkt
+}
+
+func TestSetDesiredMinTxIntervalPoll(t *testing.T) {
+	p, remote := setupUpPeer(t)
+	defer p.Shutdown()
+
+	p.SetDesiredMinTxInterval(300000)
+
+	// the old interval is used until the poll sequence terminates
+	if p.GetLocal().desiredMinTxInterval != 100000 || !p.PollActive {
+		t.Errorf("Expected a delayed update with an active poll sequence")
+	}
+
+	pkt := lastSentPacket(t, remote)
+
+	if pkt.Poll != bfd.Yes || pkt.DesiredMinTxInterval != 300000 {
+		t.Errorf("Expected a poll packet advertising the new interval, got %v", pkt)
+	}
+
+	// unanswered polls are retransmitted
+	if p.NewPacket(p.pollBit(), bfd.No).Poll != bfd.Yes {
+		t.Errorf("Expected the poll bit to be set until the sequence terminates")
+	}
+
+	err := p.handlePacket(&bfd.ControlPacket{
+		State:             bfd.Up,
+		Final:             bfd.Yes,
+		DetectMultiplier:  3,
+		MyDiscriminator:   60,
+		YourDiscriminator: 50,
+	}, nil)
+
+	if err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if p.GetLocal().desiredMinTxInterval != 300000 || p.PollActive {
+		t.Errorf("Expected the update to be applied after the final packet")
+	}
+
+	if p.pollBit() != bfd.No {
+		t.Errorf("Expected the poll bit to be cleared")
+	}
+}
+
+func TestSetRequiredMinRxIntervalPoll(t *testing.T) {
+	p, remote := setupUpPeer(t)
+	defer p.Shutdown()
+
+	p.SetRequiredMinRxInterval(50000)
+
+	if p.GetLocal().requiredMinRxInterval != 100000 || !p.PollActive {
+		t.Errorf("Expected a delayed update with an active poll sequence")
+	}
+
+	if pkt := lastSentPacket(t, remote); pkt.Poll != bfd.Yes || pkt.RequiredMinRxInterval != 50000 {
+		t.Errorf("Expected a poll packet advertising the new interval, got %v", pkt)
+	}
+
+	p.terminatePoll()
+
+	if p.GetLocal().requiredMinRxInterval != 50000 || p.PollActive {
+		t.Errorf("Expected the update to be applied after the poll sequence")
+	}
+}
+
+func TestSetDesiredMinTxIntervalDecreasePoll(t *testing.T) {
+	p, remote := setupUpPeer(t)
+	defer p.Shutdown()
+
+	// a faster rate is applied right away, but still announced with a poll
+	p.SetDesiredMinTxInterval(50000)
+
+	if p.GetLocal().desiredMinTxInterval != 50000 || !p.PollActive {
+		t.Errorf("Expected an immediate update with an active poll sequence")
+	}
+
+	if pkt := lastSentPacket(t, remote); pkt.Poll != bfd.Yes {
+		t.Errorf("Expected a poll packet, got %v", pkt)
+	}
+
+	// while the sequence is active later changes keep their order
+	p.SetDesiredMinTxInterval(200000)
+	p.SetDesiredMinTxInterval(80000)
+
+	// both are slower than the interval in use, so they're held back
+	if p.GetLocal().desiredMinTxInterval != 50000 {
+		t.Errorf("Expected the faster interval to be in use, got %d", p.GetLocal().desiredMinTxInterval)
+	}
+
+	p.terminatePoll()
+
+	if p.GetLocal().desiredMinTxInterval != 80000 {
+		t.Errorf("Expected the last interval to be in use, got %d", p.GetLocal().desiredMinTxInterval)
+	}
+}
+
+func TestPollAbortedOnSessionDown(t *testing.T) {
+	p, _ := setupUpPeer(t)
+	defer p.Shutdown()
+
+	p.SetRequiredMinRxInterval(50000)
+
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Down)})
+
+	if p.GetLocal().requiredMinRxInterval != 50000 || p.PollActive {
+		t.Errorf("Expected the held back update to be applied once the session is down")
+	}
+}
+
+func TestSetDesiredMinTxIntervalDown(t *testing.T) {
+	p, remote := setupUpPeer(t)
+	defer p.Shutdown()
+
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Down)})
+
+	p.SetDesiredMinTxInterval(300000)
+
+	if p.GetLocal().desiredMinTxInterval != 300000 || p.PollActive || len(remote.packets()) != 0 {
+		t.Errorf("Expected an immediate update without a poll sequence")
+	}
+}
+
+func TestNewPacketDemandMode(t *testing.T) {
+	p, _ := setupUpPeer(t)
+	defer p.Shutdown()
+
+	if p.NewPacket(bfd.No, bfd.No).Demand != bfd.No {
+		t.Errorf("Expected the demand bit to be clear without demand mode")
+	}
+
+	p.ApplyLocalState([]PeerStateUpdate{setDemandMode(true)})
+
+	if p.NewPacket(bfd.No, bfd.No).Demand != bfd.Yes {
+		t.Errorf("Expected the demand bit to be set")
+	}
+
+	// demand mode is only requested once the session is up
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Init)})
+
+	if p.NewPacket(bfd.No, bfd.No).Demand != bfd.No {
+		t.Errorf("Expected the demand bit to be clear while the session is not up")
+	}
+}
+
+func TestPollDemandMode(t *testing.T) {
+	p, remote := setupUpPeer(t)
+	defer p.Shutdown()
+
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Down)})
+
+	if err := p.Poll(); err != ErrSessionNotUp {
+		t.Errorf("Expected %v, got %v", ErrSessionNotUp, err)
+	}
+
+	p.ApplyLocalState([]PeerStateUpdate{setSessionState(bfd.Up), setDemandMode(true)})
+	p.ApplyRemoteState([]PeerStateUpdate{setSessionState(bfd.Up), setRequiredMinRxInterval(200000)})
+	p.DemandPollInterval = time.Hour
+
+	if !p.isDemandActive() || !p.isPollDue() {
+		t.Errorf("Expected demand mode to be active with a poll due")
+	}
+
+	if err := p.Poll(); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if pkt := lastSentPacket(t, remote); pkt.Poll != bfd.Yes || pkt.Demand != bfd.Yes {
+		t.Errorf("Expected a poll packet in demand mode, got %v", pkt)
+	}
+
+	// the detection time is based on the local multiplier and transmit interval
+	if p.detectionTime != 3*200000*time.Microsecond {
+		t.Errorf("Unexpected detection time %v", p.detectionTime)
+	}
+
+	p.terminatePoll()
+
+	if p.isPollDue() {
+		t.Errorf("Expected the next poll to be scheduled an interval later")
+	}
+}
+
+func TestRemoteDemandModeStopsTransmission(t *testing.T) {
+	p, remote := setupUpPeer(t)
+	defer p.Shutdown()
+
+	p.ApplyRemoteState([]PeerStateUpdate{
+		setSessionState(bfd.Up),
+		setRequiredMinRxInterval(1000),
+		setDemandMode(true),
+	})
+
+	if !p.isRemoteDemandActive() {
+		t.Fatalf("Expected demand mode to be active on the remote")
+	}
+
+	p.scheduleSend(1)
+	remote.network.Advance(20 * time.Millisecond)
+
+	if len(remote.packets()) != 0 {
+		t.Errorf("Expected no periodic packets while the remote is in demand mode")
+	}
+}
+
+func TestPassivePeerWaitsForRemote(t *testing.T) {
+	p, remote := setupNetworkPeer(t)
+	defer p.Shutdown()
+
+	p.Passive = true
+
+	p.Start()
+
+	p.ApplyLocalState([]PeerStateUpdate{setDiscriminator(50)})
+	p.ApplyRemoteState([]PeerStateUpdate{setRequiredMinRxInterval(1000)})
+
+	p.scheduleSend(1)
+	remote.network.Advance(20 * time.Millisecond)
+
+	if len(remote.packets()) != 0 {
+		t.Fatalf("Expected no packets before the remote discriminator is known")
+	}
+
+	p.ApplyRemoteState([]PeerStateUpdate{setDiscriminator(60)})
+	p.scheduleSend(1)
+	remote.network.Advance(20 * time.Millisecond)
+
+	if pkt := lastSentPacket(t, remote); pkt.YourDiscriminator != 60 {
+		t.Errorf("Expected YourDiscriminator 60, got %d", pkt.YourDiscriminator)
+	}
+}
+
+func TestPassivePeerSilentAfterTimeout(t *testing.T) {
+	network := NewVirtualNetwork(1)
+
+	local, err := network.NewServer("10.0.0.1")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer local.Shutdown()
+
+	remote, err := network.NewServer("10.0.0.2")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer remote.Shutdown()
+
+	passive, err := local.AddPeer(&api.Peer{
+		Address:               "10.0.0.2",
+		DesiredMinTxInterval:  100000,
+		RequiredMinRxInterval: 100,
+		DetectMultiplier:      3,
+		Passive:               true,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	active, err := remote.AddPeer(&api.Peer{
+		Address:               "10.0.0.1",
+		DesiredMinTxInterval:  100000,
+		RequiredMinRxInterval: 100,
+		DetectMultiplier:      3,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	network.Advance(5 * time.Second)
+
+	if passive.GetLocal().GetSessionState() != bfd.Up {
+		t.Fatalf("Expected the passive session to be up, got %v", passive.GetLocal())
+	}
+
+	// the passive side times out and forgets the remote discriminator
+	network.SetLoss("10.0.0.2", "10.0.0.1", 1)
+	network.Advance(time.Second)
+
+	if passive.GetLocal().GetSessionState() != bfd.Down || passive.GetRemote().GetDiscriminator() != 0 {
+		t.Fatalf("Expected the session to be down without a remote discriminator, got %v %v", passive.GetLocal(), passive.GetRemote())
+	}
+
+	received := active.Snapshot().GetReceivedPackets()
+	network.Advance(5 * time.Second)
+
+	if active.Snapshot().GetReceivedPackets() != received {
+		t.Errorf("Expected the passive session to stop sending")
+	}
+
+	if active.GetLocal().diagnosticCode != bfd.ControlDetectionTimeExpired {
+		t.Errorf("Expected the remote to time out, got %v", active.GetLocal())
+	}
+}
+
+func TestOptimizedKeyedSHA1StaysUp(t *testing.T) {
+	network := NewVirtualNetwork(1)
+	peers := make([]*Peer, 0, 2)
+
+	for _, addresses := range [][2]string{{"10.0.0.1", "10.0.0.2"}, {"10.0.0.2", "10.0.0.1"}} {
+		server, err := network.NewServer(addresses[0])
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		defer server.Shutdown()
+
+		peer, err := server.AddPeer(&api.Peer{
+			Address:               addresses[1],
+			DesiredMinTxInterval:  100000,
+			RequiredMinRxInterval: 100,
+			DetectMultiplier:      3,
+			Authentication: &api.Authentication{
+				Type:      api.AuthenticationType_KEYED_SHA1,
+				Password:  "secret",
+				KeyId:     1,
+				Optimized: true,
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		peers = append(peers, peer)
+	}
+
+	network.Advance(5 * time.Second)
+
+	transitions := make([]uint64, len(peers))
+
+	for i, peer := range peers {
+		if peer.GetLocal().GetSessionState() != bfd.Up {
+			t.Fatalf("Expected the session to be up, got %v", peer.GetLocal())
+		}
+
+		transitions[i] = peer.Snapshot().GetTransitions()
+	}
+
+	// the steady packets carry a secure sequence number that keeps advancing
+	network.Advance(time.Minute)
+
+	for i, peer := range peers {
+		if peer.GetLocal().GetSessionState() != bfd.Up || peer.Snapshot().GetTransitions() != transitions[i] {
+			t.Errorf("Expected the session to stay up, got %v", peer.GetLocal())
+		}
+	}
+}
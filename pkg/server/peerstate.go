@@ -13,12 +13,18 @@ type PeerState struct {
 	requiredMinRxInterval uint32
 	detectMultiplier      uint8
 	demandMode            bool
+	// RequiredMinEchoRxInterval, 0 = no echo packets are received
+	requiredMinEchoRxInterval uint32
 }
 
 func (state *PeerState) GetDiscriminator() uint32 {
 	return state.discriminator
 }
 
+func (state *PeerState) GetSessionState() bfd.SessionState {
+	return state.sessionState
+}
+
 func (state *PeerState) GetDesiredMinTxInterval() uint32 {
 	return state.desiredMinTxInterval
 }
@@ -27,6 +33,10 @@ func (state *PeerState) GetRequiredMinRxInterval() uint32 {
 	return state.requiredMinRxInterval
 }
 
+func (state *PeerState) GetRequiredMinEchoRxInterval() uint32 {
+	return state.requiredMinEchoRxInterval
+}
+
 func (state *PeerState) GetDetectMultiplier() uint8 {
 	return state.detectMultiplier
 }
@@ -41,6 +51,8 @@ func (state *PeerState) Clone(updates []PeerStateUpdate) *PeerState {
 		requiredMinRxInterval: state.requiredMinRxInterval,
 		detectMultiplier:      state.detectMultiplier,
 		demandMode:            state.demandMode,
+
+		requiredMinEchoRxInterval: state.requiredMinEchoRxInterval,
 	}
 
 	for _, update := range updates {
@@ -71,6 +83,12 @@ func setRequiredMinRxInterval(required uint32) PeerStateUpdate {
 	}
 }
 
+func setRequiredMinEchoRxInterval(required uint32) PeerStateUpdate {
+	return func(state *PeerState) {
+		state.requiredMinEchoRxInterval = required
+	}
+}
+
 func setDesiredMinTxInterval(desired uint32) PeerStateUpdate {
 	return func(state *PeerState) {
 		state.desiredMinTxInterval = desired
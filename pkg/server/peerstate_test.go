@@ -16,6 +16,8 @@ func NewState() *PeerState {
 		requiredMinRxInterval: 502891,
 		detectMultiplier:      20,
 		demandMode:            true,
+
+		requiredMinEchoRxInterval: 50000,
 	}
 }
 
@@ -38,6 +40,10 @@ func TestPeerStateGetters(t *testing.T) {
 		t.Fail()
 	}
 
+	if peerState.GetRequiredMinEchoRxInterval() != peerState.requiredMinEchoRxInterval {
+		t.Fail()
+	}
+
 }
 
 func TestPeerStateClone(t *testing.T) {
@@ -60,6 +66,16 @@ func TestPeerStateSetDetectMultiplier(t *testing.T) {
 	}
 }
 
+func TestPeerStateSetRequiredMinEchoRxInterval(t *testing.T) {
+	peerState := NewState()
+
+	clone := peerState.Clone([]PeerStateUpdate{setRequiredMinEchoRxInterval(0)})
+
+	if clone.requiredMinEchoRxInterval != 0 {
+		t.Fail()
+	}
+}
+
 func TestPeerStateSetRequiredMinRxInterval(t *testing.T) {
 	peerState := NewState()
 
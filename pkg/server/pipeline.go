@@ -0,0 +1,247 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/glog"
+
+	"github.com/Thoro/bfd/pkg/api"
+)
+
+const (
+	// packets queued per inbound worker, further packets are dropped and counted
+	INBOUND_QUEUE_LENGTH = 1024
+)
+
+/*
+	The listeners hand the received packets to a pool of workers, each with
+	a bounded queue of its own. A packet goes to the worker picked by the
+	hash of its source address and port, which stay the same for all packets
+	of a session (RFC5881 4), and of the interface for micro BFD. So the
+	packets of a session are handled in order, before and after it learned
+	the discriminator, while the sessions are spread over the workers. A listener never waits for a worker, packets
+	that don't fit into the queue are dropped and counted.
+
+	A session that is slow to handle its packets only delays the sessions
+	sharing its worker, the detection timers run on the scheduler regardless.
+*/
+
+var ErrInvalidInboundQueues = errors.New("Invalid inbound queues, the workers and queue length should not be negative")
+var ErrInboundQueuesStarted = errors.New("The inbound queues can't be changed once a listener is started")
+
+// inboundQueue holds the received packets of one worker
+type inboundQueue struct {
+	drops   uint64 // first for the alignment of the counter
+	packets chan packet
+}
+
+// pipeline spreads the received packets over the workers handling them
+type pipeline struct {
+	sync.Mutex
+
+	queues  []*inboundQueue
+	handle  func(packet) error
+	control chan struct{}
+	running bool
+	stopped bool
+}
+
+func newPipeline(workers, length int) *pipeline {
+	p := &pipeline{
+		queues:  make([]*inboundQueue, workers),
+		control: make(chan struct{}),
+	}
+
+	for i := range p.queues {
+		p.queues[i] = &inboundQueue{
+			packets: make(chan packet, length),
+		}
+	}
+
+	return p
+}
+
+// start runs handle for the queued packets, once per pipeline
+func (p *pipeline) start(handle func(packet) error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.running || p.stopped {
+		return
+	}
+
+	p.running = true
+	p.handle = handle
+
+	for _, q := range p.queues {
+		go p.work(q, handle)
+	}
+}
+
+func (p *pipeline) stop() {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.stopped {
+		return
+	}
+
+	p.stopped = true
+	close(p.control)
+
+	glog.Infof("Shutdown Incoming")
+}
+
+func (p *pipeline) isRunning() bool {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.running
+}
+
+func (p *pipeline) work(q *inboundQueue, handle func(packet) error) {
+	for {
+		select {
+		case pkt := <-q.packets:
+			err := handle(pkt)
+			pkt.release()
+
+			if err != nil {
+				glog.Infof("%s", err.Error())
+			}
+		case <-p.control:
+			return
+		}
+	}
+}
+
+// queue returns the queue of the worker handling a packet
+func (p *pipeline) queue(pkt *packet) *inboundQueue {
+	key := hashPacket(pkt)
+
+	// the upper bits of the product depend on all bits of the key, they pick the worker
+	index := uint64(key*2654435769) * uint64(len(p.queues)) >> 32
+
+	return p.queues[index]
+}
+
+// hashPacket is the FNV-1a hash of the source of a packet, with the interface for micro BFD
+func hashPacket(pkt *packet) uint32 {
+	hash := uint32(2166136261)
+
+	if pkt.addr != nil {
+		for _, b := range pkt.addr.IP.To16() {
+			hash = (hash ^ uint32(b)) * 16777619
+		}
+
+		hash = (hash ^ uint32(pkt.addr.Port>>8)) * 16777619
+		hash = (hash ^ uint32(pkt.addr.Port&0xff)) * 16777619
+	}
+
+	// the sessions of the LAG members may share the addresses and ports
+	if pkt.micro {
+		for shift := uint(0); shift < 32; shift += 8 {
+			hash = (hash ^ uint32(pkt.ifIndex>>shift&0xff)) * 16777619
+		}
+	}
+
+	return hash
+}
+
+// dispatch queues a packet for its worker, it's dropped and counted if the queue is full.
+// Without workers the packet is handled on the goroutine of the listener.
+func (p *pipeline) dispatch(pkt packet) bool {
+	if len(p.queues) == 0 {
+		return p.handleInline(pkt)
+	}
+
+	q := p.queue(&pkt)
+
+	select {
+	case q.packets <- pkt:
+		return true
+	default:
+		atomic.AddUint64(&q.drops, 1)
+		pkt.release()
+
+		return false
+	}
+}
+
+func (p *pipeline) handleInline(pkt packet) bool {
+	p.Lock()
+	handle := p.handle
+	stopped := p.stopped
+	p.Unlock()
+
+	defer pkt.release()
+
+	if handle == nil || stopped {
+		return false
+	}
+
+	if err := handle(pkt); err != nil {
+		glog.Infof("%s", err.Error())
+	}
+
+	return true
+}
+
+// SetInboundQueues sets the workers handling the received packets, 0 = number of CPUs, and the packets
+// queued per worker, 0 = 1024. It needs to be called before the first listener is started.
+func (s *BfdServer) SetInboundQueues(workers, length int) error {
+	if workers < 0 || length < 0 {
+		return ErrInvalidInboundQueues
+	}
+
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+
+	if length == 0 {
+		length = INBOUND_QUEUE_LENGTH
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.conns) > 0 || s.inbound.isRunning() {
+		return ErrInboundQueuesStarted
+	}
+
+	s.inbound = newPipeline(workers, length)
+
+	return nil
+}
+
+// ListInboundQueue calls cb for the queue of every worker handling received packets
+func (s *BfdServer) ListInboundQueue(ctx context.Context, cb func(*api.ListInboundQueueResponse) error) error {
+	s.RLock()
+	queues := s.inbound.queues
+	s.RUnlock()
+
+	for i, q := range queues {
+		err := cb(&api.ListInboundQueueResponse{
+			Worker:   uint32(i),
+			Depth:    uint32(len(q.packets)),
+			Capacity: uint32(cap(q.packets)),
+			Drops:    atomic.LoadUint64(&q.drops),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+
+	return nil
+}
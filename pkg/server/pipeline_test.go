@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+func pipelinePacket(your uint32, port int) packet {
+	return packet{
+		addr:   &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port},
+		packet: &bfd.ControlPacket{YourDiscriminator: your},
+	}
+}
+
+func TestPipelineQueue(t *testing.T) {
+	p := newPipeline(8, 1)
+
+	// the session learns the discriminator after its first packets
+	first := pipelinePacket(0, 49152)
+	second := pipelinePacket(42, 49152)
+
+	if p.queue(&first) != p.queue(&second) {
+		t.Errorf("Expected the packets of a source to share the worker")
+	}
+
+	used := map[*inboundQueue]bool{}
+
+	for port := 49152; port < 50152; port++ {
+		pkt := pipelinePacket(0, port)
+		used[p.queue(&pkt)] = true
+	}
+
+	if len(used) != 8 {
+		t.Errorf("Expected the sessions to be spread over all workers, got %d", len(used))
+	}
+}
+
+func TestPipelineQueueMicro(t *testing.T) {
+	p := newPipeline(8, 1)
+	used := map[*inboundQueue]bool{}
+
+	// the LAG members send from the same address and port
+	for i := 1; i <= 100; i++ {
+		pkt := pipelinePacket(0, 49152)
+		pkt.micro = true
+		pkt.ifIndex = i
+		used[p.queue(&pkt)] = true
+	}
+
+	if len(used) != 8 {
+		t.Errorf("Expected the members to be spread over all workers, got %d", len(used))
+	}
+}
+
+func TestPipelineDropsWhenFull(t *testing.T) {
+	p := newPipeline(1, 2)
+
+	for i := 0; i < 2; i++ {
+		if !p.dispatch(pipelinePacket(1, 49152)) {
+			t.Fatalf("Expected packet %d to be queued", i)
+		}
+	}
+
+	if p.dispatch(pipelinePacket(1, 49152)) {
+		t.Errorf("Expected the packet to be dropped")
+	}
+
+	if p.queues[0].drops != 1 || len(p.queues[0].packets) != 2 {
+		t.Errorf("Expected one drop and two queued packets, got %d and %d", p.queues[0].drops, len(p.queues[0].packets))
+	}
+}
+
+func TestPipelineSlowSession(t *testing.T) {
+	p := newPipeline(2, 8)
+	defer p.stop()
+
+	slow := pipelinePacket(1, 49152)
+	fast := pipelinePacket(2, 49153)
+
+	// find a session handled by the other worker
+	for fast.addr.Port++; p.queue(&fast) == p.queue(&slow); fast.addr.Port++ {
+	}
+
+	blocked := make(chan bool)
+	handled := make(chan uint32, 1)
+
+	p.start(func(pkt packet) error {
+		if pkt.packet.YourDiscriminator == slow.packet.YourDiscriminator {
+			<-blocked
+		}
+
+		handled <- pkt.packet.YourDiscriminator
+
+		return nil
+	})
+
+	p.dispatch(slow)
+	p.dispatch(fast)
+
+	select {
+	case d := <-handled:
+		if d != fast.packet.YourDiscriminator {
+			t.Errorf("Expected the packet of the other session, got %d", d)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("The slow session blocked the other worker")
+	}
+
+	close(blocked)
+	<-handled
+}
+
+func TestSetInboundQueues(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	if err := server.SetInboundQueues(-1, 10); err != ErrInvalidInboundQueues {
+		t.Errorf("Expected %v, got %v", ErrInvalidInboundQueues, err)
+	}
+
+	if err := server.SetInboundQueues(3, 10); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var responses []*api.ListInboundQueueResponse
+
+	server.inbound.dispatch(pipelinePacket(1, 49152))
+
+	err := server.ListInboundQueue(context.Background(), func(response *api.ListInboundQueueResponse) error {
+		responses = append(responses, response)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	depth := uint32(0)
+
+	for _, response := range responses {
+		depth += response.Depth
+
+		if response.Capacity != 10 {
+			t.Errorf("Expected a capacity of 10, got %d", response.Capacity)
+		}
+	}
+
+	if len(responses) != 3 || depth != 1 {
+		t.Errorf("Expected 3 queues holding 1 packet, got %v", responses)
+	}
+
+	server.conns["127.0.0.1:3784"] = &listener{}
+
+	if err := server.SetInboundQueues(3, 10); err != ErrInboundQueuesStarted {
+		t.Errorf("Expected %v, got %v", ErrInboundQueuesStarted, err)
+	}
+}
@@ -0,0 +1,507 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/glog"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+/*
+
+https://tools.ietf.org/html/rfc7880
+https://tools.ietf.org/html/rfc7881
+
+Seamless BFD skips the three-way handshake. The reflector answers every packet
+addressed to one of its discriminators without keeping any state, the initiator
+considers the target reachable as long as the answers arrive.
+
+*/
+
+const (
+	SBFD_PORT = 7784
+
+	// interval in µs of an initiator while the target isn't reachable
+	SBFD_DOWN_INTERVAL = 1000000
+)
+
+var ErrInvalidReflectorDiscriminator = errors.New("Invalid reflector discriminator, should not be 0")
+var ErrReflectorDiscriminatorExists = errors.New("Reflector discriminator already exists")
+var ErrReflectorDiscriminatorNotFound = errors.New("Unable to find reflector discriminator")
+var ErrInvalidRequiredMinRx = errors.New("Invalid Required Min RX Interval, should not be 0")
+var ErrInitiatorNotFound = errors.New("Unable to find initiator")
+
+// SbfdInitiator monitors the reachability of a reflector discriminator
+type SbfdInitiator struct {
+	sync.RWMutex
+
+	uuid []byte
+
+	Name                 string
+	Address              *net.UDPAddr
+	LocalAddress         net.IP
+	LocalDiscriminator   uint32
+	RemoteDiscriminator  uint32
+	DesiredMinTxInterval uint32 // µs
+	DetectMultiplier     uint8
+
+	state               bfd.SessionState
+	reflectorRxInterval uint32 // Required Min RX Interval of the reflector in µs
+
+	conn   io.ReadWriteCloser
+	rand   Rand
+	ticker *timer // timer for the packets to the reflector
+	expiry *timer // timer for the expiry of the answers
+}
+
+// ListenSbfdReflector starts a reflector, requiredMinRx (in µs) is advertised to the initiators
+func (s *BfdServer) ListenSbfdReflector(address string, requiredMinRx uint32) error {
+	if requiredMinRx == 0 {
+		return ErrInvalidRequiredMinRx
+	}
+
+	ip, zone, port, err := parseHostPort(address, SBFD_PORT)
+
+	if err != nil || port < 1 || port > 65535 {
+		return ErrInvalidPort
+	}
+
+	if ip == nil {
+		return ErrInvalidIP
+	}
+
+	conn, err := s.transport.ListenUDP("udp", &net.UDPAddr{IP: ip, Port: port, Zone: zone})
+
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	s.sbfdConns[address] = conn
+	s.Unlock()
+
+	s.readers.Add(1)
+	go s.reflectSbfdPackets(conn, requiredMinRx)
+
+	return nil
+}
+
+// AddSbfdReflector adds a discriminator the reflector answers to
+func (s *BfdServer) AddSbfdReflector(discriminator uint32) error {
+	if discriminator == 0 {
+		return ErrInvalidReflectorDiscriminator
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.sbfdReflectors[discriminator] {
+		return ErrReflectorDiscriminatorExists
+	}
+
+	s.sbfdReflectors[discriminator] = true
+
+	return nil
+}
+
+func (s *BfdServer) DeleteSbfdReflector(discriminator uint32) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.sbfdReflectors[discriminator] {
+		return ErrReflectorDiscriminatorNotFound
+	}
+
+	delete(s.sbfdReflectors, discriminator)
+
+	return nil
+}
+
+func (s *BfdServer) ListSbfdReflector(ctx context.Context, cb func(uint32) error) error {
+	s.RLock()
+	discriminators := make([]uint32, 0, len(s.sbfdReflectors))
+
+	for discriminator := range s.sbfdReflectors {
+		discriminators = append(discriminators, discriminator)
+	}
+	s.RUnlock()
+
+	for _, discriminator := range discriminators {
+		if err := cb(discriminator); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+
+	return nil
+}
+
+// reflectSbfdPackets answers the received packets of the initiators with the Required Min RX Interval of the listener
+func (s *BfdServer) reflectSbfdPackets(conn UDPConn, requiredMinRx uint32) {
+	defer s.readers.Done()
+
+	b := make([]byte, 256)
+
+	for {
+		n, addr, err := conn.ReadFromUDP(b)
+
+		if err != nil {
+			return
+		}
+
+		response, err := s.reflectSbfdPacket(b[:n], requiredMinRx)
+
+		if err != nil {
+			glog.Infof("%s", err.Error())
+			continue
+		}
+
+		// the answer goes back to the source port of the initiator
+		if _, err := conn.WriteToUDP(response, addr); err != nil {
+			glog.Infof("Error on S-BFD reflect: %s", err)
+		}
+	}
+}
+
+// reflectSbfdPacket builds the answer of the reflector to a received packet
+func (s *BfdServer) reflectSbfdPacket(raw []byte, requiredMinRx uint32) ([]byte, error) {
+	pkt := &bfd.ControlPacket{}
+
+	if err := pkt.UnmarshalBinary(raw); err != nil {
+		return nil, err
+	}
+
+	if err := checkPacket(pkt); err != nil {
+		return nil, ErrInvalidPacket
+	}
+
+	// authentication isn't supported for S-BFD
+	if pkt.GetAuthenticationType() != bfd.Reserved {
+		return nil, bfd.ErrInvalidAuthenticationType
+	}
+
+	s.RLock()
+	known := s.sbfdReflectors[pkt.YourDiscriminator]
+	s.RUnlock()
+
+	if !known {
+		return nil, ErrYourDiscriminatorNotFound
+	}
+
+	/*
+		RFC7880 7.2.2
+		The reflector swaps the discriminators, copies the Detect Mult and
+		Desired Min TX Interval of the initiator, sets the Final bit if the Poll
+		bit was set and advertises its own Required Min RX Interval. Echo
+		isn't used with S-BFD.
+	*/
+	response := &bfd.ControlPacket{
+		Version:                 1,
+		State:                   bfd.Up,
+		Final:                   pkt.Poll,
+		DetectMultiplier:        pkt.DetectMultiplier,
+		MyDiscriminator:         pkt.YourDiscriminator,
+		YourDiscriminator:       pkt.MyDiscriminator,
+		DesiredMinTxInterval:    pkt.DesiredMinTxInterval,
+		RequiredMinRxInterval:   requiredMinRx,
+		RequiredMinEchoInterval: 0,
+	}
+
+	return response.MarshalBinary()
+}
+
+// AddSbfdInitiator starts to monitor a reflector discriminator
+func (s *BfdServer) AddSbfdInitiator(api_initiator *api.SbfdInitiator) (*SbfdInitiator, error) {
+	if api_initiator.DetectMultiplier == 0 || api_initiator.DetectMultiplier > 255 {
+		return nil, ErrInvalidDetectionMultiplierSupplied
+	}
+
+	if api_initiator.RemoteDiscriminator == 0 {
+		return nil, ErrInvalidReflectorDiscriminator
+	}
+
+	address, zone, port, err := parseHostPort(api_initiator.Address, SBFD_PORT)
+
+	if err != nil || port < 1 || port > 65535 {
+		return nil, ErrInvalidPort
+	}
+
+	if address == nil {
+		return nil, ErrInvalidAddress
+	}
+
+	var localAddress net.IP
+
+	if api_initiator.LocalAddress != "" {
+		localAddress = net.ParseIP(api_initiator.LocalAddress)
+
+		if localAddress == nil {
+			return nil, ErrInvalidIP
+		}
+	}
+
+	id, _ := uuid.NewV4()
+
+	initiator := &SbfdInitiator{
+		uuid:                 id.Bytes(),
+		Name:                 api_initiator.Name,
+		Address:              &net.UDPAddr{IP: address, Port: port, Zone: zone},
+		LocalAddress:         localAddress,
+		LocalDiscriminator:   s.rand.Uint32(),
+		RemoteDiscriminator:  api_initiator.RemoteDiscriminator,
+		DesiredMinTxInterval: api_initiator.DesiredMinTxInterval * 1000,
+		DetectMultiplier:     uint8(api_initiator.DetectMultiplier),
+		state:                bfd.Down,
+		rand:                 s.rand,
+	}
+
+	shard := s.scheduler.shard()
+	initiator.ticker = s.scheduler.newTimer(shard, initiator.transmit)
+	initiator.expiry = s.scheduler.newTimer(shard, initiator.expire)
+
+	//  49152 through 65535
+	sourcePort := 49152 + int(s.rand.Intn(65535-49152))
+
+	conn, err := s.transport.DialUDP("udp", &net.UDPAddr{IP: localAddress, Port: sourcePort, Zone: zone}, initiator.Address)
+
+	if err != nil {
+		return nil, err
+	}
+
+	initiator.conn = conn
+
+	s.Lock()
+	s.sbfdInitiators[initiator.LocalDiscriminator] = initiator
+	s.Unlock()
+
+	initiator.Start()
+
+	return initiator, nil
+}
+
+func (s *BfdServer) GetSbfdInitiatorByUuid(uuid []byte) (*SbfdInitiator, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	for _, initiator := range s.sbfdInitiators {
+		if bytes.Equal(initiator.uuid, uuid) {
+			return initiator, nil
+		}
+	}
+
+	return nil, ErrInitiatorNotFound
+}
+
+func (s *BfdServer) DeleteSbfdInitiator(uuid []byte) error {
+	initiator, err := s.GetSbfdInitiatorByUuid(uuid)
+
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	delete(s.sbfdInitiators, initiator.LocalDiscriminator)
+	s.Unlock()
+
+	initiator.Shutdown()
+
+	return nil
+}
+
+func (s *BfdServer) ListSbfdInitiator(ctx context.Context, cb func([]byte, *api.SbfdInitiator, api.SessionState) error) error {
+	s.RLock()
+	initiators := make([]*SbfdInitiator, 0, len(s.sbfdInitiators))
+
+	for _, initiator := range s.sbfdInitiators {
+		initiators = append(initiators, initiator)
+	}
+	s.RUnlock()
+
+	for _, initiator := range initiators {
+		initiator.RLock()
+		localAddress := ""
+
+		if initiator.LocalAddress != nil {
+			localAddress = initiator.LocalAddress.String()
+		}
+
+		api_initiator := &api.SbfdInitiator{
+			Name:                 initiator.Name,
+			Address:              initiator.Address.String(),
+			RemoteDiscriminator:  initiator.RemoteDiscriminator,
+			DesiredMinTxInterval: initiator.DesiredMinTxInterval / 1000,
+			DetectMultiplier:     uint32(initiator.DetectMultiplier),
+			LocalAddress:         localAddress,
+		}
+		state := api.SessionState(initiator.state)
+		initiator.RUnlock()
+
+		if err := cb(initiator.uuid, api_initiator, state); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (i *SbfdInitiator) GetUuid() []byte {
+	return i.uuid
+}
+
+func (i *SbfdInitiator) GetState() bfd.SessionState {
+	i.RLock()
+	defer i.RUnlock()
+
+	return i.state
+}
+
+func (i *SbfdInitiator) Start() {
+	i.ticker.release()
+	i.expiry.release()
+	i.ticker.Reset(0)
+
+	go i.handleResponses()
+}
+
+func (i *SbfdInitiator) Shutdown() {
+	i.ticker.close()
+	i.expiry.close()
+	i.conn.Close()
+}
+
+// NewPacket creates the next packet sent to the reflector
+func (i *SbfdInitiator) NewPacket() *bfd.ControlPacket {
+	i.RLock()
+	defer i.RUnlock()
+
+	// the reflector keeps no state, so there is nothing to signal but Up
+	return &bfd.ControlPacket{
+		Version:               1,
+		State:                 bfd.Up,
+		DetectMultiplier:      i.DetectMultiplier,
+		MyDiscriminator:       i.LocalDiscriminator,
+		YourDiscriminator:     i.RemoteDiscriminator,
+		DesiredMinTxInterval:  i.DesiredMinTxInterval,
+		RequiredMinRxInterval: 0,
+	}
+}
+
+// txInterval returns the interval between packets, the lock needs to be held by the caller
+func (i *SbfdInitiator) txInterval() uint32 {
+	if i.state != bfd.Up {
+		return max(i.DesiredMinTxInterval, SBFD_DOWN_INTERVAL)
+	}
+
+	// the reflector limits how fast packets may be sent
+	return max(i.DesiredMinTxInterval, i.reflectorRxInterval)
+}
+
+// transmit sends the next packet to the reflector and schedules the one after, it's run by the ticker
+func (i *SbfdInitiator) transmit() {
+	packet, err := i.NewPacket().MarshalBinary()
+
+	if err == nil {
+		_, err = i.conn.Write(packet)
+	}
+
+	if err != nil {
+		glog.Infof("Error on S-BFD send to %s: %s", i.Address, err)
+	}
+
+	i.RLock()
+	interval := i.txInterval()
+	i.RUnlock()
+
+	// jittered like the periodic control packets, RFC5880 6.8.7
+	interval = interval - (interval * uint32(i.rand.Intn(25)) / 100)
+
+	i.ticker.Reset(time.Duration(interval) * time.Microsecond)
+}
+
+// expire marks the target as unreachable once the answers are missing, it's run by the expiry timer
+func (i *SbfdInitiator) expire() {
+	i.Lock()
+	defer i.Unlock()
+
+	if i.state == bfd.Up {
+		glog.Infof("S-BFD target %s (%d) is unreachable", i.Address, i.RemoteDiscriminator)
+	}
+
+	i.state = bfd.Down
+}
+
+// handleResponses reads the answers of the reflector until the connection is closed
+func (i *SbfdInitiator) handleResponses() {
+	b := make([]byte, 256)
+
+	for {
+		n, err := i.conn.Read(b)
+
+		if err != nil {
+			return
+		}
+
+		pkt := &bfd.ControlPacket{}
+
+		if err := pkt.UnmarshalBinary(b[:n]); err != nil {
+			glog.Infof("%s", err.Error())
+			continue
+		}
+
+		if err := i.handleResponse(pkt); err != nil {
+			glog.Infof("%s", err.Error())
+		}
+	}
+}
+
+// handleResponse updates the reachability of the target with an answer of the reflector
+func (i *SbfdInitiator) handleResponse(pkt *bfd.ControlPacket) error {
+	if err := checkPacket(pkt); err != nil {
+		return ErrInvalidPacket
+	}
+
+	i.Lock()
+	defer i.Unlock()
+
+	if pkt.YourDiscriminator != i.LocalDiscriminator || pkt.MyDiscriminator != i.RemoteDiscriminator {
+		return ErrYourDiscriminatorNotFound
+	}
+
+	// a reflector in AdminDown doesn't vouch for the path
+	if pkt.State != bfd.Up {
+		i.state = bfd.Down
+		return nil
+	}
+
+	if i.state != bfd.Up {
+		glog.Infof("S-BFD target %s (%d) is reachable", i.Address, i.RemoteDiscriminator)
+	}
+
+	i.state = bfd.Up
+	i.reflectorRxInterval = pkt.RequiredMinRxInterval
+
+	// the target is unreachable once Detect Mult answers are missing
+	i.expiry.Reset(time.Duration(uint32(i.DetectMultiplier)*i.txInterval()) * time.Microsecond)
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+func TestAddSbfdReflector(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	if err := server.AddSbfdReflector(0); err != ErrInvalidReflectorDiscriminator {
+		t.Errorf("Expected %v, got %v", ErrInvalidReflectorDiscriminator, err)
+	}
+
+	if err := server.AddSbfdReflector(7); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if err := server.AddSbfdReflector(7); err != ErrReflectorDiscriminatorExists {
+		t.Errorf("Expected %v, got %v", ErrReflectorDiscriminatorExists, err)
+	}
+
+	if err := server.DeleteSbfdReflector(7); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if err := server.DeleteSbfdReflector(7); err != ErrReflectorDiscriminatorNotFound {
+		t.Errorf("Expected %v, got %v", ErrReflectorDiscriminatorNotFound, err)
+	}
+}
+
+func TestReflectSbfdPacket(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	server.AddSbfdReflector(7)
+
+	raw, _ := (&bfd.ControlPacket{
+		Version:              1,
+		State:                bfd.Up,
+		Poll:                 bfd.Yes,
+		DetectMultiplier:     3,
+		MyDiscriminator:      60,
+		YourDiscriminator:    7,
+		DesiredMinTxInterval: 100000,
+	}).MarshalBinary()
+
+	response, err := server.reflectSbfdPacket(raw, 50000)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pkt := &bfd.ControlPacket{}
+
+	if err := pkt.UnmarshalBinary(response); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if pkt.MyDiscriminator != 7 || pkt.YourDiscriminator != 60 || pkt.State != bfd.Up || pkt.Final != bfd.Yes {
+		t.Errorf("Unexpected response %v", pkt)
+	}
+
+	if pkt.DetectMultiplier != 3 || pkt.DesiredMinTxInterval != 100000 || pkt.RequiredMinRxInterval != 50000 {
+		t.Errorf("Unexpected timers in response %v", pkt)
+	}
+
+	raw, _ = (&bfd.ControlPacket{
+		Version:           1,
+		State:             bfd.Up,
+		DetectMultiplier:  3,
+		MyDiscriminator:   60,
+		YourDiscriminator: 8,
+	}).MarshalBinary()
+
+	if _, err := server.reflectSbfdPacket(raw, 50000); err != ErrYourDiscriminatorNotFound {
+		t.Errorf("Expected %v, got %v", ErrYourDiscriminatorNotFound, err)
+	}
+}
+
+func TestSbfdInitiatorHandleResponse(t *testing.T) {
+	initiator := &SbfdInitiator{
+		LocalDiscriminator:   60,
+		RemoteDiscriminator:  7,
+		DesiredMinTxInterval: 100000,
+		DetectMultiplier:     3,
+		state:                bfd.Down,
+		expiry:               sharedScheduler().newTimer(0, func() {}),
+	}
+
+	response := &bfd.ControlPacket{
+		Version:               1,
+		State:                 bfd.Up,
+		DetectMultiplier:      3,
+		MyDiscriminator:       8,
+		YourDiscriminator:     60,
+		RequiredMinRxInterval: 300000,
+	}
+
+	if err := initiator.handleResponse(response); err != ErrYourDiscriminatorNotFound {
+		t.Errorf("Expected %v, got %v", ErrYourDiscriminatorNotFound, err)
+	}
+
+	response.MyDiscriminator = 7
+
+	if err := initiator.handleResponse(response); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if initiator.GetState() != bfd.Up || initiator.txInterval() != 300000 {
+		t.Errorf("Expected the target to be reachable at the interval of the reflector")
+	}
+
+	response.State = bfd.AdminDown
+
+	if err := initiator.handleResponse(response); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if initiator.GetState() != bfd.Down {
+		t.Errorf("Expected a reflector in AdminDown to take the session down")
+	}
+}
+
+// advanceToSbfdState advances the network until the initiator is in state, the initiators
+// read their answers once their goroutines ran
+func advanceToSbfdState(network *VirtualNetwork, initiator *SbfdInitiator, state bfd.SessionState) bool {
+	for i := 0; i < 50; i++ {
+		if initiator.GetState() == state {
+			return true
+		}
+
+		network.Advance(100 * time.Millisecond)
+		runtime.Gosched()
+	}
+
+	return initiator.GetState() == state
+}
+
+func TestSbfdInitiatorAndReflector(t *testing.T) {
+	network := NewVirtualNetwork(1)
+	server, err := network.NewServer("10.0.0.1")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer server.Shutdown()
+
+	if err := server.ListenSbfdReflector("10.0.0.1:7784", 10000); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	server.AddSbfdReflector(7)
+
+	initiator, err := server.AddSbfdInitiator(&api.SbfdInitiator{
+		Address:              "10.0.0.1:7784",
+		RemoteDiscriminator:  7,
+		DesiredMinTxInterval: 10,
+		DetectMultiplier:     3,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if !advanceToSbfdState(network, initiator, bfd.Up) {
+		t.Fatalf("Expected the target to be reachable")
+	}
+
+	// without the discriminator the reflector stops answering
+	server.DeleteSbfdReflector(7)
+
+	if !advanceToSbfdState(network, initiator, bfd.Down) {
+		t.Fatalf("Expected the target to be unreachable")
+	}
+}
+
+func TestSbfdReflectorsRequiredMinRx(t *testing.T) {
+	network := NewVirtualNetwork(1)
+
+	reflector, err := network.NewServer("10.0.0.1")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer reflector.Shutdown()
+
+	initiators, err := network.NewServer("10.0.0.2")
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer initiators.Shutdown()
+
+	// every listener advertises its own interval
+	if err := reflector.ListenSbfdReflector("10.0.0.1:7784", 300000); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := reflector.ListenSbfdReflector("10.0.0.1:7785", 500000); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	reflector.AddSbfdReflector(7)
+
+	expected := map[string]uint32{
+		"10.0.0.1:7784": 300000,
+		"10.0.0.1:7785": 500000,
+	}
+
+	started := map[string]*SbfdInitiator{}
+
+	for address := range expected {
+		initiator, err := initiators.AddSbfdInitiator(&api.SbfdInitiator{
+			Address:              address,
+			RemoteDiscriminator:  7,
+			DesiredMinTxInterval: 10,
+			DetectMultiplier:     3,
+		})
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		started[address] = initiator
+	}
+
+	for address, initiator := range started {
+		advanceToSbfdState(network, initiator, bfd.Up)
+
+		initiator.RLock()
+		state, interval := initiator.state, initiator.txInterval()
+		initiator.RUnlock()
+
+		if state != bfd.Up || interval != expected[address] {
+			t.Errorf("Expected %s to be reachable at %d µs, got %v at %d µs", address, expected[address], state, interval)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package server
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// resolution of the timer wheels, timers run up to one tick late but never early,
+	// a finer tick only adds wake ups as the clock doesn't wake up much more precisely
+	SCHEDULER_TICK = time.Millisecond
+
+	// slots of a timer wheel, a power of two, one revolution takes about 4 seconds
+	SCHEDULER_SLOTS = 1 << 12
+)
+
+/*
+	The timers of all sessions (transmission, detection, echo) are run by a
+	scheduler instead of a goroutine and a set of time.Timers per session.
+
+	The scheduler is split into shards, every shard is a hashed timer wheel
+	with its own lock and worker: every slot holds the timers expiring in
+	one tick, later timers wrap around and stay in their slot until the
+	wheel reaches their tick. Resetting a timer, which happens for every
+	received packet, only moves it between two lists of its shard. The
+	worker is woken by the clock at the next occupied slot and runs the
+	expired timers itself, without the scheduler having to hand them over.
+	Without workers the timers are run by the goroutine of the clock.
+
+	All timers of a session belong to the same shard and are run by the
+	same worker one after the other, so the handlers of a session never
+	run concurrently, and sessions on different shards never contend for
+	a lock of the scheduler.
+*/
+
+// scheduler runs the timers of sessions on a pool of workers, one per shard
+type scheduler struct {
+	clock   Clock
+	epoch   time.Time // deadlines and ticks are counted from
+	shards  []*wheel
+	next    uint32 // shard of the next session
+	inline  bool   // the timers are run by the goroutine of the clock
+	control chan struct{}
+	stopped sync.Once
+}
+
+// wheel is the timer wheel of one shard
+type wheel struct {
+	sync.Mutex
+
+	s       *scheduler
+	slots   [SCHEDULER_SLOTS]*timer
+	current int64 // next tick to process
+	count   int   // scheduled timers
+	wake    int64 // tick the wheel sleeps until
+	wait    ClockTimer
+	running bool          // timers are being expired, the next wake up is set once done
+	expired []job         // reused by the running expiry
+	signal  chan struct{} // wakes the worker, nil if the timers are run by the goroutine of the clock
+}
+
+// job is an expired timer, it's skipped if the timer changed since
+type job struct {
+	t   *timer
+	seq uint64
+}
+
+type timer struct {
+	w *wheel
+	f func()
+
+	when      int64 // nanoseconds since the epoch of the scheduler
+	tick      int64 // tick of the wheel the timer runs in
+	prev      *timer
+	next      *timer
+	scheduled bool
+	seq       uint64 // incremented on every change, outdated jobs aren't run
+	held      bool   // not scheduled until released, a reset only stores the deadline
+	armed     bool   // reset while held
+	closed    bool   // never scheduled again
+}
+
+var defaultScheduler *scheduler
+var defaultSchedulerOnce sync.Once
+
+// sharedScheduler returns the scheduler of sessions that aren't part of a server
+func sharedScheduler() *scheduler {
+	defaultSchedulerOnce.Do(func() {
+		defaultScheduler = newScheduler(systemClock{}, runtime.NumCPU())
+	})
+
+	return defaultScheduler
+}
+
+// newScheduler starts a scheduler on clock with the given number of workers, with
+// 0 workers the timers are run by the goroutine of the clock
+func newScheduler(clock Clock, workers int) *scheduler {
+	s := &scheduler{
+		clock:   clock,
+		epoch:   clock.Now(),
+		inline:  workers <= 0,
+		control: make(chan struct{}),
+	}
+
+	if s.inline {
+		workers = 1
+	}
+
+	s.shards = make([]*wheel, workers)
+
+	for i := range s.shards {
+		w := &wheel{
+			s:    s,
+			wake: SCHEDULER_SLOTS,
+		}
+
+		if s.inline {
+			w.wait = clock.AfterFunc(time.Duration(w.wake)*SCHEDULER_TICK, w.expire)
+		} else {
+			w.signal = make(chan struct{}, 1)
+			w.wait = clock.AfterFunc(time.Duration(w.wake)*SCHEDULER_TICK, w.notify)
+
+			go w.work()
+		}
+
+		s.shards[i] = w
+	}
+
+	return s
+}
+
+// workers returns the number of workers, 0 if the timers are run by the goroutine of the clock
+func (s *scheduler) workers() int {
+	if s.inline {
+		return 0
+	}
+
+	return len(s.shards)
+}
+
+// stop ends the scheduler, timers that are still scheduled never run
+func (s *scheduler) stop() {
+	s.stopped.Do(func() {
+		close(s.control)
+
+		for _, w := range s.shards {
+			w.Lock()
+			w.wait.Stop()
+			w.Unlock()
+		}
+	})
+}
+
+// shard returns the shard for the timers of a new session, sessions are spread over the workers
+func (s *scheduler) shard() int {
+	return int((atomic.AddUint32(&s.next, 1) - 1) % uint32(len(s.shards)))
+}
+
+// newTimer returns a held timer that runs f on the worker of shard
+func (s *scheduler) newTimer(shard int, f func()) *timer {
+	return &timer{
+		w:    s.shards[shard%len(s.shards)],
+		f:    f,
+		held: true,
+	}
+}
+
+func (s *scheduler) now() int64 {
+	return int64(s.clock.Now().Sub(s.epoch))
+}
+
+// isStopped returns true once the scheduler is stopped
+func (s *scheduler) isStopped() bool {
+	select {
+	case <-s.control:
+		return true
+	default:
+		return false
+	}
+}
+
+// notify wakes the worker of the wheel, it's called by the clock at the next occupied slot
+func (w *wheel) notify() {
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (w *wheel) work() {
+	for {
+		select {
+		case <-w.signal:
+			w.expire()
+		case <-w.s.control:
+			return
+		}
+	}
+}
+
+// expire runs the timers of the elapsed ticks
+func (w *wheel) expire() {
+	w.Lock()
+
+	// a wake up while running, the running expiry picks up the timers
+	if w.running || w.s.isStopped() {
+		w.Unlock()
+		return
+	}
+
+	w.running = true
+
+	for {
+		now := w.s.now() / int64(SCHEDULER_TICK)
+		expired := w.expired[:0]
+
+		for ; w.current <= now && w.count > 0; w.current++ {
+			for t := w.slots[w.current&(SCHEDULER_SLOTS-1)]; t != nil; {
+				next := t.next
+
+				if t.tick <= w.current {
+					w.unschedule(t)
+					expired = append(expired, job{t: t, seq: t.seq})
+				}
+
+				t = next
+			}
+		}
+
+		w.expired = expired
+
+		if len(expired) == 0 || w.s.isStopped() {
+			break
+		}
+
+		w.Unlock()
+
+		for _, j := range expired {
+			if w.isCurrent(j) {
+				j.t.f()
+			}
+		}
+
+		// the handlers may take long enough for the next timers to expire
+		w.Lock()
+	}
+
+	w.running = false
+
+	// an empty wheel catches up, otherwise it would wake up right away
+	if now := w.s.now() / int64(SCHEDULER_TICK); w.count == 0 && w.current < now {
+		w.current = now
+	}
+
+	w.wake = w.nextTick()
+
+	if !w.s.isStopped() {
+		w.wait.Reset(time.Duration(w.wake)*SCHEDULER_TICK - time.Duration(w.s.now()))
+	}
+	w.Unlock()
+}
+
+// nextTick returns the tick of the next occupied slot, the lock needs to be held by the caller
+func (w *wheel) nextTick() int64 {
+	if w.count > 0 {
+		for tick := w.current; tick < w.current+SCHEDULER_SLOTS; tick++ {
+			if w.slots[tick&(SCHEDULER_SLOTS-1)] != nil {
+				return tick
+			}
+		}
+	}
+
+	return w.current + SCHEDULER_SLOTS
+}
+
+// isCurrent returns false if the timer of a job was reset, stopped or closed after it expired
+func (w *wheel) isCurrent(j job) bool {
+	w.Lock()
+	defer w.Unlock()
+
+	return j.t.seq == j.seq && !j.t.closed
+}
+
+// schedule adds or moves a timer in the wheel, the lock needs to be held by the caller
+func (w *wheel) schedule(t *timer) {
+	w.unschedule(t)
+
+	// the wheel only turns while it holds timers, an empty one catches up at once
+	if now := w.s.now() / int64(SCHEDULER_TICK); w.count == 0 && w.current < now {
+		w.current = now
+	}
+
+	t.tick = (t.when + int64(SCHEDULER_TICK) - 1) / int64(SCHEDULER_TICK)
+
+	if t.tick < w.current {
+		t.tick = w.current
+	}
+
+	slot := &w.slots[t.tick&(SCHEDULER_SLOTS-1)]
+	t.prev = nil
+	t.next = *slot
+
+	if *slot != nil {
+		(*slot).prev = t
+	}
+
+	*slot = t
+	t.scheduled = true
+	w.count++
+
+	// while running, the next wake up is set once the expired timers are run
+	if t.tick < w.wake && !w.running {
+		w.wake = t.tick
+		w.wait.Reset(time.Duration(t.tick)*SCHEDULER_TICK - time.Duration(w.s.now()))
+	}
+}
+
+// unschedule removes a timer from the wheel, the lock needs to be held by the caller
+func (w *wheel) unschedule(t *timer) {
+	if !t.scheduled {
+		return
+	}
+
+	if t.prev != nil {
+		t.prev.next = t.next
+	} else {
+		w.slots[t.tick&(SCHEDULER_SLOTS-1)] = t.next
+	}
+
+	if t.next != nil {
+		t.next.prev = t.prev
+	}
+
+	t.prev = nil
+	t.next = nil
+	t.scheduled = false
+	w.count--
+}
+
+// Reset runs the timer after d, replacing an earlier deadline
+func (t *timer) Reset(d time.Duration) {
+	t.w.Lock()
+	defer t.w.Unlock()
+
+	if t.closed {
+		return
+	}
+
+	t.seq++
+	t.when = t.w.s.now() + int64(d)
+
+	if t.held {
+		t.armed = true
+		return
+	}
+
+	t.w.schedule(t)
+}
+
+// Stop prevents the timer from running until it's reset
+func (t *timer) Stop() {
+	t.w.Lock()
+	defer t.w.Unlock()
+
+	t.seq++
+	t.armed = false
+	t.w.unschedule(t)
+}
+
+// release schedules a held timer, with the deadline of the last reset if there was one
+func (t *timer) release() {
+	t.w.Lock()
+	defer t.w.Unlock()
+
+	if !t.held || t.closed {
+		return
+	}
+
+	t.held = false
+
+	if t.armed {
+		t.armed = false
+		t.w.schedule(t)
+	}
+}
+
+// close stops the timer for good, later resets are ignored
+func (t *timer) close() {
+	t.w.Lock()
+	defer t.w.Unlock()
+
+	t.seq++
+	t.closed = true
+	t.w.unschedule(t)
+}
+
+// deadline returns when the timer runs next, or ran last
+func (t *timer) deadline() time.Time {
+	t.w.Lock()
+	defer t.w.Unlock()
+
+	return t.w.s.epoch.Add(time.Duration(t.when))
+}
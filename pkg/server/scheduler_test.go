@@ -0,0 +1,316 @@
+package server
+
+import (
+	"math/rand"
+	"net"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+func TestSchedulerRunsTimersInOrder(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(0, 0))
+	s := newScheduler(clock, 0)
+	defer s.stop()
+
+	var order []int
+	shard := s.shard()
+
+	for i, d := range []time.Duration{30, 10, 20} {
+		i := i
+		timer := s.newTimer(shard, func() { order = append(order, i) })
+		timer.release()
+		timer.Reset(d * time.Millisecond)
+	}
+
+	clock.Advance(15 * time.Millisecond)
+
+	if len(order) != 1 || order[0] != 1 {
+		t.Errorf("Expected only timer 1 to run, got %v", order)
+	}
+
+	clock.Advance(15 * time.Millisecond)
+
+	if len(order) != 3 || order[1] != 2 || order[2] != 0 {
+		t.Errorf("Expected the timers to run in order, got %v", order)
+	}
+}
+
+func TestTimerResetAndStop(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(0, 0))
+	s := newScheduler(clock, 0)
+	defer s.stop()
+
+	runs := 0
+
+	timer := s.newTimer(s.shard(), func() { runs++ })
+	timer.release()
+
+	// only the last deadline counts
+	timer.Reset(time.Millisecond)
+	timer.Reset(5 * time.Millisecond)
+	clock.Advance(2 * time.Millisecond)
+
+	if runs != 0 {
+		t.Errorf("Expected the earlier deadline to be replaced, got %d runs", runs)
+	}
+
+	clock.Advance(3 * time.Millisecond)
+
+	if runs != 1 {
+		t.Errorf("Expected one run, got %d", runs)
+	}
+
+	timer.Reset(time.Millisecond)
+	timer.Stop()
+	clock.Advance(10 * time.Millisecond)
+
+	if runs != 1 {
+		t.Errorf("Expected a stopped timer not to run, got %d runs", runs)
+	}
+}
+
+func TestTimerHeldAndClosed(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(0, 0))
+	s := newScheduler(clock, 0)
+	defer s.stop()
+
+	runs := 0
+
+	timer := s.newTimer(s.shard(), func() { runs++ })
+	timer.Reset(0)
+	clock.Advance(10 * time.Millisecond)
+
+	if runs != 0 {
+		t.Fatalf("Expected a held timer not to run, got %d runs", runs)
+	}
+
+	// the deadline of the reset while held is kept
+	timer.release()
+	clock.Advance(time.Millisecond)
+
+	if runs != 1 {
+		t.Fatalf("Expected the released timer to run, got %d runs", runs)
+	}
+
+	timer.close()
+	timer.Reset(0)
+	clock.Advance(10 * time.Millisecond)
+
+	if runs != 1 {
+		t.Errorf("Expected a closed timer not to run, got %d runs", runs)
+	}
+}
+
+func TestSchedulerShardRunsSequentially(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(0, 0))
+	s := newScheduler(clock, 4)
+	defer s.stop()
+
+	var running, overlaps, runs int32
+
+	shard := s.shard()
+	done := make(chan struct{})
+
+	for i := 0; i < 50; i++ {
+		timer := s.newTimer(shard, func() {
+			if atomic.AddInt32(&running, 1) > 1 {
+				atomic.AddInt32(&overlaps, 1)
+			}
+
+			// give the other workers a chance to run a timer of the shard meanwhile
+			runtime.Gosched()
+			atomic.AddInt32(&running, -1)
+
+			if atomic.AddInt32(&runs, 1) == 50 {
+				close(done)
+			}
+		})
+
+		timer.release()
+		timer.Reset(time.Duration(i%5) * time.Millisecond)
+	}
+
+	// the clock wakes the worker of the shard, which runs the timers on its own goroutine
+	clock.Advance(5 * time.Millisecond)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+	}
+
+	if atomic.LoadInt32(&runs) != 50 || atomic.LoadInt32(&overlaps) != 0 {
+		t.Errorf("Expected 50 sequential runs, got %d runs and %d overlaps", runs, overlaps)
+	}
+}
+
+func TestSchedulerShardsRunIndependently(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(0, 0))
+	s := newScheduler(clock, 2)
+	defer s.stop()
+
+	block := make(chan struct{})
+	defer close(block)
+
+	ran := make(chan struct{}, 1)
+
+	blocked := s.newTimer(s.shard(), func() { <-block })
+	blocked.release()
+	blocked.Reset(0)
+
+	// the other shard has its own wheel and worker
+	timer := s.newTimer(s.shard(), func() { ran <- struct{}{} })
+	timer.release()
+	timer.Reset(5 * time.Millisecond)
+
+	clock.Advance(5 * time.Millisecond)
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected a timer of another shard to run while one shard is blocked")
+	}
+}
+
+func cpuTime() time.Duration {
+	var usage syscall.Rusage
+	syscall.Getrusage(syscall.RUSAGE_SELF, &usage)
+
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
+
+// BenchmarkScheduler20kSessions runs 20000 Up sessions at 50 ms, one operation is one
+// transmission interval. It reports how late the transmit timers ran, the packets sent per
+// second and the CPU used, and fails if the timers fall behind.
+func BenchmarkScheduler20kSessions(b *testing.B) {
+	const sessions = 20000
+	const interval = 50000 // µs
+
+	// the transmit timers need to keep up with the sessions
+	const maxLateness = 5 * time.Millisecond
+	const minThroughput = 0.9
+
+	s := newScheduler(systemClock{}, runtime.NumCPU())
+	defer s.stop()
+
+	// lateness histogram with buckets of 100 µs, the last one holds everything later
+	var buckets [1000]uint64
+	var maxLate int64
+
+	peers := make([]*Peer, sessions)
+
+	for i := range peers {
+		p, err := newPeer(net.ParseIP("127.0.0.1"), BFD_PORT, s, globalRand{})
+
+		if err != nil {
+			b.Fatalf("%v", err)
+		}
+
+		p.conn = &discardConn{}
+		p.Interval = interval
+
+		state := []PeerStateUpdate{
+			setSessionState(bfd.Up),
+			setDiscriminator(uint32(i + 1)),
+			setDesiredMinTxInterval(interval),
+			setRequiredMinRxInterval(interval),
+			setDetectMultiplier(3),
+		}
+
+		p.ApplyLocalState(state)
+		p.ApplyRemoteState(state)
+
+		ticker := p.ticker
+		ticker.f = func() {
+			late := time.Since(ticker.deadline())
+			bucket := int(late / (100 * time.Microsecond))
+
+			if bucket >= len(buckets) {
+				bucket = len(buckets) - 1
+			}
+
+			atomic.AddUint64(&buckets[bucket], 1)
+
+			for {
+				max := atomic.LoadInt64(&maxLate)
+
+				if int64(late) <= max || atomic.CompareAndSwapInt64(&maxLate, max, int64(late)) {
+					break
+				}
+			}
+
+			// the remote answers every packet, which moves the detection timer
+			p.scheduleExpiry(3 * interval)
+			p.transmit()
+		}
+
+		peers[i] = p
+	}
+
+	for _, p := range peers {
+		p.scheduleSend(uint32(rand.Intn(interval)))
+		p.Start()
+	}
+
+	// let the sessions spread out over the timer wheels before measuring
+	time.Sleep(20 * interval * time.Microsecond)
+
+	for i := range buckets {
+		atomic.StoreUint64(&buckets[i], 0)
+	}
+
+	atomic.StoreInt64(&maxLate, 0)
+
+	cpu := cpuTime()
+	start := time.Now()
+
+	b.ResetTimer()
+	time.Sleep(time.Duration(b.N) * interval * time.Microsecond)
+	b.StopTimer()
+
+	used := cpuTime() - cpu
+	elapsed := time.Since(start)
+
+	for _, p := range peers {
+		p.Shutdown()
+	}
+
+	var total, runs uint64
+
+	for i := range buckets {
+		total += atomic.LoadUint64(&buckets[i])
+	}
+
+	// upper bound of the bucket the 99th percentile falls into
+	var p99 time.Duration
+
+	for i := range buckets {
+		runs += atomic.LoadUint64(&buckets[i])
+
+		if runs*100 >= total*99 {
+			p99 = time.Duration(i+1) * 100 * time.Microsecond
+			break
+		}
+	}
+
+	// the interval is reduced by 0 - 25 % of jitter, 12.5 % on average
+	throughput := float64(total) / elapsed.Seconds()
+	expected := sessions / (interval * 0.875 / 1e6)
+
+	b.ReportMetric(throughput, "tx/s")
+	b.ReportMetric(float64(p99/time.Microsecond), "p99-late-µs")
+	b.ReportMetric(float64(atomic.LoadInt64(&maxLate))/float64(time.Microsecond), "max-late-µs")
+	b.ReportMetric(100*used.Seconds()/elapsed.Seconds(), "cpu-%")
+
+	if p99 > maxLateness {
+		b.Errorf("Expected the transmit timers to run at most %v late, the 99th percentile was %v late", maxLateness, p99)
+	}
+
+	if throughput < minThroughput*expected {
+		b.Errorf("Expected about %.0f packets per second, got %.0f", expected, throughput)
+	}
+}
@@ -4,15 +4,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
-	"math/rand"
 	"net"
+	"runtime"
 	"strconv"
-	"strings"
 	"sync"
-	"syscall"
+	"time"
 
 	"github.com/golang/glog"
-	"golang.org/x/net/ipv4"
 
 	"github.com/Thoro/bfd/pkg/api"
 	"github.com/Thoro/bfd/pkg/packet/bfd"
@@ -22,25 +20,58 @@ import (
 
 https://tools.ietf.org/html/rfc5880
 https://tools.ietf.org/html/rfc5881
+https://tools.ietf.org/html/rfc5883
 
 */
 
 const (
-	BFD_PORT = 3784
+	BFD_PORT          = 3784
+	BFD_MULTIHOP_PORT = 4784
+
+	// largest UDP payload of IPv4, the limit of padded packets
+	MAX_PAD_TO_SIZE = 65507
 )
 
 type BfdServer struct {
 	sync.RWMutex
 
-	Sessions map[uint32]*Peer
+	Sessions      map[uint32]*Peer
+	peerAddresses map[string]int // sessions per remote address, echo packets are only reflected to them
+
+	keyChains map[string]*KeyChain
+
+	dynamicRanges []*DynamicRange
+	dynamicExpiry ClockTimer // removes idle dynamic sessions, nil until a range or unsolicited interface is added
+	unsolicited   map[string]*UnsolicitedInterface
+
+	echoConns         map[string]*echoListener
+	echoRequiredMinRx uint32 // advertised Required Min Echo RX Interval, 0 = no reflector
+
+	sbfdConns      map[string]UDPConn
+	sbfdReflectors map[uint32]bool
+	sbfdInitiators map[uint32]*SbfdInitiator
+
+	lags      map[string]*Lag
+	microPort int // port of the micro BFD listeners, 0 picks a free one for all of them
 
 	conns map[string]*listener
 
-	inbound  chan packet
+	batchSize       int                     // packets per recvmmsg / sendmmsg, 0 = a single packet per syscall
+	listenerSockets int                     // sockets per listener sharing the port with SO_REUSEPORT
+	sharedSender    bool                    // the sessions write through a shared socket with sendmmsg
+	senders         map[string]*batchSender // sockets shared by the sessions for batched writes
+
+	inbound  *pipeline // spreads the received packets over the workers handling them
 	outbound chan packet
 
-	control chan bool
-	dialUDP func(network string, laddr, raddr *net.UDPAddr) (*net.UDPConn, error)
+	scheduler *scheduler // runs the timers of all sessions
+
+	clock     Clock
+	rand      Rand
+	transport Transport
+
+	readers sync.WaitGroup // goroutines reading the sockets of the listeners and reflectors
+	stopped bool
 }
 
 var ErrInvalidDetectionMultiplierSupplied = errors.New("Invalid Detection Multiplier supplied")
@@ -54,15 +85,59 @@ var ErrInvalidPacket = errors.New("Discarded Packet: Packet invalid")
 var ErrYourDiscriminatorNotFound = errors.New("Discarded Packet: YourDiscriminator not found")
 var ErrInvalidTTL = errors.New("Invalid TTL received")
 var ErrInvalidIP = errors.New("Invalid IP passed")
+var ErrInvalidMinTTL = errors.New("Invalid minimum TTL, should be between 0 and 255")
+var ErrEchoMultiHop = errors.New("The echo function can't be used with multi hop sessions")
+var ErrSessionTypeMismatch = errors.New("Discarded Packet: Single / multi hop / micro BFD / VXLAN doesn't match the session")
+var ErrInvalidPadToSize = errors.New("Invalid padding size, should not exceed 65507 bytes")
+var ErrDontFragmentNotSupported = errors.New("Disabling fragmentation isn't supported on this platform")
+var ErrAddressMismatch = errors.New("Discarded Packet: Source, destination or interface doesn't match the session")
 
 type packet struct {
-	addr   *net.UDPAddr
-	packet *bfd.ControlPacket
+	addr       *net.UDPAddr
+	packet     *bfd.ControlPacket
+	raw        []byte // received bytes, needed to verify authentication digests
+	dst        net.IP // local address the packet was received on, nil if unknown
+	ifIndex    int    // interface the packet was received on, 0 if unknown
+	ttl        uint8
+	multiHop   bool // received on the multi hop port
+	micro      bool // received on the micro BFD port of a LAG member
+	vxlan      bool // decapsulated from VXLAN
+	vni        uint32
+	violations bfd.Violations // found in the received bytes, the decoded fields are checked again
+	buf        *packetBuffer  // storage of packet and raw, nil if they weren't read by a listener
+}
+
+// packetBuffer holds a received packet until it's handled, it's reused for later packets afterwards
+type packetBuffer struct {
+	control bfd.ControlPacket
+	raw     [bfd.MAXIMUM_LENGTH]byte
+	dst     [net.IPv6len]byte
+}
+
+var packetBuffers = sync.Pool{
+	New: func() interface{} {
+		return &packetBuffer{}
+	},
+}
+
+// release returns the storage of a handled packet, it must not be used afterwards
+func (p *packet) release() {
+	if p.buf != nil {
+		packetBuffers.Put(p.buf)
+	}
 }
 
 type listener struct {
-	conn    Connection
-	control chan bool
+	violations violationCounters // first for the alignment of the counters
+
+	conn     Connection
+	sockets  []UDPConn // every socket of the listener, closed on shutdown
+	local    *net.UDPAddr
+	ifIndex  int // interface the listener is bound to, 0 = any
+	multiHop bool
+	micro    bool
+	vxlan    bool
+	macs     []net.HardwareAddr // of the local VTEP, VXLAN packets may be addressed to them
 }
 
 func max(v1, v2 uint32) uint32 {
@@ -83,61 +158,183 @@ func min(v1, v2 uint32) uint32 {
 
 func NewBfdServer() *BfdServer {
 	s := &BfdServer{
-		dialUDP:  net.DialUDP,
-		Sessions: make(map[uint32]*Peer, 0),
-		inbound:  make(chan packet, 5),
-		outbound: make(chan packet, 5),
-		control:  make(chan bool, 1),
-		conns:    make(map[string]*listener, 0),
+		Sessions:      make(map[uint32]*Peer, 0),
+		peerAddresses: make(map[string]int, 0),
+		keyChains:     make(map[string]*KeyChain, 0),
+		echoConns:     make(map[string]*echoListener, 0),
+		sbfdConns:     make(map[string]UDPConn, 0),
+		inbound:       newPipeline(runtime.NumCPU(), INBOUND_QUEUE_LENGTH),
+		outbound:      make(chan packet, 5),
+		conns:         make(map[string]*listener, 0),
+		senders:       make(map[string]*batchSender, 0),
+		scheduler:     newScheduler(systemClock{}, runtime.NumCPU()),
+		clock:         systemClock{},
+		rand:          globalRand{},
+		transport:     kernelTransport{},
+
+		sbfdReflectors: make(map[uint32]bool, 0),
+		sbfdInitiators: make(map[uint32]*SbfdInitiator, 0),
+
+		lags:        make(map[string]*Lag, 0),
+		unsolicited: make(map[string]*UnsolicitedInterface, 0),
+		microPort:   BFD_MICRO_PORT,
 	}
 
 	return s
 }
 
 func (s *BfdServer) AddPeer(api_peer *api.Peer) (*Peer, error) {
-	var address string
+	return s.addPeer(api_peer, "")
+}
+
+// addPeer creates a session, member is the interface of a micro BFD session and empty for all others
+func (s *BfdServer) addPeer(api_peer *api.Peer, member string) (*Peer, error) {
 	var err error
 
 	if api_peer.DetectMultiplier == 0 {
 		return nil, ErrInvalidDetectionMultiplierSupplied
 	}
 
-	// create a random descriptor
-	discriminator := rand.Uint32()
-	//  49152 through 65535
-	sourcePort := 49152 + int(rand.Intn(65535-49152))
+	if api_peer.MinTtl > 255 {
+		return nil, ErrInvalidMinTTL
+	}
 
-	port := BFD_PORT
+	/*
+		RFC5883 3
+		The use of the Echo function is not defined for multihop sessions.
+	*/
+	if api_peer.IsMultiHop && api_peer.EchoInterval > 0 {
+		return nil, ErrEchoMultiHop
+	}
 
-	if strings.Contains(api_peer.Address, ":") {
-		parts := strings.Split(api_peer.Address, ":")
-		address = parts[0]
+	if api_peer.IsVxlan && (api_peer.IsMultiHop || api_peer.EchoInterval > 0 || member != "") {
+		return nil, ErrVxlanSessionType
+	}
 
-		port, err = strconv.Atoi(parts[1])
+	if api_peer.Vni > bfd.VXLAN_MAX_VNI {
+		return nil, bfd.ErrInvalidVNI
+	}
+
+	if api_peer.PadToSize > MAX_PAD_TO_SIZE {
+		return nil, ErrInvalidPadToSize
+	}
+
+	// the listeners read padded packets into buffers of a fixed size
+	if batchSize, _, _ := s.getBatchIO(); batchSize > 0 && api_peer.PadToSize > BATCH_BUFFER_SIZE {
+		return nil, ErrBatchPadToSize
+	}
+
+	var localAddress net.IP
+
+	if api_peer.LocalAddress != "" {
+		localAddress = net.ParseIP(api_peer.LocalAddress)
+
+		if localAddress == nil {
+			return nil, ErrInvalidIP
+		}
+	}
+
+	authType, authKeyId, authKey, err := parseAuthentication(api_peer.Authentication)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var keyChain *KeyChain
+
+	if authType != bfd.Reserved && api_peer.Authentication.KeyChain != "" {
+		keyChain, err = s.GetKeyChain(api_peer.Authentication.KeyChain)
 
 		if err != nil {
 			return nil, err
 		}
-	} else {
-		address = api_peer.Address
+
+		for _, key := range keyChain.Keys() {
+			if len(key.Secret) > maxAuthenticationKeyLength(authType) {
+				return nil, ErrInvalidAuthenticationKey
+			}
+		}
+	}
+
+	// create a random descriptor
+	discriminator := s.rand.Uint32()
+	//  49152 through 65535
+	sourcePort := 49152 + int(s.rand.Intn(65535-49152))
+
+	port := BFD_PORT
+
+	if api_peer.IsMultiHop {
+		port = BFD_MULTIHOP_PORT
+	}
+
+	// the port of the member listeners, the one of RFC7130 unless another one was picked
+	if member != "" {
+		s.RLock()
+		port = s.microPort
+		s.RUnlock()
+	}
+
+	if api_peer.IsVxlan {
+		port = bfd.VXLAN_PORT
+	}
+
+	address, zone, port, err := parseHostPort(api_peer.Address, port)
+
+	if err != nil {
+		return nil, err
 	}
 
-	peer, err := NewPeer(net.ParseIP(address), port)
+	peer, err := newPeer(address, port, s.scheduler, s.rand)
 
 	if err != nil {
 		return nil, err
 	}
 
+	// link local addresses need the interface
+	peer.Address.Zone = zone
+	peer.IfIndex = zoneIndex(zone)
+
+	// micro BFD sessions to the same address are told apart by their member interface
+	if member != "" {
+		iface, err := net.InterfaceByName(member)
+
+		if err != nil {
+			peer.Shutdown()
+			return nil, ErrInterfaceNotFound
+		}
+
+		peer.Interface = member
+		peer.IfIndex = iface.Index
+	}
+
 	peer.Lock()
 	peer.Name = api_peer.Name
 	peer.SourcePort = sourcePort
 	peer.Interval = api_peer.DesiredMinTxInterval
+	peer.IsMultiHop = api_peer.IsMultiHop
+	peer.IsVxlan = api_peer.IsVxlan
+	peer.Vni = api_peer.Vni
+	peer.PadToSize = int(api_peer.PadToSize)
+	peer.LocalAddress = localAddress
+	peer.MinTTL = uint8(api_peer.MinTtl)
+	peer.Passive = api_peer.Passive
+	peer.AuthType = authType
+	peer.AuthKeyId = authKeyId
+	peer.AuthKey = authKey
+	peer.KeyChain = keyChain
+	peer.OptimizedAuth = authType != bfd.Reserved && api_peer.Authentication.Optimized
+	peer.DemandPollInterval = time.Duration(api_peer.DemandPollInterval) * time.Millisecond
+	peer.EchoInterval = api_peer.EchoInterval * 1000
+	peer.EchoReflector = api_peer.EchoReflector
 	peer.local = &PeerState{
 		sessionState:          bfd.Down,
 		discriminator:         discriminator,
 		desiredMinTxInterval:  1000000,
 		requiredMinRxInterval: uint32(api_peer.RequiredMinRxInterval * 1000),
 		detectMultiplier:      uint8(api_peer.DetectMultiplier),
+		demandMode:            api_peer.DemandMode,
+
+		requiredMinEchoRxInterval: s.getEchoRequiredMinRx(),
 	}
 
 	peer.remote = &PeerState{
@@ -145,23 +342,24 @@ func (s *BfdServer) AddPeer(api_peer *api.Peer) (*Peer, error) {
 		requiredMinRxInterval: 1,
 	}
 
-	conn, err := s.dialUDP("udp", &net.UDPAddr{Port: peer.SourcePort}, peer.Address)
+	err = s.connectPeer(peer, zone, member)
+
+	if err == nil {
+		peer.publish()
+	}
+	peer.Unlock()
 
+	// the timers and the sockets opened so far are closed again
 	if err != nil {
+		peer.Shutdown()
 		return nil, err
 	}
 
-	ttlConn := ipv4.NewConn(conn)
-	ttlConn.SetTTL(255)
-
-	peer.conn = conn
-	peer.Unlock()
-
 	peer.scheduleExpiry(OFFLINE_TIMEOUT)
 	peer.scheduleSend(peer.local.desiredMinTxInterval)
 
 	s.Lock()
-	s.Sessions[discriminator] = peer
+	s.addSession(peer)
 	s.Unlock()
 
 	peer.Start()
@@ -169,6 +367,89 @@ func (s *BfdServer) AddPeer(api_peer *api.Peer) (*Peer, error) {
 	return peer, nil
 }
 
+// connectPeer opens the sockets of a session, the peer lock needs to be held by the caller.
+// The sockets opened before an error are closed by the shutdown of the peer.
+func (s *BfdServer) connectPeer(peer *Peer, zone, member string) error {
+	var err error
+
+	batchSize, _, shared := s.getBatchIO()
+
+	// micro BFD, VXLAN and padded sessions need options of their own socket
+	if batchSize > 0 && shared && member == "" && !peer.IsVxlan && peer.PadToSize == 0 {
+		sender, err := s.getSender(peer.LocalAddress, peer.Address.IP, peer.SourcePort, batchSize)
+
+		if err != nil {
+			return err
+		}
+
+		peer.SourcePort = sender.conn.LocalAddr().(*net.UDPAddr).Port
+		peer.conn = &batchConn{UDPConn: sender.conn, sender: sender, remote: peer.Address}
+	} else {
+		peer.conn, err = s.dialPeer(peer, zone, member)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if peer.EchoInterval > 0 {
+		return s.connectEcho(peer)
+	}
+
+	return nil
+}
+
+// dialPeer opens the connected socket of a session, the peer lock needs to be held by the caller
+func (s *BfdServer) dialPeer(peer *Peer, zone, member string) (Connection, error) {
+	conn, err := s.transport.DialUDP("udp", &net.UDPAddr{IP: peer.LocalAddress, Port: peer.SourcePort, Zone: zone}, peer.Address)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// micro BFD, padded and VXLAN sessions set options of the kernel socket
+	if member == "" && peer.PadToSize == 0 && !peer.IsVxlan {
+		return conn, nil
+	}
+
+	udp, err := kernelConn(conn)
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if member != "" {
+		err = bindSocketToDevice(udp, member)
+
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	/*
+		RFC9764
+		Padded packets are sent with DF set, a path that can't carry the
+		full size then loses them instead of fragmenting, which takes the
+		session down.
+	*/
+	if peer.PadToSize > 0 {
+		err = setSocketDontFragment(udp, peer.Address.IP)
+
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	if peer.IsVxlan {
+		return &vxlanConn{UDPConn: udp, vni: peer.Vni, mac: vtepMAC(udp.LocalAddr().(*net.UDPAddr).IP)}, nil
+	}
+
+	return udp, nil
+}
+
 func (s *BfdServer) GetPeerByUuid(uuid []byte) (*Peer, error) {
 	s.RLock()
 	defer s.RUnlock()
@@ -182,7 +463,7 @@ func (s *BfdServer) GetPeerByUuid(uuid []byte) (*Peer, error) {
 	return nil, ErrPeerNotFound
 }
 
-func (s *BfdServer) ListPeer(ctx context.Context, cb func([]byte, *api.Peer) error) error {
+func (s *BfdServer) ListPeer(ctx context.Context, cb func([]byte, *api.Peer, []*api.ViolationCount) error) error {
 	s.RLock()
 	defer s.RUnlock()
 
@@ -190,6 +471,18 @@ func (s *BfdServer) ListPeer(ctx context.Context, cb func([]byte, *api.Peer) err
 		local := peer.GetLocal()
 
 		peer.RLock()
+		keyChain := ""
+
+		if peer.KeyChain != nil {
+			keyChain = peer.KeyChain.Name
+		}
+
+		localAddress := ""
+
+		if peer.LocalAddress != nil {
+			localAddress = peer.LocalAddress.String()
+		}
+
 		api_peer := &api.Peer{
 			Name:                  peer.Name,
 			Address:               peer.Address.String(),
@@ -197,10 +490,30 @@ func (s *BfdServer) ListPeer(ctx context.Context, cb func([]byte, *api.Peer) err
 			RequiredMinRxInterval: local.GetRequiredMinRxInterval(),
 			DetectMultiplier:      uint32(local.GetDetectMultiplier()),
 			IsMultiHop:            peer.IsMultiHop,
+			LocalAddress:          localAddress,
+			DemandMode:            local.demandMode,
+			DemandPollInterval:    uint32(peer.DemandPollInterval / time.Millisecond),
+			EchoInterval:          peer.EchoInterval / 1000,
+			EchoReflector:         peer.EchoReflector,
+			Dynamic:               peer.dynamic != nil || peer.unsolicited != nil,
+			Unsolicited:           peer.unsolicited != nil,
+			Passive:               peer.Passive,
+			MinTtl:                uint32(peer.MinTTL),
+			Interface:             peer.Interface,
+			IsVxlan:               peer.IsVxlan,
+			Vni:                   peer.Vni,
+			PadToSize:             uint32(peer.PadToSize),
+			// the password is never handed out
+			Authentication: &api.Authentication{
+				Type:      api.AuthenticationType(peer.AuthType),
+				KeyId:     uint32(uint8(peer.AuthKeyId)),
+				KeyChain:  keyChain,
+				Optimized: peer.OptimizedAuth,
+			},
 		}
 		peer.RUnlock()
 
-		err := cb(peer.uuid, api_peer)
+		err := cb(peer.uuid, api_peer, peer.violations.ToApi())
 
 		if err != nil {
 			return err
@@ -243,13 +556,194 @@ func (s *BfdServer) DeletePeer(uuid []byte) error {
 		return err
 	}
 
-	delete(s.Sessions, peer.GetLocal().GetDiscriminator())
+	if peer.Interface != "" {
+		return ErrLagMemberSession
+	}
+
+	s.removePeer(peer)
+
+	return nil
+}
+
+func (s *BfdServer) removePeer(peer *Peer) {
+	s.Lock()
+	s.removeSession(peer)
+	s.Unlock()
 
 	peer.Shutdown()
+}
+
+// addSession makes a session known to the server, the lock needs to be held by the caller
+func (s *BfdServer) addSession(peer *Peer) {
+	s.Sessions[peer.GetLocal().GetDiscriminator()] = peer
+	s.peerAddresses[peer.Address.IP.String()]++
+}
+
+// removeSession is the counterpart of addSession, the lock needs to be held by the caller
+func (s *BfdServer) removeSession(peer *Peer) {
+	discriminator := peer.GetLocal().GetDiscriminator()
+
+	if s.Sessions[discriminator] != peer {
+		return
+	}
+
+	delete(s.Sessions, discriminator)
+
+	peer.RLock()
+	if peer.dynamic != nil {
+		peer.dynamic.sessions--
+	}
+	peer.RUnlock()
+
+	key := peer.Address.IP.String()
+
+	if s.peerAddresses[key]--; s.peerAddresses[key] <= 0 {
+		delete(s.peerAddresses, key)
+	}
+}
+
+func (s *BfdServer) AddKeyChain(api_chain *api.KeyChain) error {
+	if api_chain.Name == "" {
+		return ErrInvalidKeyChainName
+	}
+
+	chain := NewKeyChain(api_chain.Name)
+
+	for _, api_key := range api_chain.Keys {
+		key, err := NewKeyFromApi(api_key)
+
+		if err != nil {
+			return err
+		}
+
+		err = chain.SetKey(key)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.keyChains[chain.Name]; ok {
+		return ErrKeyChainExists
+	}
+
+	s.keyChains[chain.Name] = chain
 
 	return nil
 }
 
+func (s *BfdServer) GetKeyChain(name string) (*KeyChain, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	chain, ok := s.keyChains[name]
+
+	if !ok {
+		return nil, ErrKeyChainNotFound
+	}
+
+	return chain, nil
+}
+
+func (s *BfdServer) DeleteKeyChain(name string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	chain, ok := s.keyChains[name]
+
+	if !ok {
+		return ErrKeyChainNotFound
+	}
+
+	if len(s.keyChainUsers(chain)) > 0 {
+		return ErrKeyChainInUse
+	}
+
+	delete(s.keyChains, name)
+
+	return nil
+}
+
+func (s *BfdServer) ListKeyChain(ctx context.Context, cb func(*api.KeyChain) error) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	for _, chain := range s.keyChains {
+		err := cb(chain.ToApi())
+
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+
+	return nil
+}
+
+// AddKey adds or replaces a key of a chain, live sessions pick it up on their next packet
+func (s *BfdServer) AddKey(name string, api_key *api.Key) error {
+	key, err := NewKeyFromApi(api_key)
+
+	if err != nil {
+		return err
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	chain, ok := s.keyChains[name]
+
+	if !ok {
+		return ErrKeyChainNotFound
+	}
+
+	// the key has to be usable by every peer referencing the chain
+	for _, peer := range s.keyChainUsers(chain) {
+		if len(key.Secret) > maxAuthenticationKeyLength(peer.GetAuthenticationType()) {
+			return ErrInvalidAuthenticationKey
+		}
+	}
+
+	return chain.SetKey(key)
+}
+
+func (s *BfdServer) DeleteKey(name string, id uint32) error {
+	if id > 255 {
+		return ErrInvalidAuthenticationKeyId
+	}
+
+	chain, err := s.GetKeyChain(name)
+
+	if err != nil {
+		return err
+	}
+
+	return chain.DeleteKey(uint8(id))
+}
+
+// keyChainUsers returns the peers referencing the chain, the lock needs to be held by the caller
+func (s *BfdServer) keyChainUsers(chain *KeyChain) []*Peer {
+	peers := make([]*Peer, 0)
+
+	for _, peer := range s.Sessions {
+		peer.RLock()
+		if peer.KeyChain == chain {
+			peers = append(peers, peer)
+		}
+		peer.RUnlock()
+	}
+
+	return peers
+}
+
 /*
 
 Packets = udp
@@ -259,67 +753,99 @@ src port = 49152 - 65535
 
 */
 
+// Listen starts a listener for single hop sessions, it needs to be called before Serve
+// as the port is taken by the listener on the wildcard address otherwise
 func (s *BfdServer) Listen(address string) error {
-	port := BFD_PORT
+	return s.listen(address, BFD_PORT, false, false)
+}
+
+// ListenMultiHop starts a listener for multi hop sessions, like Listen before Serve
+func (s *BfdServer) ListenMultiHop(address string) error {
+	return s.listen(address, BFD_MULTIHOP_PORT, true, false)
+}
 
+func (s *BfdServer) listen(address string, port int, multiHop, vxlan bool) error {
 	// parse our address and determine if a port is passed
-	host, str_port, err := net.SplitHostPort(address)
+	ip, zone, port, err := parseHostPort(address, port)
 
-	if err != nil {
-		return err
+	// port 0 binds a free port
+	if err != nil || port < 0 || port > 65535 {
+		return ErrInvalidPort
 	}
 
-	ip := net.ParseIP(host)
-
 	if ip == nil {
 		return ErrInvalidIP
 	}
 
-	if str_port != "" {
-		port, err = strconv.Atoi(str_port)
-
-		if err != nil || port < 1 || port > 65536 {
-			return ErrInvalidPort
-		}
-	}
-
 	// now start a separate server for this address
 	addr := &net.UDPAddr{
 		IP:   ip,
 		Port: port,
+		Zone: zone,
 	}
 
-	conn, err := net.ListenUDP("udp", addr)
+	batchSize, sockets, _ := s.getBatchIO()
+
+	var conns []UDPConn
+
+	if batchSize > 0 && sockets > 1 {
+		conns, err = listenReusePort(addr, sockets)
+	} else {
+		transport := s.transport
+
+		// batched reads need a kernel socket
+		if batchSize > 0 {
+			transport = kernelTransport{}
+		}
+
+		var conn UDPConn
+
+		conn, err = transport.ListenUDP("udp", addr)
+		conns = []UDPConn{conn}
+	}
 
 	if err != nil {
 		return err
 	}
 
-	// Setup so that we receive the TTL of incoming packets
-	file, err := conn.File()
+	addr.Port = conns[0].LocalAddr().(*net.UDPAddr).Port
 
-	if err != nil {
-		return err
+	l := &listener{
+		conn:     conns[0],
+		sockets:  conns,
+		local:    addr,
+		multiHop: multiHop,
+		vxlan:    vxlan,
 	}
 
-	syscall.SetsockoptInt(int(file.Fd()), syscall.IPPROTO_IP, syscall.IP_RECVTTL, 1)
+	if vxlan {
+		l.macs = vtepMACs(ip)
+	}
 
-	file.Close()
+	// keyed by the resolved address, listeners of different session types may share the host
+	s.conns[addr.String()] = l
 
-	s.conns[address] = &listener{
-		conn,
-		make(chan bool, 1),
+	if batchSize == 0 {
+		s.readers.Add(1)
+		go s.handleIncomingPackets(l)
+
+		return nil
 	}
 
-	go s.handleIncomingPackets(conn)
+	for _, conn := range conns {
+		s.readers.Add(1)
+		go s.handleIncomingBatches(l, newBatchReadWriter(conn.(*net.UDPConn), ip), batchSize)
+	}
 
 	return nil
 }
 
+// Serve handles the received packets, it listens on the wildcard address for the session
+// types without a listener
 func (s *BfdServer) Serve() error {
-	go s.handleIncomingBfdPacket()
+	s.inbound.start(s.handlePacket)
 
-	if len(s.conns) == 0 {
+	if !s.hasListener(false) {
 		err := s.Listen("0.0.0.0:" + strconv.Itoa(BFD_PORT))
 
 		if err != nil {
@@ -327,43 +853,211 @@ func (s *BfdServer) Serve() error {
 		}
 	}
 
+	if !s.hasListener(true) {
+		err := s.ListenMultiHop("0.0.0.0:" + strconv.Itoa(BFD_MULTIHOP_PORT))
+
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+func (s *BfdServer) hasListener(multiHop bool) bool {
+	for _, l := range s.conns {
+		if l.multiHop == multiHop && !l.micro && !l.vxlan {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ListListener calls cb for every listener along with the violations of the packets it received
+func (s *BfdServer) ListListener(ctx context.Context, cb func(*api.ListListenerResponse) error) error {
+	s.RLock()
+	listeners := make([]*listener, 0, len(s.conns))
+
+	for _, l := range s.conns {
+		listeners = append(listeners, l)
+	}
+	s.RUnlock()
+
+	for _, l := range listeners {
+		iface := ""
+
+		if l.ifIndex != 0 {
+			if i, err := net.InterfaceByIndex(l.ifIndex); err == nil {
+				iface = i.Name
+			}
+		}
+
+		address := ""
+
+		if l.local != nil {
+			address = l.local.String()
+		}
+
+		err := cb(&api.ListListenerResponse{
+			Address:    address,
+			IsMultiHop: l.multiHop,
+			IsMicro:    l.micro,
+			IsVxlan:    l.vxlan,
+			Interface:  iface,
+			Violations: l.violations.ToApi(),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Shutdown stops all sessions, closes the sockets and waits until their readers returned
 func (s *BfdServer) Shutdown() {
-	s.control <- true
+	s.Lock()
+
+	if s.stopped {
+		s.Unlock()
+		return
+	}
+
+	s.stopped = true
+
+	if s.dynamicExpiry != nil {
+		s.dynamicExpiry.Stop()
+	}
+
+	// the sessions and sockets are closed without the lock, the handlers of their packets take it
+	peers := make([]*Peer, 0, len(s.Sessions))
+	initiators := make([]*SbfdInitiator, 0, len(s.sbfdInitiators))
+	conns := []UDPConn{}
+	senders := make([]*batchSender, 0, len(s.senders))
 
 	for _, peer := range s.Sessions {
+		peers = append(peers, peer)
+	}
+
+	for _, initiator := range s.sbfdInitiators {
+		initiators = append(initiators, initiator)
+	}
+
+	for _, l := range s.conns {
+		conns = append(conns, l.sockets...)
+	}
+
+	for _, l := range s.echoConns {
+		conns = append(conns, l.conn)
+	}
+
+	for _, conn := range s.sbfdConns {
+		conns = append(conns, conn)
+	}
+
+	for _, sender := range s.senders {
+		senders = append(senders, sender)
+	}
+	s.Unlock()
+
+	for _, peer := range peers {
 		peer.Shutdown()
 	}
-}
 
-func (s *BfdServer) handleIncomingBfdPacket() {
-	for {
-		select {
-		case pkt := <-s.inbound:
-			err := s.handlePacket(pkt)
+	for _, initiator := range initiators {
+		initiator.Shutdown()
+	}
 
-			if err != nil {
-				glog.Infof("%s", err.Error())
-			}
-		case <-s.control:
-			glog.Infof("Shutdown Incoming")
-			return
-		}
+	for _, conn := range conns {
+		conn.Close()
+	}
+
+	s.readers.Wait()
+
+	for _, sender := range senders {
+		sender.close()
 	}
+
+	s.inbound.stop()
+
+	s.scheduler.stop()
+}
+
+func (s *BfdServer) isStopped() bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.stopped
 }
 
-func (s *BfdServer) handlePacket(pkt packet) error {
-	var peer *Peer
-	var ok bool
+func (s *BfdServer) getEchoRequiredMinRx() uint32 {
+	s.RLock()
+	defer s.RUnlock()
 
+	return s.echoRequiredMinRx
+}
+
+func (s *BfdServer) handlePacket(pkt packet) (err error) {
 	p := pkt.packet
+	violations := pkt.violations | p.Validate()
+
+	peer, err := s.findPeer(&pkt)
+
+	if violations != 0 {
+		// counted for the session of the packet, as far as it can be found
+		if peer != nil {
+			peer.violations.add(violations)
+		}
 
-	if err := checkPacket(p); err != nil {
-		return ErrInvalidPacket
+		if violations.Discard() {
+			return ErrInvalidPacket
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if peer == nil {
+		peer, err = s.createDynamicPeer(&pkt)
+
+		if err != nil {
+			return err
+		}
+
+		// don't keep sessions for packets that are discarded
+		defer func() {
+			if err != nil {
+				s.DeletePeer(peer.uuid)
+			}
+		}()
 	}
 
+	/*
+		RFC5883 5
+		Since the TTL cannot be used to ensure that the packets
+		originate from an adjacent system, a configured minimum TTL limits
+		how many hops away the packets may be sent from.
+	*/
+	if pkt.multiHop && pkt.ttl < peer.MinTTL {
+		return ErrInvalidTTL
+	}
+
+	return peer.handlePacket(p, pkt.raw)
+}
+
+// findPeer returns the session of a packet, nil if there is none
+func (s *BfdServer) findPeer(pkt *packet) (*Peer, error) {
+	p := pkt.packet
+
 	/*
 		If the Your Discriminator field is nonzero,
 		it MUST be used to select the session with which this BFD packet is associated.
@@ -372,128 +1066,189 @@ func (s *BfdServer) handlePacket(pkt packet) error {
 
 	if p.YourDiscriminator != 0 {
 		s.RLock()
-		peer, ok = s.Sessions[p.YourDiscriminator]
+		peer, ok := s.Sessions[p.YourDiscriminator]
 		s.RUnlock()
 		if !ok {
-			return ErrYourDiscriminatorNotFound
+			return nil, ErrYourDiscriminatorNotFound
 		}
-	} else {
-		/*
-			If the Your Discriminator field is zero, the session MUST be
-			selected based on some combination of other fields, possibly
-			including source addressing information, the My Discriminator
-			field, and the interface over which the packet was received.  The
-			exact method of selection is application specific and is thus
-			outside the scope of this specification.  If a matching session is
-			not found, a new session MAY be created, or the packet MAY be
-			discarded.  This choice is outside the scope of this
-			specification.
-		*/
-		s.RLock()
-		for _, lp := range s.Sessions {
-			if lp.Address.IP.Equal(pkt.addr.IP) {
-				peer = lp
-				break
-			}
-		}
-		s.RUnlock()
-	}
 
-	if peer == nil {
-		return ErrPeerNotFound
-	}
+		if !peer.matchesSessionType(pkt) {
+			return nil, ErrSessionTypeMismatch
+		}
 
-	return peer.handlePacket(p)
-}
+		// a guessed discriminator alone isn't enough, the packet needs to reach us via the session's path
+		if !peer.matchesPacket(pkt) {
+			return nil, ErrAddressMismatch
+		}
 
-func checkPacket(p *bfd.ControlPacket) error {
-	// If the version number is not correct (1), the packet MUST be discarded.
-	if p.Version != 1 {
-		return ErrInvalidVersion
+		return peer, nil
 	}
 
 	/*
-		If the Length field is less than the minimum correct value (24 if
-		the A bit is clear, or 26 if the A bit is set), the packet MUST be
-		discarded.
-
-		If the Length field is greater than the payload of the
-		encapsulating protocol, the packet MUST be discarded.
-
-		=> handled with a length check on parse
+		If the Your Discriminator field is zero, the session MUST be
+		selected based on some combination of other fields, possibly
+		including source addressing information, the My Discriminator
+		field, and the interface over which the packet was received.  The
+		exact method of selection is application specific and is thus
+		outside the scope of this specification.  If a matching session is
+		not found, a new session MAY be created, or the packet MAY be
+		discarded.  This choice is outside the scope of this
+		specification.
 	*/
+	s.RLock()
+	defer s.RUnlock()
 
-	// If the Detect Mult field is zero, the packet MUST be discarded.
-	if p.DetectMultiplier == 0 {
-		return ErrInvalidDetectMultiplier
-	}
-
-	// If the Multipoint (M) bit is nonzero, the packet MUST be discarded.
-	if p.Multipoint == bfd.Yes {
-		return ErrInvalidMultiPoint
-	}
-
-	// If the My Discriminator field is zero, the packet MUST be discarded.
-	if p.MyDiscriminator == 0 {
-		return ErrInvalidMyDiscriminator
+	for _, lp := range s.Sessions {
+		if lp.matchesSessionType(pkt) && lp.matchesPacket(pkt) {
+			return lp, nil
+		}
 	}
 
-	/*
-		If the Your Discriminator field is zero and the State field is not
-		Down or AdminDown, the packet MUST be discarded.
-	*/
+	return nil, nil
+}
 
-	if p.YourDiscriminator == 0 && (p.State != bfd.Down && p.State != bfd.AdminDown) {
-		return ErrInvalidYourDiscriminator
+// checkPacket returns the error of the first violation of RFC5880 6.8.6 that discards the packet
+func checkPacket(p *bfd.ControlPacket) error {
+	for _, violation := range p.Validate().List() {
+		if err, ok := violationErrors[violation]; ok {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (s *BfdServer) handleIncomingPackets(conn Connection) {
-	b := make([]byte, 256)
+func (s *BfdServer) handleIncomingPackets(l *listener) {
+	defer s.readers.Done()
+
+	// large enough for padded packets
+	b := make([]byte, 65536)
 	oob := make([]byte, 256)
 
 	for {
-		err := s.readIncomingPacket(conn, b, oob)
+		err := s.readIncomingPacket(l, b, oob)
 
 		if err != nil {
+			// the sockets are closed on shutdown
+			if s.isStopped() {
+				return
+			}
+
 			glog.Errorf("%v", err)
 		}
+	}
+}
 
-		select {
-		case <-s.control:
-			return
-		default:
+func (s *BfdServer) readIncomingPacket(l *listener, b, oob []byte) error {
+	if r, ok := l.conn.(packetReader); ok {
+		n, cm, addr, err := r.readPacket(b)
+
+		if err != nil {
+			return err
 		}
+
+		return s.handleReceived(l, b[:n], cm, addr)
 	}
-}
 
-func (s *BfdServer) readIncomingPacket(conn Connection, b, oob []byte) error {
-	n, _, _, addr, err := conn.ReadMsgUDP(b, oob)
+	n, oobn, _, addr, err := l.conn.ReadMsgUDP(b, oob)
 
 	if err != nil {
 		return err
 	}
 
-	ttl := oob[16]
+	return s.handleDatagram(l, b[:n], oob[:oobn], addr)
+}
+
+// handleDatagram checks and decodes a datagram received by a listener and queues it to be handled,
+// data is copied, so the buffers can be reused once it returns
+func (s *BfdServer) handleDatagram(l *listener, data, oob []byte, addr *net.UDPAddr) error {
+	var cm controlMessage
+
+	if err := parseControlMessage(oob, &cm); err != nil {
+		return err
+	}
+
+	return s.handleReceived(l, data, &cm, addr)
+}
 
-	if ttl != 255 {
+// handleReceived checks and decodes a datagram received with the context cm, see handleDatagram
+func (s *BfdServer) handleReceived(l *listener, data []byte, cm *controlMessage, addr *net.UDPAddr) error {
+	var err error
+	var vni uint32
+
+	if l.vxlan {
+		data, vni, err = decapsulateVxlan(data, l.macs)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	/*
+		RFC5881 5
+		If BFD authentication is not in use on a session, all BFD Control
+		packets for the session MUST be sent with a Time to Live (TTL) or
+		Hop Limit value of 255.  All received BFD Control packets that are
+		demultiplexed to the session MUST be discarded if the received TTL
+		or Hop Limit is not equal to 255.
+
+		Multi hop packets are checked against the minimum TTL of the session,
+		the TTL of VXLAN packets is the one of the routed tunnel, the inner
+		one is checked once decapsulated.
+	*/
+	if !l.multiHop && !l.vxlan && cm.ttl != 255 {
 		return ErrInvalidTTL
 	}
 
-	pkt := &bfd.ControlPacket{}
+	// every violation is counted, even if the packet can't be decoded
+	violations := bfd.Validate(data)
+	l.violations.add(violations)
 
-	err = pkt.UnmarshalBinary(b[:n])
+	// the header of the previous packet held by the buffer is reused if the type matches
+	buf := packetBuffers.Get().(*packetBuffer)
 
-	if err != nil {
+	if err = buf.control.DecodeFromBytes(data); err != nil {
+		packetBuffers.Put(buf)
 		return err
 	}
 
-	s.inbound <- packet{
-		addr,
-		pkt,
+	// data is reused for the next read, so keep a copy for authentication,
+	// which doesn't cover the padding of large packets
+	raw := buf.raw[:copy(buf.raw[:], data[:data[3]])]
+
+	// without a destination in the control messages it's only known for listeners bound to an address,
+	// the one of the control messages refers to the buffers of the read
+	var dst net.IP
+
+	if cm.dst != nil {
+		dst = buf.dst[:copy(buf.dst[:], cm.dst)]
+	} else if l.local != nil && !l.local.IP.IsUnspecified() {
+		dst = l.local.IP
+	}
+
+	// listeners bound to an interface receive the packets of a member link,
+	// which the control messages may report as the interface of the LAG
+	ifIndex := cm.ifIndex
+
+	if l.ifIndex != 0 {
+		ifIndex = l.ifIndex
 	}
 
+	// a full queue drops the packet, which is counted by the pipeline
+	s.inbound.dispatch(packet{
+		addr:       addr,
+		packet:     &buf.control,
+		raw:        raw,
+		dst:        dst,
+		ifIndex:    ifIndex,
+		ttl:        cm.ttl,
+		multiHop:   l.multiHop,
+		micro:      l.micro,
+		vxlan:      l.vxlan,
+		vni:        vni,
+		violations: violations,
+		buf:        buf,
+	})
+
 	return nil
 }
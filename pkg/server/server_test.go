@@ -1,12 +1,17 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"net"
+	"reflect"
 	"strconv"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
+	"unsafe"
 
 	"github.com/Thoro/bfd/pkg/api"
 	"github.com/Thoro/bfd/pkg/packet/bfd"
@@ -128,21 +133,102 @@ func TestAddPeerValidAddressWithoutPort(t *testing.T) {
 	}
 }
 
+func TestAddPeerUsesServerScheduler(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	shared := sharedScheduler()
+
+	next := atomic.LoadUint32(&shared.next)
+
+	p, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		DetectMultiplier: 1,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for _, timer := range []*timer{p.ticker, p.expiry, p.echoTicker, p.echoExpiry} {
+		if timer.w.s != server.scheduler {
+			t.Errorf("Expected the timers to run on the scheduler of the server")
+		}
+	}
+
+	if atomic.LoadUint32(&shared.next) != next {
+		t.Errorf("Expected no timers on the shared scheduler")
+	}
+}
+
 func FakeDialUdp(network string, laddr, raddr *net.UDPAddr) (*net.UDPConn, error) {
 	return nil, errors.New("Fake error for testing")
 }
 
+// dialTransport opens the connected sockets with dial instead of net.DialUDP
+type dialTransport struct {
+	kernelTransport
+
+	dial func(network string, laddr, raddr *net.UDPAddr) (*net.UDPConn, error)
+}
+
+func (t dialTransport) DialUDP(network string, laddr, raddr *net.UDPAddr) (UDPConn, error) {
+	conn, err := t.dial(network, laddr, raddr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// echoFailTransport opens virtual sockets, except the ones to echo reflectors
+type echoFailTransport struct {
+	Transport
+}
+
+func (t echoFailTransport) DialUDP(network string, laddr, raddr *net.UDPAddr) (UDPConn, error) {
+	if raddr.Port == ECHO_PORT {
+		return nil, ErrNotImplemented
+	}
+
+	return t.Transport.DialUDP(network, laddr, raddr)
+}
+
+func TestAddPeerDialEchoError(t *testing.T) {
+	network := NewVirtualNetwork(1)
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	server.transport = echoFailTransport{network.Transport(net.ParseIP("10.0.0.1"))}
+
+	_, err := server.AddPeer(&api.Peer{
+		Address:          "10.0.0.2",
+		DetectMultiplier: 1,
+		EchoInterval:     100,
+		EchoReflector:    true,
+	})
+
+	if err != ErrNotImplemented {
+		t.Errorf("Expected %v, got %v", ErrNotImplemented, err)
+	}
+
+	if len(network.sockets) != 0 || len(server.Sessions) != 0 {
+		t.Errorf("Expected the control socket to be closed, got %v", network.sockets)
+	}
+}
+
 func TestAddPeerDialUDPError(t *testing.T) {
 	server := NewBfdServer()
 
-	server.dialUDP = FakeDialUdp
+	server.transport = dialTransport{dial: FakeDialUdp}
 
 	_, err := server.AddPeer(&api.Peer{
 		Address:          "127.0.0.1",
 		DetectMultiplier: 1,
 	})
 
-	server.dialUDP = net.DialUDP
+	server.transport = kernelTransport{}
 
 	if err == nil {
 		t.Errorf("Expected fake error")
@@ -202,7 +288,7 @@ func TestGetListPeer(t *testing.T) {
 
 	server.ListPeer(
 		context.Background(),
-		func(uuid []byte, peer *api.Peer) error {
+		func(uuid []byte, peer *api.Peer, violations []*api.ViolationCount) error {
 			count++
 			return nil
 		},
@@ -235,11 +321,12 @@ func TestGetListPeerDone(t *testing.T) {
 
 	count := 0
 
-	context, _ := context.WithTimeout(context.Background(), time.Duration(0))
+	context, cancel := context.WithTimeout(context.Background(), time.Duration(0))
+	defer cancel()
 
 	server.ListPeer(
 		context,
-		func(uuid []byte, peer *api.Peer) error {
+		func(uuid []byte, peer *api.Peer, violations []*api.ViolationCount) error {
 			count++
 
 			return nil
@@ -381,6 +468,28 @@ func TestServe(t *testing.T) {
 	}
 }
 
+func TestServeKeepsListeners(t *testing.T) {
+	network := NewVirtualNetwork(1)
+
+	server := NewBfdServer()
+	server.SetClock(network.Clock())
+	server.SetTransport(network.Transport(net.ParseIP("10.0.0.1")))
+	defer server.Shutdown()
+
+	if err := server.ListenMultiHop("10.0.0.1"); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// the wildcard address would take the port of the multi hop listener
+	if err := server.Serve(); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if !server.hasListener(false) || len(server.conns) != 2 || server.conns["10.0.0.1:4784"] == nil {
+		t.Errorf("Expected the multi hop listener and a single hop one, got %v", server.conns)
+	}
+}
+
 func TestServeInvalidIP(t *testing.T) {
 	server := NewBfdServer()
 	defer server.Shutdown()
@@ -405,34 +514,69 @@ func TestServeInvalidPort(t *testing.T) {
 	}
 }
 
-func TestServeMissingPort(t *testing.T) {
+func TestShutdown(t *testing.T) {
 	server := NewBfdServer()
-	defer server.Shutdown()
+	server.Shutdown()
+}
 
-	err := server.Listen("127.0.0.1")
+func TestShutdownClosesSockets(t *testing.T) {
+	network := NewVirtualNetwork(1)
 
-	if err.Error() != "address 127.0.0.1: missing port in address" {
-		t.Log(err.Error())
-		t.Fail()
+	server, err := network.NewServer("10.0.0.1")
+
+	if err != nil {
+		t.Fatalf("%v", err)
 	}
-}
 
-func TestShutdown(t *testing.T) {
-	server := NewBfdServer()
+	if err := server.ListenEcho("10.0.0.1", 50); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := server.ListenSbfdReflector("10.0.0.1", 50); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// returns once the readers of all sockets stopped
+	server.Shutdown()
 	server.Shutdown()
+
+	if len(network.sockets) != 0 {
+		t.Errorf("Expected all sockets to be closed, got %v", network.sockets)
+	}
+}
+
+func TestShutdownReleasesPort(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	addr := conn.LocalAddr().String()
+	conn.Close()
+
+	for i := 0; i < 2; i++ {
+		server := NewBfdServer()
+
+		if err := server.Listen(addr); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		server.Shutdown()
+	}
 }
 
 func TestHandleIncomingBfdPacket(t *testing.T) {
 	server := NewBfdServer()
 	defer server.Shutdown()
 
-	server.inbound <- packet{
-		&net.UDPAddr{
+	server.inbound.dispatch(packet{
+		addr: &net.UDPAddr{
 			IP:   net.ParseIP("127.0.0.1"),
 			Port: 15662,
 		},
-		&bfd.ControlPacket{},
-	}
+		packet: &bfd.ControlPacket{},
+	})
 
 	server.Serve()
 }
@@ -442,11 +586,11 @@ func TestHandleInvalidPacket(t *testing.T) {
 	defer server.Shutdown()
 
 	err := server.handlePacket(packet{
-		&net.UDPAddr{
+		addr: &net.UDPAddr{
 			IP:   net.ParseIP("127.0.0.1"),
 			Port: 15662,
 		},
-		&bfd.ControlPacket{},
+		packet: &bfd.ControlPacket{},
 	})
 
 	if err != ErrInvalidPacket {
@@ -459,11 +603,11 @@ func TestHandlePacketDiscriminatorNotFound(t *testing.T) {
 	defer server.Shutdown()
 
 	err := server.handlePacket(packet{
-		&net.UDPAddr{
+		addr: &net.UDPAddr{
 			IP:   net.ParseIP("127.0.0.1"),
 			Port: 15662,
 		},
-		&bfd.ControlPacket{
+		packet: &bfd.ControlPacket{
 			Version:           1,
 			DetectMultiplier:  3,
 			YourDiscriminator: 55,
@@ -482,11 +626,11 @@ func TestHandlePacketNoDiscriminator(t *testing.T) {
 	defer server.Shutdown()
 
 	err := server.handlePacket(packet{
-		&net.UDPAddr{
+		addr: &net.UDPAddr{
 			IP:   net.ParseIP("127.0.0.1"),
 			Port: 15662,
 		},
-		&bfd.ControlPacket{
+		packet: &bfd.ControlPacket{
 			Version:          1,
 			DetectMultiplier: 3,
 			MyDiscriminator:  60,
@@ -510,11 +654,11 @@ func TestHandlePacketSuccessNoDiscriminatorWithIP(t *testing.T) {
 	})
 
 	err := server.handlePacket(packet{
-		&net.UDPAddr{
+		addr: &net.UDPAddr{
 			IP:   net.ParseIP("127.0.0.1"),
 			Port: 15662,
 		},
-		&bfd.ControlPacket{
+		packet: &bfd.ControlPacket{
 			Version:          1,
 			DetectMultiplier: 3,
 			MyDiscriminator:  60,
@@ -527,6 +671,92 @@ func TestHandlePacketSuccessNoDiscriminatorWithIP(t *testing.T) {
 	}
 }
 
+// cmsg builds the ancillary data the kernel passes along with a received packet
+func cmsg(level, typ int, data []byte) []byte {
+	oob := make([]byte, syscall.CmsgSpace(len(data)))
+
+	header := (*syscall.Cmsghdr)(unsafe.Pointer(&oob[0]))
+	header.Level = int32(level)
+	header.Type = int32(typ)
+	header.SetLen(syscall.CmsgLen(len(data)))
+
+	copy(oob[syscall.CmsgLen(0):], data)
+
+	return oob
+}
+
+// queuedPacket returns the next packet queued for any of the inbound workers, it waits for
+// packets sent through kernel sockets
+func queuedPacket(t testing.TB, s *BfdServer) packet {
+	// packets read by the test are queued already, without allocating for the select
+	for _, q := range s.inbound.queues {
+		select {
+		case pkt := <-q.packets:
+			return pkt
+		default:
+		}
+	}
+
+	cases := []reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(time.After(time.Second))}}
+
+	for _, q := range s.inbound.queues {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(q.packets)})
+	}
+
+	chosen, pkt, _ := reflect.Select(cases)
+
+	if chosen == 0 {
+		t.Fatalf("No packet was queued")
+	}
+
+	return pkt.Interface().(packet)
+}
+
+// advanceUntilUp advances the clock of a session on kernel sockets until it's up, every advance
+// sends a packet and the state change it causes is waited for
+func advanceUntilUp(clock *VirtualClock, peer *Peer) bool {
+	w := peer.Watch()
+	defer w.Stop()
+
+	for i := 0; i < 10 && peer.GetLocal().GetSessionState() != bfd.Up; i++ {
+		clock.Advance(time.Second)
+
+		select {
+		case <-w.Event():
+		case <-time.After(time.Second):
+		}
+	}
+
+	return peer.GetLocal().GetSessionState() == bfd.Up
+}
+
+// hopLimitCmsg builds a TTL or Hop Limit control message, the value is an int in host byte order
+func hopLimitCmsg(level, typ int, value int32) []byte {
+	data := make([]byte, 4)
+	*(*int32)(unsafe.Pointer(&data[0])) = value
+
+	return cmsg(level, typ, data)
+}
+
+// pktInfoCmsg builds an IP_PKTINFO or IPV6_PKTINFO control message
+func pktInfoCmsg(dst net.IP, ifIndex int32) []byte {
+	if dst.To4() != nil {
+		// struct in_pktinfo { int ifindex; in_addr spec_dst; in_addr addr }
+		data := make([]byte, 12)
+		*(*int32)(unsafe.Pointer(&data[0])) = ifIndex
+		copy(data[8:], dst.To4())
+
+		return cmsg(syscall.IPPROTO_IP, syscall.IP_PKTINFO, data)
+	}
+
+	// struct in6_pktinfo { in6_addr addr; unsigned int ifindex }
+	data := make([]byte, 20)
+	copy(data, dst.To16())
+	*(*int32)(unsafe.Pointer(&data[16])) = ifIndex
+
+	return cmsg(syscall.IPPROTO_IPV6, syscall.IPV6_PKTINFO, data)
+}
+
 func TestHandleIncomingPacketsUdpError(t *testing.T) {
 	server := NewBfdServer()
 	defer server.Shutdown()
@@ -538,7 +768,7 @@ func TestHandleIncomingPacketsUdpError(t *testing.T) {
 	b := make([]byte, 256)
 	oob := make([]byte, 256)
 
-	err := server.readIncomingPacket(fake, b, oob)
+	err := server.readIncomingPacket(&listener{conn: fake}, b, oob)
 
 	if err != fake.err {
 		t.Fail()
@@ -553,13 +783,12 @@ func TestHandleIncomingPacketsWrongTTL(t *testing.T) {
 	fake := &FakeConn{}
 
 	fake.n = 40
-	fake.oobn = 16
-	fake.oob = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 254}
+	fake.oob = hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 254)
 
 	b := make([]byte, 256)
 	oob := make([]byte, 256)
 
-	err := server.readIncomingPacket(fake, b, oob)
+	err := server.readIncomingPacket(&listener{conn: fake}, b, oob)
 
 	if err != ErrInvalidTTL {
 		t.Fail()
@@ -573,14 +802,13 @@ func TestHandleIncomingPacketsUnmarshalError(t *testing.T) {
 	fake := &FakeConn{}
 
 	fake.n = 40
-	fake.oobn = 16
-	fake.oob = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 255, 255}
+	fake.oob = hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 255)
 	fake.data = []byte{255, 255}
 
 	b := make([]byte, 256)
 	oob := make([]byte, 256)
 
-	err := server.readIncomingPacket(fake, b, oob)
+	err := server.readIncomingPacket(&listener{conn: fake}, b, oob)
 
 	if err != bfd.ErrInvalidPacketLength {
 		t.Errorf("%v", err)
@@ -595,8 +823,7 @@ func TestHandleIncomingPacketsSuccess(t *testing.T) {
 	fake := &FakeConn{}
 
 	fake.n = 40
-	fake.oobn = 16
-	fake.oob = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 255, 255}
+	fake.oob = hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 255)
 	fake.data, _ = (&bfd.ControlPacket{
 		Version: 1,
 	}).MarshalBinary()
@@ -604,12 +831,116 @@ func TestHandleIncomingPacketsSuccess(t *testing.T) {
 	b := make([]byte, 256)
 	oob := make([]byte, 256)
 
-	err := server.readIncomingPacket(fake, b, oob)
+	err := server.readIncomingPacket(&listener{conn: fake}, b, oob)
 
 	if err != nil {
 		t.Errorf("%v", err)
 		t.Fail()
 	}
+
+	pkt := queuedPacket(t, server)
+	defer pkt.release()
+
+	if pkt.buf == nil || pkt.packet != &pkt.buf.control || len(pkt.raw) != bfd.MINIMUM_SIZE {
+		t.Errorf("Expected the packet to be held by a pooled buffer, got %v", pkt)
+	}
+}
+
+func BenchmarkReadIncomingPacket(b *testing.B) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	fake := &FakeConn{
+		addr: &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 49152},
+		oob:  hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 255),
+	}
+
+	fake.data, _ = (&bfd.ControlPacket{
+		Version:          1,
+		DetectMultiplier: 3,
+		MyDiscriminator:  1,
+	}).MarshalBinary()
+
+	l := &listener{conn: fake}
+	buf := make([]byte, 65536)
+	oob := make([]byte, 256)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := server.readIncomingPacket(l, buf, oob); err != nil {
+			b.Fatalf("%v", err)
+		}
+
+		pkt := queuedPacket(b, server)
+		pkt.release()
+	}
+}
+
+func BenchmarkReadAuthenticatedPacket(b *testing.B) {
+	for _, authType := range []bfd.AuthenticationType{bfd.MeticulousKeyedMD5, bfd.MeticulousKeyedSHA1, bfd.MeticulousKeyedHMACSHA256} {
+		b.Run(authType.String(), func(b *testing.B) {
+			server := NewBfdServer()
+			defer server.Shutdown()
+
+			provider, _ := bfd.GetAuthenticationProvider(authType)
+			key := []byte("secret")
+
+			peer := Setup(b)
+			defer peer.Shutdown()
+
+			peer.AuthType = authType
+			peer.AuthKeyId = 1
+			peer.AuthKey = key
+
+			fake := &FakeConn{
+				addr: &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 49152},
+				oob:  hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 255),
+			}
+
+			fake.data, _ = (&bfd.ControlPacket{
+				Version:              1,
+				DetectMultiplier:     3,
+				MyDiscriminator:      1,
+				AuthenticationHeader: provider.NewHeader(1, key, 10),
+			}).MarshalBinary()
+
+			l := &listener{conn: fake}
+			buf := make([]byte, 65536)
+			oob := make([]byte, 256)
+
+			receive := func() {
+				if err := server.readIncomingPacket(l, buf, oob); err != nil {
+					b.Fatalf("%v", err)
+				}
+
+				pkt := queuedPacket(b, server)
+
+				// the same packet is received again, its sequence number isn't known yet
+				peer.Lock()
+				peer.AuthSequenceKnown = 0
+				peer.Unlock()
+
+				if err := peer.authenticate(pkt.packet, pkt.raw); err != nil {
+					b.Fatalf("%v", err)
+				}
+
+				pkt.release()
+			}
+
+			if allocs := testing.AllocsPerRun(100, receive); allocs != 0 {
+				b.Errorf("Expected no allocations, got %v per packet", allocs)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				receive()
+			}
+		})
+	}
 }
 
 func TestCheckPacketInvalidVersion(t *testing.T) {
@@ -690,3 +1021,600 @@ func TestCheckPacketValid(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestAddPeerWithAuthentication(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	p, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		DetectMultiplier: 1,
+		Authentication: &api.Authentication{
+			Type:     api.AuthenticationType_KEYED_SHA1,
+			Password: "secret",
+			KeyId:    5,
+		},
+	})
+
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	if p.AuthType != bfd.KeyedSHA1 || p.AuthKeyId != 5 || string(p.AuthKey) != "secret" {
+		t.Fail()
+	}
+
+	if _, ok := p.NewPacket(bfd.No, bfd.No).AuthenticationHeader.(*bfd.KeyedSHA1Header); !ok {
+		t.Errorf("Expected packets to be authenticated")
+	}
+
+	server.ListPeer(
+		context.Background(),
+		func(uuid []byte, peer *api.Peer, violations []*api.ViolationCount) error {
+			if peer.Authentication.Type != api.AuthenticationType_KEYED_SHA1 || peer.Authentication.Password != "" {
+				t.Errorf("Unexpected authentication %v", peer.Authentication)
+			}
+
+			return nil
+		},
+	)
+}
+
+func TestAddPeerInvalidAuthentication(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	_, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		DetectMultiplier: 1,
+		Authentication: &api.Authentication{
+			Type:     api.AuthenticationType_KEYED_MD5,
+			Password: "0123456789abcdefg",
+		},
+	})
+
+	if err != ErrInvalidAuthenticationKey {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestAddPeerWithKeyChain(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	_, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		DetectMultiplier: 1,
+		Authentication: &api.Authentication{
+			Type:     api.AuthenticationType_KEYED_MD5,
+			KeyChain: "core",
+		},
+	})
+
+	if err != ErrKeyChainNotFound {
+		t.Errorf("Expected %v, got %v", ErrKeyChainNotFound, err)
+	}
+
+	err = server.AddKeyChain(&api.KeyChain{
+		Name: "core",
+		Keys: []*api.Key{{Id: 1, Password: "secret"}},
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := server.AddKeyChain(&api.KeyChain{Name: "core"}); err != ErrKeyChainExists {
+		t.Errorf("Expected %v, got %v", ErrKeyChainExists, err)
+	}
+
+	p, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		DetectMultiplier: 1,
+		Authentication: &api.Authentication{
+			Type:     api.AuthenticationType_KEYED_MD5,
+			KeyChain: "core",
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if p.KeyChain == nil || p.KeyChain.Name != "core" {
+		t.Errorf("Expected peer to reference key chain core")
+	}
+
+	// keys of a chain used by a MD5 peer are limited to 16 bytes
+	err = server.AddKey("core", &api.Key{Id: 2, Password: "01234567890123456789"})
+
+	if err != ErrInvalidAuthenticationKey {
+		t.Errorf("Expected %v, got %v", ErrInvalidAuthenticationKey, err)
+	}
+
+	if err := server.AddKey("core", &api.Key{Id: 2, Password: "rotated"}); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if err := server.DeleteKeyChain("core"); err != ErrKeyChainInUse {
+		t.Errorf("Expected %v, got %v", ErrKeyChainInUse, err)
+	}
+
+	server.ListPeer(
+		context.Background(),
+		func(uuid []byte, peer *api.Peer, violations []*api.ViolationCount) error {
+			if peer.Authentication.KeyChain != "core" {
+				t.Errorf("Unexpected authentication %v", peer.Authentication)
+			}
+
+			return nil
+		},
+	)
+
+	server.ListKeyChain(
+		context.Background(),
+		func(chain *api.KeyChain) error {
+			if chain.Name != "core" || len(chain.Keys) != 2 {
+				t.Errorf("Unexpected key chain %v", chain)
+			}
+
+			return nil
+		},
+	)
+
+	if err := server.DeletePeer(p.GetUuid()); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := server.DeleteKeyChain("core"); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestAddPeerMultiHop(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	p, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		DetectMultiplier: 3,
+		IsMultiHop:       true,
+		LocalAddress:     "127.0.0.1",
+		MinTtl:           250,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if p.Address.Port != BFD_MULTIHOP_PORT || !p.IsMultiHop || p.MinTTL != 250 || !p.LocalAddress.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("Unexpected multi hop peer %v", p)
+	}
+
+	invalid := []struct {
+		peer *api.Peer
+		err  error
+	}{
+		{&api.Peer{Address: "127.0.0.1", DetectMultiplier: 3, IsMultiHop: true, EchoInterval: 50}, ErrEchoMultiHop},
+		{&api.Peer{Address: "127.0.0.1", DetectMultiplier: 3, IsMultiHop: true, MinTtl: 256}, ErrInvalidMinTTL},
+		{&api.Peer{Address: "127.0.0.1", DetectMultiplier: 3, IsMultiHop: true, LocalAddress: "local"}, ErrInvalidIP},
+	}
+
+	for _, test := range invalid {
+		if _, err := server.AddPeer(test.peer); err != test.err {
+			t.Errorf("Expected %v, got %v", test.err, err)
+		}
+	}
+}
+
+func TestHandlePacketMultiHop(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	p, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		DetectMultiplier: 3,
+		IsMultiHop:       true,
+		LocalAddress:     "127.0.0.2",
+		MinTtl:           250,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	newPacket := func(dst string, ttl uint8, multiHop bool) packet {
+		return packet{
+			addr: &net.UDPAddr{
+				IP:   net.ParseIP("127.0.0.1"),
+				Port: 15662,
+			},
+			packet: &bfd.ControlPacket{
+				Version:          1,
+				DetectMultiplier: 3,
+				MyDiscriminator:  60,
+				State:            bfd.Down,
+			},
+			dst:      net.ParseIP(dst),
+			ttl:      ttl,
+			multiHop: multiHop,
+		}
+	}
+
+	if err := server.handlePacket(newPacket("127.0.0.2", 250, true)); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if err := server.handlePacket(newPacket("127.0.0.2", 249, true)); err != ErrInvalidTTL {
+		t.Errorf("Expected %v, got %v", ErrInvalidTTL, err)
+	}
+
+	// sessions are matched by the (source, destination) pair
+	if err := server.handlePacket(newPacket("127.0.0.3", 255, true)); err != ErrPeerNotFound {
+		t.Errorf("Expected %v, got %v", ErrPeerNotFound, err)
+	}
+
+	if err := server.handlePacket(newPacket("127.0.0.2", 255, false)); err != ErrPeerNotFound {
+		t.Errorf("Expected %v, got %v", ErrPeerNotFound, err)
+	}
+
+	// single hop packets are not accepted for a multi hop session
+	pkt := newPacket("127.0.0.2", 255, false)
+	pkt.packet.YourDiscriminator = p.GetLocal().GetDiscriminator()
+
+	if err := server.handlePacket(pkt); err != ErrSessionTypeMismatch {
+		t.Errorf("Expected %v, got %v", ErrSessionTypeMismatch, err)
+	}
+}
+
+func TestHandleIncomingPacketsMultiHopTTL(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	fake := &FakeConn{}
+
+	fake.oob = hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 250)
+	fake.data, _ = (&bfd.ControlPacket{
+		Version: 1,
+	}).MarshalBinary()
+
+	b := make([]byte, 256)
+	oob := make([]byte, 256)
+
+	l := &listener{
+		conn:     fake,
+		local:    &net.UDPAddr{IP: net.ParseIP("127.0.0.2"), Port: BFD_MULTIHOP_PORT},
+		multiHop: true,
+	}
+
+	if err := server.readIncomingPacket(l, b, oob); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pkt := queuedPacket(t, server)
+
+	if pkt.ttl != 250 || !pkt.multiHop || !pkt.dst.Equal(net.ParseIP("127.0.0.2")) {
+		t.Errorf("Unexpected packet context %v", pkt)
+	}
+}
+
+// LoopbackDialUdp dials 127.0.0.1 instead, link local addresses aren't reachable on every test host
+func LoopbackDialUdp(network string, laddr, raddr *net.UDPAddr) (*net.UDPConn, error) {
+	return net.DialUDP("udp", nil, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: raddr.Port})
+}
+
+func TestAddPeerIPv6(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	server.transport = dialTransport{dial: LoopbackDialUdp}
+
+	tests := []struct {
+		address  string
+		expected string
+	}{
+		{"::1", "[::1]:3784"},
+		{"[::1]", "[::1]:3784"},
+		{"[::1]:4000", "[::1]:4000"},
+		{"fe80::1%lo", "[fe80::1%lo]:3784"},
+		{"[fe80::1%lo]:4000", "[fe80::1%lo]:4000"},
+	}
+
+	for _, test := range tests {
+		p, err := server.AddPeer(&api.Peer{
+			Address:          test.address,
+			DetectMultiplier: 1,
+		})
+
+		if err != nil {
+			t.Errorf("%s: %v", test.address, err)
+			continue
+		}
+
+		if p.Address.String() != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.address, test.expected, p.Address.String())
+		}
+	}
+}
+
+func TestAddPeerInvalidZone(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	_, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1%lo",
+		DetectMultiplier: 1,
+	})
+
+	if err != ErrInvalidAddress {
+		t.Errorf("Expected %v, got %v", ErrInvalidAddress, err)
+	}
+}
+
+func TestHandleIncomingPacketsIPv6HopLimit(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	fake := &FakeConn{}
+
+	fake.addr = &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 49152}
+	fake.data, _ = (&bfd.ControlPacket{
+		Version: 1,
+	}).MarshalBinary()
+
+	b := make([]byte, 256)
+	oob := make([]byte, 256)
+
+	fake.oob = hopLimitCmsg(syscall.IPPROTO_IPV6, syscall.IPV6_HOPLIMIT, 254)
+
+	if err := server.readIncomingPacket(&listener{conn: fake}, b, oob); err != ErrInvalidTTL {
+		t.Errorf("Expected %v, got %v", ErrInvalidTTL, err)
+	}
+
+	fake.oob = hopLimitCmsg(syscall.IPPROTO_IPV6, syscall.IPV6_HOPLIMIT, 255)
+
+	if err := server.readIncomingPacket(&listener{conn: fake}, b, oob); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pkt := queuedPacket(t, server)
+
+	if pkt.ttl != 255 || !pkt.addr.IP.Equal(net.ParseIP("2001:db8::1")) {
+		t.Errorf("Unexpected packet context %v", pkt)
+	}
+}
+
+func TestHandleIncomingPacketsMissingTTL(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	fake := &FakeConn{}
+
+	fake.data, _ = (&bfd.ControlPacket{
+		Version: 1,
+	}).MarshalBinary()
+
+	b := make([]byte, 256)
+	oob := make([]byte, 256)
+
+	if err := server.readIncomingPacket(&listener{conn: fake}, b, oob); err != ErrInvalidTTL {
+		t.Errorf("Expected %v, got %v", ErrInvalidTTL, err)
+	}
+}
+
+func TestHandlePacketLinkLocalZone(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	server.transport = dialTransport{dial: LoopbackDialUdp}
+
+	p, err := server.AddPeer(&api.Peer{
+		Address:          "fe80::1%lo",
+		DetectMultiplier: 1,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if p.IfIndex == 0 {
+		t.Fatalf("Expected the interface index of lo")
+	}
+
+	pkt := &packet{
+		addr:    &net.UDPAddr{IP: net.ParseIP("fe80::1")},
+		ifIndex: p.IfIndex + 1,
+	}
+
+	if p.matchesPacket(pkt) {
+		t.Errorf("Packets received on another interface shouldn't match")
+	}
+
+	pkt.ifIndex = p.IfIndex
+
+	if !p.matchesPacket(pkt) {
+		t.Errorf("Packets received on the interface of the peer should match")
+	}
+}
+
+func TestHandleIncomingPacketsDestination(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	fake := &FakeConn{}
+
+	fake.data, _ = (&bfd.ControlPacket{
+		Version: 1,
+	}).MarshalBinary()
+
+	b := make([]byte, 256)
+	oob := make([]byte, 256)
+
+	tests := []struct {
+		oob []byte
+		dst net.IP
+	}{
+		{
+			append(hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 255), pktInfoCmsg(net.ParseIP("192.0.2.1"), 3)...),
+			net.ParseIP("192.0.2.1"),
+		},
+		{
+			append(pktInfoCmsg(net.ParseIP("2001:db8::2"), 3), hopLimitCmsg(syscall.IPPROTO_IPV6, syscall.IPV6_HOPLIMIT, 255)...),
+			net.ParseIP("2001:db8::2"),
+		},
+	}
+
+	// the listener address is only used if no destination is received
+	l := &listener{
+		conn:  fake,
+		local: &net.UDPAddr{IP: net.ParseIP("127.0.0.2"), Port: BFD_PORT},
+	}
+
+	for _, test := range tests {
+		fake.oob = test.oob
+
+		if err := server.readIncomingPacket(l, b, oob); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		pkt := queuedPacket(t, server)
+
+		if pkt.ttl != 255 || pkt.ifIndex != 3 || !pkt.dst.Equal(test.dst) {
+			t.Errorf("Unexpected packet context %v", pkt)
+		}
+	}
+}
+
+func TestHandlePacketAddressMismatch(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	p, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		LocalAddress:     "127.0.0.2",
+		DetectMultiplier: 1,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	newPacket := func(src, dst string) packet {
+		return packet{
+			addr: &net.UDPAddr{IP: net.ParseIP(src), Port: 15662},
+			packet: &bfd.ControlPacket{
+				Version:           1,
+				DetectMultiplier:  3,
+				MyDiscriminator:   60,
+				YourDiscriminator: p.GetLocal().GetDiscriminator(),
+				State:             bfd.Down,
+			},
+			dst: net.ParseIP(dst),
+			ttl: 255,
+		}
+	}
+
+	if err := server.handlePacket(newPacket("127.0.0.3", "127.0.0.2")); err != ErrAddressMismatch {
+		t.Errorf("Expected %v, got %v", ErrAddressMismatch, err)
+	}
+
+	if err := server.handlePacket(newPacket("127.0.0.1", "127.0.0.3")); err != ErrAddressMismatch {
+		t.Errorf("Expected %v, got %v", ErrAddressMismatch, err)
+	}
+
+	if err := server.handlePacket(newPacket("127.0.0.1", "127.0.0.2")); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestAddPeerPassive(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	_, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		DetectMultiplier: 1,
+		Passive:          true,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	server.ListPeer(context.Background(), func(uuid []byte, peer *api.Peer, violations []*api.ViolationCount) error {
+		if !peer.Passive {
+			t.Errorf("Expected the passive role in the api peer")
+		}
+
+		return nil
+	})
+}
+
+func TestAddPeerPadToSize(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	if _, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		DetectMultiplier: 1,
+		PadToSize:        MAX_PAD_TO_SIZE + 1,
+	}); err != ErrInvalidPadToSize {
+		t.Errorf("Expected %v, got %v", ErrInvalidPadToSize, err)
+	}
+
+	peer, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		DetectMultiplier: 1,
+		PadToSize:        1400,
+	})
+
+	if err == ErrDontFragmentNotSupported {
+		t.Skipf("%v", err)
+	}
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if peer.PadToSize != 1400 {
+		t.Errorf("Expected a padding size of 1400, got %d", peer.PadToSize)
+	}
+
+	// the loopback MTU is large enough
+	if err := peer.Send(peer.NewPacket(bfd.No, bfd.No)); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestHandleIncomingPacketsPadded(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	data, _ := (&bfd.ControlPacket{
+		Version: 1,
+	}).MarshalBinary()
+
+	fake := &FakeConn{
+		data: bfd.Pad(data, 1400),
+		oob:  hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 255),
+	}
+
+	l := &listener{
+		conn: fake,
+	}
+
+	b := make([]byte, 65536)
+	oob := make([]byte, 256)
+
+	if err := server.readIncomingPacket(l, b, oob); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pkt := queuedPacket(t, server)
+
+	// the digest is verified without the padding
+	if !bytes.Equal(pkt.raw, data) {
+		t.Errorf("Expected the raw packet without padding, got %d bytes", len(pkt.raw))
+	}
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+)
+
+/*
+	Every change of a session publishes an immutable snapshot of it, the
+	local and remote state together with the negotiated timers and the
+	counters. The changes are made under the lock of the peer, the API,
+	the watchers and the packet handling read the snapshot without taking
+	it. They always see a local and remote state that belong together and
+	never wait for the timers or the receive path of the session.
+*/
+
+// Snapshot is an immutable view of a session, it's replaced as a whole whenever the session changes
+type Snapshot struct {
+	local         *PeerState
+	remote        *PeerState
+	polled        *PeerState // local state including the changes of an active poll sequence
+	pollActive    bool
+	detectionTime time.Duration
+	transitions   uint64
+	received      uint64
+}
+
+func (s *Snapshot) GetLocal() *PeerState {
+	return s.local
+}
+
+func (s *Snapshot) GetRemote() *PeerState {
+	return s.remote
+}
+
+func (s *Snapshot) IsPollActive() bool {
+	return s.pollActive
+}
+
+// GetTxInterval returns the negotiated transmit interval in µs, before the jitter is applied
+func (s *Snapshot) GetTxInterval() uint32 {
+	return max(s.local.desiredMinTxInterval, s.remote.requiredMinRxInterval)
+}
+
+// GetDetectionTime returns the detection time the expiry of the session was last scheduled with
+func (s *Snapshot) GetDetectionTime() time.Duration {
+	return s.detectionTime
+}
+
+// GetTransitions returns the number of changes of the local session state
+func (s *Snapshot) GetTransitions() uint64 {
+	return s.transitions
+}
+
+// GetReceivedPackets returns the number of received packets applied to the session
+func (s *Snapshot) GetReceivedPackets() uint64 {
+	return s.received
+}
+
+func (s *Snapshot) ToApi() *api.PeerStateResponse {
+	return &api.PeerStateResponse{
+		Local:  s.local.ToApi(),
+		Remote: s.remote.ToApi(),
+	}
+}
+
+// Snapshot returns the current state of the session, it doesn't take the lock
+func (p *Peer) Snapshot() *Snapshot {
+	return p.snapshot.Load().(*Snapshot)
+}
+
+// publish replaces the snapshot with the current state, the lock needs to be held by the caller
+func (p *Peer) publish() *Snapshot {
+	s := &Snapshot{
+		local:         p.local,
+		remote:        p.remote,
+		polled:        p.local,
+		pollActive:    p.PollActive,
+		detectionTime: p.detectionTime,
+		transitions:   p.transitions,
+		received:      p.received,
+	}
+
+	// the timer values being polled for are advertised before they're used locally
+	if p.PollActive && len(p.pollUpdates) > 0 {
+		s.polled = p.local.Clone(p.pollUpdates)
+	}
+
+	p.snapshot.Store(s)
+
+	return s
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+func TestSnapshotHandlePacket(t *testing.T) {
+	p := Setup(t)
+	defer p.Shutdown()
+
+	p.conn = &FakeConn{}
+	p.Interval = 100000
+	p.Start()
+
+	before := p.Snapshot()
+	watcher := p.Watch()
+
+	pkt := &bfd.ControlPacket{
+		State:                 bfd.Init,
+		DetectMultiplier:      3,
+		MyDiscriminator:       70,
+		DesiredMinTxInterval:  100000,
+		RequiredMinRxInterval: 200000,
+	}
+
+	if err := p.handlePacket(pkt, nil); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	snapshot := p.Snapshot()
+
+	// the local and remote side change together
+	if snapshot.GetLocal().GetSessionState() != bfd.Up || snapshot.GetRemote().GetSessionState() != bfd.Init {
+		t.Errorf("Unexpected states %v and %v", snapshot.GetLocal(), snapshot.GetRemote())
+	}
+
+	if snapshot.GetReceivedPackets() != 1 || snapshot.GetTransitions() != 1 {
+		t.Errorf("Expected 1 packet and 1 transition, got %d and %d", snapshot.GetReceivedPackets(), snapshot.GetTransitions())
+	}
+
+	if snapshot.GetTxInterval() != 200000 || snapshot.GetDetectionTime() != 300*time.Millisecond {
+		t.Errorf("Unexpected timers %d and %v", snapshot.GetTxInterval(), snapshot.GetDetectionTime())
+	}
+
+	// a published snapshot never changes
+	if before.GetLocal().GetSessionState() != bfd.Down || before.GetReceivedPackets() != 0 {
+		t.Errorf("Expected the old snapshot to be unchanged")
+	}
+
+	select {
+	case state := <-watcher.Event():
+		if state.Local.State != api.SessionState_UP || state.Remote.State != api.SessionState_INIT {
+			t.Errorf("Expected the watcher to see both sides of the change, got %v", state)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected the watcher to be notified")
+	}
+}
+
+func TestSnapshotWithoutLock(t *testing.T) {
+	p := Setup(t)
+	defer p.Shutdown()
+
+	done := make(chan *Snapshot)
+
+	p.Lock()
+
+	go func() {
+		p.GetLocal()
+		p.GetRemote()
+		p.isDemandActive()
+		done <- p.Snapshot()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the state to be read while the peer is locked")
+	}
+
+	p.Unlock()
+}
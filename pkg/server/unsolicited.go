@@ -0,0 +1,177 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+
+	"github.com/Thoro/bfd/pkg/api"
+)
+
+/*
+
+https://tools.ietf.org/html/rfc9468
+
+Unsolicited BFD lets a remote system bring up a single hop session without any
+configuration for it on our side. It's enabled per interface, the created
+sessions use a locally configured profile and take the passive role.
+
+*/
+
+var ErrUnsolicitedExists = errors.New("Unsolicited BFD is already enabled on the interface")
+var ErrUnsolicitedNotFound = errors.New("Unsolicited BFD isn't enabled on the interface")
+
+// UnsolicitedInterface answers sessions started by remotes on an interface
+type UnsolicitedInterface struct {
+	Interface   string
+	IfIndex     int
+	Prefixes    []*net.IPNet  // permitted remotes, empty = any
+	Profile     *api.Peer     // timers and authentication of the created sessions, the address is ignored
+	IdleTimeout time.Duration // sessions are removed if no packet was received for this long
+}
+
+// permits checks if a session may be created for a packet from ip
+func (u *UnsolicitedInterface) permits(ip net.IP) bool {
+	if len(u.Prefixes) == 0 {
+		return true
+	}
+
+	for _, prefix := range u.Prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnableUnsolicited creates sessions for remotes within prefixes that send a Down packet on iface
+func (s *BfdServer) EnableUnsolicited(iface string, prefixes []string, profile *api.Peer, idleTimeout time.Duration) error {
+	netIface, err := net.InterfaceByName(iface)
+
+	if err != nil {
+		return ErrInterfaceNotFound
+	}
+
+	ipNets := make([]*net.IPNet, len(prefixes))
+
+	for idx, prefix := range prefixes {
+		_, ipNet, err := net.ParseCIDR(prefix)
+
+		if err != nil {
+			return ErrInvalidPrefix
+		}
+
+		ipNets[idx] = ipNet
+	}
+
+	if profile == nil || profile.DetectMultiplier == 0 {
+		return ErrInvalidDetectionMultiplierSupplied
+	}
+
+	if _, _, _, err := parseAuthentication(profile.Authentication); err != nil {
+		return err
+	}
+
+	if idleTimeout == 0 {
+		idleTimeout = DYNAMIC_IDLE_TIMEOUT
+	}
+
+	/*
+		RFC9468 2
+		Unsolicited BFD sessions are single hop sessions, the local system
+		takes the passive role and doesn't send any packet before it
+		received one from the remote system.
+	*/
+	profile = proto.Clone(profile).(*api.Peer)
+	profile.Passive = true
+	profile.IsMultiHop = false
+	profile.IsVxlan = false
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.unsolicited[iface]; ok {
+		return ErrUnsolicitedExists
+	}
+
+	s.unsolicited[iface] = &UnsolicitedInterface{
+		Interface:   iface,
+		IfIndex:     netIface.Index,
+		Prefixes:    ipNets,
+		Profile:     profile,
+		IdleTimeout: idleTimeout,
+	}
+
+	s.startDynamicExpiry()
+
+	return nil
+}
+
+// DisableUnsolicited stops creating sessions on iface and removes the ones already created
+func (s *BfdServer) DisableUnsolicited(iface string) error {
+	s.Lock()
+	u, ok := s.unsolicited[iface]
+	delete(s.unsolicited, iface)
+
+	sessions := make([]*Peer, 0)
+
+	for _, peer := range s.Sessions {
+		peer.RLock()
+		if ok && peer.unsolicited == u {
+			sessions = append(sessions, peer)
+		}
+		peer.RUnlock()
+	}
+	s.Unlock()
+
+	if !ok {
+		return ErrUnsolicitedNotFound
+	}
+
+	for _, peer := range sessions {
+		s.removePeer(peer)
+	}
+
+	return nil
+}
+
+// unsolicitedInterface returns the interface a packet may create an unsolicited session on, nil if none
+func (s *BfdServer) unsolicitedInterface(pkt *packet) *UnsolicitedInterface {
+	// the interface is needed to tell if unsolicited BFD is enabled
+	if pkt.multiHop || pkt.ifIndex == 0 {
+		return nil
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	for _, u := range s.unsolicited {
+		if u.IfIndex == pkt.ifIndex && u.permits(pkt.addr.IP) {
+			return u
+		}
+	}
+
+	return nil
+}
+
+// createUnsolicitedPeer creates a passive session with the profile of u for the sender of pkt
+func (s *BfdServer) createUnsolicitedPeer(pkt *packet, u *UnsolicitedInterface) (*Peer, error) {
+	peer, err := s.addDynamicPeer(pkt, u.Profile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	peer.Lock()
+	peer.unsolicited = u
+	peer.IfIndex = u.IfIndex
+	peer.Unlock()
+
+	glog.Infof("Created unsolicited session for %s on %s", peer.Address, u.Interface)
+
+	return peer, nil
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+func loopbackIndex(t *testing.T) int {
+	iface, err := net.InterfaceByName("lo")
+
+	if err != nil {
+		t.Skipf("No loopback interface: %v", err)
+	}
+
+	return iface.Index
+}
+
+func TestEnableUnsolicitedInvalid(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	loopbackIndex(t)
+
+	tests := []struct {
+		iface    string
+		prefixes []string
+		profile  *api.Peer
+		err      error
+	}{
+		{"bfd-missing0", nil, &api.Peer{DetectMultiplier: 3}, ErrInterfaceNotFound},
+		{"lo", []string{"127.0.0.1"}, &api.Peer{DetectMultiplier: 3}, ErrInvalidPrefix},
+		{"lo", nil, &api.Peer{}, ErrInvalidDetectionMultiplierSupplied},
+		{"lo", nil, nil, ErrInvalidDetectionMultiplierSupplied},
+	}
+
+	for _, test := range tests {
+		if err := server.EnableUnsolicited(test.iface, test.prefixes, test.profile, 0); err != test.err {
+			t.Errorf("Expected %v, got %v", test.err, err)
+		}
+	}
+
+	if err := server.EnableUnsolicited("lo", nil, &api.Peer{DetectMultiplier: 3}, 0); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := server.EnableUnsolicited("lo", nil, &api.Peer{DetectMultiplier: 3}, 0); err != ErrUnsolicitedExists {
+		t.Errorf("Expected %v, got %v", ErrUnsolicitedExists, err)
+	}
+
+	if err := server.DisableUnsolicited("bfd-missing0"); err != ErrUnsolicitedNotFound {
+		t.Errorf("Expected %v, got %v", ErrUnsolicitedNotFound, err)
+	}
+}
+
+func TestHandlePacketUnsolicitedPeer(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	ifIndex := loopbackIndex(t)
+
+	err := server.EnableUnsolicited("lo", []string{"127.0.0.0/24"}, &api.Peer{
+		DesiredMinTxInterval:  300,
+		RequiredMinRxInterval: 300,
+		DetectMultiplier:      3,
+	}, time.Minute)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	// the interface of the packet is needed
+	if err := server.handlePacket(newDynamicPacket("127.0.0.9", bfd.Down)); err != ErrPeerNotFound {
+		t.Errorf("Expected %v, got %v", ErrPeerNotFound, err)
+	}
+
+	pkt := newDynamicPacket("127.0.1.1", bfd.Down)
+	pkt.ifIndex = ifIndex
+
+	if err := server.handlePacket(pkt); err != ErrPeerNotFound {
+		t.Errorf("Expected %v, got %v", ErrPeerNotFound, err)
+	}
+
+	pkt = newDynamicPacket("127.0.0.9", bfd.Down)
+	pkt.ifIndex = ifIndex
+
+	if err := server.handlePacket(pkt); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(server.Sessions) != 1 {
+		t.Fatalf("Expected an unsolicited session, got %d sessions", len(server.Sessions))
+	}
+
+	var peer *Peer
+
+	for _, p := range server.Sessions {
+		peer = p
+	}
+
+	if peer.unsolicited == nil || !peer.Passive || peer.IfIndex != ifIndex {
+		t.Errorf("Unexpected unsolicited session %v", peer)
+	}
+
+	if idle := server.idleDynamicPeers(time.Now().Add(2 * time.Minute)); len(idle) != 1 {
+		t.Errorf("Expected the unsolicited session to be idle, got %v", idle)
+	}
+
+	if err := server.DisableUnsolicited("lo"); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(server.Sessions) != 0 {
+		t.Errorf("Expected the unsolicited sessions to be removed, got %d sessions", len(server.Sessions))
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"sync/atomic"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+// violationErrors maps the violations that discard a packet to the error of checkPacket
+var violationErrors = map[bfd.Violation]error{
+	bfd.InvalidVersion:              ErrInvalidVersion,
+	bfd.InvalidLength:               bfd.ErrInvalidPacketLength,
+	bfd.InvalidAuthenticationLength: bfd.ErrInvalidPacketLength,
+	bfd.InvalidAuthenticationType:   bfd.ErrInvalidAuthenticationType,
+	bfd.ZeroDetectMultiplier:        ErrInvalidDetectMultiplier,
+	bfd.MultipointSet:               ErrInvalidMultiPoint,
+	bfd.ZeroMyDiscriminator:         ErrInvalidMyDiscriminator,
+	bfd.ZeroYourDiscriminator:       ErrInvalidYourDiscriminator,
+}
+
+// violationCounters counts the received packets per violation, the receiving goroutine
+// updates them while the api reads them. It needs to be the first field of a struct,
+// otherwise the counters aren't aligned for atomic operations on 32 bit platforms.
+type violationCounters [bfd.VIOLATION_TYPES]uint64
+
+func (c *violationCounters) add(violations bfd.Violations) {
+	if violations == 0 {
+		return
+	}
+
+	for v := bfd.Violation(0); v < bfd.VIOLATION_TYPES; v++ {
+		if violations.Has(v) {
+			atomic.AddUint64(&c[v], 1)
+		}
+	}
+}
+
+func (c *violationCounters) get(violation bfd.Violation) uint64 {
+	return atomic.LoadUint64(&c[violation])
+}
+
+// ToApi returns the counters of the violations that occurred
+func (c *violationCounters) ToApi() []*api.ViolationCount {
+	counts := []*api.ViolationCount{}
+
+	for v := bfd.Violation(0); v < bfd.VIOLATION_TYPES; v++ {
+		if count := c.get(v); count > 0 {
+			counts = append(counts, &api.ViolationCount{
+				Violation: api.Violation(v),
+				Count:     count,
+			})
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+func TestHandlePacketViolations(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	peer, err := server.AddPeer(&api.Peer{
+		Address:          "127.0.0.1",
+		DetectMultiplier: 1,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pkt := packet{
+		addr: &net.UDPAddr{
+			IP:   net.ParseIP("127.0.0.1"),
+			Port: 15662,
+		},
+		packet: &bfd.ControlPacket{
+			Version:          1,
+			DetectMultiplier: 3,
+			MyDiscriminator:  60,
+			Multipoint:       bfd.Yes,
+			State:            bfd.Down,
+		},
+	}
+
+	if err := server.handlePacket(pkt); err != ErrInvalidPacket {
+		t.Errorf("Expected %v, got %v", ErrInvalidPacket, err)
+	}
+
+	if peer.violations.get(bfd.MultipointSet) != 1 {
+		t.Errorf("Expected the violation to be counted for the peer, got %v", peer.violations)
+	}
+
+	// reserved fields are counted, but the packet is still accepted
+	pkt.packet.Multipoint = bfd.No
+	pkt.violations = bfd.Violations(1 << uint(bfd.ReservedFieldSet))
+
+	if err := server.handlePacket(pkt); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	if peer.violations.get(bfd.ReservedFieldSet) != 1 || peer.violations.get(bfd.MultipointSet) != 1 {
+		t.Errorf("Unexpected violations %v", peer.violations)
+	}
+
+	counts := peer.violations.ToApi()
+
+	if len(counts) != 2 || counts[0].Violation != api.Violation_MULTIPOINT_SET || counts[0].Count != 1 {
+		t.Errorf("Unexpected api counts %v", counts)
+	}
+}
+
+func TestReadIncomingPacketViolations(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	fake := &FakeConn{}
+	fake.oob = hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 255)
+	fake.data, _ = (&bfd.ControlPacket{
+		Version: 1,
+	}).MarshalBinary()
+
+	l := &listener{conn: fake}
+	b := make([]byte, 256)
+	oob := make([]byte, 256)
+
+	if err := server.readIncomingPacket(l, b, oob); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pkt := queuedPacket(t, server)
+	pkt.release()
+
+	if !pkt.violations.Has(bfd.ZeroDetectMultiplier) || !pkt.violations.Has(bfd.ZeroMyDiscriminator) {
+		t.Errorf("Expected the violations to be passed along, got %s", pkt.violations)
+	}
+
+	// packets that can't be decoded are counted as well
+	fake.data = []byte{255, 255}
+
+	if err := server.readIncomingPacket(l, b, oob); err != bfd.ErrInvalidPacketLength {
+		t.Errorf("Expected %v, got %v", bfd.ErrInvalidPacketLength, err)
+	}
+
+	expected := map[bfd.Violation]uint64{
+		bfd.InvalidVersion:       1,
+		bfd.InvalidLength:        1,
+		bfd.ZeroDetectMultiplier: 1,
+		bfd.ZeroMyDiscriminator:  1,
+	}
+
+	for v := bfd.Violation(0); v < bfd.VIOLATION_TYPES; v++ {
+		if l.violations.get(v) != expected[v] {
+			t.Errorf("Expected %d %s, got %d", expected[v], v, l.violations.get(v))
+		}
+	}
+}
+
+func TestListListener(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	l := &listener{
+		local:    &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: BFD_MULTIHOP_PORT},
+		multiHop: true,
+	}
+
+	l.violations.add(bfd.Violations(1 << uint(bfd.InvalidVersion)))
+	server.conns[l.local.String()] = l
+
+	var responses []*api.ListListenerResponse
+
+	err := server.ListListener(context.Background(), func(response *api.ListListenerResponse) error {
+		responses = append(responses, response)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if len(responses) != 1 || responses[0].Address != "127.0.0.1:4784" || !responses[0].IsMultiHop {
+		t.Fatalf("Unexpected listeners %v", responses)
+	}
+
+	if v := responses[0].Violations; len(v) != 1 || v[0].Violation != api.Violation_INVALID_VERSION || v[0].Count != 1 {
+		t.Errorf("Unexpected violations %v", v)
+	}
+}
@@ -0,0 +1,593 @@
+package server
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// delay of the packets sent over a VirtualNetwork
+	VIRTUAL_NETWORK_LATENCY = time.Millisecond
+
+	// first port handed out to virtual sockets bound to port 0
+	VIRTUAL_EPHEMERAL_PORT = 49152
+)
+
+/*
+	A VirtualNetwork connects servers through in-memory sockets running on
+	a VirtualClock. Time only passes when the network is advanced, which
+	runs the due timers and delivers the sent packets in order on the
+	calling goroutine. The timers of the servers created by NewServer run
+	inline and a delivery waits until the listener handled the packet, so
+	a simulation only depends on its seed and an hour of sessions passes
+	in a fraction of a second.
+
+	Packets are delayed by the latency of the network and dropped with the
+	loss rate of the direction between two hosts. They always arrive with
+	a TTL / Hop Limit of 255.
+*/
+
+var ErrVirtualAddressInUse = errors.New("The virtual address is already in use")
+var ErrVirtualNotConnected = errors.New("The virtual socket isn't connected")
+var ErrInvalidLoss = errors.New("Invalid loss, it should be between 0 and 1")
+
+// VirtualClock is a Clock that only moves forward when it's advanced
+type VirtualClock struct {
+	sync.Mutex
+
+	now    time.Time
+	seq    uint64
+	timers []*virtualTimer // active timers
+}
+
+type virtualTimer struct {
+	clock  *VirtualClock
+	f      func()
+	when   time.Time
+	seq    uint64 // timers with the same deadline run in the order they were set
+	active bool
+}
+
+func NewVirtualClock(now time.Time) *VirtualClock {
+	return &VirtualClock{now: now}
+}
+
+func (c *VirtualClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.now
+}
+
+// AfterFunc calls f once the clock is advanced by d, on the goroutine advancing it
+func (c *VirtualClock) AfterFunc(d time.Duration, f func()) ClockTimer {
+	t := &virtualTimer{clock: c, f: f}
+	t.Reset(d)
+
+	return t
+}
+
+// Advance moves the clock forward by d and runs the timers that are due in the order of
+// their deadline, timers set by them run too if they're due. It isn't safe for concurrent use.
+func (c *VirtualClock) Advance(d time.Duration) {
+	c.Lock()
+	end := c.now.Add(d)
+
+	for t := c.next(end); t != nil; t = c.next(end) {
+		if t.when.After(c.now) {
+			c.now = t.when
+		}
+
+		c.Unlock()
+		t.f()
+		c.Lock()
+	}
+
+	c.now = end
+	c.Unlock()
+}
+
+// next removes and returns the earliest timer due at end, the lock needs to be held by the caller
+func (c *VirtualClock) next(end time.Time) *virtualTimer {
+	index := -1
+
+	for i, t := range c.timers {
+		if t.when.After(end) {
+			continue
+		}
+
+		if index < 0 || t.when.Before(c.timers[index].when) ||
+			(t.when.Equal(c.timers[index].when) && t.seq < c.timers[index].seq) {
+			index = i
+		}
+	}
+
+	if index < 0 {
+		return nil
+	}
+
+	t := c.timers[index]
+	c.remove(index)
+
+	return t
+}
+
+// remove removes the timer at index, the lock needs to be held by the caller
+func (c *VirtualClock) remove(index int) {
+	c.timers[index].active = false
+	c.timers[index] = c.timers[len(c.timers)-1]
+	c.timers[len(c.timers)-1] = nil
+	c.timers = c.timers[:len(c.timers)-1]
+}
+
+func (t *virtualTimer) Reset(d time.Duration) bool {
+	c := t.clock
+
+	c.Lock()
+	defer c.Unlock()
+
+	active := t.active
+
+	c.seq++
+	t.seq = c.seq
+	t.when = c.now.Add(d)
+
+	if !active {
+		t.active = true
+		c.timers = append(c.timers, t)
+	}
+
+	return active
+}
+
+func (t *virtualTimer) Stop() bool {
+	c := t.clock
+
+	c.Lock()
+	defer c.Unlock()
+
+	if !t.active {
+		return false
+	}
+
+	for i, timer := range c.timers {
+		if timer == t {
+			c.remove(i)
+			break
+		}
+	}
+
+	return true
+}
+
+// VirtualNetwork connects the virtual sockets of servers, its hosts have an address each
+type VirtualNetwork struct {
+	sync.Mutex
+
+	clock    *VirtualClock
+	random   *rand.Rand // losses and the seeds of the servers
+	latency  time.Duration
+	loss     map[[2]string]float64   // loss rate from one host to another
+	sockets  map[string]*virtualConn // bound sockets by address and port
+	nextPort int
+}
+
+// NewVirtualNetwork returns a network without loss, its losses and servers are derived from seed
+func NewVirtualNetwork(seed int64) *VirtualNetwork {
+	return &VirtualNetwork{
+		clock:    NewVirtualClock(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		random:   rand.New(rand.NewSource(seed)),
+		latency:  VIRTUAL_NETWORK_LATENCY,
+		loss:     make(map[[2]string]float64, 0),
+		sockets:  make(map[string]*virtualConn, 0),
+		nextPort: VIRTUAL_EPHEMERAL_PORT,
+	}
+}
+
+func (n *VirtualNetwork) Clock() *VirtualClock {
+	return n.clock
+}
+
+// Advance moves the clock of the network forward, see VirtualClock.Advance
+func (n *VirtualNetwork) Advance(d time.Duration) {
+	n.clock.Advance(d)
+}
+
+// SetLatency sets the delay of the packets sent from now on
+func (n *VirtualNetwork) SetLatency(d time.Duration) {
+	n.Lock()
+	defer n.Unlock()
+
+	n.latency = d
+}
+
+// SetLoss sets the rate of the packets lost from one host to the other, 1 drops all of them
+func (n *VirtualNetwork) SetLoss(from, to string, loss float64) error {
+	src := net.ParseIP(from)
+	dst := net.ParseIP(to)
+
+	if src == nil || dst == nil {
+		return ErrInvalidIP
+	}
+
+	if loss < 0 || loss > 1 {
+		return ErrInvalidLoss
+	}
+
+	n.Lock()
+	defer n.Unlock()
+
+	n.loss[[2]string{src.String(), dst.String()}] = loss
+
+	return nil
+}
+
+// Transport returns the sockets of the host with address ip
+func (n *VirtualNetwork) Transport(ip net.IP) Transport {
+	return &virtualTransport{network: n, host: ip}
+}
+
+// NewServer returns a server of the host with address, listening for single and multi hop sessions.
+// Its timers and received packets are handled on the goroutine advancing the network.
+func (n *VirtualNetwork) NewServer(address string) (*BfdServer, error) {
+	ip := net.ParseIP(address)
+
+	if ip == nil {
+		return nil, ErrInvalidIP
+	}
+
+	n.Lock()
+	seed := n.random.Int63()
+	n.Unlock()
+
+	s := NewBfdServer()
+	s.setClock(n.clock, 0)
+	s.SetRand(NewRand(seed))
+	s.SetTransport(n.Transport(ip))
+	s.inbound = newPipeline(0, 0)
+
+	if err := s.Listen(address); err != nil {
+		s.Shutdown()
+		return nil, err
+	}
+
+	if err := s.ListenMultiHop(address); err != nil {
+		s.Shutdown()
+		return nil, err
+	}
+
+	if err := s.Serve(); err != nil {
+		s.Shutdown()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// bind registers a socket under its address, port 0 picks a free port.
+// A wildcard address binds the address of the host.
+func (n *VirtualNetwork) bind(c *virtualConn) error {
+	n.Lock()
+	defer n.Unlock()
+
+	ip := c.local.IP
+
+	if ip == nil || ip.IsUnspecified() {
+		ip = c.host
+	}
+
+	if c.local.Port == 0 {
+		for n.sockets[virtualKey(ip, n.nextPort)] != nil {
+			n.nextPort++
+		}
+
+		c.local.Port = n.nextPort
+		n.nextPort++
+	}
+
+	key := virtualKey(ip, c.local.Port)
+
+	if n.sockets[key] != nil {
+		return ErrVirtualAddressInUse
+	}
+
+	c.key = key
+	n.sockets[key] = c
+
+	return nil
+}
+
+func (n *VirtualNetwork) unbind(c *virtualConn) {
+	n.Lock()
+	defer n.Unlock()
+
+	if n.sockets[c.key] == c {
+		delete(n.sockets, c.key)
+	}
+}
+
+// send copies a packet and delivers it once the latency passed, unless it's lost
+func (n *VirtualNetwork) send(src *net.UDPAddr, b []byte, dst *net.UDPAddr) {
+	n.forward(src, b, dst, nil)
+}
+
+// forward sends a packet to dst through the forwarding plane of the host hop, nil sends it directly
+func (n *VirtualNetwork) forward(src *net.UDPAddr, b []byte, dst *net.UDPAddr, hop net.IP) {
+	n.Lock()
+	latency := n.latency
+	var lost bool
+
+	if hop == nil {
+		lost = n.lost(src.IP, dst.IP)
+	} else {
+		latency *= 2
+		lost = n.lost(src.IP, hop) || n.lost(hop, dst.IP)
+	}
+	n.Unlock()
+
+	if lost {
+		return
+	}
+
+	pkt := virtualPacket{
+		data: append([]byte(nil), b...),
+		src:  src,
+		dst:  dst,
+	}
+
+	n.clock.AfterFunc(latency, func() {
+		n.Lock()
+		c := n.sockets[virtualKey(dst.IP, dst.Port)]
+		n.Unlock()
+
+		if c != nil {
+			c.deliver(pkt)
+		}
+	})
+}
+
+// lost decides whether a packet from one host to the other is lost, the lock needs to be held by the caller
+func (n *VirtualNetwork) lost(from, to net.IP) bool {
+	loss := n.loss[[2]string{from.String(), to.String()}]
+
+	return loss > 0 && n.random.Float64() < loss
+}
+
+func virtualKey(ip net.IP, port int) string {
+	return net.JoinHostPort(ip.String(), strconv.Itoa(port))
+}
+
+// virtualTransport opens the virtual sockets of a host
+type virtualTransport struct {
+	network *VirtualNetwork
+	host    net.IP
+}
+
+func (t *virtualTransport) ListenUDP(network string, laddr *net.UDPAddr) (UDPConn, error) {
+	return t.open(laddr, nil, true)
+}
+
+func (t *virtualTransport) DialUDP(network string, laddr, raddr *net.UDPAddr) (UDPConn, error) {
+	return t.open(laddr, raddr, false)
+}
+
+// DialEcho sends the echo packets through the host nextHop, which forwards them back
+func (t *virtualTransport) DialEcho(local net.IP, nextHop *net.UDPAddr, clock Clock) (EchoConn, error) {
+	if local == nil {
+		local = t.host
+	}
+
+	return &virtualEchoConn{network: t.network, local: local, nextHop: nextHop.IP}, nil
+}
+
+func (t *virtualTransport) open(laddr, raddr *net.UDPAddr, listening bool) (UDPConn, error) {
+	local := &net.UDPAddr{}
+
+	if laddr != nil {
+		local.IP = laddr.IP
+		local.Port = laddr.Port
+	}
+
+	c := &virtualConn{
+		network:   t.network,
+		host:      t.host,
+		local:     local,
+		remote:    raddr,
+		listening: listening,
+	}
+	c.cond = sync.NewCond(&c.Mutex)
+
+	if err := t.network.bind(c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+type virtualPacket struct {
+	data []byte
+	src  *net.UDPAddr
+	dst  *net.UDPAddr
+}
+
+/*
+	A delivery to a listening socket waits until its reader handled the
+	packet and is back waiting for the next one, so the state of the
+	servers only changes while the network is advanced. Dialed sockets
+	wait for their reader once it started reading.
+*/
+
+// virtualEchoConn sends echo packets addressed to the local host via the next hop
+type virtualEchoConn struct {
+	network *VirtualNetwork
+	local   net.IP
+	nextHop net.IP
+}
+
+func (c *virtualEchoConn) Write(b []byte) (int, error) {
+	addr := &net.UDPAddr{IP: c.local, Port: ECHO_PORT}
+
+	c.network.forward(addr, b, addr, c.nextHop)
+
+	return len(b), nil
+}
+
+func (c *virtualEchoConn) LocalAddr() net.Addr {
+	return &net.UDPAddr{IP: c.local, Port: ECHO_PORT}
+}
+
+func (c *virtualEchoConn) Close() error {
+	return nil
+}
+
+// virtualConn is a socket of a VirtualNetwork
+type virtualConn struct {
+	sync.Mutex
+
+	network   *VirtualNetwork
+	host      net.IP // address of the host, the source of packets sent from a wildcard socket
+	local     *net.UDPAddr
+	remote    *net.UDPAddr // nil if not connected
+	key       string       // address the socket is bound to
+	cond      *sync.Cond
+	queue     []virtualPacket
+	listening bool // deliveries wait for the reader
+	waiting   bool // the reader waits for a packet
+	closed    bool
+}
+
+func (c *virtualConn) deliver(pkt virtualPacket) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	c.queue = append(c.queue, pkt)
+	c.cond.Broadcast()
+
+	for c.listening && !c.closed && (len(c.queue) > 0 || !c.waiting) {
+		c.cond.Wait()
+	}
+}
+
+// read waits for the next packet
+func (c *virtualConn) read() (virtualPacket, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.listening = true
+
+	for len(c.queue) == 0 && !c.closed {
+		c.waiting = true
+		c.cond.Broadcast()
+		c.cond.Wait()
+	}
+
+	c.waiting = false
+
+	if c.closed {
+		return virtualPacket{}, net.ErrClosed
+	}
+
+	pkt := c.queue[0]
+	c.queue = c.queue[1:]
+
+	return pkt, nil
+}
+
+func (c *virtualConn) readPacket(b []byte) (int, *controlMessage, *net.UDPAddr, error) {
+	pkt, err := c.read()
+
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	return copy(b, pkt.data), &controlMessage{ttl: 255, dst: pkt.dst.IP}, pkt.src, nil
+}
+
+// ReadMsgUDP reads a packet without control messages, the context is reported by readPacket
+func (c *virtualConn) ReadMsgUDP(b, oob []byte) (int, int, int, *net.UDPAddr, error) {
+	pkt, err := c.read()
+
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+
+	return copy(b, pkt.data), 0, 0, pkt.src, nil
+}
+
+func (c *virtualConn) ReadFromUDP(b []byte) (int, *net.UDPAddr, error) {
+	pkt, err := c.read()
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return copy(b, pkt.data), pkt.src, nil
+}
+
+func (c *virtualConn) Read(b []byte) (int, error) {
+	n, _, err := c.ReadFromUDP(b)
+
+	return n, err
+}
+
+func (c *virtualConn) Write(b []byte) (int, error) {
+	if c.remote == nil {
+		return 0, ErrVirtualNotConnected
+	}
+
+	return c.WriteToUDP(b, c.remote)
+}
+
+func (c *virtualConn) WriteToUDP(b []byte, addr *net.UDPAddr) (int, error) {
+	c.Lock()
+	closed := c.closed
+	c.Unlock()
+
+	if closed {
+		return 0, net.ErrClosed
+	}
+
+	src := &net.UDPAddr{IP: c.local.IP, Port: c.local.Port}
+
+	if src.IP == nil || src.IP.IsUnspecified() {
+		src.IP = c.host
+	}
+
+	c.network.send(src, b, addr)
+
+	return len(b), nil
+}
+
+func (c *virtualConn) LocalAddr() net.Addr {
+	return &net.UDPAddr{IP: c.local.IP, Port: c.local.Port}
+}
+
+func (c *virtualConn) File() (*os.File, error) {
+	return nil, ErrNotImplemented
+}
+
+func (c *virtualConn) Close() error {
+	c.Lock()
+
+	if c.closed {
+		c.Unlock()
+		return net.ErrClosed
+	}
+
+	c.closed = true
+	c.cond.Broadcast()
+	c.Unlock()
+
+	c.network.unbind(c)
+
+	return nil
+}
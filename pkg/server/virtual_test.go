@@ -0,0 +1,173 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+// virtualPair returns two servers of a virtual network with a session to each other
+func virtualPair(t *testing.T, seed int64) (*VirtualNetwork, *Peer, *Peer) {
+	network := NewVirtualNetwork(seed)
+	peers := make([]*Peer, 2)
+	addresses := []string{"10.0.0.1", "10.0.0.2"}
+
+	for i, address := range addresses {
+		server, err := network.NewServer(address)
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Cleanup(server.Shutdown)
+
+		peers[i], err = server.AddPeer(&api.Peer{
+			Address:               addresses[1-i],
+			DesiredMinTxInterval:  100000,
+			RequiredMinRxInterval: 100,
+			DetectMultiplier:      3,
+		})
+
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+
+	return network, peers[0], peers[1]
+}
+
+func TestVirtualClock(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(0, 0))
+
+	var order []int
+
+	clock.AfterFunc(2*time.Second, func() { order = append(order, 2) })
+	first := clock.AfterFunc(time.Second, func() { order = append(order, 1) })
+	stopped := clock.AfterFunc(time.Second, func() { order = append(order, 0) })
+
+	if !stopped.Stop() || stopped.Stop() {
+		t.Errorf("Expected the timer to be stopped once")
+	}
+
+	clock.Advance(500 * time.Millisecond)
+
+	if len(order) != 0 || clock.Now() != time.Unix(0, 0).Add(500*time.Millisecond) {
+		t.Errorf("Expected no timer to run, got %v at %v", order, clock.Now())
+	}
+
+	// timers set by a timer run within the same advance
+	first.Reset(2 * time.Second)
+	clock.AfterFunc(time.Second, func() {
+		clock.AfterFunc(time.Second, func() { order = append(order, 3) })
+	})
+
+	clock.Advance(2 * time.Second)
+
+	if len(order) != 3 || order[0] != 2 || order[1] != 1 || order[2] != 3 {
+		t.Errorf("Expected the timers to run in order, got %v", order)
+	}
+}
+
+func TestVirtualNetworkSession(t *testing.T) {
+	network, a, b := virtualPair(t, 1)
+
+	network.Advance(5 * time.Second)
+
+	if a.GetLocal().GetSessionState() != bfd.Up || b.GetLocal().GetSessionState() != bfd.Up {
+		t.Fatalf("Expected the sessions to be up, got %v and %v", a.GetLocal(), b.GetLocal())
+	}
+
+	// losing the packets of one direction takes both sides down
+	network.SetLoss("10.0.0.1", "10.0.0.2", 1)
+	network.Advance(time.Second)
+
+	if b.GetLocal().GetSessionState() != bfd.Down || b.GetLocal().diagnosticCode != bfd.ControlDetectionTimeExpired {
+		t.Errorf("Expected the detection time to expire, got %v", b.GetLocal())
+	}
+
+	if a.GetLocal().GetSessionState() != bfd.Down {
+		t.Errorf("Expected the neighbor to signal down, got %v", a.GetLocal())
+	}
+
+	network.SetLoss("10.0.0.1", "10.0.0.2", 0)
+	network.Advance(5 * time.Second)
+
+	if a.GetLocal().GetSessionState() != bfd.Up || b.GetLocal().GetSessionState() != bfd.Up {
+		t.Errorf("Expected the sessions to come back up, got %v and %v", a.GetLocal(), b.GetLocal())
+	}
+}
+
+func TestVirtualNetworkLoss(t *testing.T) {
+	network, a, b := virtualPair(t, 1)
+
+	network.Advance(5 * time.Second)
+
+	// a session with a detection multiplier of 3 survives a light loss
+	network.SetLoss("10.0.0.1", "10.0.0.2", 0.05)
+	network.SetLoss("10.0.0.2", "10.0.0.1", 0.05)
+	network.Advance(10 * time.Second)
+
+	if a.GetLocal().GetSessionState() != bfd.Up || b.GetLocal().GetSessionState() != bfd.Up {
+		t.Errorf("Expected the sessions to stay up, got %v and %v", a.GetLocal(), b.GetLocal())
+	}
+
+	received := b.Snapshot().GetReceivedPackets()
+
+	if received == 0 || received >= a.Snapshot().GetReceivedPackets()+100 {
+		t.Errorf("Unexpected received packets %d and %d", received, a.Snapshot().GetReceivedPackets())
+	}
+}
+
+func TestVirtualNetworkDeterministic(t *testing.T) {
+	run := func() (uint32, uint64, uint64) {
+		network, a, b := virtualPair(t, 42)
+
+		network.SetLoss("10.0.0.1", "10.0.0.2", 0.3)
+		network.Advance(time.Minute)
+
+		return a.GetLocal().GetDiscriminator(), a.Snapshot().GetTransitions(), b.Snapshot().GetReceivedPackets()
+	}
+
+	discriminator, transitions, received := run()
+	discriminator2, transitions2, received2 := run()
+
+	if discriminator != discriminator2 || transitions != transitions2 || received != received2 {
+		t.Errorf("Expected the same run, got %d, %d, %d and %d, %d, %d",
+			discriminator, transitions, received, discriminator2, transitions2, received2)
+	}
+
+	if transitions < 2 {
+		t.Errorf("Expected the session to flap, got %d transitions", transitions)
+	}
+}
+
+func TestSetClockInUse(t *testing.T) {
+	network := NewVirtualNetwork(1)
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	if err := server.SetTransport(network.Transport(nil)); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	_, err := server.AddPeer(&api.Peer{
+		Address:               "10.0.0.2",
+		DesiredMinTxInterval:  100000,
+		RequiredMinRxInterval: 100,
+		DetectMultiplier:      3,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := server.SetClock(network.Clock()); err != ErrServerInUse {
+		t.Errorf("Expected %v, got %v", ErrServerInUse, err)
+	}
+
+	if err := server.SetRand(NewRand(1)); err != ErrServerInUse {
+		t.Errorf("Expected %v, got %v", ErrServerInUse, err)
+	}
+}
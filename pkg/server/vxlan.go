@@ -0,0 +1,165 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"net"
+
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+/*
+
+https://tools.ietf.org/html/rfc8971
+
+BFD for VXLAN verifies the forwarding between two VTEPs. The control packets
+are sent inside the tunnel to the remote VTEP, sessions are told apart by their
+VNI and address.
+
+*/
+
+var ErrVxlanSessionType = errors.New("VXLAN sessions can't be multi hop or use the echo function")
+var ErrNotBfd = errors.New("Discarded Packet: VXLAN payload isn't addressed to the BFD port")
+var ErrInvalidVxlanDestination = errors.New("Discarded Packet: VXLAN payload isn't addressed to the VTEP")
+
+// the inner destination addresses, taken from 127/8 and ::ffff:127.0.0.0/104
+var vxlanInnerIPv4 = net.IPv4(127, 0, 0, 1)
+var vxlanInnerIPv6 = net.ParseIP("::ffff:127.0.0.1")
+
+// ListenVxlan starts a listener for sessions inside VXLAN, the port defaults to 4789
+func (s *BfdServer) ListenVxlan(address string) error {
+	return s.listen(address, bfd.VXLAN_PORT, false, true)
+}
+
+// vxlanConn encapsulates the written control packets for the remote VTEP
+type vxlanConn struct {
+	*net.UDPConn
+
+	vni uint32
+	mac net.HardwareAddr // of the local VTEP
+}
+
+// vtepMACs returns the MAC addresses of the interfaces with the address ip, of all interfaces for
+// the wildcard address. Interfaces without one, like the loopback, are skipped.
+func vtepMACs(ip net.IP) []net.HardwareAddr {
+	macs := []net.HardwareAddr{}
+	ifaces, _ := net.Interfaces()
+
+	for _, iface := range ifaces {
+		if len(iface.HardwareAddr) != 6 {
+			continue
+		}
+
+		addrs, _ := iface.Addrs()
+
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok && (ip.IsUnspecified() || ipnet.IP.Equal(ip)) {
+				macs = append(macs, iface.HardwareAddr)
+				break
+			}
+		}
+	}
+
+	return macs
+}
+
+// vtepMAC returns the MAC address of the local VTEP with the address ip, all zero if it has none
+func vtepMAC(ip net.IP) net.HardwareAddr {
+	if macs := vtepMACs(ip); len(macs) > 0 {
+		return macs[0]
+	}
+
+	return make(net.HardwareAddr, 6)
+}
+
+func (c *vxlanConn) Write(b []byte) (int, error) {
+	local := c.LocalAddr().(*net.UDPAddr)
+	remote := c.RemoteAddr().(*net.UDPAddr)
+
+	/*
+		RFC8971 3
+		The destination is a loopback address, so the packet is never
+		routed after leaving the tunnel. The TTL / Hop Limit is 255 like
+		the one of single hop sessions (RFC5881), the source MAC is the
+		one of the local VTEP.
+	*/
+	dst := vxlanInnerIPv4
+
+	if remote.IP.To4() == nil {
+		dst = vxlanInnerIPv6
+	}
+
+	pkt := &bfd.VxlanPacket{
+		VNI:     c.vni,
+		DstMAC:  bfd.VxlanMAC,
+		SrcMAC:  c.mac,
+		SrcIP:   local.IP,
+		DstIP:   dst,
+		TTL:     255,
+		SrcPort: uint16(local.Port),
+		DstPort: BFD_PORT,
+		Payload: b,
+	}
+
+	data, err := pkt.MarshalBinary()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := c.UDPConn.Write(data); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// decapsulateVxlan returns the control packet carried by a received VXLAN packet and its VNI,
+// macs are the MAC addresses of the local VTEP
+func decapsulateVxlan(buf []byte, macs []net.HardwareAddr) ([]byte, uint32, error) {
+	pkt := &bfd.VxlanPacket{}
+
+	if err := pkt.UnmarshalBinary(buf); err != nil {
+		return nil, 0, err
+	}
+
+	/*
+		RFC8971 4
+		The inner destination MAC is either the one assigned to BFD for
+		VXLAN or one of the VTEP, the inner destination address is taken
+		from 127/8 or ::ffff:127.0.0.0/104. Everything else belongs to the
+		tenant.
+	*/
+	if !isVxlanMAC(pkt.DstMAC, macs) {
+		return nil, 0, ErrInvalidVxlanDestination
+	}
+
+	if dst := pkt.DstIP.To4(); dst == nil || dst[0] != 127 {
+		return nil, 0, ErrInvalidVxlanDestination
+	}
+
+	if pkt.DstPort != BFD_PORT {
+		return nil, 0, ErrNotBfd
+	}
+
+	// the inner TTL / Hop Limit is checked like the one of single hop sessions
+	if pkt.TTL != 255 {
+		return nil, 0, ErrInvalidTTL
+	}
+
+	return pkt.Payload, pkt.VNI, nil
+}
+
+func isVxlanMAC(mac net.HardwareAddr, macs []net.HardwareAddr) bool {
+	if bytes.Equal(mac, bfd.VxlanMAC) {
+		return true
+	}
+
+	for _, m := range macs {
+		if bytes.Equal(mac, m) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"bytes"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Thoro/bfd/pkg/api"
+	"github.com/Thoro/bfd/pkg/packet/bfd"
+)
+
+func TestVxlanConnWrite(t *testing.T) {
+	vtep, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer vtep.Close()
+
+	conn, err := net.DialUDP("udp", nil, vtep.LocalAddr().(*net.UDPAddr))
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	defer conn.Close()
+
+	payload := []byte{1, 2, 3, 4}
+
+	mac := net.HardwareAddr{0x02, 0, 0, 0, 0, 1}
+	n, err := (&vxlanConn{UDPConn: conn, vni: 100, mac: mac}).Write(payload)
+
+	if err != nil || n != len(payload) {
+		t.Fatalf("Expected the payload to be written, got %d %v", n, err)
+	}
+
+	b := make([]byte, 256)
+	vtep.SetReadDeadline(time.Now().Add(time.Second))
+
+	n, err = vtep.Read(b)
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pkt := &bfd.VxlanPacket{}
+
+	if err := pkt.UnmarshalBinary(b[:n]); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if pkt.VNI != 100 || pkt.TTL != 255 || pkt.DstPort != BFD_PORT || !pkt.DstIP.Equal(vxlanInnerIPv4) {
+		t.Errorf("Unexpected encapsulation %v", pkt)
+	}
+
+	if !bytes.Equal(pkt.SrcMAC, mac) || !bytes.Equal(pkt.DstMAC, bfd.VxlanMAC) {
+		t.Errorf("Expected the MAC of the VTEP, got %v", pkt)
+	}
+
+	if !pkt.SrcIP.Equal(net.ParseIP("127.0.0.1")) || !bytes.Equal(pkt.Payload, payload) {
+		t.Errorf("Unexpected inner packet %v", pkt)
+	}
+}
+
+func TestHandleIncomingPacketsVxlan(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	inner := &bfd.VxlanPacket{
+		VNI:     100,
+		DstMAC:  bfd.VxlanMAC,
+		SrcIP:   net.ParseIP("192.0.2.1"),
+		DstIP:   vxlanInnerIPv4,
+		TTL:     255,
+		SrcPort: 49152,
+		DstPort: BFD_PORT,
+	}
+
+	inner.Encapsulate(&bfd.ControlPacket{
+		Version:          1,
+		State:            bfd.Down,
+		DetectMultiplier: 3,
+		MyDiscriminator:  7,
+	})
+
+	fake := &FakeConn{}
+	fake.data, _ = inner.MarshalBinary()
+
+	// the tunnel is routed, so the outer TTL isn't 255
+	fake.oob = hopLimitCmsg(syscall.IPPROTO_IP, syscall.IP_TTL, 62)
+
+	l := &listener{
+		conn:  fake,
+		local: &net.UDPAddr{IP: net.IPv4zero, Port: bfd.VXLAN_PORT},
+		vxlan: true,
+	}
+
+	b := make([]byte, 256)
+	oob := make([]byte, 256)
+
+	if err := server.readIncomingPacket(l, b, oob); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	pkt := queuedPacket(t, server)
+
+	if !pkt.vxlan || pkt.vni != 100 || pkt.packet.MyDiscriminator != 7 {
+		t.Errorf("Unexpected packet %v", pkt)
+	}
+
+	// the MAC of the VTEP is accepted as well
+	vtep := net.HardwareAddr{0x02, 0, 0, 0, 0, 1}
+	l.macs = []net.HardwareAddr{vtep}
+	inner.DstMAC = vtep
+	fake.data, _ = inner.MarshalBinary()
+
+	if err := server.readIncomingPacket(l, b, oob); err != nil {
+		t.Errorf("%v", err)
+	}
+
+	pkt = queuedPacket(t, server)
+	pkt.release()
+
+	tests := []struct {
+		update func(*bfd.VxlanPacket)
+		err    error
+	}{
+		// tenant traffic in the tunnel isn't for us
+		{func(p *bfd.VxlanPacket) { p.DstPort = 80 }, ErrNotBfd},
+		{func(p *bfd.VxlanPacket) { p.DstMAC = net.HardwareAddr{0x02, 0, 0, 0, 0, 2} }, ErrInvalidVxlanDestination},
+		{func(p *bfd.VxlanPacket) { p.DstIP = net.ParseIP("192.0.2.2") }, ErrInvalidVxlanDestination},
+		{func(p *bfd.VxlanPacket) { p.TTL = 254 }, ErrInvalidTTL},
+	}
+
+	for _, test := range tests {
+		invalid := *inner
+		test.update(&invalid)
+		fake.data, _ = invalid.MarshalBinary()
+
+		if err := server.readIncomingPacket(l, b, oob); err != test.err {
+			t.Errorf("Expected %v, got %v", test.err, err)
+		}
+	}
+}
+
+func TestAddPeerVxlanInvalid(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	tests := []struct {
+		peer *api.Peer
+		err  error
+	}{
+		{&api.Peer{Address: "192.0.2.1", DetectMultiplier: 1, IsVxlan: true, Vni: 1 << 24}, bfd.ErrInvalidVNI},
+		{&api.Peer{Address: "192.0.2.1", DetectMultiplier: 1, IsVxlan: true, IsMultiHop: true}, ErrVxlanSessionType},
+		{&api.Peer{Address: "192.0.2.1", DetectMultiplier: 1, IsVxlan: true, EchoInterval: 50}, ErrVxlanSessionType},
+	}
+
+	for _, test := range tests {
+		if _, err := server.AddPeer(test.peer); err != test.err {
+			t.Errorf("Expected %v, got %v", test.err, err)
+		}
+	}
+}
+
+func TestHandlePacketVxlanVni(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	peer, err := server.AddPeer(&api.Peer{
+		Address:          "192.0.2.1",
+		DetectMultiplier: 1,
+		IsVxlan:          true,
+		Vni:              100,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if peer.Address.Port != bfd.VXLAN_PORT {
+		t.Errorf("Expected port %d, got %d", bfd.VXLAN_PORT, peer.Address.Port)
+	}
+
+	pkt := packet{
+		addr: &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 49152},
+		packet: &bfd.ControlPacket{
+			Version:           1,
+			DetectMultiplier:  3,
+			MyDiscriminator:   20,
+			YourDiscriminator: peer.GetLocal().GetDiscriminator(),
+			State:             bfd.Init,
+		},
+		vni: 100,
+	}
+
+	if err := server.handlePacket(pkt); err != ErrSessionTypeMismatch {
+		t.Errorf("Expected %v, got %v", ErrSessionTypeMismatch, err)
+	}
+
+	// the same VTEP, but another VNI
+	pkt.vxlan = true
+	pkt.vni = 200
+
+	if err := server.handlePacket(pkt); err != ErrAddressMismatch {
+		t.Errorf("Expected %v, got %v", ErrAddressMismatch, err)
+	}
+}
+
+func TestVxlanLoopback(t *testing.T) {
+	server := NewBfdServer()
+	defer server.Shutdown()
+
+	clock := NewVirtualClock(time.Unix(0, 0))
+
+	if err := server.SetClock(clock); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := server.ListenVxlan("127.0.0.1:0"); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	server.inbound.start(server.handlePacket)
+
+	var vtep string
+
+	for address := range server.conns {
+		vtep = address
+	}
+
+	// the session receives its own packets through the tunnel, packets are sent once per second while it's down
+	peer, err := server.AddPeer(&api.Peer{
+		Address:               vtep,
+		DesiredMinTxInterval:  10,
+		RequiredMinRxInterval: 10,
+		DetectMultiplier:      3,
+		IsVxlan:               true,
+		Vni:                   100,
+	})
+
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if !advanceUntilUp(clock, peer) {
+		t.Errorf("Expected the session to be up")
+	}
+}